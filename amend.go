@@ -0,0 +1,181 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Amendment errors
+var (
+	ErrOverExecution = errors.New("execution above the remaining amount")
+)
+
+// Execution is one partial execution of an accepted quote.
+type Execution struct {
+	QuoteID string `json:"quoteId"`
+	// Amount is the source amount executed and FinalAmount its share of
+	// the quote's FinalAmount. The shares of all executions add up to the
+	// quote's FinalAmount exactly.
+	Amount      decimal.Decimal `json:"amount"`
+	FinalAmount decimal.Decimal `json:"finalAmount"`
+	// Remaining is the source amount left to execute afterwards.
+	Remaining decimal.Decimal `json:"remaining"`
+	Time      time.Time       `json:"time"`
+}
+
+// Remaining returns the source amount of the quote left to execute.
+func (g *Guarantee) Remaining() decimal.Decimal {
+	return g.Amount.Sub(g.Executed)
+}
+
+// Amend changes the amount of an accepted quote while its rate is
+// guaranteed. The quote is re-derived at the guaranteed rate and its fee
+// scaled in proportion to the amount, rounded up to the precision the quote
+// was priced at. The amount may not go below what has been
+// executed. It returns the amended quote, saved to the store.
+func (g *Guarantees) Amend(ctx context.Context, id string, amount decimal.Decimal) (*Quote, error) {
+	if err := validateAmount("amount", amount); err != nil {
+		return nil, err
+	}
+	now := g.clock().Now()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	guarantee, err := g.active(id, now)
+	if err != nil {
+		return nil, err
+	}
+	if amount.LessThan(guarantee.Executed) {
+		return nil, fmt.Errorf("%w: %s already executed", ErrInvalidAmount, guarantee.Executed)
+	}
+
+	q, err := g.Store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if q.Status != QuoteStatusAccepted {
+		return nil, fmt.Errorf("%w: quote %s is %s", ErrQuoteStatusChanged, id, q.Status)
+	}
+	if err := amendQuote(q, amount, guarantee.Rate); err != nil {
+		return nil, err
+	}
+	if err := g.Store.Save(ctx, q); err != nil {
+		return nil, err
+	}
+
+	guarantee.Amount = q.FromAmount
+	guarantee.FinalAmount = q.FinalAmount
+	g.emit(Event{Type: EventQuoteAmended, Time: now, QuoteID: id, Pair: guarantee.Pair, Actor: ActorFromContext(ctx)})
+	return q, nil
+}
+
+// Execute executes part of an accepted quote while its rate is guaranteed.
+// It returns an ErrOverExecution error above the remaining amount. Once
+// nothing remains the quote moves to executed and its guarantee is
+// released.
+func (g *Guarantees) Execute(ctx context.Context, id string, amount decimal.Decimal) (*Execution, error) {
+	if err := validateAmount("amount", amount); err != nil {
+		return nil, err
+	}
+	now := g.clock().Now()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	guarantee, err := g.active(id, now)
+	if err != nil {
+		return nil, err
+	}
+	remaining := guarantee.Remaining().Sub(amount)
+	if remaining.IsNegative() {
+		return nil, fmt.Errorf("%w: %s of %s remaining", ErrOverExecution, amount, guarantee.Remaining())
+	}
+
+	// Executions get their share of the final amount rounded down; the
+	// last one gets the rest.
+	finalAmount := guarantee.FinalAmount.Sub(guarantee.Delivered)
+	if remaining.IsPositive() {
+		share := guarantee.FinalAmount.Mul(amount).DivRound(guarantee.Amount, maxDigits)
+		finalAmount = share.RoundDown(int32(guarantee.toPrecision))
+	}
+
+	if remaining.IsZero() {
+		if err := g.Store.UpdateStatus(ctx, id, QuoteStatusAccepted, QuoteStatusExecuted); err != nil {
+			return nil, err
+		}
+		delete(g.guarantees, id)
+	}
+
+	execution := Execution{QuoteID: id, Amount: amount, FinalAmount: finalAmount, Remaining: remaining, Time: now}
+	guarantee.Executed = guarantee.Executed.Add(amount)
+	guarantee.Delivered = guarantee.Delivered.Add(finalAmount)
+	guarantee.Executions = append(guarantee.Executions, execution)
+	g.emit(Event{Type: EventQuoteExecuted, Time: now, QuoteID: id, Pair: guarantee.Pair, Actor: ActorFromContext(ctx)})
+	return &execution, nil
+}
+
+// amendQuote re-derives q for amount at rate, scaling its fee in proportion.
+func amendQuote(q *Quote, amount, rate decimal.Decimal) error {
+	fromPrecision, toPrecision := quotePrecisions(q)
+	from := &Currency{ISOCode: q.FromCurrency, Precision: fromPrecision}
+	to := &Currency{ISOCode: q.ToCurrency, Precision: toPrecision}
+	mode := q.FeeMode
+	if mode == "" {
+		mode = FeeAdditive
+	}
+	feeCurrency := from
+	if mode == FeeInDestination {
+		feeCurrency = to
+	}
+
+	fee := q.Fee
+	if q.FromAmount.IsPositive() {
+		fee = fee.Mul(amount).DivRound(q.FromAmount, maxDigits).RoundCeil(int32(feeCurrency.Precision))
+	}
+
+	converted, amountToDeduct := amount, amount.RoundCeil(int32(from.Precision))
+	switch mode {
+	case FeeAdditive:
+		amountToDeduct = amountToDeduct.Add(fee)
+	case FeeDeducted:
+		converted = amount.Sub(fee)
+	}
+	finalAmount := converted.Mul(rate).RoundCeil(int32(to.Precision))
+	if mode == FeeInDestination {
+		finalAmount = finalAmount.Sub(fee)
+	}
+	if finalAmount.IsNegative() || converted.IsNegative() {
+		return fmt.Errorf("%w: fee %s %s", ErrFeeExceedsAmount, fee, feeCurrency.ISOCode)
+	}
+
+	q.FromAmount = amount
+	q.Fee = fee
+	q.AmountToDeduct = amountToDeduct
+	q.FinalAmount = finalAmount
+	q.Send, q.Receive, q.EffectiveRate = quoteSides(from, to, amountToDeduct, finalAmount, fee, rate, mode, nil)
+	return nil
+}
+
+// quotePrecisions returns the precisions q was priced at. Quotes saved
+// before they were recorded have neither, and fall back to the ISO minor
+// units of their currencies.
+func quotePrecisions(q *Quote) (from, to int) {
+	if q.FromPrecision != 0 || q.ToPrecision != 0 {
+		return q.FromPrecision, q.ToPrecision
+	}
+	return quotePrecision(q.FromCurrency), quotePrecision(q.ToCurrency)
+}
+
+// quotePrecision returns the ISO minor units of a currency, or 2 for
+// currencies without known minor units.
+func quotePrecision(code string) int {
+	if units, ok := MinorUnits(code); ok {
+		return units
+	}
+	return 2
+}
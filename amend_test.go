@@ -0,0 +1,147 @@
+package converter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func acceptedQuote(t *testing.T, fee decimal.Decimal, opts ...QuoteOption) (*Guarantees, *Quote) {
+	t.Helper()
+	now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryQuoteStore()
+	quote, err := NewQuote(testCurrencies(), "USD", "USD", "NGN", decimal.NewFromInt(100), fee, append(opts, WithClock(fixedClock(now)))...)
+	assert.NoError(t, err)
+	assert.NoError(t, store.Save(context.Background(), quote))
+
+	g, err := NewGuarantees(store, time.Hour)
+	assert.NoError(t, err)
+	g.Clock = fixedClock(now)
+	_, err = g.Accept(context.Background(), quote.ID)
+	assert.NoError(t, err)
+	return g, quote
+}
+
+func TestGuarantees_Amend(t *testing.T) {
+	ctx := context.Background()
+	g, quote := acceptedQuote(t, decimal.NewFromInt(3))
+
+	amended, err := g.Amend(ctx, quote.ID, decimal.RequireFromString("50.5"))
+	assert.NoError(t, err)
+	assert.Equal(t, quote.ID, amended.ID)
+	assert.Equal(t, "1.52", amended.Fee.String(), "1.515 rounded up")
+	assert.Equal(t, "52.02", amended.AmountToDeduct.String())
+	assert.Equal(t, "78275", amended.FinalAmount.String())
+	assert.Equal(t, amended.AmountToDeduct, amended.Send.Amount)
+	assert.Equal(t, QuoteStatusAccepted, amended.Status)
+
+	stored, err := g.Store.Get(ctx, quote.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, amended, stored)
+
+	guarantee, err := g.Get(quote.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "50.5", guarantee.Amount.String())
+	assert.Equal(t, "78275", guarantee.FinalAmount.String())
+
+	_, err = g.Amend(ctx, "missing", decimal.NewFromInt(1))
+	assert.ErrorIs(t, err, ErrNoGuarantee)
+	_, err = g.Amend(ctx, quote.ID, decimal.NewFromInt(-1))
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+}
+
+func TestGuarantees_AmendFeeInDestination(t *testing.T) {
+	g, quote := acceptedQuote(t, decimal.NewFromInt(3100), WithFeeMode(FeeInDestination))
+
+	amended, err := g.Amend(context.Background(), quote.ID, decimal.NewFromInt(200))
+	assert.NoError(t, err)
+	assert.Equal(t, "6200", amended.Fee.String())
+	assert.Equal(t, "200", amended.AmountToDeduct.String())
+	assert.Equal(t, "303800", amended.FinalAmount.String())
+}
+
+func TestGuarantees_Execute(t *testing.T) {
+	ctx := context.Background()
+	g, quote := acceptedQuote(t, decimal.Zero)
+	final := quote.FinalAmount // 155000
+
+	first, err := g.Execute(ctx, quote.ID, decimal.RequireFromString("33.333"))
+	assert.NoError(t, err)
+	assert.Equal(t, "51666.15", first.FinalAmount.String())
+	assert.Equal(t, "66.667", first.Remaining.String())
+
+	_, err = g.Execute(ctx, quote.ID, decimal.NewFromInt(70))
+	assert.ErrorIs(t, err, ErrOverExecution)
+
+	_, err = g.Amend(ctx, quote.ID, decimal.NewFromInt(30))
+	assert.ErrorIs(t, err, ErrInvalidAmount, "below the executed amount")
+
+	last, err := g.Execute(ctx, quote.ID, decimal.RequireFromString("66.667"))
+	assert.NoError(t, err)
+	assert.True(t, last.Remaining.IsZero())
+	assert.True(t, first.FinalAmount.Add(last.FinalAmount).Equal(final))
+
+	stored, err := g.Store.Get(ctx, quote.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, QuoteStatusExecuted, stored.Status)
+	_, err = g.Get(quote.ID)
+	assert.ErrorIs(t, err, ErrNoGuarantee)
+}
+
+func TestGuarantees_KeepQuotePrecision(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	currencies := append(testCurrencies(), Currency{ISOCode: "XYZ", Precision: 8, BuyRate: decimal.RequireFromString("0.00001"), SellRate: decimal.RequireFromString("0.00001234")})
+	store := NewMemoryQuoteStore()
+	g, err := NewGuarantees(store, time.Hour)
+	assert.NoError(t, err)
+	g.Clock = fixedClock(now)
+	accept := func(from, to string, amount int64) *Quote {
+		quote, err := NewQuote(currencies, "USD", from, to, decimal.NewFromInt(amount), decimal.Zero, WithClock(fixedClock(now)))
+		assert.NoError(t, err)
+		assert.NoError(t, store.Save(ctx, quote))
+		_, err = g.Accept(ctx, quote.ID)
+		assert.NoError(t, err)
+		return quote
+	}
+
+	// Amendments round the source amount at the precision XYZ was quoted
+	// at, not at a default of 2 places.
+	quote := accept("XYZ", "USD", 1)
+	assert.Equal(t, 8, quote.FromPrecision)
+	assert.Equal(t, 2, quote.ToPrecision)
+	amended, err := g.Amend(ctx, quote.ID, decimal.RequireFromString("0.01234"))
+	assert.NoError(t, err)
+	assert.Equal(t, "0.01234", amended.AmountToDeduct.String())
+
+	// Executions' shares keep XYZ's 8 places.
+	quote = accept("USD", "XYZ", 200)
+	assert.Equal(t, "0.002468", quote.FinalAmount.String())
+	execution, err := g.Execute(ctx, quote.ID, decimal.NewFromInt(100))
+	assert.NoError(t, err)
+	assert.Equal(t, "0.001234", execution.FinalAmount.String())
+}
+
+func TestGuarantees_AmendRacesCancel(t *testing.T) {
+	ctx := context.Background()
+	for i := 0; i < 50; i++ {
+		g, quote := acceptedQuote(t, decimal.Zero)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_, _ = g.Amend(ctx, quote.ID, decimal.NewFromInt(50))
+		}()
+		assert.NoError(t, g.Cancel(ctx, quote.ID, CancelCustomerCancelled))
+		<-done
+
+		// Whichever ran first, the amendment never brings the cancelled
+		// quote back.
+		stored, err := g.Store.Get(ctx, quote.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, QuoteStatusCancelled, stored.Status)
+		assert.Equal(t, CancelCustomerCancelled, stored.CancelReason)
+	}
+}
@@ -0,0 +1,162 @@
+package converter
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/shopspring/decimal"
+)
+
+// ANSI escapes used to highlight rate changes.
+const (
+	ansiGreen  = "\x1b[32m"
+	ansiRed    = "\x1b[31m"
+	ansiYellow = "\x1b[33m"
+	ansiReset  = "\x1b[0m"
+)
+
+// RateTable renders rates as a boxed text table for terminals and ops
+// runbooks:
+//
+//	+---------+------+------+--------+
+//	| Pair    |  Buy | Sell | Change |
+//	+---------+------+------+--------+
+//	| USD/EUR | 0.91 | 0.95 | +1.11% |
+//	| USD/NGN | 1500 | 1550 |        |
+//	+---------+------+------+--------+
+type RateTable struct {
+	// Base is the currency the rates are quoted against; it is left out
+	// of the rows.
+	Base       string
+	Currencies []Currency
+	// Previous, if not nil, adds a Change column comparing each currency
+	// with its previous rates: the percent movement of its mid rate, "new"
+	// for added currencies and "removed" for removed ones, which are
+	// listed with their previous rates.
+	Previous []Currency
+	// Color highlights changes with ANSI colours: green for rising rates,
+	// red for falling ones and yellow for added and removed currencies.
+	Color bool
+}
+
+// tableCell is a rendered cell; colour is an ANSI escape or empty.
+type tableCell struct {
+	text   string
+	colour string
+	right  bool
+}
+
+// WriteText writes the table, one row per currency sorted by ISO code.
+func (t *RateTable) WriteText(w io.Writer) error {
+	base := strings.ToUpper(t.Base)
+	header := []tableCell{{text: "Pair"}, {text: "Buy", right: true}, {text: "Sell", right: true}}
+	if t.Previous != nil {
+		header = append(header, tableCell{text: "Change", right: true})
+	}
+
+	var changes map[string]RateDiff
+	if t.Previous != nil {
+		changes = map[string]RateDiff{}
+		for _, c := range DiffTables(base, t.Previous, t.Currencies).Changes {
+			changes[c.ISOCode] = c
+		}
+	}
+
+	// Removed currencies are listed with their previous rates.
+	listed := slices.Clone(t.Currencies)
+	for _, d := range changes {
+		if d.Current == nil {
+			listed = append(listed, *d.Previous)
+		}
+	}
+
+	var rows [][]tableCell
+	for _, c := range sortedCurrencies(listed) {
+		code := strings.ToUpper(c.ISOCode)
+		if code == base {
+			continue
+		}
+		cells := []tableCell{
+			{text: base + "/" + code},
+			{text: c.BuyRate.String(), right: true},
+			{text: c.SellRate.String(), right: true},
+		}
+		if changes != nil {
+			cells = append(cells, changeCell(changes[code]))
+		}
+		rows = append(rows, cells)
+	}
+
+	widths := make([]int, len(header))
+	for _, r := range append([][]tableCell{header}, rows...) {
+		for i, c := range r {
+			widths[i] = max(widths[i], utf8.RuneCountInString(c.text))
+		}
+	}
+
+	var b strings.Builder
+	rule := func() {
+		for _, width := range widths {
+			b.WriteString("+" + strings.Repeat("-", width+2))
+		}
+		b.WriteString("+\n")
+	}
+	line := func(cells []tableCell) {
+		for i, c := range cells {
+			pad := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(c.text))
+			text := c.text
+			if t.Color && c.colour != "" {
+				text = c.colour + text + ansiReset
+			}
+			if c.right {
+				text = pad + text
+			} else {
+				text += pad
+			}
+			b.WriteString("| " + text + " ")
+		}
+		b.WriteString("|\n")
+	}
+
+	rule()
+	line(header)
+	rule()
+	for _, r := range rows {
+		line(r)
+	}
+	rule()
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// changeCell formats a currency's change; the zero RateDiff is unchanged.
+func changeCell(d RateDiff) tableCell {
+	switch {
+	case d.Previous == nil && d.Current == nil:
+		return tableCell{right: true}
+	case d.Previous == nil:
+		return tableCell{text: "new", colour: ansiYellow, right: true}
+	case d.Current == nil:
+		return tableCell{text: "removed", colour: ansiYellow, right: true}
+	}
+
+	half := decimal.New(5, -1)
+	mid := func(c *Currency) decimal.Decimal {
+		return c.BuyRate.Add(c.SellRate).Mul(half)
+	}
+	change := percentChange(mid(d.Previous), mid(d.Current))
+	if change == nil || change.IsZero() {
+		return tableCell{right: true}
+	}
+	c := tableCell{text: fmt.Sprintf("%s%%", change.StringFixed(2)), right: true}
+	if change.IsPositive() {
+		c.text = "+" + c.text
+		c.colour = ansiGreen
+	} else {
+		c.colour = ansiRed
+	}
+	return c
+}
@@ -0,0 +1,53 @@
+package converter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateTable_WriteText(t *testing.T) {
+	var buf bytes.Buffer
+	table := &RateTable{Base: "usd", Currencies: testCurrencies()}
+	assert.NoError(t, table.WriteText(&buf))
+	assert.Equal(t, `+---------+------+------+
+| Pair    |  Buy | Sell |
++---------+------+------+
+| USD/EUR |  0.9 | 0.95 |
+| USD/NGN | 1500 | 1550 |
++---------+------+------+
+`, buf.String())
+}
+
+func TestRateTable_WriteTextChanges(t *testing.T) {
+	eur := Currency{ISOCode: "EUR", Precision: 2, BuyRate: decimal.RequireFromString("0.91"), SellRate: decimal.RequireFromString("0.96")}
+	gbp := Currency{ISOCode: "GBP", Precision: 2, BuyRate: decimal.RequireFromString("0.8"), SellRate: decimal.RequireFromString("0.82")}
+	jpy := Currency{ISOCode: "JPY", Precision: 0, BuyRate: decimal.NewFromInt(150), SellRate: decimal.NewFromInt(152)}
+	rates := testCurrencies()
+	current := []Currency{rates[0], eur, gbp, rates[2]}
+	previous := append(testCurrencies(), jpy)
+
+	var buf bytes.Buffer
+	table := &RateTable{Base: "USD", Currencies: current, Previous: previous}
+	assert.NoError(t, table.WriteText(&buf))
+	assert.Equal(t, `+---------+------+------+---------+
+| Pair    |  Buy | Sell |  Change |
++---------+------+------+---------+
+| USD/EUR | 0.91 | 0.96 |  +1.08% |
+| USD/GBP |  0.8 | 0.82 |     new |
+| USD/JPY |  150 |  152 | removed |
+| USD/NGN | 1500 | 1550 |         |
++---------+------+------+---------+
+`, buf.String())
+
+	buf.Reset()
+	eur.BuyRate = decimal.RequireFromString("0.8")
+	table = &RateTable{Base: "USD", Currencies: []Currency{rates[0], eur}, Previous: rates[:2], Color: true}
+	assert.NoError(t, table.WriteText(&buf))
+	assert.Contains(t, buf.String(), "| \x1b[31m-4.86%\x1b[0m |")
+	lines := strings.Split(buf.String(), "\n")
+	assert.Equal(t, len(lines[0]), len(strings.ReplaceAll(strings.ReplaceAll(lines[3], "\x1b[31m", ""), "\x1b[0m", "")))
+}
@@ -0,0 +1,203 @@
+package converter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Audit errors
+var (
+	ErrAuditChainBroken = errors.New("audit chain broken")
+)
+
+// AuditEntry is a single rate change recorded in an AuditLog.
+// Previous is nil for an added currency and Current is nil for a removed one.
+type AuditEntry struct {
+	Sequence int       `json:"sequence"`
+	Time     time.Time `json:"time"`
+	ISOCode  string    `json:"isoCode"`
+	Previous *Currency `json:"previous,omitempty"`
+	Current  *Currency `json:"current,omitempty"`
+	PrevHash string    `json:"prevHash"`
+	Hash     string    `json:"hash"`
+}
+
+// AuditLog is an append-only log of rate table changes. Every entry carries
+// the hash of the entry before it, so any edit to the history breaks the chain.
+type AuditLog struct {
+	mu      sync.Mutex
+	entries []AuditEntry
+}
+
+// NewAuditLog creates an empty audit log.
+func NewAuditLog() *AuditLog {
+	return &AuditLog{}
+}
+
+// Record compares two versions of a rate table and appends one entry per
+// added, removed or changed currency. It returns the appended entries.
+func (l *AuditLog) Record(previous, current []Currency, at time.Time) []AuditEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var recorded []AuditEntry
+	for _, change := range currencyChanges(previous, current) {
+		entry := AuditEntry{
+			Sequence: len(l.entries) + 1,
+			Time:     at.UTC(),
+			ISOCode:  change.code,
+			Previous: change.previous,
+			Current:  change.current,
+		}
+		if n := len(l.entries); n > 0 {
+			entry.PrevHash = l.entries[n-1].Hash
+		}
+		entry.Hash = entry.computeHash()
+
+		l.entries = append(l.entries, entry)
+		recorded = append(recorded, entry)
+	}
+
+	return recorded
+}
+
+// Entries returns a copy of all entries in the log.
+func (l *AuditLog) Entries() []AuditEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return append([]AuditEntry(nil), l.entries...)
+}
+
+// Head returns the hash of the last entry, or "" for an empty log. The hash
+// chain alone cannot reveal entries dropped from the end of an export, so
+// store the head separately (e.g. sign it or log it elsewhere) and check
+// exports against it with VerifyAuditHead.
+func (l *AuditLog) Head() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.entries) == 0 {
+		return ""
+	}
+	return l.entries[len(l.entries)-1].Hash
+}
+
+// Verify checks the hash chain of the log.
+func (l *AuditLog) Verify() error {
+	return VerifyAuditEntries(l.Entries())
+}
+
+// Export writes the log as a JSON array for compliance reviews.
+func (l *AuditLog) Export(w io.Writer) error {
+	entries := l.Entries()
+	if entries == nil {
+		entries = []AuditEntry{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(entries)
+}
+
+// VerifyAuditEntries checks the hash chain of exported audit entries.
+func VerifyAuditEntries(entries []AuditEntry) error {
+	prevHash := ""
+	for i, entry := range entries {
+		if entry.Sequence != i+1 {
+			return fmt.Errorf("%w: entry %d out of sequence", ErrAuditChainBroken, entry.Sequence)
+		}
+		if entry.PrevHash != prevHash {
+			return fmt.Errorf("%w: entry %d does not follow previous entry", ErrAuditChainBroken, entry.Sequence)
+		}
+		if entry.Hash != entry.computeHash() {
+			return fmt.Errorf("%w: entry %d has been modified", ErrAuditChainBroken, entry.Sequence)
+		}
+		prevHash = entry.Hash
+	}
+
+	return nil
+}
+
+// VerifyAuditHead checks the hash chain of exported audit entries and that the
+// last entry has the expected head hash, which detects truncation.
+func VerifyAuditHead(entries []AuditEntry, head string) error {
+	if err := VerifyAuditEntries(entries); err != nil {
+		return err
+	}
+
+	last := ""
+	if n := len(entries); n > 0 {
+		last = entries[n-1].Hash
+	}
+	if last != head {
+		return fmt.Errorf("%w: last entry does not match head", ErrAuditChainBroken)
+	}
+
+	return nil
+}
+
+// computeHash hashes every field of the entry except Hash itself.
+func (e AuditEntry) computeHash() string {
+	e.Hash = ""
+	b, _ := json.Marshal(e)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+type currencyChange struct {
+	code     string
+	previous *Currency
+	current  *Currency
+}
+
+// currencyChanges lists the currencies that differ between two tables,
+// sorted by ISO code.
+func currencyChanges(previous, current []Currency) []currencyChange {
+	before := indexByCode(previous)
+	after := indexByCode(current)
+
+	var changes []currencyChange
+	for code, prev := range before {
+		cur, ok := after[code]
+		if !ok {
+			changes = append(changes, currencyChange{code: code, previous: prev})
+			continue
+		}
+		if !sameCurrency(*prev, *cur) {
+			changes = append(changes, currencyChange{code: code, previous: prev, current: cur})
+		}
+	}
+	for code, cur := range after {
+		if _, ok := before[code]; !ok {
+			changes = append(changes, currencyChange{code: code, current: cur})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].code < changes[j].code })
+	return changes
+}
+
+func indexByCode(currencies []Currency) map[string]*Currency {
+	index := make(map[string]*Currency, len(currencies))
+	for i := range currencies {
+		c := currencies[i]
+		index[strings.ToUpper(c.ISOCode)] = &c
+	}
+	return index
+}
+
+func sameCurrency(a, b Currency) bool {
+	return a.Precision == b.Precision &&
+		a.BuyRate.Equal(b.BuyRate) &&
+		a.SellRate.Equal(b.SellRate) &&
+		strings.EqualFold(a.BaseISOCode, b.BaseISOCode)
+}
@@ -0,0 +1,92 @@
+package converter
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditLog(t *testing.T) {
+	var (
+		at = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		v1 = []Currency{
+			{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+			{ISOCode: "EUR", Precision: 2, BuyRate: decimal.NewFromFloat(0.9), SellRate: decimal.NewFromFloat(0.95)},
+		}
+		v2 = []Currency{
+			{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+			{ISOCode: "EUR", Precision: 2, BuyRate: decimal.NewFromFloat(0.91), SellRate: decimal.NewFromFloat(0.96)},
+			{ISOCode: "NGN", Precision: 2, BuyRate: decimal.NewFromInt(450), SellRate: decimal.NewFromInt(460)},
+		}
+	)
+
+	log := NewAuditLog()
+
+	// Initial load records every currency as added
+	entries := log.Record(nil, v1, at)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "EUR", entries[0].ISOCode)
+	assert.Nil(t, entries[0].Previous)
+	assert.Empty(t, entries[0].PrevHash)
+
+	// Only changed and added currencies are recorded
+	entries = log.Record(v1, v2, at.Add(time.Minute))
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "EUR", entries[0].ISOCode)
+	assert.Equal(t, "0.9", entries[0].Previous.BuyRate.String())
+	assert.Equal(t, "0.91", entries[0].Current.BuyRate.String())
+	assert.Equal(t, "NGN", entries[1].ISOCode)
+
+	// Removed currencies are recorded with no current value
+	entries = log.Record(v2, v1, at.Add(2*time.Minute))
+	assert.Len(t, entries, 2)
+	assert.Nil(t, entries[1].Current)
+
+	// No change, no entries
+	assert.Empty(t, log.Record(v1, v1, at))
+
+	all := log.Entries()
+	assert.Len(t, all, 6)
+	for i := 1; i < len(all); i++ {
+		assert.Equal(t, all[i-1].Hash, all[i].PrevHash)
+	}
+	assert.NoError(t, log.Verify())
+}
+
+func TestAuditLog_ExportAndVerify(t *testing.T) {
+	log := NewAuditLog()
+	log.Record(nil, []Currency{
+		{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+		{ISOCode: "EUR", Precision: 2, BuyRate: decimal.NewFromFloat(0.9), SellRate: decimal.NewFromFloat(0.95)},
+	}, time.Now())
+
+	var buf bytes.Buffer
+	assert.NoError(t, log.Export(&buf))
+
+	var exported []AuditEntry
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &exported))
+	assert.NoError(t, VerifyAuditEntries(exported))
+
+	// Tampering with a rate breaks the chain
+	exported[0].Current.SellRate = decimal.NewFromInt(2)
+	assert.ErrorIs(t, VerifyAuditEntries(exported), ErrAuditChainBroken)
+
+	// Dropping an entry breaks the chain
+	assert.ErrorIs(t, VerifyAuditEntries(exported[1:]), ErrAuditChainBroken)
+
+	// Dropping the last entry is only caught against the head
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &exported))
+	assert.NoError(t, VerifyAuditHead(exported, log.Head()))
+	assert.NoError(t, VerifyAuditEntries(exported[:1]))
+	assert.ErrorIs(t, VerifyAuditHead(exported[:1], log.Head()), ErrAuditChainBroken)
+	assert.Empty(t, NewAuditLog().Head())
+
+	// Empty log exports an empty array
+	buf.Reset()
+	assert.NoError(t, NewAuditLog().Export(&buf))
+	assert.JSONEq(t, "[]", buf.String())
+}
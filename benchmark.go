@@ -0,0 +1,146 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Benchmark errors
+var (
+	ErrRateDeviation = errors.New("rate deviates from benchmark")
+)
+
+// DeviationError reports a rate further from its benchmark than the
+// tolerance allows. It matches ErrRateDeviation.
+type DeviationError struct {
+	ISOCode string
+	// Side is "buy" or "sell".
+	Side      string
+	Rate      decimal.Decimal
+	Benchmark decimal.Decimal
+	// Deviation is |Rate-Benchmark|/Benchmark, e.g. 0.1 for 10%.
+	Deviation decimal.Decimal
+	Tolerance decimal.Decimal
+}
+
+// Error implements error.
+func (e *DeviationError) Error() string {
+	return fmt.Sprintf("%v: %s %s rate %s is %s from %s, beyond %s",
+		ErrRateDeviation, e.ISOCode, e.Side, e.Rate, e.Deviation.StringFixed(4), e.Benchmark, e.Tolerance)
+}
+
+// Is makes every DeviationError match ErrRateDeviation.
+func (e *DeviationError) Is(target error) bool {
+	return target == ErrRateDeviation
+}
+
+// BenchmarkGuard is a RateProvider that checks the rates of Provider against
+// a reference Benchmark source, so fat-finger feeds are kept out of a live
+// table. Both sources must quote against the same base currency. Currencies
+// the benchmark does not quote are not checked.
+type BenchmarkGuard struct {
+	Provider  RateProvider
+	Benchmark RateProvider
+	// Tolerance is the largest accepted relative deviation, e.g. 0.02 for 2%.
+	Tolerance decimal.Decimal
+	// Tolerances overrides Tolerance per currency, e.g. "NGN".
+	Tolerances map[string]decimal.Decimal
+	// FlagOnly passes deviating rates through, only reporting them to
+	// OnDeviation, instead of failing the fetch.
+	FlagOnly bool
+	// OnDeviation, if set, is called with the deviations of every fetch
+	// that has any.
+	OnDeviation func(deviations []*DeviationError)
+	// IgnoreBenchmarkErrors passes rates through unchecked when the
+	// benchmark cannot be fetched, instead of failing the fetch.
+	IgnoreBenchmarkErrors bool
+}
+
+// FetchRates implements RateProvider. Unless FlagOnly is set, it fails with
+// the joined deviations when any rate is beyond its tolerance.
+func (g *BenchmarkGuard) FetchRates(ctx context.Context) ([]Currency, error) {
+	currencies, err := g.Provider.FetchRates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	benchmark, err := g.Benchmark.FetchRates(ctx)
+	if err != nil {
+		if g.IgnoreBenchmarkErrors {
+			return currencies, nil
+		}
+		return nil, fmt.Errorf("fetch benchmark: %w", err)
+	}
+
+	deviations := g.Deviations(currencies, benchmark)
+	if len(deviations) == 0 {
+		return currencies, nil
+	}
+	if g.OnDeviation != nil {
+		g.OnDeviation(deviations)
+	}
+	if g.FlagOnly {
+		return currencies, nil
+	}
+
+	errs := make([]error, len(deviations))
+	for i, d := range deviations {
+		errs[i] = d
+	}
+	return nil, errors.Join(errs...)
+}
+
+// Deviations compares currencies against benchmark rates and returns the
+// buy and sell rates beyond their tolerance, in the order of currencies.
+func (g *BenchmarkGuard) Deviations(currencies, benchmark []Currency) []*DeviationError {
+	reference := make(map[string]Currency, len(benchmark))
+	for _, b := range benchmark {
+		reference[strings.ToUpper(b.ISOCode)] = b
+	}
+
+	var deviations []*DeviationError
+	for _, c := range currencies {
+		code := strings.ToUpper(c.ISOCode)
+		b, ok := reference[code]
+		if !ok {
+			continue
+		}
+		tolerance := g.Tolerance
+		for c, t := range g.Tolerances {
+			if strings.EqualFold(c, code) {
+				tolerance = t
+			}
+		}
+		if d := deviation(code, "buy", c.BuyRate, b.BuyRate, tolerance); d != nil {
+			deviations = append(deviations, d)
+		}
+		if d := deviation(code, "sell", c.SellRate, b.SellRate, tolerance); d != nil {
+			deviations = append(deviations, d)
+		}
+	}
+	return deviations
+}
+
+// deviation returns the deviation of rate from benchmark if it is beyond
+// tolerance. Rates without a positive benchmark are not checked.
+func deviation(code, side string, rate, benchmark, tolerance decimal.Decimal) *DeviationError {
+	if !benchmark.IsPositive() {
+		return nil
+	}
+	d := rate.Sub(benchmark).Abs().DivRound(benchmark, maxDigits)
+	if d.LessThanOrEqual(tolerance) {
+		return nil
+	}
+	return &DeviationError{
+		ISOCode:   code,
+		Side:      side,
+		Rate:      rate,
+		Benchmark: benchmark,
+		Deviation: d,
+		Tolerance: tolerance,
+	}
+}
@@ -0,0 +1,91 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBenchmarkGuard_FetchRates(t *testing.T) {
+	fatFinger := testCurrencies()
+	fatFinger[2].SellRate = decimal.NewFromInt(15500) // NGN, 10x the benchmark
+	feed := fatFinger
+	provider := RateProviderFunc(func(ctx context.Context) ([]Currency, error) {
+		return feed, nil
+	})
+	benchmark := RateProviderFunc(func(ctx context.Context) ([]Currency, error) {
+		return testCurrencies(), nil
+	})
+
+	var flagged []*DeviationError
+	guard := &BenchmarkGuard{
+		Provider:    provider,
+		Benchmark:   benchmark,
+		Tolerance:   decimal.NewFromFloat(0.02),
+		OnDeviation: func(d []*DeviationError) { flagged = d },
+	}
+
+	_, err := guard.FetchRates(context.Background())
+	assert.ErrorIs(t, err, ErrRateDeviation)
+	var deviation *DeviationError
+	assert.ErrorAs(t, err, &deviation)
+	assert.Equal(t, "NGN", deviation.ISOCode)
+	assert.Equal(t, "sell", deviation.Side)
+	assert.Equal(t, "9", deviation.Deviation.String())
+	assert.Len(t, flagged, 1)
+
+	// Flagging passes the rates through.
+	guard.FlagOnly = true
+	flagged = nil
+	currencies, err := guard.FetchRates(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, fatFinger, currencies)
+	assert.Len(t, flagged, 1)
+
+	// A wider per-currency tolerance and rates within tolerance pass.
+	guard.FlagOnly = false
+	guard.Tolerances = map[string]decimal.Decimal{"ngn": decimal.NewFromInt(10)}
+	_, err = guard.FetchRates(context.Background())
+	assert.NoError(t, err)
+
+	guard.Tolerances = nil
+	feed = testCurrencies()
+	feed[2].SellRate = decimal.NewFromInt(1560)
+	_, err = guard.FetchRates(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestBenchmarkGuard_BenchmarkError(t *testing.T) {
+	guard := &BenchmarkGuard{
+		Provider: RateProviderFunc(func(ctx context.Context) ([]Currency, error) {
+			return testCurrencies(), nil
+		}),
+		Benchmark: RateProviderFunc(func(ctx context.Context) ([]Currency, error) {
+			return nil, errors.New("benchmark down")
+		}),
+	}
+
+	_, err := guard.FetchRates(context.Background())
+	assert.ErrorContains(t, err, "benchmark down")
+
+	guard.IgnoreBenchmarkErrors = true
+	currencies, err := guard.FetchRates(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, currencies, 3)
+}
+
+func TestBenchmarkGuard_Deviations(t *testing.T) {
+	guard := &BenchmarkGuard{}
+	benchmark := []Currency{{ISOCode: "usd", BuyRate: decimal.Zero, SellRate: decimal.NewFromInt(1)}}
+	currencies := []Currency{
+		{ISOCode: "USD", BuyRate: decimal.NewFromInt(5), SellRate: decimal.NewFromInt(1)},
+		{ISOCode: "EUR", BuyRate: decimal.NewFromInt(5), SellRate: decimal.NewFromInt(5)},
+	}
+
+	// Zero benchmark rates and currencies missing from the benchmark are
+	// not checked.
+	assert.Empty(t, guard.Deviations(currencies, benchmark))
+}
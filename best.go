@@ -0,0 +1,51 @@
+package converter
+
+// Quote comparisons order two quotes for the same pair, returning a
+// negative number when a is better for the customer than b, zero when they
+// are as good and a positive number otherwise, so slices.SortFunc sorts
+// quotes best first.
+
+// ByFinalAmount prefers the quote delivering more.
+func ByFinalAmount(a, b *Quote) int {
+	return b.FinalAmount.Cmp(a.FinalAmount)
+}
+
+// ByEffectiveRate prefers the quote delivering more per unit paid, fees
+// included.
+func ByEffectiveRate(a, b *Quote) int {
+	return b.EffectiveRate.Cmp(a.EffectiveRate)
+}
+
+// ByTotalCost prefers the quote deducting less from the customer.
+func ByTotalCost(a, b *Quote) int {
+	return a.AmountToDeduct.Cmp(b.AmountToDeduct)
+}
+
+// BestQuote returns the best of competing quotes for the same pair: the one
+// with the highest effective rate, then delivering the most, then costing
+// the least. Among equal quotes the first wins. Nil quotes are skipped and
+// BestQuote returns nil if there are none.
+func BestQuote(quotes ...*Quote) *Quote {
+	return BestQuoteBy(func(a, b *Quote) int {
+		if c := ByEffectiveRate(a, b); c != 0 {
+			return c
+		}
+		if c := ByFinalAmount(a, b); c != 0 {
+			return c
+		}
+		return ByTotalCost(a, b)
+	}, quotes...)
+}
+
+// BestQuoteBy returns the best of quotes by cmp, e.g. ByTotalCost. Among
+// equal quotes the first wins. Nil quotes are skipped and BestQuoteBy
+// returns nil if there are none.
+func BestQuoteBy(cmp func(a, b *Quote) int, quotes ...*Quote) *Quote {
+	var best *Quote
+	for _, q := range quotes {
+		if q != nil && (best == nil || cmp(q, best) < 0) {
+			best = q
+		}
+	}
+	return best
+}
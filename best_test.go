@@ -0,0 +1,34 @@
+package converter
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBestQuote(t *testing.T) {
+	amount := decimal.NewFromInt(100)
+	additive, err := NewQuote(testCurrencies(), "USD", "USD", "NGN", amount, decimal.NewFromInt(2))
+	assert.NoError(t, err)
+	deducted, err := NewQuote(testCurrencies(), "USD", "USD", "NGN", amount, decimal.NewFromInt(1), WithFeeMode(FeeDeducted))
+	assert.NoError(t, err)
+	free, err := NewQuote(testCurrencies(), "USD", "USD", "NGN", amount, decimal.Zero)
+	assert.NoError(t, err)
+
+	assert.Same(t, free, BestQuote(additive, nil, deducted, free))
+	assert.Same(t, deducted, BestQuote(additive, deducted))
+	assert.Same(t, additive, BestQuoteBy(ByFinalAmount, additive, deducted))
+	assert.Same(t, deducted, BestQuoteBy(ByTotalCost, additive, deducted))
+	assert.Nil(t, BestQuote())
+	assert.Nil(t, BestQuote(nil))
+
+	same, err := NewQuote(testCurrencies(), "USD", "USD", "NGN", amount, decimal.Zero)
+	assert.NoError(t, err)
+	assert.Same(t, free, BestQuote(free, same), "the first of equal quotes wins")
+
+	quotes := []*Quote{additive, deducted, free}
+	slices.SortFunc(quotes, ByEffectiveRate)
+	assert.Equal(t, []*Quote{free, deducted, additive}, quotes)
+}
@@ -0,0 +1,53 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Blob store errors
+var (
+	ErrBlobNotFound = errors.New("blob not found")
+)
+
+// BlobStore stores opaque byte blobs by key. Implementations can wrap object
+// storage or a key-value database.
+type BlobStore interface {
+	// Put inserts or replaces the blob stored under key.
+	Put(ctx context.Context, key string, data []byte) error
+	// Get returns the blob stored under key or ErrBlobNotFound.
+	Get(ctx context.Context, key string) ([]byte, error)
+}
+
+// MemoryBlobStore is an in-memory BlobStore.
+type MemoryBlobStore struct {
+	mu    sync.RWMutex
+	blobs map[string][]byte
+}
+
+// NewMemoryBlobStore creates an empty in-memory blob store.
+func NewMemoryBlobStore() *MemoryBlobStore {
+	return &MemoryBlobStore{blobs: map[string][]byte{}}
+}
+
+// Put implements BlobStore.
+func (s *MemoryBlobStore) Put(_ context.Context, key string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.blobs[key] = append([]byte(nil), data...)
+	return nil
+}
+
+// Get implements BlobStore.
+func (s *MemoryBlobStore) Get(_ context.Context, key string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	data, ok := s.blobs[key]
+	if !ok {
+		return nil, ErrBlobNotFound
+	}
+	return append([]byte(nil), data...), nil
+}
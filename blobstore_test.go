@@ -0,0 +1,25 @@
+package converter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryBlobStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryBlobStore()
+
+	data := []byte("sealed")
+	assert.NoError(t, store.Put(ctx, "a", data))
+
+	// Stored blobs are copies
+	data[0] = 'S'
+	got, err := store.Get(ctx, "a")
+	assert.NoError(t, err)
+	assert.Equal(t, "sealed", string(got))
+
+	_, err = store.Get(ctx, "missing")
+	assert.ErrorIs(t, err, ErrBlobNotFound)
+}
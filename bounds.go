@@ -0,0 +1,150 @@
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Bounds errors
+var (
+	ErrInvalidAmount     = errors.New("invalid amount")
+	ErrRateOutOfBounds   = errors.New("rate out of bounds")
+	ErrAmountOutOfBounds = errors.New("amount out of bounds")
+	ErrDustAmount        = errors.New("amount below smallest unit")
+)
+
+// MaxPrecision is the largest number of decimal places a currency may use.
+const MaxPrecision = maxDigits
+
+// Bounds limits the magnitudes accepted when pricing, so hyperinflated rates
+// or dust amounts are rejected with a typed error instead of producing a
+// nonsensical quote. Zero-valued fields are not checked.
+type Bounds struct {
+	// MinRate and MaxRate bound currency rates and the rates of quotes.
+	MinRate decimal.Decimal
+	MaxRate decimal.Decimal
+	// MinAmount and MaxAmount bound the amount a quote converts.
+	MinAmount decimal.Decimal
+	MaxAmount decimal.Decimal
+	// MaxPrecision bounds currency precision below MaxPrecision.
+	MaxPrecision int
+	// RejectDust rejects quotes converting less than one minor unit of the
+	// source currency or yielding less than one minor unit of the target.
+	RejectDust bool
+}
+
+// BoundsError reports a value outside its bounds. It wraps one of
+// ErrRateOutOfBounds, ErrAmountOutOfBounds, ErrDustAmount or
+// ErrInvalidPrecision.
+type BoundsError struct {
+	// Field names the checked value, e.g. "NGN sell rate" or "amount".
+	Field string
+	Value decimal.Decimal
+	Limit decimal.Decimal
+	Err   error
+}
+
+// Error implements error.
+func (e *BoundsError) Error() string {
+	return fmt.Sprintf("%v: %s %s is beyond %s", e.Err, e.Field, e.Value, e.Limit)
+}
+
+// Unwrap returns the sentinel error.
+func (e *BoundsError) Unwrap() error {
+	return e.Err
+}
+
+// Check verifies every currency against the rate and precision bounds. It
+// has the signature of Refresher.Verify, so out-of-bounds feeds can be kept
+// out of a live table.
+func (b Bounds) Check(currencies []Currency) error {
+	var errs []error
+	for _, c := range currencies {
+		if err := b.CheckCurrency(c); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CheckCurrency verifies one currency against the rate and precision bounds.
+func (b Bounds) CheckCurrency(c Currency) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	if b.MaxPrecision > 0 && c.Precision > b.MaxPrecision {
+		return &BoundsError{
+			Field: c.ISOCode + " precision",
+			Value: decimal.NewFromInt(int64(c.Precision)),
+			Limit: decimal.NewFromInt(int64(b.MaxPrecision)),
+			Err:   ErrInvalidPrecision,
+		}
+	}
+	code := strings.ToUpper(c.ISOCode)
+	if err := b.checkRate(code+" buy rate", c.BuyRate); err != nil {
+		return err
+	}
+	return b.checkRate(code+" sell rate", c.SellRate)
+}
+
+// checkQuote verifies the values of a quote being priced.
+func (b Bounds) checkQuote(rate, fromAmount, converted decimal.Decimal, from, to *Currency) error {
+	if err := b.checkRate("rate", rate); err != nil {
+		return err
+	}
+	if b.MinAmount.IsPositive() && fromAmount.LessThan(b.MinAmount) {
+		return &BoundsError{Field: "amount", Value: fromAmount, Limit: b.MinAmount, Err: ErrAmountOutOfBounds}
+	}
+	if b.MaxAmount.IsPositive() && fromAmount.GreaterThan(b.MaxAmount) {
+		return &BoundsError{Field: "amount", Value: fromAmount, Limit: b.MaxAmount, Err: ErrAmountOutOfBounds}
+	}
+	if b.RejectDust {
+		if unit := minorUnit(from); fromAmount.LessThan(unit) {
+			return &BoundsError{Field: "amount", Value: fromAmount, Limit: unit, Err: ErrDustAmount}
+		}
+		if unit := minorUnit(to); converted.LessThan(unit) {
+			return &BoundsError{Field: "converted amount", Value: converted, Limit: unit, Err: ErrDustAmount}
+		}
+	}
+	return nil
+}
+
+func (b Bounds) checkRate(field string, rate decimal.Decimal) error {
+	// A zero rate is unset, not below the minimum.
+	if b.MinRate.IsPositive() && rate.IsPositive() && rate.LessThan(b.MinRate) {
+		return &BoundsError{Field: field, Value: rate, Limit: b.MinRate, Err: ErrRateOutOfBounds}
+	}
+	if b.MaxRate.IsPositive() && rate.GreaterThan(b.MaxRate) {
+		return &BoundsError{Field: field, Value: rate, Limit: b.MaxRate, Err: ErrRateOutOfBounds}
+	}
+	return nil
+}
+
+// minorUnit returns the smallest amount of a currency, e.g. 0.01 for USD.
+func minorUnit(c *Currency) decimal.Decimal {
+	return decimal.New(1, -int32(c.Precision))
+}
+
+// validateAmount checks that an amount is non-negative and small enough to
+// compute with. The amount is not formatted into the error, since printing an
+// oversized value is the work the check avoids.
+func validateAmount(field string, amount decimal.Decimal) error {
+	if !saneDecimal(amount) {
+		return fmt.Errorf("%w: %s out of range", ErrInvalidAmount, field)
+	}
+	if amount.IsNegative() {
+		return fmt.Errorf("%w: %s is negative", ErrInvalidAmount, field)
+	}
+	return nil
+}
+
+// WithBounds rejects quotes whose rate or amounts fall outside b, and quotes
+// between currencies outside its rate and precision bounds.
+func WithBounds(b Bounds) QuoteOption {
+	return func(o *quoteOptions) {
+		o.bounds = &b
+	}
+}
@@ -0,0 +1,81 @@
+package converter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBounds_Check(t *testing.T) {
+	bounds := Bounds{MaxRate: decimal.NewFromInt(1000), MaxPrecision: 8}
+
+	// NGN sells at 1550
+	err := bounds.Check(testCurrencies())
+	assert.ErrorIs(t, err, ErrRateOutOfBounds)
+	var be *BoundsError
+	assert.True(t, errors.As(err, &be))
+	assert.Equal(t, "NGN buy rate", be.Field)
+	assert.Equal(t, "1000", be.Limit.String())
+
+	bounds.MaxRate = decimal.NewFromInt(1e9)
+	assert.NoError(t, bounds.Check(testCurrencies()))
+
+	// Tiny rates are caught by the minimum
+	bounds.MinRate = decimal.NewFromInt(1)
+	assert.ErrorIs(t, bounds.Check(testCurrencies()), ErrRateOutOfBounds)
+
+	// Precision beyond the configured maximum
+	token := Currency{ISOCode: "ETH", Precision: 18, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)}
+	assert.ErrorIs(t, Bounds{MaxPrecision: 8}.CheckCurrency(token), ErrInvalidPrecision)
+	assert.NoError(t, Bounds{}.CheckCurrency(token))
+}
+
+func TestNewQuote_Bounds(t *testing.T) {
+	currencies := testCurrencies()
+	quote := func(from, to string, amount string, b Bounds) error {
+		_, err := NewQuote(currencies, "USD", from, to, decimal.RequireFromString(amount), decimal.Zero, WithBounds(b))
+		return err
+	}
+
+	assert.NoError(t, quote("USD", "NGN", "100", Bounds{}))
+
+	// Hyperinflated rates
+	assert.ErrorIs(t, quote("USD", "NGN", "100", Bounds{MaxRate: decimal.NewFromInt(1000)}), ErrRateOutOfBounds)
+
+	// Amount limits
+	limits := Bounds{MinAmount: decimal.NewFromInt(1), MaxAmount: decimal.NewFromInt(10000)}
+	assert.NoError(t, quote("USD", "NGN", "100", limits))
+	assert.ErrorIs(t, quote("USD", "NGN", "0.5", limits), ErrAmountOutOfBounds)
+	assert.ErrorIs(t, quote("USD", "NGN", "10000.01", limits), ErrAmountOutOfBounds)
+
+	// Dust: below a cent, or converting to less than a cent
+	dust := Bounds{RejectDust: true}
+	assert.ErrorIs(t, quote("USD", "NGN", "0.001", dust), ErrDustAmount)
+	assert.ErrorIs(t, quote("NGN", "USD", "1", dust), ErrDustAmount)
+	assert.NoError(t, quote("NGN", "USD", "100", dust))
+	assert.NoError(t, quote("USD", "NGN", "0.001", Bounds{}))
+
+	// Exact tables check the exact converted amount
+	table := NewTable(currencies, WithExactArithmetic())
+	_, err := table.NewQuote("USD", "NGN", "USD", decimal.NewFromInt(15), decimal.Zero, WithBounds(dust))
+	assert.NoError(t, err)
+	_, err = table.NewQuote("USD", "NGN", "USD", decimal.RequireFromString("14.99"), decimal.Zero, WithBounds(dust))
+	assert.ErrorIs(t, err, ErrDustAmount)
+}
+
+func TestNewQuote_InvalidAmount(t *testing.T) {
+	currencies := testCurrencies()
+
+	_, err := NewQuote(currencies, "USD", "USD", "NGN", decimal.NewFromInt(-1), decimal.Zero)
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+
+	_, err = NewQuote(currencies, "USD", "USD", "NGN", decimal.NewFromInt(1), decimal.NewFromInt(-1))
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+
+	// Oversized amounts are rejected without being formatted
+	_, err = NewQuote(currencies, "USD", "USD", "NGN", decimal.New(1, 2000000000), decimal.Zero)
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+	assert.ErrorContains(t, err, "out of range")
+}
@@ -0,0 +1,230 @@
+package converter
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Cache defaults
+const (
+	// DefaultCacheTTL is how long a CachedCurrencies keeps a lookup.
+	DefaultCacheTTL = 30 * time.Second
+	// DefaultCacheMaxEntries is how many lookups a CachedCurrencies keeps.
+	DefaultCacheMaxEntries = 10000
+)
+
+// ErrCacheLoadPanicked is returned to lookups that waited on a backend call
+// that panicked.
+var ErrCacheLoadPanicked = errors.New("cache backend call panicked")
+
+// RateSource looks up currencies and prices pairs. *Currencies implements
+// it, and so can a slower backend, such as a SQL table or a remote rate
+// service, to be put behind a CachedCurrencies.
+type RateSource interface {
+	FindCurrency(code string) (*Currency, error)
+	CalculateRate(baseCurrency, from, to string) (decimal.Decimal, error)
+	List() []Currency
+}
+
+var (
+	_ RateSource = (*Currencies)(nil)
+	_ RateSource = (*CachedCurrencies)(nil)
+)
+
+// CacheStats counts a CachedCurrencies' lookups.
+type CacheStats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+}
+
+// CachedCurrencies is a read-through cache of a RateSource, so the latency
+// of the hot path does not depend on a slow backend. Lookups are kept for
+// TTL; unknown currencies are cached as well, so repeated bad codes do not
+// reach the backend either. Concurrent misses of the same lookup share one
+// backend call.
+//
+// The cache holds at most MaxEntries lookups: once full, expired lookups
+// are dropped, then arbitrary ones, so callers sending ever new bad codes
+// cannot grow it without bound.
+type CachedCurrencies struct {
+	Backend RateSource
+	// TTL is DefaultCacheTTL if zero.
+	TTL time.Duration
+	// MaxEntries is DefaultCacheMaxEntries if zero.
+	MaxEntries int
+	Clock      Clock
+
+	mu       sync.Mutex
+	entries  map[string]cacheEntry
+	inflight map[string]*cacheCall
+	// generation counts invalidations, so loads started before one are
+	// neither cached nor shared with later misses.
+	generation uint64
+	stats      CacheStats
+}
+
+type cacheEntry struct {
+	value   any
+	err     error
+	expires time.Time
+}
+
+// cacheCall is a backend call shared by concurrent misses.
+type cacheCall struct {
+	done  chan struct{}
+	value any
+	err   error
+}
+
+// NewCachedCurrencies creates a cache of backend keeping lookups for ttl.
+func NewCachedCurrencies(backend RateSource, ttl time.Duration) *CachedCurrencies {
+	return &CachedCurrencies{Backend: backend, TTL: ttl}
+}
+
+// FindCurrency implements RateSource.
+func (c *CachedCurrencies) FindCurrency(code string) (*Currency, error) {
+	v, err := c.lookup("currency:"+strings.ToUpper(code), func() (any, error) {
+		currency, err := c.Backend.FindCurrency(code)
+		if err != nil {
+			return nil, err
+		}
+		return *currency, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	currency := v.(Currency)
+	return &currency, nil
+}
+
+// CalculateRate implements RateSource.
+func (c *CachedCurrencies) CalculateRate(baseCurrency, from, to string) (decimal.Decimal, error) {
+	// A currency converts to itself at 1 on any backend.
+	if strings.EqualFold(from, to) {
+		return decimalOne, nil
+	}
+	key := "rate:" + strings.ToUpper(baseCurrency+"/"+from+"/"+to)
+	v, err := c.lookup(key, func() (any, error) {
+		return c.Backend.CalculateRate(baseCurrency, from, to)
+	})
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return v.(decimal.Decimal), nil
+}
+
+// List implements RateSource.
+func (c *CachedCurrencies) List() []Currency {
+	v, _ := c.lookup("list", func() (any, error) {
+		return c.Backend.List(), nil
+	})
+	list, _ := v.([]Currency)
+	return slices.Clone(list)
+}
+
+// Invalidate drops every cached lookup, e.g. as the OnInvalidate of an
+// Invalidation once the backend's rates changed.
+func (c *CachedCurrencies) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = nil
+	c.inflight = nil
+	c.generation++
+}
+
+// Stats returns the cache's hit and miss counts.
+func (c *CachedCurrencies) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.stats
+}
+
+// lookup returns the cached result for key, or loads, caches and returns
+// it. Errors other than unknown currencies are not cached.
+func (c *CachedCurrencies) lookup(key string, load func() (any, error)) (any, error) {
+	clock := c.Clock
+	if clock == nil {
+		clock = SystemClock
+	}
+	ttl := c.TTL
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+
+	c.mu.Lock()
+	if e, ok := c.entries[key]; ok {
+		if clock.Now().Before(e.expires) {
+			c.stats.Hits++
+			c.mu.Unlock()
+			return e.value, e.err
+		}
+		delete(c.entries, key)
+	}
+	c.stats.Misses++
+	if call, ok := c.inflight[key]; ok {
+		c.mu.Unlock()
+		<-call.done
+		return call.value, call.err
+	}
+	call := &cacheCall{done: make(chan struct{})}
+	if c.inflight == nil {
+		c.inflight = map[string]*cacheCall{}
+	}
+	c.inflight[key] = call
+	generation := c.generation
+	c.mu.Unlock()
+
+	// Waiters are released even if load panics, with ErrCacheLoadPanicked.
+	loaded := false
+	defer func() {
+		if !loaded {
+			call.value, call.err = nil, ErrCacheLoadPanicked
+		}
+		c.mu.Lock()
+		if c.inflight[key] == call {
+			delete(c.inflight, key)
+		}
+		cacheable := call.err == nil || errors.Is(call.err, ErrUnknownCurrency)
+		if loaded && cacheable && generation == c.generation {
+			c.store(key, cacheEntry{value: call.value, err: call.err, expires: clock.Now().Add(ttl)}, clock.Now())
+		}
+		c.mu.Unlock()
+		close(call.done)
+	}()
+
+	call.value, call.err = load()
+	loaded = true
+	return call.value, call.err
+}
+
+// store caches e under key, first making room if the cache is full. The
+// caller holds c.mu.
+func (c *CachedCurrencies) store(key string, e cacheEntry, now time.Time) {
+	limit := c.MaxEntries
+	if limit <= 0 {
+		limit = DefaultCacheMaxEntries
+	}
+	if c.entries == nil {
+		c.entries = map[string]cacheEntry{}
+	}
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= limit {
+		maps.DeleteFunc(c.entries, func(_ string, old cacheEntry) bool {
+			return !now.Before(old.expires)
+		})
+		for old := range c.entries {
+			if len(c.entries) < limit {
+				break
+			}
+			delete(c.entries, old)
+		}
+	}
+	c.entries[key] = e
+}
@@ -0,0 +1,196 @@
+package converter
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingSource counts the lookups reaching a table, blocking them while
+// gate is set.
+type countingSource struct {
+	*Currencies
+	calls atomic.Int32
+	gate  chan struct{}
+}
+
+func (s *countingSource) wait() {
+	s.calls.Add(1)
+	if s.gate != nil {
+		<-s.gate
+	}
+}
+
+func (s *countingSource) FindCurrency(code string) (*Currency, error) {
+	s.wait()
+	return s.Currencies.FindCurrency(code)
+}
+
+func (s *countingSource) CalculateRate(base, from, to string) (decimal.Decimal, error) {
+	s.wait()
+	return s.Currencies.CalculateRate(base, from, to)
+}
+
+func (s *countingSource) List() []Currency {
+	s.wait()
+	return s.Currencies.List()
+}
+
+func TestCachedCurrencies(t *testing.T) {
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	backend := &countingSource{Currencies: NewTable(testCurrencies())}
+	c := NewCachedCurrencies(backend, time.Minute)
+	c.Clock = fixedClock(start)
+
+	rate, err := c.CalculateRate("USD", "USD", "ngn")
+	require.NoError(t, err)
+	assert.Equal(t, "1550", rate.String())
+	rate, err = c.CalculateRate("usd", "USD", "NGN")
+	require.NoError(t, err)
+	assert.Equal(t, "1550", rate.String())
+	assert.EqualValues(t, 1, backend.calls.Load())
+
+	// Cached values are copies
+	eur, err := c.FindCurrency("EUR")
+	require.NoError(t, err)
+	eur.SellRate = decimal.NewFromInt(100)
+	eur, err = c.FindCurrency("eur")
+	require.NoError(t, err)
+	assert.Equal(t, "0.95", eur.SellRate.String())
+	list := c.List()
+	list[0].ISOCode = "XXX"
+	assert.Equal(t, "EUR", c.List()[0].ISOCode)
+	assert.EqualValues(t, 3, backend.calls.Load())
+
+	// Unknown currencies are cached too
+	_, err = c.FindCurrency("GBP")
+	assert.ErrorIs(t, err, ErrUnknownCurrency)
+	_, err = c.CalculateRate("USD", "USD", "GBP")
+	assert.ErrorIs(t, err, ErrUnknownCurrency)
+	_, err = c.FindCurrency("GBP")
+	assert.ErrorIs(t, err, ErrUnknownCurrency)
+	assert.EqualValues(t, 5, backend.calls.Load())
+	assert.Equal(t, CacheStats{Hits: 4, Misses: 5}, c.Stats())
+
+	// Lookups expire after the TTL, or when invalidated
+	updated := testCurrencies()
+	updated[2].SellRate = decimal.NewFromInt(1600)
+	backend.Update(updated, start)
+	c.Clock = fixedClock(start.Add(time.Minute))
+	rate, err = c.CalculateRate("USD", "USD", "NGN")
+	require.NoError(t, err)
+	assert.Equal(t, "1600", rate.String())
+
+	updated[2].SellRate = decimal.NewFromInt(1700)
+	backend.Update(updated, start)
+	c.Invalidate()
+	rate, err = c.CalculateRate("USD", "USD", "NGN")
+	require.NoError(t, err)
+	assert.Equal(t, "1700", rate.String())
+}
+
+func TestCachedCurrencies_Concurrent(t *testing.T) {
+	backend := &countingSource{Currencies: NewTable(testCurrencies()), gate: make(chan struct{})}
+	c := NewCachedCurrencies(backend, time.Minute)
+
+	// Concurrent misses share one backend call
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rate, err := c.CalculateRate("USD", "EUR", "NGN")
+			assert.NoError(t, err)
+			assert.False(t, rate.IsZero())
+		}()
+	}
+	require.Eventually(t, func() bool { return c.Stats().Misses == 10 }, time.Second, time.Millisecond)
+	close(backend.gate)
+	wg.Wait()
+	assert.EqualValues(t, 1, backend.calls.Load())
+}
+
+func TestCachedCurrencies_SameCurrency(t *testing.T) {
+	backend := &countingSource{Currencies: NewTable(testCurrencies())}
+	c := NewCachedCurrencies(backend, time.Minute)
+
+	rate, err := c.CalculateRate("USD", "ngn", "NGN")
+	require.NoError(t, err)
+	assert.Equal(t, "1", rate.String())
+	assert.Zero(t, backend.calls.Load())
+	assert.Equal(t, CacheStats{}, c.Stats())
+}
+
+func TestCachedCurrencies_Bounded(t *testing.T) {
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	backend := &countingSource{Currencies: NewTable(testCurrencies())}
+	c := NewCachedCurrencies(backend, time.Minute)
+	c.MaxEntries = 10
+	c.Clock = fixedClock(start)
+
+	// Ever new bad codes never grow the cache past its limit.
+	for i := 0; i < 100; i++ {
+		_, err := c.FindCurrency(fmt.Sprintf("X%02d", i))
+		assert.ErrorIs(t, err, ErrUnknownCurrency)
+	}
+	assert.Len(t, c.entries, 10)
+
+	// An expired lookup is dropped when read and loaded afresh, and a
+	// full cache drops its expired lookups before any live one.
+	c.Clock = fixedClock(start.Add(2 * time.Minute))
+	calls := backend.calls.Load()
+	_, err := c.FindCurrency("X99")
+	assert.ErrorIs(t, err, ErrUnknownCurrency)
+	assert.Equal(t, calls+1, backend.calls.Load())
+	for _, code := range []string{"USD", "EUR"} {
+		_, err := c.FindCurrency(code)
+		assert.NoError(t, err)
+	}
+	assert.Len(t, c.entries, 3)
+	assert.Contains(t, c.entries, "currency:X99")
+}
+
+// panickingSource panics on its first rate lookup, once released.
+type panickingSource struct {
+	*Currencies
+	release chan struct{}
+}
+
+func (s *panickingSource) CalculateRate(string, string, string) (decimal.Decimal, error) {
+	<-s.release
+	panic("backend failure")
+}
+
+func TestCachedCurrencies_LoadPanics(t *testing.T) {
+	backend := &panickingSource{Currencies: NewTable(testCurrencies()), release: make(chan struct{})}
+	c := NewCachedCurrencies(backend, time.Minute)
+
+	panicked := make(chan any)
+	go func() {
+		defer func() { panicked <- recover() }()
+		_, _ = c.CalculateRate("USD", "USD", "NGN")
+	}()
+	// Wait for the first call to be in flight, then join it.
+	require.Eventually(t, func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return len(c.inflight) == 1
+	}, time.Second, time.Millisecond)
+	waited := make(chan error)
+	go func() {
+		_, err := c.CalculateRate("USD", "USD", "NGN")
+		waited <- err
+	}()
+	require.Eventually(t, func() bool { return c.Stats().Misses == 2 }, time.Second, time.Millisecond)
+
+	close(backend.release)
+	assert.Equal(t, "backend failure", <-panicked, "the caller that loaded sees the panic")
+	assert.ErrorIs(t, <-waited, ErrCacheLoadPanicked, "callers waiting on it are released")
+	assert.Empty(t, c.inflight)
+}
@@ -0,0 +1,263 @@
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Calendar errors
+var (
+	ErrNoTradingDay = errors.New("no trading day")
+)
+
+// maxCalendarSearch bounds the days searched for a trading day, so a
+// calendar closing every day fails instead of looping.
+const maxCalendarSearch = 366
+
+// DateAdjustment is a business-day convention for moving a date that falls on
+// a non-trading day.
+type DateAdjustment int
+
+// Date adjustments
+const (
+	// AdjustFollowing moves to the next trading day.
+	AdjustFollowing DateAdjustment = iota
+	// AdjustModifiedFollowing moves to the next trading day unless that is
+	// in the next month, in which case it moves to the previous one.
+	AdjustModifiedFollowing
+	// AdjustPreceding moves to the previous trading day.
+	AdjustPreceding
+)
+
+// String returns the name of the adjustment.
+func (a DateAdjustment) String() string {
+	switch a {
+	case AdjustFollowing:
+		return "following"
+	case AdjustModifiedFollowing:
+		return "modified-following"
+	case AdjustPreceding:
+		return "preceding"
+	default:
+		return "unknown"
+	}
+}
+
+// defaultWeekend is the weekend of currencies without their own.
+var defaultWeekend = []time.Weekday{time.Saturday, time.Sunday}
+
+// civilDate is a calendar day, independent of time zone.
+type civilDate struct {
+	year  int
+	month time.Month
+	day   int
+}
+
+func dateOf(t time.Time) civilDate {
+	y, m, d := t.Date()
+	return civilDate{y, m, d}
+}
+
+// Calendar knows the trading days of currencies: every day except the
+// currency's weekend and holidays. Dates are taken in their own location.
+// Queries on a nil Calendar see Saturday and Sunday weekends and no holidays.
+// A Calendar is safe for concurrent use.
+type Calendar struct {
+	mu       sync.RWMutex
+	weekends map[string][]time.Weekday
+	holidays map[string]map[civilDate]string
+	cutOffs  map[string]CutOff
+	// settlements holds settlement days keyed by pairKey.
+	settlements map[string]int
+}
+
+// NewCalendar creates a calendar with Saturday and Sunday weekends and no
+// holidays.
+func NewCalendar() *Calendar {
+	return &Calendar{
+		weekends: map[string][]time.Weekday{},
+		holidays: map[string]map[civilDate]string{},
+	}
+}
+
+// SetWeekend sets the non-trading weekdays of a currency, e.g. Friday and
+// Saturday. A currency must trade on at least one weekday.
+func (c *Calendar) SetWeekend(code string, days ...time.Weekday) error {
+	closed := map[time.Weekday]bool{}
+	for _, d := range days {
+		closed[d] = true
+	}
+	if len(closed) >= 7 {
+		return fmt.Errorf("%w: %s would never trade", ErrNoTradingDay, code)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.weekends[strings.ToUpper(code)] = slices.Clone(days)
+	return nil
+}
+
+// AddHoliday marks date as a named holiday of a currency.
+func (c *Calendar) AddHoliday(code string, date time.Time, name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	code = strings.ToUpper(code)
+	if c.holidays[code] == nil {
+		c.holidays[code] = map[civilDate]string{}
+	}
+	c.holidays[code][dateOf(date)] = name
+}
+
+// Holiday returns the name of a currency's holiday on date, if any.
+func (c *Calendar) Holiday(code string, date time.Time) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	name, ok := c.holidays[strings.ToUpper(code)][dateOf(date)]
+	return name, ok
+}
+
+// IsTradingDay reports whether a currency trades on date.
+func (c *Calendar) IsTradingDay(code string, date time.Time) bool {
+	weekend := defaultWeekend
+	if c != nil {
+		c.mu.RLock()
+		if days, ok := c.weekends[strings.ToUpper(code)]; ok {
+			weekend = days
+		}
+		c.mu.RUnlock()
+	}
+	if slices.Contains(weekend, date.Weekday()) {
+		return false
+	}
+	_, holiday := c.Holiday(code, date)
+	return !holiday
+}
+
+// tradingForAll reports whether every currency trades on date.
+func (c *Calendar) tradingForAll(date time.Time, codes []string) bool {
+	for _, code := range codes {
+		if !c.IsTradingDay(code, date) {
+			return false
+		}
+	}
+	return true
+}
+
+// step moves from date by dir days until every currency trades.
+func (c *Calendar) step(date time.Time, dir int, codes []string) (time.Time, error) {
+	for i := 0; i < maxCalendarSearch; i++ {
+		date = date.AddDate(0, 0, dir)
+		if c.tradingForAll(date, codes) {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: %s within %d days", ErrNoTradingDay, strings.Join(codes, "/"), maxCalendarSearch)
+}
+
+// Adjust moves date to a day every currency in codes trades on, using the
+// adjustment convention. A trading day is returned unchanged.
+func (c *Calendar) Adjust(date time.Time, adjustment DateAdjustment, codes ...string) (time.Time, error) {
+	if c.tradingForAll(date, codes) {
+		return date, nil
+	}
+
+	switch adjustment {
+	case AdjustFollowing:
+		return c.step(date, 1, codes)
+	case AdjustModifiedFollowing:
+		next, err := c.step(date, 1, codes)
+		if err != nil || next.Month() == date.Month() {
+			return next, err
+		}
+		return c.step(date, -1, codes)
+	case AdjustPreceding:
+		return c.step(date, -1, codes)
+	default:
+		return time.Time{}, fmt.Errorf("unknown date adjustment %d", adjustment)
+	}
+}
+
+// AddTradingDays returns the date n days on from date counting only days
+// every currency in codes trades on; a negative n counts back. With n zero,
+// date is returned unchanged.
+func (c *Calendar) AddTradingDays(date time.Time, n int, codes ...string) (time.Time, error) {
+	dir := 1
+	if n < 0 {
+		dir, n = -1, -n
+	}
+	for ; n > 0; n-- {
+		var err error
+		if date, err = c.step(date, dir, codes); err != nil {
+			return time.Time{}, err
+		}
+	}
+	return date, nil
+}
+
+// CutOff is the time of day after which a currency's trades book on the
+// next trading day.
+type CutOff struct {
+	Hour   int
+	Minute int
+	// Location is the time zone of the cut-off. It defaults to UTC.
+	Location *time.Location
+}
+
+// SetCutOff sets the daily cut-off of a currency.
+func (c *Calendar) SetCutOff(code string, cutOff CutOff) {
+	if cutOff.Location == nil {
+		cutOff.Location = time.UTC
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cutOffs == nil {
+		c.cutOffs = map[string]CutOff{}
+	}
+	c.cutOffs[strings.ToUpper(code)] = cutOff
+}
+
+// pastCutOff reports whether at is at or after the cut-off of a currency on
+// at's day in the cut-off's time zone.
+func (c *Calendar) pastCutOff(code string, at time.Time) bool {
+	if c == nil {
+		return false
+	}
+	c.mu.RLock()
+	cutOff, ok := c.cutOffs[strings.ToUpper(code)]
+	c.mu.RUnlock()
+	if !ok {
+		return false
+	}
+
+	local := at.In(cutOff.Location)
+	y, m, d := local.Date()
+	return !local.Before(time.Date(y, m, d, cutOff.Hour, cutOff.Minute, 0, 0, cutOff.Location))
+}
+
+// TradeDate returns the day a trade made at the given time books on for the
+// currencies in codes, at midnight in at's location: at's own date if every
+// currency trades that day and none has passed its cut-off, otherwise the
+// next day every currency trades.
+func (c *Calendar) TradeDate(at time.Time, codes ...string) (time.Time, error) {
+	y, m, d := at.Date()
+	date := time.Date(y, m, d, 0, 0, 0, 0, at.Location())
+
+	for _, code := range codes {
+		if c.pastCutOff(code, at) {
+			return c.step(date, 1, codes)
+		}
+	}
+	return c.Adjust(date, AdjustFollowing, codes...)
+}
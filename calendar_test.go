@@ -0,0 +1,131 @@
+package converter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func day(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestCalendar_IsTradingDay(t *testing.T) {
+	cal := NewCalendar()
+	cal.AddHoliday("ngn", day(2024, 10, 1), "Independence Day")
+	assert.NoError(t, cal.SetWeekend("SAR", time.Friday, time.Saturday))
+
+	assert.True(t, cal.IsTradingDay("USD", day(2024, 10, 1)))
+	assert.False(t, cal.IsTradingDay("NGN", day(2024, 10, 1)))
+	assert.False(t, cal.IsTradingDay("USD", day(2024, 10, 5)))
+	assert.False(t, cal.IsTradingDay("SAR", day(2024, 10, 4)))
+	assert.True(t, cal.IsTradingDay("SAR", day(2024, 10, 6)))
+
+	name, ok := cal.Holiday("NGN", time.Date(2024, 10, 1, 15, 0, 0, 0, time.UTC))
+	assert.True(t, ok)
+	assert.Equal(t, "Independence Day", name)
+
+	// A nil calendar only has weekends.
+	var none *Calendar
+	assert.True(t, none.IsTradingDay("NGN", day(2024, 10, 1)))
+	assert.False(t, none.IsTradingDay("NGN", day(2024, 10, 6)))
+
+	assert.ErrorIs(t, cal.SetWeekend("XXX", time.Sunday, time.Monday, time.Tuesday, time.Wednesday,
+		time.Thursday, time.Friday, time.Saturday), ErrNoTradingDay)
+}
+
+func TestCalendar_Adjust(t *testing.T) {
+	cal := NewCalendar()
+	cal.AddHoliday("NGN", day(2024, 4, 1), "Easter Monday")
+
+	// Saturday 30 March 2024
+	saturday := day(2024, 3, 30)
+
+	date, err := cal.Adjust(saturday, AdjustFollowing, "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, day(2024, 4, 1), date)
+
+	date, err = cal.Adjust(saturday, AdjustFollowing, "USD", "NGN")
+	assert.NoError(t, err)
+	assert.Equal(t, day(2024, 4, 2), date)
+
+	date, err = cal.Adjust(saturday, AdjustModifiedFollowing, "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, day(2024, 3, 29), date)
+
+	date, err = cal.Adjust(saturday, AdjustPreceding, "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, day(2024, 3, 29), date)
+
+	date, err = cal.Adjust(day(2024, 3, 28), AdjustFollowing, "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, day(2024, 3, 28), date)
+
+	_, err = cal.Adjust(saturday, DateAdjustment(9), "USD")
+	assert.Error(t, err)
+}
+
+func TestCalendar_AddTradingDays(t *testing.T) {
+	cal := NewCalendar()
+	cal.AddHoliday("NGN", day(2024, 4, 1), "Easter Monday")
+
+	// Thursday + 2 skips the weekend and the NGN holiday.
+	date, err := cal.AddTradingDays(day(2024, 3, 28), 2, "USD", "NGN")
+	assert.NoError(t, err)
+	assert.Equal(t, day(2024, 4, 2), date)
+
+	date, err = cal.AddTradingDays(day(2024, 4, 2), -2, "USD", "NGN")
+	assert.NoError(t, err)
+	assert.Equal(t, day(2024, 3, 28), date)
+
+	date, err = cal.AddTradingDays(day(2024, 3, 30), 0, "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, day(2024, 3, 30), date)
+
+	// A calendar closed on every day of the search fails.
+	closed := NewCalendar()
+	for d := day(2024, 1, 1); d.Year() < 2026; d = d.AddDate(0, 0, 1) {
+		closed.AddHoliday("XXX", d, "closed")
+	}
+	_, err = closed.AddTradingDays(day(2024, 1, 1), 1, "XXX")
+	assert.ErrorIs(t, err, ErrNoTradingDay)
+}
+
+func TestCalendar_TradeDate(t *testing.T) {
+	lagos, err := time.LoadLocation("Africa/Lagos")
+	if err != nil {
+		t.Skip(err)
+	}
+	cal := NewCalendar()
+	cal.SetCutOff("NGN", CutOff{Hour: 16, Location: lagos})
+
+	// Thursday 14:59 UTC is 15:59 in Lagos, before the cut-off.
+	date, err := cal.TradeDate(time.Date(2024, 3, 28, 14, 59, 0, 0, time.UTC), "USD", "NGN")
+	assert.NoError(t, err)
+	assert.Equal(t, day(2024, 3, 28), date)
+
+	// At the cut-off the trade books on Friday, or past Friday's on Monday.
+	date, err = cal.TradeDate(time.Date(2024, 3, 28, 15, 0, 0, 0, time.UTC), "USD", "NGN")
+	assert.NoError(t, err)
+	assert.Equal(t, day(2024, 3, 29), date)
+	date, err = cal.TradeDate(time.Date(2024, 3, 29, 15, 0, 0, 0, time.UTC), "USD", "NGN")
+	assert.NoError(t, err)
+	assert.Equal(t, day(2024, 4, 1), date)
+
+	// Currencies without a cut-off book the same trading day.
+	date, err = cal.TradeDate(time.Date(2024, 3, 28, 23, 0, 0, 0, time.UTC), "USD", "EUR")
+	assert.NoError(t, err)
+	assert.Equal(t, day(2024, 3, 28), date)
+
+	// Weekend trades book on the next trading day, in at's location.
+	at := time.Date(2024, 3, 30, 10, 0, 0, 0, lagos)
+	date, err = cal.TradeDate(at, "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 4, 1, 0, 0, 0, 0, lagos), date)
+
+	// A nil calendar only skips weekends.
+	date, err = (*Calendar)(nil).TradeDate(time.Date(2024, 3, 28, 23, 0, 0, 0, time.UTC), "NGN")
+	assert.NoError(t, err)
+	assert.Equal(t, day(2024, 3, 28), date)
+}
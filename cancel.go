@@ -0,0 +1,70 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Cancellation errors
+var (
+	ErrInvalidCancelReason = errors.New("invalid cancel reason")
+)
+
+// CancelReason categorizes why a quote was cancelled, for reporting on
+// abandoned quotes.
+type CancelReason string
+
+// Cancel reasons
+const (
+	CancelExpired           CancelReason = "expired"
+	CancelCustomerCancelled CancelReason = "customer_cancelled"
+	CancelComplianceHold    CancelReason = "compliance_hold"
+)
+
+// valid reports whether r is a known cancel reason.
+func (r CancelReason) valid() bool {
+	switch r {
+	case CancelExpired, CancelCustomerCancelled, CancelComplianceHold:
+		return true
+	}
+	return false
+}
+
+// Cancel cancels a pending or accepted quote for a reason, recorded on the
+// stored quote, and releases its guarantee. It returns
+// ErrQuoteStatusChanged if the quote is neither pending nor accepted and
+// emits an EventQuoteCancelled carrying the reason.
+func (g *Guarantees) Cancel(ctx context.Context, id string, reason CancelReason) error {
+	if !reason.valid() {
+		return fmt.Errorf("%w: %q", ErrInvalidCancelReason, reason)
+	}
+	now := g.clock().Now()
+
+	// Amend saves whole quotes too; holding the lock across the save keeps
+	// an amendment from restoring a quote cancelled under it.
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	err := g.Store.UpdateStatus(ctx, id, QuoteStatusPending, QuoteStatusCancelled)
+	if errors.Is(err, ErrQuoteStatusChanged) {
+		err = g.Store.UpdateStatus(ctx, id, QuoteStatusAccepted, QuoteStatusCancelled)
+	}
+	if err != nil {
+		return err
+	}
+	delete(g.guarantees, id)
+
+	// The status moved atomically; the reason follows it.
+	q, err := g.Store.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	q.CancelReason = reason
+	if err := g.Store.Save(ctx, q); err != nil {
+		return err
+	}
+
+	g.emit(Event{Type: EventQuoteCancelled, Time: now, QuoteID: id, Pair: q.FromCurrency + "/" + q.ToCurrency, Reason: string(reason), Actor: ActorFromContext(ctx)})
+	return nil
+}
@@ -0,0 +1,41 @@
+package converter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGuarantees_Cancel(t *testing.T) {
+	ctx := context.Background()
+	g, accepted := acceptedQuote(t, decimal.Zero)
+	var events []Event
+	g.OnEvent = func(e Event) { events = append(events, e) }
+
+	pending, err := NewQuote(testCurrencies(), "USD", "EUR", "NGN", decimal.NewFromInt(10), decimal.Zero)
+	assert.NoError(t, err)
+	assert.NoError(t, g.Store.Save(ctx, pending))
+
+	assert.NoError(t, g.Cancel(ctx, pending.ID, CancelCustomerCancelled))
+	assert.NoError(t, g.Cancel(ctx, accepted.ID, CancelComplianceHold))
+
+	stored, err := g.Store.Get(ctx, accepted.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, QuoteStatusCancelled, stored.Status)
+	assert.Equal(t, CancelComplianceHold, stored.CancelReason)
+	_, err = g.Get(accepted.ID)
+	assert.ErrorIs(t, err, ErrNoGuarantee)
+
+	assert.ErrorIs(t, g.Cancel(ctx, accepted.ID, CancelExpired), ErrQuoteStatusChanged)
+	assert.ErrorIs(t, g.Cancel(ctx, pending.ID, "bored"), ErrInvalidCancelReason)
+	assert.ErrorIs(t, g.Cancel(ctx, "missing", CancelExpired), ErrQuoteNotFound)
+
+	now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, []Event{
+		{Type: EventQuoteCancelled, Time: now, QuoteID: pending.ID, Pair: "EUR/NGN", Reason: "customer_cancelled"},
+		{Type: EventQuoteCancelled, Time: now, QuoteID: accepted.ID, Pair: "USD/NGN", Reason: "compliance_hold"},
+	}, events)
+}
@@ -0,0 +1,109 @@
+package converter
+
+import (
+	"fmt"
+	"maps"
+	"math/big"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Decimals of the native units of Ether (wei) and Bitcoin (satoshis).
+const (
+	WeiDecimals  = 18
+	SatsDecimals = 8
+)
+
+// AssetDecimals maps assets to the decimals of their on-chain integer unit,
+// e.g. 18 for ETH, whose native unit is the wei, or 6 for USDC. Keys are
+// matched case-insensitively.
+type AssetDecimals map[string]int
+
+// defaultAssetDecimals are the native units of well-known assets.
+var defaultAssetDecimals = AssetDecimals{
+	"BTC":  SatsDecimals,
+	"ETH":  WeiDecimals,
+	"USDC": 6,
+	"USDT": 6,
+	"DAI":  18,
+	"SOL":  9,
+	"TRX":  6,
+	"LTC":  8,
+}
+
+// DefaultAssetDecimals returns a copy of the native unit decimals of
+// well-known assets, to extend with other tokens.
+func DefaultAssetDecimals() AssetDecimals {
+	return maps.Clone(defaultAssetDecimals)
+}
+
+// Decimals returns the decimals of an asset's native unit.
+func (d AssetDecimals) Decimals(asset string) (int, bool) {
+	asset = strings.TrimSpace(asset)
+	for code, decimals := range d {
+		if strings.EqualFold(code, asset) {
+			return decimals, true
+		}
+	}
+	return 0, false
+}
+
+// ToNative converts an asset amount to a whole number of its native units,
+// e.g. 1.5 ETH to 1500000000000000000 wei. It returns an ErrUnknownCurrency
+// error for assets without configured decimals and an ErrFractionalAmount
+// error for amounts finer than the native unit.
+func (d AssetDecimals) ToNative(amount decimal.Decimal, asset string) (*big.Int, error) {
+	decimals, ok := d.Decimals(asset)
+	if !ok {
+		return nil, fmt.Errorf("%w: no decimals for %s", ErrUnknownCurrency, asset)
+	}
+	return toNative(amount, decimals, asset)
+}
+
+// FromNative converts a whole number of an asset's native units to an asset
+// amount, e.g. 150000 sats to 0.0015 BTC. It returns an ErrUnknownCurrency
+// error for assets without configured decimals.
+func (d AssetDecimals) FromNative(units *big.Int, asset string) (decimal.Decimal, error) {
+	decimals, ok := d.Decimals(asset)
+	if !ok {
+		return decimal.Zero, fmt.Errorf("%w: no decimals for %s", ErrUnknownCurrency, asset)
+	}
+	return decimal.NewFromBigInt(units, -int32(decimals)), nil
+}
+
+func toNative(amount decimal.Decimal, decimals int, asset string) (*big.Int, error) {
+	c := Currency{ISOCode: strings.ToUpper(asset), Precision: decimals}
+	if err := c.CheckAmount(amount); err != nil {
+		return nil, err
+	}
+	return amount.Shift(int32(decimals)).BigInt(), nil
+}
+
+// ToWei converts an ETH amount to wei.
+func ToWei(eth decimal.Decimal) (*big.Int, error) {
+	return toNative(eth, WeiDecimals, "ETH")
+}
+
+// FromWei converts wei to an ETH amount.
+func FromWei(wei *big.Int) decimal.Decimal {
+	return decimal.NewFromBigInt(wei, -WeiDecimals)
+}
+
+// ToSats converts a BTC amount to satoshis. Every BTC amount that can exist
+// fits an int64.
+func ToSats(btc decimal.Decimal) (int64, error) {
+	sats, err := toNative(btc, SatsDecimals, "BTC")
+	if err != nil {
+		return 0, err
+	}
+	if !sats.IsInt64() {
+		return 0, fmt.Errorf("%w: %s BTC does not fit in int64 satoshis", ErrInvalidAmount, btc)
+	}
+	return sats.Int64(), nil
+}
+
+// FromSats converts satoshis to a BTC amount.
+func FromSats(sats int64) decimal.Decimal {
+	return decimal.New(sats, -SatsDecimals)
+}
@@ -0,0 +1,62 @@
+package converter
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssetDecimals_Native(t *testing.T) {
+	d := DefaultAssetDecimals()
+	d["PEPE"] = 18
+
+	units, err := d.ToNative(decimal.RequireFromString("12.345678"), "usdc")
+	assert.NoError(t, err)
+	assert.Equal(t, "12345678", units.String())
+
+	amount, err := d.FromNative(big.NewInt(12345678), "USDC")
+	assert.NoError(t, err)
+	assert.Equal(t, "12.345678", amount.String())
+
+	units, err = d.ToNative(decimal.NewFromInt(2), "PEPE")
+	assert.NoError(t, err)
+	assert.Equal(t, "2000000000000000000", units.String())
+
+	_, err = d.ToNative(decimal.RequireFromString("1.0000001"), "USDT")
+	assert.ErrorIs(t, err, ErrFractionalAmount)
+
+	_, err = d.ToNative(decimal.NewFromInt(1), "DOGE")
+	assert.ErrorIs(t, err, ErrUnknownCurrency)
+	_, err = d.FromNative(big.NewInt(1), "DOGE")
+	assert.ErrorIs(t, err, ErrUnknownCurrency)
+
+	_, ok := DefaultAssetDecimals().Decimals("PEPE")
+	assert.False(t, ok, "defaults are copied")
+}
+
+func TestWei(t *testing.T) {
+	wei, err := ToWei(decimal.RequireFromString("1.5"))
+	assert.NoError(t, err)
+	assert.Equal(t, "1500000000000000000", wei.String())
+
+	wei, _ = new(big.Int).SetString("123456789012345678901234567890", 10)
+	assert.Equal(t, "123456789012.34567890123456789", FromWei(wei).String())
+
+	_, err = ToWei(decimal.RequireFromString("0.0000000000000000001"))
+	assert.ErrorIs(t, err, ErrFractionalAmount)
+}
+
+func TestSats(t *testing.T) {
+	sats, err := ToSats(decimal.RequireFromString("0.0015"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(150000), sats)
+	assert.Equal(t, "0.0015", FromSats(150000).String())
+
+	_, err = ToSats(decimal.RequireFromString("0.000000001"))
+	assert.ErrorIs(t, err, ErrFractionalAmount)
+
+	_, err = ToSats(decimal.RequireFromString("1e12"))
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+}
@@ -0,0 +1,22 @@
+package converter
+
+import "time"
+
+// Clock tells the current time. Components take a Clock so tests can control
+// time-dependent behaviour such as quote expiry.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the Clock backed by time.Now.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// instantClock is a Clock stopped at one instant, so every part of an
+// operation sees the same time.
+type instantClock time.Time
+
+func (c instantClock) Now() time.Time { return time.Time(c) }
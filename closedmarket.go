@@ -0,0 +1,97 @@
+package converter
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// ClosedMarketPolicy decides how a table prices currencies whose market is
+// closed (see Calendar), instead of silently quoting whatever rates arrived
+// last. It applies to every currency of a quote except the base.
+type ClosedMarketPolicy struct {
+	// Calendar tells when each currency trades. A nil Calendar closes
+	// markets on Saturdays and Sundays.
+	Calendar *Calendar
+	// Freeze pins a closed currency to its rates as of the table's last
+	// update on one of its trading days, e.g. Friday's close. Currencies
+	// never updated on a trading day keep their current rates.
+	Freeze bool
+	// Spread widens a closed currency's rates by this fraction, e.g. 0.005
+	// for 0.5%, in the table's favour: buy rates are raised and sell rates
+	// lowered.
+	Spread decimal.Decimal
+	// Clock tells when quotes are priced. It defaults to SystemClock.
+	Clock Clock
+}
+
+// WithClosedMarketPolicy makes the table price currencies whose market is
+// closed by the policy.
+func WithClosedMarketPolicy(p ClosedMarketPolicy) TableOption {
+	return func(c *Currencies) {
+		if p.Clock == nil {
+			p.Clock = SystemClock
+		}
+		c.closedMarket = &p
+	}
+}
+
+// recordClose remembers the rates of the currencies trading when the table
+// is updated at the time of the update. The caller holds the write lock.
+func (c *Currencies) recordClose() {
+	p := c.closedMarket
+	if p == nil || !p.Freeze {
+		return
+	}
+	if c.closes == nil {
+		c.closes = map[string]Currency{}
+	}
+	for _, item := range c.items {
+		if p.Calendar.IsTradingDay(item.ISOCode, c.updatedAt) {
+			c.closes[strings.ToUpper(item.ISOCode)] = item
+		}
+	}
+}
+
+// pricingItems returns the currencies to price a quote between from and to
+// against base with, applying the closed market policy to the table's items.
+// The items themselves are returned when the policy changes nothing. The
+// caller holds the read lock.
+func (c *Currencies) pricingItems(base, from, to string) []Currency {
+	p := c.closedMarket
+	if p == nil {
+		return c.items
+	}
+	now := p.Clock.Now()
+	one := decimal.NewFromInt(1)
+
+	var items []Currency
+	for i, item := range c.items {
+		if strings.EqualFold(item.ISOCode, base) ||
+			!strings.EqualFold(item.ISOCode, from) && !strings.EqualFold(item.ISOCode, to) ||
+			p.Calendar.IsTradingDay(item.ISOCode, now) {
+			continue
+		}
+
+		if p.Freeze {
+			if closed, ok := c.closes[strings.ToUpper(item.ISOCode)]; ok {
+				item = closed
+			}
+		}
+		if p.Spread.IsPositive() {
+			item.BuyRate = item.BuyRate.Mul(one.Add(p.Spread))
+			item.SellRate = item.SellRate.Mul(one.Sub(p.Spread))
+		}
+
+		if items == nil {
+			items = slices.Clone(c.items)
+		}
+		items[i] = item
+	}
+
+	if items == nil {
+		return c.items
+	}
+	return items
+}
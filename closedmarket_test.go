@@ -0,0 +1,64 @@
+package converter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurrencies_ClosedMarketPolicy(t *testing.T) {
+	friday := time.Date(2024, 3, 29, 16, 0, 0, 0, time.UTC)
+	saturday := friday.Add(24 * time.Hour)
+	clock := fixedClock(saturday)
+
+	weekend := testCurrencies()
+	weekend[2].SellRate = decimal.NewFromInt(1650)
+
+	rate := func(table *Currencies, to string) string {
+		t.Helper()
+		r, err := table.CalculateRate("USD", "USD", to)
+		assert.NoError(t, err)
+		return r.String()
+	}
+
+	// Without a policy the weekend rate is quoted.
+	table := NewTable(nil)
+	table.Update(testCurrencies(), friday)
+	table.Update(weekend, saturday)
+	assert.Equal(t, "1650", rate(table, "NGN"))
+
+	frozen := NewTable(nil, WithClosedMarketPolicy(ClosedMarketPolicy{Freeze: true, Clock: &clock}))
+	frozen.Update(testCurrencies(), friday)
+	frozen.Update(weekend, saturday)
+	assert.Equal(t, "1550", rate(frozen, "NGN"))
+	quote, err := frozen.NewQuote("USD", "USD", "NGN", decimal.NewFromInt(10), decimal.Zero)
+	assert.NoError(t, err)
+	assert.Equal(t, "1550", quote.Rate.String())
+	// Listings show the latest rates.
+	assert.Equal(t, "1650", frozen.List()[1].SellRate.String())
+
+	// A currency trading on Saturday is quoted live.
+	cal := NewCalendar()
+	assert.NoError(t, cal.SetWeekend("NGN", time.Sunday))
+	live := NewTable(nil, WithClosedMarketPolicy(ClosedMarketPolicy{Calendar: cal, Freeze: true, Clock: &clock}))
+	live.Update(testCurrencies(), friday)
+	live.Update(weekend, saturday)
+	assert.Equal(t, "1650", rate(live, "NGN"))
+
+	// On Monday the market is open again.
+	clock = fixedClock(saturday.Add(48 * time.Hour))
+	assert.Equal(t, "1650", rate(frozen, "NGN"))
+	clock = fixedClock(saturday)
+
+	spread := NewTable(nil, WithClosedMarketPolicy(ClosedMarketPolicy{Spread: decimal.RequireFromString("0.01"), Clock: &clock}))
+	spread.Update(testCurrencies(), saturday)
+	assert.Equal(t, "1534.5", rate(spread, "NGN"))
+	r, err := spread.CalculateRate("USD", "EUR", "USD")
+	assert.NoError(t, err)
+	assert.True(t, r.LessThan(decimal.NewFromInt(1).Div(decimal.RequireFromString("0.9"))))
+
+	// Clones keep the policy and the recorded closes.
+	assert.Equal(t, "1550", rate(frozen.Clone(), "NGN"))
+}
@@ -0,0 +1,16 @@
+//go:build js && wasm
+
+// Command converter-wasm installs the converter's JavaScript bindings as the
+// global object "converter", see package jsapi. Build it with:
+//
+//	GOOS=js GOARCH=wasm go build -o converter.wasm ./cmd/converter-wasm
+//
+// and load it with Go's wasm_exec.js.
+package main
+
+import "github.com/otyang/converter/jsapi"
+
+func main() {
+	jsapi.Register("converter")
+	select {}
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/otyang/converter"
+	"github.com/otyang/converter/loadtest"
+	"github.com/shopspring/decimal"
+)
+
+func runBench(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	base := fs.String("base", "USD", "base currency of the rates")
+	source := fs.String("source", os.Getenv("CONVERTER_SOURCE"), "rate file, http(s) URL of a JSON rate feed, or synthetic:CODE=START,... to load a table to bench (default $CONVERTER_SOURCE)")
+	rateURL := fs.String("url", "", "rate endpoint `URL` of a server to bench rate lookups against, instead of a table")
+	graphqlURL := fs.String("graphql", "", "GraphQL endpoint `URL` of a server to bench quote creations against, instead of a table")
+	mix := fs.String("mix", "rate=9,quote=1", "weights of the operations, rate and quote")
+	workers := fs.Int("workers", loadtest.DefaultWorkers, "concurrent workers")
+	duration := fs.Duration("duration", 10*time.Second, "how long to run; 0 runs until -requests are made")
+	requests := fs.Int("requests", 0, "stop after this many operations; 0 runs for -duration")
+	amount := fs.String("amount", "100", "amount quoted")
+	format := fs.String("format", "text", "report format, text or json")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: converter bench [-source rates.json|URL | -url URL -graphql URL] [-mix rate=9,quote=1] [-workers 8] [-duration 10s] [-requests N] USD/NGN [EUR/NGN ...]")
+		fs.PrintDefaults()
+	}
+	pairArgs, err := parseInterspersed(fs, args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	remote := *rateURL != "" || *graphqlURL != ""
+	if len(pairArgs) == 0 || (!remote && *source == "") || *duration < 0 || *requests < 0 ||
+		(*duration == 0 && *requests == 0) || (*format != "text" && *format != "json") {
+		fs.Usage()
+		return exitUsage
+	}
+
+	var pairs []loadtest.Pair
+	for _, arg := range pairArgs {
+		from, to, ok := strings.Cut(arg, "/")
+		if !ok || from == "" || to == "" {
+			fmt.Fprintf(stderr, "converter bench: %v: %q\n", converter.ErrInvalidPair, arg)
+			return exitUsage
+		}
+		pairs = append(pairs, loadtest.Pair{From: strings.ToUpper(from), To: strings.ToUpper(to)})
+	}
+	quoted, err := decimal.NewFromString(*amount)
+	if err != nil {
+		fmt.Fprintf(stderr, "converter bench: %v: %q is not a decimal number\n", converter.ErrInvalidAmount, *amount)
+		return exitUsage
+	}
+	weights, err := parseMix(*mix)
+	if err != nil {
+		fmt.Fprintf(stderr, "converter bench: %v\n", err)
+		return exitUsage
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	b := benchTarget{base: *base, pairs: pairs, amount: quoted, rateURL: *rateURL, graphqlURL: *graphqlURL}
+	if !remote {
+		provider, err := sourceProvider(*source, *base)
+		if err == nil {
+			b.table, err = loadTable(ctx, provider)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "converter bench: %s: %v\n", *source, err)
+			return exitFailed
+		}
+	}
+	ops, err := b.ops(weights)
+	if err != nil {
+		fmt.Fprintf(stderr, "converter bench: %v\n", err)
+		return exitUsage
+	}
+
+	report, err := loadtest.Run(ctx, loadtest.Config{
+		Ops:      ops,
+		Workers:  *workers,
+		Duration: *duration,
+		Requests: *requests,
+		Seed:     time.Now().UnixNano(),
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "converter bench: %v\n", err)
+		return exitFailed
+	}
+	if *format == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(report)
+	} else {
+		err = report.WriteText(stdout)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "converter bench: %v\n", err)
+		return exitFailed
+	}
+	if report.Total.Errors > 0 {
+		return exitFailed
+	}
+	return exitOK
+}
+
+// loadTable fetches rates once into a table.
+func loadTable(ctx context.Context, provider converter.RateProvider) (*converter.Currencies, error) {
+	currencies, err := provider.FetchRates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return converter.NewTable(currencies), nil
+}
+
+// benchOps are the operations a mix may weigh.
+var benchOps = []string{"rate", "quote"}
+
+// parseMix parses weights such as "rate=9,quote=1". Operations left out
+// are not run.
+func parseMix(mix string) (map[string]int, error) {
+	weights := map[string]int{}
+	for _, entry := range strings.Split(mix, ",") {
+		name, weight, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		n, err := strconv.Atoi(weight)
+		if !ok || err != nil || n < 0 || !slices.Contains(benchOps, name) {
+			return nil, fmt.Errorf("invalid mix %q: want rate=N,quote=N", mix)
+		}
+		if n > 0 {
+			weights[name] = n
+		}
+	}
+	if len(weights) == 0 {
+		return nil, fmt.Errorf("invalid mix %q: no operation has weight", mix)
+	}
+	return weights, nil
+}
+
+// benchTarget builds the operations for a table, or for a server when one
+// of its URLs is set.
+type benchTarget struct {
+	table      *converter.Currencies
+	base       string
+	pairs      []loadtest.Pair
+	amount     decimal.Decimal
+	rateURL    string
+	graphqlURL string
+}
+
+func (b benchTarget) ops(weights map[string]int) ([]loadtest.Op, error) {
+	var ops []loadtest.Op
+	for _, name := range benchOps {
+		weight, ok := weights[name]
+		if !ok {
+			continue
+		}
+		var (
+			op  loadtest.Op
+			err error
+		)
+		switch {
+		case b.table != nil && name == "rate":
+			op, err = loadtest.RateOp(b.table, b.base, b.pairs...)
+		case b.table != nil:
+			op, err = loadtest.QuoteOp(b.table, b.base, b.amount, b.pairs...)
+		case name == "rate" && b.rateURL != "":
+			op = loadtest.HTTPRateOp(nil, b.rateURL)
+		case name == "quote" && b.graphqlURL != "":
+			op, err = loadtest.GraphQLQuoteOp(nil, b.graphqlURL, b.amount, b.pairs...)
+		default:
+			return nil, fmt.Errorf("the mix weighs %s operations but no server URL serves them", name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		op.Weight = weight
+		ops = append(ops, op)
+	}
+	return ops, nil
+}
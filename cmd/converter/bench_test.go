@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/otyang/converter"
+	"github.com/otyang/converter/graphqlapi"
+	"github.com/otyang/converter/httpapi"
+	"github.com/otyang/converter/loadtest"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBenchCommand(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitOK, run([]string{"bench", "-source", "synthetic:NGN=1500,EUR=0.92", "-requests", "200", "-workers", "2", "USD/NGN", "EUR/NGN"}, &stdout, &stderr), stderr.String())
+	assert.Contains(t, stdout.String(), "rate")
+	assert.Contains(t, stdout.String(), "quote")
+	assert.Contains(t, stdout.String(), "total  200")
+	assert.Contains(t, stdout.String(), "2 workers")
+
+	// Failed operations fail the run.
+	stdout.Reset()
+	assert.Equal(t, exitFailed, run([]string{"bench", "-source", "synthetic:NGN=1500", "-requests", "10", "-mix", "rate=1", "USD/GBP"}, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), "rate: first error: ")
+}
+
+func TestBenchCommandServer(t *testing.T) {
+	table := converter.NewTable([]converter.Currency{
+		{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+		{ISOCode: "NGN", Precision: 2, BuyRate: decimal.NewFromInt(1500), SellRate: decimal.NewFromInt(1550)},
+	})
+	graphql, err := graphqlapi.NewHandler(graphqlapi.Config{Table: table, Base: "USD", Quotes: converter.NewMemoryQuoteStore()})
+	require.NoError(t, err)
+	mux := http.NewServeMux()
+	mux.Handle("/rates/matrix", httpapi.MatrixHandler(table, "USD"))
+	mux.Handle("/graphql", graphql)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"bench", "-url", srv.URL + "/rates/matrix", "-graphql", srv.URL + "/graphql", "-requests", "50", "-format", "json", "USD/NGN"}
+	require.Equal(t, exitOK, run(args, &stdout, &stderr), stderr.String())
+
+	var report loadtest.Report
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &report))
+	assert.Equal(t, 50, report.Total.Count)
+	assert.Zero(t, report.Total.Errors)
+	require.Len(t, report.Ops, 2)
+
+	// Quotes need the GraphQL endpoint.
+	assert.Equal(t, exitUsage, run([]string{"bench", "-url", srv.URL + "/rates/matrix", "-requests", "5", "USD/NGN"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "no server URL serves them")
+}
+
+func TestBenchCommandUsage(t *testing.T) {
+	t.Setenv("CONVERTER_SOURCE", "")
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitUsage, run([]string{"bench", "USD/NGN"}, &stdout, &stderr))
+	assert.Equal(t, exitUsage, run([]string{"bench", "-source", "rates.json"}, &stdout, &stderr))
+	assert.Equal(t, exitUsage, run([]string{"bench", "-source", "rates.json", "-duration", "0s", "USD/NGN"}, &stdout, &stderr))
+	assert.Equal(t, exitUsage, run([]string{"bench", "-source", "rates.json", "-format", "xml", "USD/NGN"}, &stdout, &stderr))
+	assert.Equal(t, exitUsage, run([]string{"bench", "-source", "rates.json", "USDNGN"}, &stdout, &stderr))
+	assert.Equal(t, exitUsage, run([]string{"bench", "-source", "rates.json", "-amount", "lots", "USD/NGN"}, &stdout, &stderr))
+	assert.Equal(t, exitUsage, run([]string{"bench", "-source", "rates.json", "-mix", "rate=x", "USD/NGN"}, &stdout, &stderr))
+	assert.Equal(t, exitUsage, run([]string{"bench", "-source", "rates.json", "-mix", "rate=0", "USD/NGN"}, &stdout, &stderr))
+	assert.Equal(t, exitFailed, run([]string{"bench", "-source", "missing.json", "USD/NGN"}, &stdout, &stderr))
+}
+
+func TestParseMix(t *testing.T) {
+	weights, err := parseMix("rate=3, quote=0")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"rate": 3}, weights)
+
+	_, err = parseMix("convert=1")
+	assert.Error(t, err)
+}
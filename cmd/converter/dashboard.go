@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/otyang/converter"
+	"github.com/otyang/converter/dashboard"
+	"golang.org/x/term"
+)
+
+func runDashboard(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("dashboard", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	base := fs.String("base", "USD", "base currency of the rates")
+	source := fs.String("source", os.Getenv("CONVERTER_SOURCE"), "rate file, http(s) URL of a JSON rate feed, or synthetic:CODE=START,... for generated rates (default $CONVERTER_SOURCE)")
+	interval := fs.Duration("interval", 30*time.Second, "time between fetches")
+	snapshot := fs.String("snapshot", "", "snapshot `file` loaded at start and saved after each refresh; gob for .gob, else JSON")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: converter dashboard [-source rates.json|URL] [-base USD] [-interval 30s] [-snapshot rates.json]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if fs.NArg() != 0 || *source == "" {
+		fs.Usage()
+		return exitUsage
+	}
+
+	provider, err := sourceProvider(*source, *base)
+	if err != nil {
+		fmt.Fprintf(stderr, "converter dashboard: %v\n", err)
+		return exitUsage
+	}
+	table := converter.NewTable(nil)
+	refresher, err := converter.NewRefresher(table, provider, *interval)
+	if err != nil {
+		fmt.Fprintf(stderr, "converter dashboard: %v\n", err)
+		return exitUsage
+	}
+	if *snapshot != "" {
+		refresher.Snapshots = converter.NewFileSnapshotStore(*snapshot)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go refresher.Run(ctx)
+
+	cfg := dashboard.Config{Table: table, Base: *base}
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		// Without a terminal to read keys from, run until interrupted.
+		err = dashboard.Run(ctx, cfg, nil, stdout)
+	} else {
+		state, rawErr := term.MakeRaw(fd)
+		if rawErr != nil {
+			fmt.Fprintf(stderr, "converter dashboard: %v\n", rawErr)
+			return exitFailed
+		}
+		defer term.Restore(fd, state)
+
+		fmt.Fprint(stdout, dashboard.EnterScreen)
+		defer fmt.Fprint(stdout, dashboard.ExitScreen)
+		err = dashboard.Run(ctx, cfg, os.Stdin, stdout)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "converter dashboard: %v\n", err)
+		return exitFailed
+	}
+	return exitOK
+}
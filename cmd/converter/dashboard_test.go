@@ -0,0 +1,21 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDashboardCommandUsage(t *testing.T) {
+	t.Setenv("CONVERTER_SOURCE", "")
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitUsage, run([]string{"dashboard"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "usage: converter dashboard")
+	assert.Equal(t, exitUsage, run([]string{"dashboard", "-source", "rates.json", "extra"}, &stdout, &stderr))
+
+	stderr.Reset()
+	assert.Equal(t, exitUsage, run([]string{"dashboard", "-source", "rates.json", "-interval", "0s"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "converter dashboard: ")
+	assert.Equal(t, exitOK, run([]string{"dashboard", "-h"}, &stdout, &stderr))
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/otyang/converter"
+)
+
+func runLint(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	base := fs.String("base", "USD", "base currency of the rate file")
+	format := fs.String("format", "json", "report format: json or text")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: converter lint [-base USD] [-format json|text] rates.json")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if fs.NArg() != 1 || (*format != "json" && *format != "text") {
+		fs.Usage()
+		return exitUsage
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "converter lint: %v\n", err)
+		return exitUsage
+	}
+	defer f.Close()
+
+	report, err := converter.LintJSON(f, *base)
+	if err != nil {
+		fmt.Fprintf(stderr, "converter lint: %s: %v\n", fs.Arg(0), err)
+		return exitUsage
+	}
+
+	if *format == "json" {
+		if report.Issues == nil {
+			report.Issues = []converter.LintIssue{}
+		}
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			fmt.Fprintf(stderr, "converter lint: %v\n", err)
+			return exitUsage
+		}
+	} else {
+		writeLintText(stdout, fs.Arg(0), report)
+	}
+
+	if !report.OK() {
+		return exitFailed
+	}
+	return exitOK
+}
+
+// writeLintText writes one issue per line, compiler style, then a summary.
+func writeLintText(w io.Writer, name string, report *converter.LintReport) {
+	for _, i := range report.Issues {
+		where := name
+		if i.Index >= 0 {
+			where = fmt.Sprintf("%s[%d]", name, i.Index)
+		}
+		fmt.Fprintf(w, "%s: %s: %s (%s)\n", where, i.Severity, i.Message, i.Check)
+	}
+	fmt.Fprintf(w, "%d error(s), %d warning(s)\n", report.Errors, report.Warnings)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/otyang/converter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLintCommand(t *testing.T) {
+	good := writeRates(t, `[
+		{"isoCode": "USD", "precision": 2, "buyRate": "1", "sellRate": "1"},
+		{"isoCode": "NGN", "precision": 2, "buyRate": "1550", "sellRate": "1500"}
+	]`)
+	bad := writeRates(t, `[
+		{"isoCode": "USD", "precision": 2, "buyRate": "1", "sellRate": "1"},
+		{"isoCode": "EUR", "buyRate": "0.9", "sellRate": "0.95"},
+		{"isoCode": "usd", "precision": 2, "buyRate": "1", "sellRate": "1"}
+	]`)
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitOK, run([]string{"lint", good}, &stdout, &stderr))
+	var report converter.LintReport
+	assert.NoError(t, json.Unmarshal(stdout.Bytes(), &report))
+	assert.True(t, report.OK())
+	assert.Empty(t, report.Issues)
+	assert.Contains(t, stdout.String(), `"issues": []`)
+
+	stdout.Reset()
+	assert.Equal(t, exitFailed, run([]string{"lint", bad}, &stdout, &stderr))
+	report = converter.LintReport{}
+	assert.NoError(t, json.Unmarshal(stdout.Bytes(), &report))
+	var checks []string
+	for _, i := range report.Issues {
+		checks = append(checks, i.Check)
+	}
+	assert.Equal(t, []string{"inverted-spread", "missing-precision", "precision", "duplicate"}, checks)
+	assert.Equal(t, 3, report.Errors)
+	assert.Equal(t, 1, report.Warnings)
+
+	stdout.Reset()
+	assert.Equal(t, exitFailed, run([]string{"lint", "-format", "text", bad}, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), "[1]: error: ")
+	assert.Contains(t, stdout.String(), "(missing-precision)\n")
+	assert.Contains(t, stdout.String(), "3 error(s), 1 warning(s)\n")
+}
+
+func TestLintCommandUsage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitUsage, run([]string{"lint"}, &stdout, &stderr))
+	assert.Equal(t, exitUsage, run([]string{"lint", "-format", "xml", "rates.json"}, &stdout, &stderr))
+	assert.Equal(t, exitUsage, run([]string{"lint", filepath.Join(t.TempDir(), "missing.json")}, &stdout, &stderr))
+	assert.Equal(t, exitUsage, run([]string{"lint", writeRates(t, `{"not": "an array"}`)}, &stdout, &stderr))
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeRates(t *testing.T, rates string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "rates.json")
+	assert.NoError(t, os.WriteFile(path, []byte(rates), 0o600))
+	return path
+}
+
+func TestRun(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitUsage, run(nil, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "lint")
+	assert.Contains(t, stderr.String(), "table")
+
+	assert.Equal(t, exitUsage, run([]string{"bogus"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), `unknown command "bogus"`)
+
+	assert.Equal(t, exitOK, run([]string{"help"}, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), "usage: converter")
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/otyang/converter"
+	"github.com/shopspring/decimal"
+	"golang.org/x/term"
+)
+
+const replHelp = `commands:
+  rate USD NGN                          rate from one currency to another
+  quote 100 USD -> NGN [fee 2|fee 2%] [mode additive|deducted|destination]
+                                        price a quote; the fee is in the source currency
+  codes                                 list the table's currencies
+  history                               list this session's commands
+  help                                  show this help
+  exit                                  leave (or Ctrl-D)
+`
+
+// replCommands are completed at the start of a line.
+var replCommands = []string{"codes", "exit", "help", "history", "quit", "quote", "rate"}
+
+func runREPL(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("repl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	base := fs.String("base", "USD", "base currency of the rate file")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: converter repl [-base USD] rates.json")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	currencies, err := converter.NewCurrenciesFromFile(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "converter repl: %s: %v\n", fs.Arg(0), err)
+		return exitFailed
+	}
+	r := &repl{table: converter.NewTable(currencies), base: *base}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		r.loop(os.Stdin, stdout)
+		return exitOK
+	}
+
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		fmt.Fprintf(stderr, "converter repl: %v\n", err)
+		return exitFailed
+	}
+	defer term.Restore(fd, state)
+
+	t := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, stdout}, "> ")
+	t.AutoCompleteCallback = r.complete
+	fmt.Fprintln(t, `converter repl: type "help" for commands`)
+	for {
+		line, err := t.ReadLine()
+		if err != nil {
+			return exitOK
+		}
+		if r.eval(line, t) {
+			return exitOK
+		}
+	}
+}
+
+// repl evaluates commands against a rate table.
+type repl struct {
+	table   *converter.Currencies
+	base    string
+	history []string
+}
+
+// loop evaluates the lines of r until EOF or exit, for piped input.
+func (r *repl) loop(in io.Reader, w io.Writer) {
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		if r.eval(scanner.Text(), w) {
+			return
+		}
+	}
+}
+
+// eval evaluates one line, writing its output to w. It reports whether the
+// session should end.
+func (r *repl) eval(line string, w io.Writer) bool {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return false
+	}
+	r.history = append(r.history, strings.Join(fields, " "))
+
+	var err error
+	switch strings.ToLower(fields[0]) {
+	case "exit", "quit":
+		return true
+	case "help":
+		fmt.Fprint(w, replHelp)
+	case "codes":
+		fmt.Fprintln(w, strings.Join(r.codes(), " "))
+	case "history":
+		for i, h := range r.history {
+			fmt.Fprintf(w, "%4d  %s\n", i+1, h)
+		}
+	case "rate":
+		err = r.rate(fields[1:], w)
+	case "quote":
+		err = r.quote(fields[1:], w)
+	default:
+		err = fmt.Errorf("unknown command %q; try help", fields[0])
+	}
+	if err != nil {
+		fmt.Fprintf(w, "error: %v\n", err)
+	}
+	return false
+}
+
+func (r *repl) rate(args []string, w io.Writer) error {
+	if len(args) == 1 && strings.Contains(args[0], "/") {
+		args = strings.SplitN(args[0], "/", 2)
+	}
+	if len(args) != 2 {
+		return errors.New("usage: rate USD NGN")
+	}
+	from, to := strings.ToUpper(args[0]), strings.ToUpper(args[1])
+	rate, err := r.table.CalculateRate(r.base, from, to)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "1 %s = %s %s\n", from, rate, to)
+	return nil
+}
+
+// quote parses "100 USD -> NGN [fee 2|fee 2%] [mode deducted]"; "to" may
+// stand for the arrow.
+func (r *repl) quote(args []string, w io.Writer) error {
+	usage := errors.New("usage: quote 100 USD -> NGN [fee 2|fee 2%] [mode additive|deducted|destination]")
+	if len(args) < 4 || (args[2] != "->" && !strings.EqualFold(args[2], "to")) {
+		return usage
+	}
+	amount, err := decimal.NewFromString(args[0])
+	if err != nil {
+		return fmt.Errorf("%w: %q is not a decimal number", converter.ErrInvalidAmount, args[0])
+	}
+	from, to := strings.ToUpper(args[1]), strings.ToUpper(args[3])
+
+	fee := decimal.Zero
+	var opts []converter.QuoteOption
+	for rest := args[4:]; len(rest) > 0; rest = rest[2:] {
+		if len(rest) < 2 {
+			return usage
+		}
+		switch strings.ToLower(rest[0]) {
+		case "fee":
+			percent, isPercent := strings.CutSuffix(rest[1], "%")
+			value, err := decimal.NewFromString(percent)
+			if err != nil {
+				return fmt.Errorf("%w: fee %q is not a decimal number", converter.ErrInvalidAmount, rest[1])
+			}
+			if isPercent {
+				opts = append(opts, converter.WithFeeSchedule(converter.FeeSchedule{Rate: value.Shift(-2)}))
+			} else {
+				fee = value
+			}
+		case "mode":
+			opts = append(opts, converter.WithFeeMode(converter.FeeMode(strings.ToLower(rest[1]))))
+		default:
+			return usage
+		}
+	}
+
+	q, err := r.table.NewQuote(r.base, from, to, amount, fee, opts...)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "send     %s %s (fee %s)\n", q.Send.Amount, q.Send.Currency, q.Send.Fee)
+	fmt.Fprintf(w, "rate     %s\n", q.Rate)
+	fmt.Fprintf(w, "receive  %s %s\n", q.Receive.Amount, q.Receive.Currency)
+	return nil
+}
+
+func (r *repl) codes() []string {
+	list := r.table.List()
+	codes := make([]string, len(list))
+	for i, c := range list {
+		codes[i] = c.ISOCode
+	}
+	return codes
+}
+
+// complete completes the word before the cursor on tab: a command at the
+// start of the line, else a currency code. Ambiguous words are completed to
+// the candidates' common prefix.
+func (r *repl) complete(line string, pos int, key rune) (string, int, bool) {
+	if key != '\t' {
+		return "", 0, false
+	}
+	start := strings.LastIndexAny(line[:pos], " /") + 1
+	word := line[start:pos]
+
+	candidates := r.codes()
+	if strings.TrimSpace(line[:start]) == "" {
+		candidates = replCommands
+	}
+	var matches []string
+	for _, c := range candidates {
+		if len(word) <= len(c) && strings.EqualFold(c[:len(word)], word) {
+			matches = append(matches, c)
+		}
+	}
+	if len(matches) == 0 {
+		return "", 0, false
+	}
+
+	completion := commonPrefix(matches)
+	if len(matches) == 1 {
+		completion += " "
+	}
+	if len(completion) <= len(word) && completion == word {
+		return "", 0, false
+	}
+	return line[:start] + completion + line[pos:], start + len(completion), true
+}
+
+// commonPrefix returns the longest prefix of all words.
+func commonPrefix(words []string) string {
+	prefix := slices.Min(words)
+	last := slices.Max(words)
+	n := 0
+	for n < len(prefix) && n < len(last) && prefix[n] == last[n] {
+		n++
+	}
+	return prefix[:n]
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/otyang/converter"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func testREPL() *repl {
+	return &repl{
+		table: converter.NewTable([]converter.Currency{
+			{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+			{ISOCode: "NGN", Precision: 2, BuyRate: decimal.NewFromInt(1550), SellRate: decimal.NewFromInt(1500)},
+			{ISOCode: "NOK", Precision: 2, BuyRate: decimal.NewFromInt(11), SellRate: decimal.NewFromInt(10)},
+		}),
+		base: "USD",
+	}
+}
+
+func TestREPL(t *testing.T) {
+	r := testREPL()
+	var out bytes.Buffer
+	r.loop(strings.NewReader(`rate usd ngn
+rate USD/NGN
+quote 100 USD -> NGN fee 2
+quote 100 usd to ngn fee 2% mode deducted
+
+codes
+bogus
+history
+exit
+rate USD NGN
+`), &out)
+
+	assert.Equal(t, `1 USD = 1500 NGN
+1 USD = 1500 NGN
+send     102 USD (fee 2)
+rate     1500
+receive  150000 NGN
+send     100 USD (fee 2)
+rate     1500
+receive  147000 NGN
+NGN NOK USD
+error: unknown command "bogus"; try help
+   1  rate usd ngn
+   2  rate USD/NGN
+   3  quote 100 USD -> NGN fee 2
+   4  quote 100 usd to ngn fee 2% mode deducted
+   5  codes
+   6  bogus
+   7  history
+`, out.String())
+}
+
+func TestREPLErrors(t *testing.T) {
+	r := testREPL()
+	for line, want := range map[string]string{
+		"rate USD":                      "error: usage: rate USD NGN\n",
+		"rate USD GBP":                  "error: currency GBP not found\n",
+		"quote 100 USD NGN":             "error: usage: quote",
+		"quote abc USD -> NGN":          `error: invalid amount: "abc" is not a decimal number` + "\n",
+		"quote 100 USD -> NGN fee":      "error: usage: quote",
+		"quote 100 USD -> NGN fee x%":   `error: invalid amount: fee "x%" is not a decimal number` + "\n",
+		"quote 100 USD -> NGN tip 2":    "error: usage: quote",
+		"quote 100 USD -> NGN mode odd": "error: invalid fee mode",
+	} {
+		var out bytes.Buffer
+		assert.False(t, r.eval(line, &out))
+		assert.True(t, strings.HasPrefix(out.String(), want), "%s: %s", line, out.String())
+	}
+
+	var out bytes.Buffer
+	r.eval("help", &out)
+	assert.Equal(t, replHelp, out.String())
+}
+
+func TestREPLComplete(t *testing.T) {
+	r := testREPL()
+	tests := []struct {
+		line    string
+		pos     int
+		want    string
+		wantPos int
+		ok      bool
+	}{
+		{"ra", 2, "rate ", 5, true},
+		{"q", 1, "qu", 2, true},
+		{"quo", 3, "quote ", 6, true},
+		{"h", 1, "h", 1, false},
+		{"rate u", 6, "rate USD ", 9, true},
+		{"rate USD n", 10, "rate USD N", 10, true},
+		{"rate USD NG", 11, "rate USD NGN ", 13, true},
+		{"rate usd/n", 10, "rate usd/N", 10, true},
+		{"rate x", 6, "", 0, false},
+		{"rate USD N", 10, "", 0, false},
+	}
+	for _, tt := range tests {
+		line, pos, ok := r.complete(tt.line, tt.pos, '\t')
+		assert.Equal(t, tt.ok, ok, tt.line)
+		if ok {
+			assert.Equal(t, tt.want, line, tt.line)
+			assert.Equal(t, tt.wantPos, pos, tt.line)
+		}
+	}
+
+	_, _, ok := r.complete("ra", 2, 'a')
+	assert.False(t, ok)
+}
+
+func TestREPLCommand(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitUsage, run([]string{"repl"}, &stdout, &stderr))
+	assert.Equal(t, exitFailed, run([]string{"repl", "missing.json"}, &stdout, &stderr))
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/otyang/converter"
+	"github.com/shopspring/decimal"
+)
+
+// syntheticPrefix starts a source generating rates instead of reading
+// them, e.g. "synthetic:NGN=1500,EUR=0.92:0.001".
+const syntheticPrefix = "synthetic:"
+
+// Defaults of synthetic currencies: 0.2% volatility a fetch, a 1% spread.
+const (
+	syntheticVolatility = 0.002
+	syntheticSpread     = 0.01
+)
+
+// syntheticProvider parses a synthetic source, a comma-separated list of
+// CODE=START[:VOLATILITY[:SPREAD]], into a generator of rates against base.
+func syntheticProvider(spec, base string, seed int64) (*converter.SyntheticRates, error) {
+	var currencies []converter.SyntheticCurrency
+	for _, entry := range strings.Split(strings.TrimPrefix(spec, syntheticPrefix), ",") {
+		code, params, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: %q is not CODE=START[:VOLATILITY[:SPREAD]]", converter.ErrInvalidSynthetic, entry)
+		}
+		fields := strings.Split(params, ":")
+		if len(fields) > 3 {
+			return nil, fmt.Errorf("%w: %q is not CODE=START[:VOLATILITY[:SPREAD]]", converter.ErrInvalidSynthetic, entry)
+		}
+		c := converter.SyntheticCurrency{
+			ISOCode:    strings.ToUpper(code),
+			Precision:  2,
+			Volatility: syntheticVolatility,
+			Spread:     syntheticSpread,
+		}
+		start, err := decimal.NewFromString(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s start %q is not a decimal number", converter.ErrInvalidSynthetic, c.ISOCode, fields[0])
+		}
+		c.Start = start
+		for i, target := range []*float64{&c.Volatility, &c.Spread} {
+			if i+1 >= len(fields) {
+				break
+			}
+			if *target, err = strconv.ParseFloat(fields[i+1], 64); err != nil {
+				return nil, fmt.Errorf("%w: %s has %q, not a number", converter.ErrInvalidSynthetic, c.ISOCode, fields[i+1])
+			}
+		}
+		currencies = append(currencies, c)
+	}
+	return converter.NewSyntheticRates(base, seed, currencies...)
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/otyang/converter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyntheticProvider(t *testing.T) {
+	g, err := syntheticProvider("synthetic:ngn=1500, EUR=0.92:0:0.004", "USD", 1)
+	require.NoError(t, err)
+
+	rates, err := g.FetchRates(context.Background())
+	require.NoError(t, err)
+	require.Len(t, rates, 3)
+	assert.Equal(t, "NGN", rates[1].ISOCode)
+	assert.True(t, rates[1].SellRate.GreaterThan(rates[1].BuyRate))
+	// Without volatility, EUR stays at its start.
+	assert.Equal(t, "0.91816", rates[2].BuyRate.String())
+	assert.Equal(t, "0.92184", rates[2].SellRate.String())
+
+	for _, spec := range []string{
+		"synthetic:NGN",
+		"synthetic:NGN=lots",
+		"synthetic:NGN=1500:high",
+		"synthetic:NGN=1500:0:0:0",
+		"synthetic:NGN=0",
+		"synthetic:USD=1",
+	} {
+		_, err := syntheticProvider(spec, "USD", 1)
+		assert.ErrorIs(t, err, converter.ErrInvalidSynthetic, spec)
+	}
+}
+
+func TestWatchCommandSynthetic(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitOK, run([]string{"watch", "-source", "synthetic:NGN=1500", "-count", "1", "USD/NGN"}, &stdout, &stderr))
+	assert.Regexp(t, `USD/NGN 1[45]\d\d`, stdout.String())
+
+	assert.Equal(t, exitUsage, run([]string{"watch", "-source", "synthetic:NGN", "USD/NGN"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "invalid synthetic currency")
+}
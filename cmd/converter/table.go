@@ -0,0 +1,51 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/otyang/converter"
+)
+
+func runTable(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("table", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	base := fs.String("base", "USD", "base currency of the rate files")
+	previous := fs.String("previous", "", "previous rate file to show changes against")
+	color := fs.Bool("color", false, "highlight changes with ANSI colours")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: converter table [-base USD] [-previous old.json] [-color] rates.json")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	table := &converter.RateTable{Base: *base, Color: *color}
+	var err error
+	if table.Currencies, err = converter.NewCurrenciesFromFile(fs.Arg(0)); err != nil {
+		fmt.Fprintf(stderr, "converter table: %s: %v\n", fs.Arg(0), err)
+		return exitFailed
+	}
+	if *previous != "" {
+		if table.Previous, err = converter.NewCurrenciesFromFile(*previous); err != nil {
+			fmt.Fprintf(stderr, "converter table: %s: %v\n", *previous, err)
+			return exitFailed
+		}
+	}
+
+	if err := table.WriteText(stdout); err != nil {
+		fmt.Fprintf(stderr, "converter table: %v\n", err)
+		return exitFailed
+	}
+	return exitOK
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTableCommand(t *testing.T) {
+	previous := writeRates(t, `[
+		{"isoCode": "USD", "precision": 2, "buyRate": "1", "sellRate": "1"},
+		{"isoCode": "NGN", "precision": 2, "buyRate": "1550", "sellRate": "1500"}
+	]`)
+	current := writeRates(t, `[
+		{"isoCode": "USD", "precision": 2, "buyRate": "1", "sellRate": "1"},
+		{"isoCode": "NGN", "precision": 2, "buyRate": "1600", "sellRate": "1550"}
+	]`)
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitOK, run([]string{"table", current}, &stdout, &stderr))
+	assert.Equal(t, `+---------+------+------+
+| Pair    |  Buy | Sell |
++---------+------+------+
+| USD/NGN | 1600 | 1550 |
++---------+------+------+
+`, stdout.String())
+
+	stdout.Reset()
+	assert.Equal(t, exitOK, run([]string{"table", "-previous", previous, current}, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), "| USD/NGN | 1600 | 1550 | +3.28% |\n")
+
+	stdout.Reset()
+	assert.Equal(t, exitOK, run([]string{"table", "-previous", previous, "-color", current}, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), "\x1b[32m+3.28%\x1b[0m")
+}
+
+func TestTableCommandErrors(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitUsage, run([]string{"table"}, &stdout, &stderr))
+	assert.Equal(t, exitFailed, run([]string{"table", filepath.Join(t.TempDir(), "missing.json")}, &stdout, &stderr))
+
+	rates := writeRates(t, `[{"isoCode": "USD", "precision": 2, "buyRate": "1", "sellRate": "1"}]`)
+	assert.Equal(t, exitFailed, run([]string{"table", "-previous", "missing.json", rates}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "missing.json")
+}
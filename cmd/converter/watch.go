@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/otyang/converter"
+	"github.com/shopspring/decimal"
+	"golang.org/x/term"
+)
+
+func runWatch(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	base := fs.String("base", "USD", "base currency of the rates")
+	source := fs.String("source", os.Getenv("CONVERTER_SOURCE"), "rate file, http(s) URL of a JSON rate feed, or synthetic:CODE=START,... for generated rates (default $CONVERTER_SOURCE)")
+	interval := fs.Duration("interval", 30*time.Second, "time between fetches")
+	count := fs.Int("count", 0, "stop after this many fetches; 0 watches until interrupted")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: converter watch [-source rates.json|URL] [-base USD] [-interval 30s] USD/NGN [EUR/NGN ...]")
+		fs.PrintDefaults()
+	}
+	pairArgs, err := parseInterspersed(fs, args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if len(pairArgs) == 0 || *source == "" || *interval <= 0 || *count < 0 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	provider, err := sourceProvider(*source, *base)
+	if err != nil {
+		fmt.Fprintf(stderr, "converter watch: %v\n", err)
+		return exitUsage
+	}
+	w := &watcher{provider: provider, base: *base}
+	for _, arg := range pairArgs {
+		from, to, ok := strings.Cut(arg, "/")
+		if !ok || from == "" || to == "" {
+			fmt.Fprintf(stderr, "converter watch: %v: %q\n", converter.ErrInvalidPair, arg)
+			return exitUsage
+		}
+		w.pairs = append(w.pairs, [2]string{strings.ToUpper(from), strings.ToUpper(to)})
+	}
+	if f, ok := stdout.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		w.live = true
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	w.run(ctx, *interval, *count, stdout)
+	return exitOK
+}
+
+// parseInterspersed parses flags anywhere among the positional arguments,
+// as in "watch USD/NGN -interval 30s", and returns the positional ones.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// sourceProvider fetches rates from a JSON feed for http and https URLs,
+// generates them against base for synthetic sources, else reads them from
+// a rate file, read again on every fetch.
+func sourceProvider(source, base string) (converter.RateProvider, error) {
+	if strings.HasPrefix(source, syntheticPrefix) {
+		return syntheticProvider(source, base, time.Now().UnixNano())
+	}
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		return converter.RateProviderFunc(func(context.Context) ([]converter.Currency, error) {
+			return converter.NewCurrenciesFromFile(source)
+		}), nil
+	}
+	return converter.RateProviderFunc(func(ctx context.Context) ([]converter.Currency, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("fetch %s: %s", source, resp.Status)
+		}
+		return converter.NewCurrenciesFromJSON(io.LimitReader(resp.Body, 10<<20))
+	}), nil
+}
+
+// watcher prints a line of pair rates per fetch, marking each rate's
+// change since the previous fetch.
+type watcher struct {
+	provider converter.RateProvider
+	base     string
+	pairs    [][2]string
+	// live rewrites one line in place instead of printing a line per
+	// fetch, for terminals.
+	live  bool
+	clock converter.Clock
+	last  map[[2]string]decimal.Decimal
+}
+
+// run fetches every interval until ctx is done or count fetches are made,
+// if count is positive.
+func (w *watcher) run(ctx context.Context, interval time.Duration, count int, out io.Writer) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	if w.live {
+		defer fmt.Fprintln(out)
+	}
+
+	for n := 1; ; n++ {
+		w.tick(ctx, out)
+		if count > 0 && n >= count {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// tick fetches the rates once and prints them. A failed fetch prints the
+// error and keeps the previous rates for comparison.
+func (w *watcher) tick(ctx context.Context, out io.Writer) {
+	clock := w.clock
+	if clock == nil {
+		clock = converter.SystemClock
+	}
+	line := clock.Now().Format("15:04:05")
+
+	currencies, err := w.provider.FetchRates(ctx)
+	if err != nil {
+		line += "  error: " + err.Error()
+	} else {
+		if w.last == nil {
+			w.last = map[[2]string]decimal.Decimal{}
+		}
+		for _, pair := range w.pairs {
+			line += "  " + pair[0] + "/" + pair[1] + " " + w.rate(currencies, pair)
+		}
+	}
+
+	if w.live {
+		fmt.Fprint(out, "\r\x1b[K"+line)
+	} else {
+		fmt.Fprintln(out, line)
+	}
+}
+
+// rate formats a pair's rate with its change since the last fetch: ↑ or ↓
+// and the percent movement, or = if unchanged.
+func (w *watcher) rate(currencies []converter.Currency, pair [2]string) string {
+	rate, err := converter.CalculateRate(currencies, w.base, pair[0], pair[1])
+	if err != nil {
+		return "error: " + err.Error()
+	}
+	previous, seen := w.last[pair]
+	w.last[pair] = rate
+	if !seen {
+		return rate.String()
+	}
+
+	switch rate.Cmp(previous) {
+	case 0:
+		return rate.String() + " ="
+	case 1:
+		return fmt.Sprintf("%s ↑ +%s%%", rate, percent(previous, rate))
+	default:
+		return fmt.Sprintf("%s ↓ %s%%", rate, percent(previous, rate))
+	}
+}
+
+// percent returns the movement from old to new in percent, to two places.
+func percent(old, new decimal.Decimal) string {
+	if old.IsZero() {
+		return "∞"
+	}
+	return new.Sub(old).Mul(decimal.NewFromInt(100)).DivRound(old, 2).StringFixed(2)
+}
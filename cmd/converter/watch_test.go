@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/otyang/converter"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+func TestWatcher(t *testing.T) {
+	sells := []int64{1500, 1500, 0, 1530, 1515}
+	fetches := 0
+	provider := converter.RateProviderFunc(func(context.Context) ([]converter.Currency, error) {
+		if fetches == 2 {
+			fetches++
+			return nil, errors.New("feed down")
+		}
+		sell := sells[min(fetches, len(sells)-1)]
+		fetches++
+		return []converter.Currency{
+			{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+			{ISOCode: "NGN", Precision: 2, BuyRate: decimal.NewFromInt(1550), SellRate: decimal.NewFromInt(sell)},
+		}, nil
+	})
+
+	w := &watcher{
+		provider: provider,
+		base:     "USD",
+		pairs:    [][2]string{{"USD", "NGN"}, {"USD", "GBP"}},
+		clock:    fixedClock(time.Date(2024, 3, 28, 12, 0, 30, 0, time.UTC)),
+	}
+	var out bytes.Buffer
+	w.run(context.Background(), time.Millisecond, 5, &out)
+	assert.Equal(t, `12:00:30  USD/NGN 1500  USD/GBP error: currency GBP not found
+12:00:30  USD/NGN 1500 =  USD/GBP error: currency GBP not found
+12:00:30  error: feed down
+12:00:30  USD/NGN 1530 ↑ +2.00%  USD/GBP error: currency GBP not found
+12:00:30  USD/NGN 1515 ↓ -0.98%  USD/GBP error: currency GBP not found
+`, out.String())
+
+	out.Reset()
+	w.live = true
+	w.pairs = w.pairs[:1]
+	w.run(context.Background(), time.Millisecond, 1, &out)
+	assert.Equal(t, "\r\x1b[K12:00:30  USD/NGN 1515 =\n", out.String())
+}
+
+func TestWatchCommand(t *testing.T) {
+	rates := writeRates(t, `[
+		{"isoCode": "USD", "precision": 2, "buyRate": "1", "sellRate": "1"},
+		{"isoCode": "NGN", "precision": 2, "buyRate": "1550", "sellRate": "1500"}
+	]`)
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitOK, run([]string{"watch", "usd/ngn", "-source", rates, "-count", "2", "-interval", "1ms"}, &stdout, &stderr))
+	assert.Regexp(t, `^\d\d:\d\d:\d\d  USD/NGN 1500\n\d\d:\d\d:\d\d  USD/NGN 1500 =\n$`, stdout.String())
+
+	feed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rates" {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeFile(w, r, rates)
+	}))
+	defer feed.Close()
+
+	stdout.Reset()
+	assert.Equal(t, exitOK, run([]string{"watch", "-source", feed.URL + "/rates", "-count", "1", "NGN/USD"}, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), "NGN/USD 0.0006451612903226\n")
+
+	stdout.Reset()
+	assert.Equal(t, exitOK, run([]string{"watch", "-source", feed.URL + "/missing", "-count", "1", "USD/NGN"}, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), "error: fetch "+feed.URL+"/missing: 404 Not Found\n")
+}
+
+func TestWatchCommandUsage(t *testing.T) {
+	t.Setenv("CONVERTER_SOURCE", "")
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitUsage, run([]string{"watch", "USD/NGN"}, &stdout, &stderr))
+	assert.Equal(t, exitUsage, run([]string{"watch", "-source", "rates.json"}, &stdout, &stderr))
+	assert.Equal(t, exitUsage, run([]string{"watch", "-source", "rates.json", "-interval", "0s", "USD/NGN"}, &stdout, &stderr))
+	assert.Equal(t, exitUsage, run([]string{"watch", "-source", "rates.json", "USDNGN"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), `invalid currency pair: "USDNGN"`)
+	assert.Equal(t, exitUsage, run([]string{"watch", "-bogus"}, &stdout, &stderr))
+}
@@ -0,0 +1,171 @@
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Compliance errors
+var (
+	ErrBlocked = errors.New("blocked by compliance")
+)
+
+// BlockReason is the reason code of a compliance block.
+type BlockReason string
+
+// Block reasons
+const (
+	ReasonSanctions  BlockReason = "sanctions"
+	ReasonRegulatory BlockReason = "regulatory"
+	ReasonRisk       BlockReason = "risk"
+	ReasonLicensing  BlockReason = "licensing"
+)
+
+// Block describes why a currency or corridor is blocked.
+type Block struct {
+	Reason BlockReason `json:"reason"`
+	// Note is free text for reviewers, e.g. a case reference.
+	Note string `json:"note,omitempty"`
+}
+
+// ComplianceError reports a quote refused by compliance. It matches
+// ErrBlocked.
+type ComplianceError struct {
+	// Pair is the refused quote's pair, e.g. "USD/RUB".
+	Pair string
+	// Blocked is the blocked currency or corridor, e.g. "RUB".
+	Blocked string
+	Block   Block
+}
+
+// Error implements error.
+func (e *ComplianceError) Error() string {
+	msg := fmt.Sprintf("%v: %s (%s blocked for %s)", ErrBlocked, e.Pair, e.Blocked, e.Block.Reason)
+	if e.Block.Note != "" {
+		msg += ": " + e.Block.Note
+	}
+	return msg
+}
+
+// Is makes every ComplianceError match ErrBlocked.
+func (e *ComplianceError) Is(target error) bool {
+	return target == ErrBlocked
+}
+
+// Compliance holds blocked currencies and corridors. Quotes involving a
+// blocked currency, or between the two currencies of a blocked corridor in
+// either direction, are refused with a *ComplianceError. A Compliance is safe
+// for concurrent use.
+type Compliance struct {
+	// OnEvent, if set, receives an EventQuoteBlocked per refused quote.
+	OnEvent EventHandler
+	// Clock defaults to SystemClock.
+	Clock Clock
+
+	mu         sync.RWMutex
+	currencies map[string]Block
+	pairs      map[string]Block
+}
+
+// NewCompliance creates a compliance layer blocking nothing.
+func NewCompliance() *Compliance {
+	return &Compliance{
+		Clock:      SystemClock,
+		currencies: map[string]Block{},
+		pairs:      map[string]Block{},
+	}
+}
+
+// BlockCurrency blocks every quote involving a currency.
+func (c *Compliance) BlockCurrency(code string, block Block) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.currencies[strings.ToUpper(code)] = block
+}
+
+// UnblockCurrency lifts the block of a currency.
+func (c *Compliance) UnblockCurrency(code string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.currencies, strings.ToUpper(code))
+}
+
+// BlockPair blocks quotes between the currencies of a pair such as
+// "USD/RUB", in either direction.
+func (c *Compliance) BlockPair(pair string, block Block) error {
+	from, to, err := parsePair(pair)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pairs[pairKey(from, to)] = block
+	return nil
+}
+
+// UnblockPair lifts the block of a pair.
+func (c *Compliance) UnblockPair(pair string) error {
+	from, to, err := parsePair(pair)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.pairs, pairKey(from, to))
+	return nil
+}
+
+// Check returns a *ComplianceError if a quote from one currency to another
+// is blocked, emitting an EventQuoteBlocked.
+func (c *Compliance) Check(from, to string) error {
+	clock := c.Clock
+	if clock == nil {
+		clock = SystemClock
+	}
+	return c.check(from, to, clock.Now())
+}
+
+func (c *Compliance) check(from, to string, at time.Time) error {
+	err := c.blocked(from, to)
+	if err == nil {
+		return nil
+	}
+	if c.OnEvent != nil {
+		c.OnEvent(Event{Type: EventQuoteBlocked, Time: at, Pair: err.Pair, Reason: string(err.Block.Reason)})
+	}
+	return err
+}
+
+func (c *Compliance) blocked(from, to string) *ComplianceError {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	pair := from + "/" + to
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if block, ok := c.pairs[pairKey(from, to)]; ok {
+		return &ComplianceError{Pair: pair, Blocked: pairKey(from, to), Block: block}
+	}
+	for _, code := range []string{from, to} {
+		if block, ok := c.currencies[code]; ok {
+			return &ComplianceError{Pair: pair, Blocked: code, Block: block}
+		}
+	}
+	return nil
+}
+
+// WithCompliance makes the table refuse quotes blocked by c.
+func WithCompliance(c *Compliance) TableOption {
+	return func(t *Currencies) {
+		t.compliance = c
+	}
+}
@@ -0,0 +1,56 @@
+package converter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompliance_Check(t *testing.T) {
+	compliance := NewCompliance()
+	compliance.BlockCurrency("rub", Block{Reason: ReasonSanctions, Note: "case 42"})
+	assert.NoError(t, compliance.BlockPair("USD/NGN", Block{Reason: ReasonLicensing}))
+
+	err := compliance.Check("EUR", "RUB")
+	assert.ErrorIs(t, err, ErrBlocked)
+	var blocked *ComplianceError
+	assert.ErrorAs(t, err, &blocked)
+	assert.Equal(t, "EUR/RUB", blocked.Pair)
+	assert.Equal(t, "RUB", blocked.Blocked)
+	assert.Equal(t, ReasonSanctions, blocked.Block.Reason)
+	assert.EqualError(t, err, "blocked by compliance: EUR/RUB (RUB blocked for sanctions): case 42")
+
+	// Corridors are blocked in both directions.
+	err = compliance.Check("ngn", "usd")
+	assert.ErrorAs(t, err, &blocked)
+	assert.Equal(t, "NGN/USD", blocked.Pair)
+	assert.Equal(t, "NGN/USD", blocked.Blocked)
+	assert.NoError(t, compliance.Check("EUR", "NGN"))
+
+	compliance.UnblockCurrency("RUB")
+	assert.NoError(t, compliance.UnblockPair("NGN/USD"))
+	assert.NoError(t, compliance.Check("EUR", "RUB"))
+	assert.NoError(t, compliance.Check("USD", "NGN"))
+
+	assert.ErrorIs(t, compliance.BlockPair("USD", Block{}), ErrInvalidPair)
+	assert.ErrorIs(t, compliance.UnblockPair("USD"), ErrInvalidPair)
+}
+
+func TestWithCompliance(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	compliance := NewCompliance()
+	compliance.BlockCurrency("NGN", Block{Reason: ReasonRegulatory})
+	var events []Event
+	compliance.OnEvent = func(e Event) { events = append(events, e) }
+	table := NewTable(testCurrencies(), WithCompliance(compliance))
+
+	_, err := table.NewQuote("USD", "USD", "NGN", decimal.NewFromInt(100), decimal.Zero, WithClock(fixedClock(now)))
+	assert.ErrorIs(t, err, ErrBlocked)
+	assert.Equal(t, []Event{{Type: EventQuoteBlocked, Time: now, Pair: "USD/NGN", Reason: "regulatory"}}, events)
+
+	_, err = table.NewQuote("USD", "USD", "EUR", decimal.NewFromInt(100), decimal.Zero, WithClock(fixedClock(now)))
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+}
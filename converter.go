@@ -1,10 +1,13 @@
 package converter
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
+	"reflect"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -15,36 +18,123 @@ var (
 	ErrCurrencyNotFound     = "currency %s not found"
 	ErrEmptyCurrencySource  = errors.New("empty currency source: no rates or currency")
 	ErrBaseCurrencyNotFound = errors.New("base currency not found")
+	ErrInvalidRate          = errors.New("invalid rate")
+	ErrInvalidPrecision     = errors.New("invalid precision")
+	ErrMissingISOCode       = errors.New("currency has no ISO code")
 )
 
+// maxDigits bounds the integer and fractional digits of ingested numbers and
+// the precision of currencies. Values such as 1e2000000000 parse fine but make
+// every later operation allocate gigabytes.
+const maxDigits = 64
+
 // Currency structure
 type Currency struct {
 	ISOCode   string          `json:"isoCode"`
 	Precision int             `json:"precision"`
 	BuyRate   decimal.Decimal `json:"buyRate"`
 	SellRate  decimal.Decimal `json:"sellRate"`
+	// BaseISOCode, if set, is the currency the rates are per unit of, e.g.
+	// "EUR" for a GBP entry from a EUR-based feed. Empty means the base
+	// currency passed when calculating rates. See CalculateRate.
+	BaseISOCode string `json:"baseIsoCode,omitempty"`
+}
+
+// Validate checks that the currency's precision is within range and that its
+// rates are non-negative numbers of sane magnitude.
+func (c Currency) Validate() error {
+	if c.Precision < 0 || c.Precision > maxDigits {
+		return fmt.Errorf("%w: %s has precision %d", ErrInvalidPrecision, c.ISOCode, c.Precision)
+	}
+	for _, rate := range []decimal.Decimal{c.BuyRate, c.SellRate} {
+		// The rate is not formatted: printing an oversized value is exactly
+		// the work the check is meant to avoid.
+		if !saneDecimal(rate) {
+			return fmt.Errorf("%w: %s has a rate with exponent %d", ErrInvalidRate, c.ISOCode, rate.Exponent())
+		}
+		if rate.IsNegative() {
+			return fmt.Errorf("%w: %s has a negative rate", ErrInvalidRate, c.ISOCode)
+		}
+	}
+	return nil
+}
+
+// saneDecimal reports whether d has few enough integer and fractional digits
+// to compute with.
+func saneDecimal(d decimal.Decimal) bool {
+	exp := int64(d.Exponent())
+	return exp >= -maxDigits && int64(d.NumDigits())+exp <= maxDigits
 }
 
 // NewCurrencies creates a Currencies instance from a source of rates.
+//
+// If T is a struct with converter tags (see TagKey), the tagged fields are
+// read directly. Otherwise rates are decoded from their JSON text (see
+// Currency.UnmarshalJSON), so sources carrying rates as json.Number, strings
+// or decimals keep every digit. A float64 field in the source has already been
+// rounded to about 17 significant digits before NewCurrencies sees it; decode
+// provider feeds with json.Decoder.UseNumber to avoid that.
+//
+// An entry without an ISO code, usually a source whose field names do not
+// match, is an error wrapping ErrMissingISOCode.
 func NewCurrencies[T any](sourceRates []T) ([]Currency, error) {
-	b, err := json.Marshal(sourceRates)
+	fields, err := taggedFields(reflect.TypeOf((*T)(nil)).Elem())
 	if err != nil {
 		return nil, err
 	}
 
 	var currencies []Currency
-	if err := json.Unmarshal(b, &currencies); err != nil {
+	if fields != nil {
+		currencies, err = currenciesFromTagged(sourceRates, fields)
+	} else {
+		currencies, err = currenciesFromJSON(sourceRates)
+	}
+	if err != nil {
 		return nil, err
 	}
 
+	if err := checkIngested(currencies); err != nil {
+		return nil, err
+	}
+	return currencies, nil
+}
+
+// checkIngested checks that a source yielded currencies, each with an ISO code
+// and valid.
+func checkIngested(currencies []Currency) error {
 	if len(currencies) == 0 {
-		return nil, ErrEmptyCurrencySource
+		return ErrEmptyCurrencySource
 	}
 
-	return currencies, nil
+	for i, c := range currencies {
+		if strings.TrimSpace(c.ISOCode) == "" {
+			return fmt.Errorf("%w: entry %d", ErrMissingISOCode, i)
+		}
+		if err := c.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CurrencyNotFoundError reports a currency missing from a table. Its message
+// is formatted with ErrCurrencyNotFound and it matches ErrUnknownCurrency.
+type CurrencyNotFoundError struct {
+	Code string
+}
+
+// Error implements error.
+func (e *CurrencyNotFoundError) Error() string {
+	return fmt.Sprintf(ErrCurrencyNotFound, e.Code)
+}
+
+// Is makes every CurrencyNotFoundError match ErrUnknownCurrency.
+func (e *CurrencyNotFoundError) Is(target error) bool {
+	return target == ErrUnknownCurrency
 }
 
-// FindCurrency finds a currency by its ISO code.
+// FindCurrency finds a currency by its ISO code. A missing currency is a
+// *CurrencyNotFoundError.
 func FindCurrency(currencies []Currency, code string) (*Currency, error) {
 	if len(currencies) == 0 {
 		return nil, ErrEmptyCurrencySource
@@ -56,7 +146,7 @@ func FindCurrency(currencies []Currency, code string) (*Currency, error) {
 		}
 	}
 
-	return nil, fmt.Errorf(ErrCurrencyNotFound, code)
+	return nil, &CurrencyNotFoundError{Code: code}
 }
 
 // CalculateRate calculates the exchange rate between two currencies.
@@ -79,74 +169,273 @@ func FindCurrency(currencies []Currency, code string) (*Currency, error) {
 //   - from 	(you have/source) 	= another currency
 //   - to 		(you want/target) 	= another currency
 //   - Rate: 	[Target to Base of: from] * [Base to Target of: to]
+//
+// Currencies carrying their own BaseISOCode are routed across bases: each
+// currency's rates are multiplied along its chain of bases (a currency
+// without one is per baseCurrency) and the rate is the sell rate of to over
+// the buy rate of from, both so chained. A GBP entry per EUR and a EUR entry
+// per USD thus price USD to GBP as GBP sell * EUR sell.
+//
+// Same-currency conversions are answered without looking at currencies,
+// and conversions from the base look up only the target, so the base need
+// not be listed for them.
+//
+// Divisions use shopspring/decimal's global DivisionPrecision. A Currencies
+// table created with WithDivision uses its own precision and rounding.
 func CalculateRate(currencies []Currency, baseCurrency, from, to string) (decimal.Decimal, error) {
-	baseCurrency = strings.ToUpper(baseCurrency)
-	from = strings.ToUpper(from)
-	to = strings.ToUpper(to)
+	return calculateRate(currencies, baseCurrency, from, to, nil)
+}
 
-	// Same Currency Conversion
-	if from == to {
-		return decimal.NewFromInt(1), nil
+// calculateRate implements CalculateRate, dividing with div.
+func calculateRate(currencies []Currency, baseCurrency, from, to string, div *Division) (decimal.Decimal, error) {
+	sell, buy, err := ratePath(currencies, baseCurrency, from, to)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if isOne(buy) {
+		return sell, nil
 	}
 
-	_, err := FindCurrency(currencies, baseCurrency)
-	if err != nil {
-		return decimal.Zero, ErrBaseCurrencyNotFound
+	// (target to base) to target
+	return divide(div, decimalOne, buy).Mul(sell), nil
+}
+
+// ratePath resolves the rate from one currency to another as sell / buy,
+// following the rules documented on CalculateRate. A buy of 1 means no
+// division is needed.
+func ratePath(currencies []Currency, baseCurrency, from, to string) (sell, buy decimal.Decimal, err error) {
+	// Same Currency Conversion, before looking at the table at all
+	if strings.EqualFold(from, to) {
+		return decimalOne, decimalOne, nil
 	}
 
-	// Base to Target Currency (Sell Rate)
+	if hasOwnBases(currencies) {
+		return crossBasePath(currencies, baseCurrency, from, to)
+	}
+
+	baseCurrency = strings.ToUpper(baseCurrency)
+	from = strings.ToUpper(from)
+	to = strings.ToUpper(to)
+
+	// Base to Target Currency (Sell Rate). Only the target is looked up:
+	// its sell rate is all the conversion needs from the base.
 	if from == baseCurrency {
 		toCurrency, err := FindCurrency(currencies, to)
 		if err != nil {
-			return decimal.Zero, err
+			return decimal.Zero, decimal.Zero, err
 		}
 
-		return toCurrency.SellRate, nil
+		return toCurrency.SellRate, decimalOne, nil
 	}
 
-	// Target to Base Currency (Buy Rate)
-	if to == baseCurrency {
-		fromCurrency, err := FindCurrency(currencies, from)
-		if err != nil {
-			return decimal.Zero, err
-		}
-		return decimal.NewFromInt(1).Div(fromCurrency.BuyRate), nil
+	_, err = FindCurrency(currencies, baseCurrency)
+	if err != nil {
+		return decimal.Zero, decimal.Zero, ErrBaseCurrencyNotFound
 	}
 
-	// Cross Rate Conversion
 	fromCurrency, err := FindCurrency(currencies, from)
 	if err != nil {
-		return decimal.Zero, err
+		return decimal.Zero, decimal.Zero, err
+	}
+	if !fromCurrency.BuyRate.IsPositive() {
+		return decimal.Zero, decimal.Zero, fmt.Errorf("%w: %s buy rate is not positive", ErrInvalidRate, fromCurrency.ISOCode)
 	}
+
+	// Target to Base Currency (Buy Rate)
+	if to == baseCurrency {
+		return decimalOne, fromCurrency.BuyRate, nil
+	}
+
+	// Cross Rate Conversion
 	toCurrency, err := FindCurrency(currencies, to)
 	if err != nil {
-		return decimal.Zero, err
+		return decimal.Zero, decimal.Zero, err
 	}
 
-	// (target to base) to target
-	return (decimal.NewFromInt(1).Div(fromCurrency.BuyRate)).Mul(toCurrency.SellRate), nil
+	return toCurrency.SellRate, fromCurrency.BuyRate, nil
 }
 
 // Quote structure
 type Quote struct {
+	ID             string          `json:"id"`
+	Reference      string          `json:"reference"`
 	BaseCurrency   string          `json:"baseCurrency"`
 	FromCurrency   string          `json:"fromCurrency"`
 	FromAmount     decimal.Decimal `json:"fromAmount"`
 	Fee            decimal.Decimal `json:"fee"`
+	FeeMode        FeeMode         `json:"feeMode,omitempty"`
+	FeeCurrency    string          `json:"feeCurrency,omitempty"`
 	AmountToDeduct decimal.Decimal `json:"amountToDeduct"`
 	Rate           decimal.Decimal `json:"rate"`
+	Spread         decimal.Decimal `json:"spread"`
 	ToCurrency     string          `json:"toCurrency"`
 	FinalAmount    decimal.Decimal `json:"totalAmount"`
-	Date           time.Time       `json:"date"`
+	// FromPrecision and ToPrecision are the decimal places the amounts of
+	// each currency were rounded to when the quote was priced, which its
+	// amendments and executions keep.
+	FromPrecision int          `json:"fromPrecision"`
+	ToPrecision   int          `json:"toPrecision"`
+	Date          time.Time    `json:"date"`
+	ExpiresAt     time.Time    `json:"expiresAt"`
+	ValueDate     time.Time    `json:"valueDate"`
+	Status        QuoteStatus  `json:"status"`
+	CancelReason  CancelReason `json:"cancelReason,omitempty"`
+	CustomerRef   string       `json:"customerRef,omitempty"`
+	Flags         []QuoteFlag  `json:"flags,omitempty"`
+	// Send and Receive present the quote from the sender's and the
+	// recipient's side, and EffectiveRate is the all-in rate: Receive's
+	// amount per unit of Send's, fees included.
+	Send          QuoteSide       `json:"send"`
+	Receive       QuoteSide       `json:"receive"`
+	EffectiveRate decimal.Decimal `json:"effectiveRate"`
+}
+
+// IsExpired reports whether the quote has an expiry time that has passed.
+func (q *Quote) IsExpired(now time.Time) bool {
+	return !q.ExpiresAt.IsZero() && !now.Before(q.ExpiresAt)
+}
+
+// QuoteStatus is the lifecycle state of a quote.
+type QuoteStatus string
+
+// Quote statuses
+const (
+	QuoteStatusPending   QuoteStatus = "pending"
+	QuoteStatusAccepted  QuoteStatus = "accepted"
+	QuoteStatusExecuted  QuoteStatus = "executed"
+	QuoteStatusExpired   QuoteStatus = "expired"
+	QuoteStatusCancelled QuoteStatus = "cancelled"
+)
+
+// QuoteOption configures optional behaviour of NewQuote.
+type QuoteOption func(*quoteOptions)
+
+type quoteOptions struct {
+	idGenerator IDGenerator
+	customerRef string
+	ttl         time.Duration
+	clock       Clock
+	division    *Division
+	exact       *Division
+	bounds      *Bounds
+	calendar    *Calendar
+	review      *ReviewThresholds
+	tier        *Tier
+	fees        *FeeSchedule
+	feeRounds   *Division
+	spread      decimal.Decimal
+	flags       []QuoteFlag
+	feeMode     FeeMode
+	pinned      *decimal.Decimal
+}
+
+var defaultIDGenerator IDGenerator = NewULIDGenerator(nil)
+
+// WithIDGenerator sets the generator used for the quote ID.
+func WithIDGenerator(g IDGenerator) QuoteOption {
+	return func(o *quoteOptions) {
+		if g != nil {
+			o.idGenerator = g
+		}
+	}
+}
+
+// WithCustomerRef attaches the caller's customer reference to the quote.
+func WithCustomerRef(ref string) QuoteOption {
+	return func(o *quoteOptions) {
+		o.customerRef = ref
+	}
+}
+
+// WithTTL sets how long the quote stays valid. Quotes without a TTL never expire.
+func WithTTL(ttl time.Duration) QuoteOption {
+	return func(o *quoteOptions) {
+		o.ttl = ttl
+	}
+}
+
+// WithClock sets the clock used to date the quote. It defaults to SystemClock.
+func WithClock(c Clock) QuoteOption {
+	return func(o *quoteOptions) {
+		o.clock = c
+	}
+}
+
+// WithCalendar sets the quote's ValueDate to the day it settles on in cal:
+// the trading day it books on for its two currencies, rolled forward past
+// non-trading days and daily cut-offs (see Calendar.TradeDate), then moved on
+// by the pair's settlement convention (see Calendar.SettlementDate).
+func WithCalendar(cal *Calendar) QuoteOption {
+	return func(o *quoteOptions) {
+		o.calendar = cal
+	}
+}
+
+// quoteOptionsPool recycles quote options, which escape to the heap through
+// the QuoteOption closures. Nothing in them outlives the quote's creation.
+var quoteOptionsPool = sync.Pool{New: func() any { return new(quoteOptions) }}
+
+// getQuoteOptions returns pooled options set to a quote's defaults, before
+// any QuoteOption is applied. Return them with putQuoteOptions.
+func getQuoteOptions() *quoteOptions {
+	o := quoteOptionsPool.Get().(*quoteOptions)
+	*o = quoteOptions{idGenerator: defaultIDGenerator, clock: SystemClock, feeMode: FeeAdditive}
+	return o
+}
+
+// putQuoteOptions returns options to the pool.
+func putQuoteOptions(o *quoteOptions) {
+	*o = quoteOptions{}
+	quoteOptionsPool.Put(o)
 }
 
 // NewQuote creates a new quote object.
-func NewQuote(rateSource []Currency, baseCurrency, fromCurrency, toCurrency string, fromAmount, fee decimal.Decimal) (*Quote, error) {
+func NewQuote(rateSource []Currency, baseCurrency, fromCurrency, toCurrency string, fromAmount, fee decimal.Decimal, opts ...QuoteOption) (*Quote, error) {
+	options := getQuoteOptions()
+	defer putQuoteOptions(options)
+	for _, opt := range opts {
+		opt(options)
+	}
+	return newQuote(rateSource, baseCurrency, fromCurrency, toCurrency, fromAmount, fee, options)
+}
+
+// newQuote implements NewQuote once its options are applied.
+func newQuote(rateSource []Currency, baseCurrency, fromCurrency, toCurrency string, fromAmount, fee decimal.Decimal, options *quoteOptions) (*Quote, error) {
 	if rateSource == nil {
 		return nil, errors.New("currency object empty. shouldnt be")
 	}
 
-	rate, err := CalculateRate(rateSource, baseCurrency, fromCurrency, toCurrency)
+	if err := validateAmount("amount", fromAmount); err != nil {
+		return nil, err
+	}
+	if err := validateAmount("fee", fee); err != nil {
+		return nil, err
+	}
+	if !options.feeMode.valid() {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidFeeMode, options.feeMode)
+	}
+
+	var (
+		rate      decimal.Decimal
+		exactRate *big.Rat
+		err       error
+	)
+	switch {
+	case options.pinned != nil:
+		rate = *options.pinned
+		if options.exact != nil {
+			exactRate = rate.Rat()
+		}
+	case options.exact != nil:
+		exactRate, err = CalculateRateExact(rateSource, baseCurrency, fromCurrency, toCurrency)
+		if err == nil {
+			exactRate = applySpreadExact(exactRate, options.spread)
+			rate = RatToDecimal(exactRate, *options.exact)
+		}
+	default:
+		rate, err = calculateRate(rateSource, baseCurrency, fromCurrency, toCurrency, options.division)
+		rate = applySpread(rate, options.spread)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -161,15 +450,142 @@ func NewQuote(rateSource []Currency, baseCurrency, fromCurrency, toCurrency stri
 		return nil, err
 	}
 
+	for _, info := range []*Currency{infoFrom, infoTo} {
+		if err := info.Validate(); err != nil {
+			return nil, err
+		}
+		if options.bounds != nil {
+			if err := options.bounds.CheckCurrency(*info); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if options.bounds != nil {
+		converted := fromAmount.Mul(rate)
+		if exactRate != nil {
+			converted = RatToDecimal(new(big.Rat).Mul(fromAmount.Rat(), exactRate), Division{Precision: int32(infoTo.Precision) + 1, Rounding: RoundDown})
+		}
+		if err := options.bounds.checkQuote(rate, fromAmount, converted, infoFrom, infoTo); err != nil {
+			return nil, err
+		}
+	}
+
+	// Zero- and three-decimal currencies are quoted in their ISO minor
+	// units whatever precision the feed gave them.
+	from, to := isoPrecision(*infoFrom), isoPrecision(*infoTo)
+	infoFrom, infoTo = &from, &to
+
+	// Zero-decimal currencies such as JPY are only ever quoted in whole
+	// units, fee included.
+	if infoFrom.Precision == 0 {
+		if err := infoFrom.CheckAmount(fromAmount); err != nil {
+			return nil, err
+		}
+	}
+	feeCurrency := infoFrom
+	if options.feeMode == FeeInDestination {
+		feeCurrency = infoTo
+	}
+	sourceFees, fees := options.feeRounding(infoFrom, feeCurrency)
+	fee = fees.round(fee)
+
+	if options.tier != nil {
+		infoBase, err := FindCurrency(rateSource, baseCurrency)
+		if err != nil {
+			return nil, err
+		}
+		if err := options.tier.check(fromAmount, infoFrom, infoBase); err != nil {
+			return nil, err
+		}
+		if options.tier.Fees != nil {
+			options.fees = options.tier.Fees
+		}
+	}
+	if options.fees != nil {
+		fee = options.fees.fee(fromAmount, infoFrom, sourceFees)
+		if options.feeMode == FeeInDestination {
+			fee = fees.round(fee.Mul(rate))
+		}
+	}
+
+	now := options.clock.Now()
+	id := options.idGenerator.NewID(now)
+
+	var expiresAt time.Time
+	if options.ttl > 0 {
+		expiresAt = now.Add(options.ttl)
+	}
+
+	var valueDate time.Time
+	if options.calendar != nil {
+		tradeDate, err := options.calendar.TradeDate(now, infoFrom.ISOCode, infoTo.ISOCode)
+		if err != nil {
+			return nil, err
+		}
+		valueDate, err = options.calendar.settlementDate(infoFrom.ISOCode, infoTo.ISOCode, tradeDate)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	flags := slices.Clone(options.flags)
+	if options.review != nil && options.review.exceeded(fromAmount, infoFrom, infoTo) {
+		flags = append(flags, FlagReviewRequired)
+	}
+
+	// The amount deducted is the rounded principal plus the rounded fee,
+	// exactly.
+	converted, amountToDeduct := fromAmount, fromAmount.RoundCeil(int32(infoFrom.Precision))
+	if options.feeMode == FeeAdditive {
+		amountToDeduct = amountToDeduct.Add(fee)
+	}
+	if options.feeMode == FeeDeducted {
+		converted = fromAmount.Sub(fee)
+		if converted.IsNegative() {
+			return nil, fmt.Errorf("%w: fee %s %s is above the amount", ErrFeeExceedsAmount, fee, infoFrom.ISOCode)
+		}
+	}
+
+	finalAmount := converted.Mul(rate).RoundCeil(int32(infoTo.Precision))
+	if exactRate != nil {
+		exactAmount := new(big.Rat).Mul(converted.Rat(), exactRate)
+		finalAmount = RatToDecimal(exactAmount, Division{Precision: int32(infoTo.Precision), Rounding: RoundCeiling})
+	}
+
+	if options.feeMode == FeeInDestination {
+		finalAmount = finalAmount.Sub(fee)
+		if finalAmount.IsNegative() {
+			return nil, fmt.Errorf("%w: fee %s %s is above the converted amount", ErrFeeExceedsAmount, fee, infoTo.ISOCode)
+		}
+	}
+
+	send, receive, effectiveRate := quoteSides(infoFrom, infoTo, amountToDeduct, finalAmount, fee, rate, options.feeMode, options.division)
+
 	return &Quote{
+		ID:             id,
+		Reference:      QuoteReference(id),
 		BaseCurrency:   baseCurrency,
 		FromCurrency:   fromCurrency,
 		FromAmount:     fromAmount,
 		Fee:            fee,
-		AmountToDeduct: fromAmount.Add(fee).RoundCeil(int32(infoFrom.Precision)),
+		FeeMode:        options.feeMode,
+		FeeCurrency:    feeCurrency.ISOCode,
+		AmountToDeduct: amountToDeduct,
 		Rate:           rate,
+		Spread:         options.spread,
 		ToCurrency:     toCurrency,
-		FinalAmount:    fromAmount.Mul(rate).RoundCeil(int32(infoTo.Precision)),
-		Date:           time.Now(),
+		FinalAmount:    finalAmount,
+		FromPrecision:  infoFrom.Precision,
+		ToPrecision:    infoTo.Precision,
+		Date:           now,
+		ExpiresAt:      expiresAt,
+		ValueDate:      valueDate,
+		Status:         QuoteStatusPending,
+		CustomerRef:    options.customerRef,
+		Flags:          flags,
+		Send:           send,
+		Receive:        receive,
+		EffectiveRate:  effectiveRate,
 	}, nil
 }
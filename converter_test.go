@@ -56,7 +56,8 @@ func TestFindCurrency(t *testing.T) {
 	// Invalid code
 	_, err = FindCurrency(currencies, "GBP")
 	assert.Error(t, err)
-	assert.Equal(t, fmt.Errorf(ErrCurrencyNotFound, "GBP"), err)
+	assert.EqualError(t, err, fmt.Sprintf(ErrCurrencyNotFound, "GBP"))
+	assert.ErrorIs(t, err, ErrUnknownCurrency)
 
 	// Empty currency source
 	_, err = FindCurrency(nil, "USD")
@@ -194,3 +195,180 @@ func TestNewQuote_EmptyRateSource(t *testing.T) {
 	assert.Error(t, err)
 	assert.Nil(t, quote)
 }
+
+func TestNewQuote_StatusAndCustomerRef(t *testing.T) {
+	rateSource := []Currency{
+		{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+		{ISOCode: "EUR", Precision: 2, BuyRate: decimal.NewFromFloat(0.95), SellRate: decimal.NewFromFloat(0.95)},
+	}
+
+	quote, err := NewQuote(rateSource, "USD", "USD", "EUR", decimal.NewFromInt(100), decimal.Zero, WithCustomerRef("cust-42"))
+	assert.NoError(t, err)
+	assert.Equal(t, QuoteStatusPending, quote.Status)
+	assert.Equal(t, "cust-42", quote.CustomerRef)
+}
+
+func TestNewQuote_TTL(t *testing.T) {
+	rateSource := []Currency{
+		{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+		{ISOCode: "EUR", Precision: 2, BuyRate: decimal.NewFromFloat(0.95), SellRate: decimal.NewFromFloat(0.95)},
+	}
+
+	quote, err := NewQuote(rateSource, "USD", "USD", "EUR", decimal.NewFromInt(100), decimal.Zero)
+	assert.NoError(t, err)
+	assert.True(t, quote.ExpiresAt.IsZero())
+
+	quote, err = NewQuote(rateSource, "USD", "USD", "EUR", decimal.NewFromInt(100), decimal.Zero, WithTTL(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, quote.Date.Add(time.Minute), quote.ExpiresAt)
+}
+
+func TestNewQuote_Clock(t *testing.T) {
+	rateSource := []Currency{
+		{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+		{ISOCode: "EUR", Precision: 2, BuyRate: decimal.NewFromFloat(0.95), SellRate: decimal.NewFromFloat(0.95)},
+	}
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	quote, err := NewQuote(rateSource, "USD", "USD", "EUR", decimal.NewFromInt(100), decimal.Zero, WithClock(fixedClock(now)), WithTTL(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, now, quote.Date)
+	assert.Equal(t, now.Add(time.Minute), quote.ExpiresAt)
+}
+
+func TestNewQuote_Calendar(t *testing.T) {
+	rateSource := []Currency{
+		{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+		{ISOCode: "NGN", Precision: 2, BuyRate: decimal.NewFromInt(1500), SellRate: decimal.NewFromInt(1550)},
+	}
+	cal := NewCalendar()
+	cal.SetCutOff("NGN", CutOff{Hour: 15})
+	before := time.Date(2024, 3, 28, 14, 0, 0, 0, time.UTC)
+
+	quote, err := NewQuote(rateSource, "USD", "USD", "NGN", decimal.NewFromInt(100), decimal.Zero, WithClock(fixedClock(before)))
+	assert.NoError(t, err)
+	assert.True(t, quote.ValueDate.IsZero())
+
+	quote, err = NewQuote(rateSource, "USD", "USD", "NGN", decimal.NewFromInt(100), decimal.Zero, WithClock(fixedClock(before)), WithCalendar(cal))
+	assert.NoError(t, err)
+	assert.Equal(t, day(2024, 3, 28), quote.ValueDate)
+
+	quote, err = NewQuote(rateSource, "USD", "USD", "NGN", decimal.NewFromInt(100), decimal.Zero, WithClock(fixedClock(before.Add(time.Hour))), WithCalendar(cal))
+	assert.NoError(t, err)
+	assert.Equal(t, day(2024, 3, 29), quote.ValueDate)
+}
+
+func TestCurrency_Validate(t *testing.T) {
+	valid := Currency{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)}
+	assert.NoError(t, valid.Validate())
+
+	invalid := valid
+	invalid.Precision = -1
+	assert.ErrorIs(t, invalid.Validate(), ErrInvalidPrecision)
+
+	invalid = valid
+	invalid.Precision = 1000
+	assert.ErrorIs(t, invalid.Validate(), ErrInvalidPrecision)
+
+	invalid = valid
+	invalid.SellRate = decimal.NewFromInt(-1)
+	assert.ErrorIs(t, invalid.Validate(), ErrInvalidRate)
+
+	invalid = valid
+	invalid.BuyRate = decimal.New(1, 1000)
+	assert.ErrorIs(t, invalid.Validate(), ErrInvalidRate)
+
+	// NewCurrencies rejects invalid entries
+	_, err := NewCurrencies([]Currency{invalid})
+	assert.ErrorIs(t, err, ErrInvalidRate)
+}
+
+func TestCalculateRate_ZeroBuyRate(t *testing.T) {
+	currencies := []Currency{
+		{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+		{ISOCode: "EUR", Precision: 2, BuyRate: decimal.Zero, SellRate: decimal.NewFromFloat(0.95)},
+		{ISOCode: "NGN", Precision: 2, BuyRate: decimal.NewFromInt(1500), SellRate: decimal.NewFromInt(1550)},
+	}
+
+	// Dividing by a zero buy rate is an error, not a panic
+	_, err := CalculateRate(currencies, "USD", "EUR", "USD")
+	assert.ErrorIs(t, err, ErrInvalidRate)
+	_, err = CalculateRate(currencies, "USD", "EUR", "NGN")
+	assert.ErrorIs(t, err, ErrInvalidRate)
+}
+
+func BenchmarkCalculateRate(b *testing.B) {
+	currencies := testCurrencies()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := CalculateRate(currencies, "USD", "EUR", "NGN"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkNewQuote(b *testing.B) {
+	currencies := testCurrencies()
+	amount, fee := decimal.NewFromInt(100), decimal.NewFromInt(2)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := NewQuote(currencies, "USD", "EUR", "NGN", amount, fee); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestCalculateRate_Allocs(t *testing.T) {
+	currencies := testCurrencies()
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = CalculateRate(currencies, "USD", "EUR", "NGN")
+	})
+	// Guards the hot path: shared constants and comparisons that do not
+	// rescale leave only shopspring/decimal's own arithmetic allocating.
+	assert.LessOrEqual(t, allocs, float64(12))
+
+	// Direct rates divide nothing.
+	allocs = testing.AllocsPerRun(100, func() {
+		_, _ = CalculateRate(currencies, "USD", "USD", "NGN")
+	})
+	assert.Zero(t, allocs)
+}
+
+func TestCalculateRate_FastPaths(t *testing.T) {
+	// Same-currency conversions never look at the table.
+	rate, err := CalculateRate(nil, "USD", "ngn", "NGN")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", rate.String())
+
+	// From the base, only the target is looked up.
+	withoutBase := testCurrencies()[1:]
+	rate, err = CalculateRate(withoutBase, "USD", "USD", "NGN")
+	assert.NoError(t, err)
+	assert.Equal(t, "1550", rate.String())
+
+	// Other conversions still need the base.
+	_, err = CalculateRate(withoutBase, "USD", "NGN", "USD")
+	assert.ErrorIs(t, err, ErrBaseCurrencyNotFound)
+	_, err = CalculateRate(withoutBase, "USD", "EUR", "NGN")
+	assert.ErrorIs(t, err, ErrBaseCurrencyNotFound)
+}
+
+func BenchmarkCalculateRate_SameCurrency(b *testing.B) {
+	currencies := testCurrencies()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := CalculateRate(currencies, "USD", "NGN", "NGN"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCalculateRate_FromBase(b *testing.B) {
+	currencies := testCurrencies()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := CalculateRate(currencies, "USD", "USD", "NGN"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
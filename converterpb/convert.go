@@ -0,0 +1,168 @@
+// Package converterpb provides protobuf messages for converter quotes and
+// currencies, and functions converting between them and the core types.
+package converterpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative converter.proto
+
+import (
+	"github.com/otyang/converter"
+	"github.com/shopspring/decimal"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// CurrencyToProto converts a currency to its protobuf message.
+func CurrencyToProto(c converter.Currency) *Currency {
+	return &Currency{
+		IsoCode:     c.ISOCode,
+		Precision:   int32(c.Precision),
+		BuyRate:     c.BuyRate.String(),
+		SellRate:    c.SellRate.String(),
+		BaseIsoCode: c.BaseISOCode,
+	}
+}
+
+// CurrencyFromProto converts a protobuf message to a currency.
+func CurrencyFromProto(p *Currency) (converter.Currency, error) {
+	buyRate, err := parseDecimal(p.GetBuyRate())
+	if err != nil {
+		return converter.Currency{}, err
+	}
+
+	sellRate, err := parseDecimal(p.GetSellRate())
+	if err != nil {
+		return converter.Currency{}, err
+	}
+
+	return converter.Currency{
+		ISOCode:     p.GetIsoCode(),
+		Precision:   int(p.GetPrecision()),
+		BuyRate:     buyRate,
+		SellRate:    sellRate,
+		BaseISOCode: p.GetBaseIsoCode(),
+	}, nil
+}
+
+// QuoteToProto converts a quote to its protobuf message.
+func QuoteToProto(q *converter.Quote) *Quote {
+	msg := &Quote{
+		Id:             q.ID,
+		Reference:      q.Reference,
+		BaseCurrency:   q.BaseCurrency,
+		FromCurrency:   q.FromCurrency,
+		FromAmount:     q.FromAmount.String(),
+		Fee:            q.Fee.String(),
+		AmountToDeduct: q.AmountToDeduct.String(),
+		Rate:           q.Rate.String(),
+		ToCurrency:     q.ToCurrency,
+		FinalAmount:    q.FinalAmount.String(),
+		Date:           timestamppb.New(q.Date),
+		Status:         string(q.Status),
+		CustomerRef:    q.CustomerRef,
+		FeeMode:        string(q.FeeMode),
+		FeeCurrency:    q.FeeCurrency,
+		Spread:         q.Spread.String(),
+		Send:           quoteSideToProto(q.Send),
+		Receive:        quoteSideToProto(q.Receive),
+		EffectiveRate:  q.EffectiveRate.String(),
+		CancelReason:   string(q.CancelReason),
+		FromPrecision:  int32(q.FromPrecision),
+		ToPrecision:    int32(q.ToPrecision),
+	}
+	if !q.ExpiresAt.IsZero() {
+		msg.ExpiresAt = timestamppb.New(q.ExpiresAt)
+	}
+	if !q.ValueDate.IsZero() {
+		msg.ValueDate = timestamppb.New(q.ValueDate)
+	}
+	for _, flag := range q.Flags {
+		msg.Flags = append(msg.Flags, string(flag))
+	}
+
+	return msg
+}
+
+// QuoteFromProto converts a protobuf message to a quote.
+func QuoteFromProto(p *Quote) (*converter.Quote, error) {
+	var (
+		q = &converter.Quote{
+			ID:            p.GetId(),
+			Reference:     p.GetReference(),
+			BaseCurrency:  p.GetBaseCurrency(),
+			FromCurrency:  p.GetFromCurrency(),
+			ToCurrency:    p.GetToCurrency(),
+			Status:        converter.QuoteStatus(p.GetStatus()),
+			CustomerRef:   p.GetCustomerRef(),
+			FeeMode:       converter.FeeMode(p.GetFeeMode()),
+			FeeCurrency:   p.GetFeeCurrency(),
+			CancelReason:  converter.CancelReason(p.GetCancelReason()),
+			FromPrecision: int(p.GetFromPrecision()),
+			ToPrecision:   int(p.GetToPrecision()),
+		}
+		err error
+	)
+
+	for _, field := range []struct {
+		dst *decimal.Decimal
+		src string
+	}{
+		{&q.FromAmount, p.GetFromAmount()},
+		{&q.Fee, p.GetFee()},
+		{&q.AmountToDeduct, p.GetAmountToDeduct()},
+		{&q.Rate, p.GetRate()},
+		{&q.FinalAmount, p.GetFinalAmount()},
+		{&q.Spread, p.GetSpread()},
+		{&q.EffectiveRate, p.GetEffectiveRate()},
+	} {
+		if *field.dst, err = parseDecimal(field.src); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.GetDate() != nil {
+		q.Date = p.GetDate().AsTime()
+	}
+	if p.GetExpiresAt() != nil {
+		q.ExpiresAt = p.GetExpiresAt().AsTime()
+	}
+	if p.GetValueDate() != nil {
+		q.ValueDate = p.GetValueDate().AsTime()
+	}
+	for _, flag := range p.GetFlags() {
+		q.Flags = append(q.Flags, converter.QuoteFlag(flag))
+	}
+	if q.Send, err = quoteSideFromProto(p.GetSend()); err != nil {
+		return nil, err
+	}
+	if q.Receive, err = quoteSideFromProto(p.GetReceive()); err != nil {
+		return nil, err
+	}
+
+	return q, nil
+}
+
+// quoteSideToProto converts a side of a quote to its protobuf message.
+func quoteSideToProto(s converter.QuoteSide) *QuoteSide {
+	return &QuoteSide{Currency: s.Currency, Amount: s.Amount.String(), Fee: s.Fee.String()}
+}
+
+// quoteSideFromProto converts a protobuf message to a side of a quote,
+// treating an unset message as an empty side.
+func quoteSideFromProto(p *QuoteSide) (converter.QuoteSide, error) {
+	amount, err := parseDecimal(p.GetAmount())
+	if err != nil {
+		return converter.QuoteSide{}, err
+	}
+	fee, err := parseDecimal(p.GetFee())
+	if err != nil {
+		return converter.QuoteSide{}, err
+	}
+	return converter.QuoteSide{Currency: p.GetCurrency(), Amount: amount, Fee: fee}, nil
+}
+
+// parseDecimal parses a decimal string, treating an unset field as zero.
+func parseDecimal(s string) (decimal.Decimal, error) {
+	if s == "" {
+		return decimal.Zero, nil
+	}
+	return decimal.NewFromString(s)
+}
@@ -0,0 +1,113 @@
+package converterpb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/otyang/converter"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCurrencyRoundTrip(t *testing.T) {
+	c := converter.Currency{
+		ISOCode:     "BTC",
+		Precision:   8,
+		BuyRate:     decimal.RequireFromString("0.0000152345678901234567"),
+		SellRate:    decimal.RequireFromString("0.0000152445678901234567"),
+		BaseISOCode: "EUR",
+	}
+
+	b, err := proto.Marshal(CurrencyToProto(c))
+	assert.NoError(t, err)
+
+	var msg Currency
+	assert.NoError(t, proto.Unmarshal(b, &msg))
+
+	got, err := CurrencyFromProto(&msg)
+	assert.NoError(t, err)
+	assert.Equal(t, c.ISOCode, got.ISOCode)
+	assert.Equal(t, c.Precision, got.Precision)
+	assert.Equal(t, c.BuyRate.String(), got.BuyRate.String())
+	assert.Equal(t, c.SellRate.String(), got.SellRate.String())
+	assert.Equal(t, "EUR", got.BaseISOCode)
+
+	// Invalid rate
+	_, err = CurrencyFromProto(&Currency{IsoCode: "USD", BuyRate: "abc"})
+	assert.Error(t, err)
+}
+
+func TestQuoteRoundTrip(t *testing.T) {
+	q := &converter.Quote{
+		ID:             "01HKA0B5R8Z3J2XQW7N6T4M1C9",
+		Reference:      "7K3M-9XPA",
+		BaseCurrency:   "USD",
+		FromCurrency:   "USD",
+		FromAmount:     decimal.NewFromInt(100),
+		Fee:            decimal.RequireFromString("1.5"),
+		AmountToDeduct: decimal.RequireFromString("101.5"),
+		Rate:           decimal.RequireFromString("1523.123456789012345678"),
+		ToCurrency:     "NGN",
+		FinalAmount:    decimal.RequireFromString("152312.35"),
+		Date:           time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC),
+		ExpiresAt:      time.Date(2024, 1, 2, 3, 19, 5, 6, time.UTC),
+		Status:         converter.QuoteStatusAccepted,
+		CustomerRef:    "cust-42",
+		FeeMode:        converter.FeeInDestination,
+		FeeCurrency:    "NGN",
+		Spread:         decimal.RequireFromString("0.005"),
+		ValueDate:      time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC),
+		Flags:          []converter.QuoteFlag{converter.FlagSandbox, converter.FlagPinned},
+		CancelReason:   converter.CancelComplianceHold,
+		FromPrecision:  2,
+		ToPrecision:    8,
+		Send:           converter.QuoteSide{Currency: "USD", Amount: decimal.RequireFromString("101.5"), Fee: decimal.RequireFromString("1.5")},
+		Receive:        converter.QuoteSide{Currency: "NGN", Amount: decimal.RequireFromString("152312.35"), Fee: decimal.RequireFromString("2284.69")},
+		EffectiveRate:  decimal.RequireFromString("1500.61428571"),
+	}
+
+	b, err := proto.Marshal(QuoteToProto(q))
+	assert.NoError(t, err)
+
+	var msg Quote
+	assert.NoError(t, proto.Unmarshal(b, &msg))
+
+	got, err := QuoteFromProto(&msg)
+	assert.NoError(t, err)
+	assert.Equal(t, q.ID, got.ID)
+	assert.Equal(t, q.Reference, got.Reference)
+	assert.Equal(t, q.Rate.String(), got.Rate.String())
+	assert.Equal(t, q.FinalAmount.String(), got.FinalAmount.String())
+	assert.Equal(t, q.AmountToDeduct.String(), got.AmountToDeduct.String())
+	assert.True(t, q.Date.Equal(got.Date))
+	assert.True(t, q.ExpiresAt.Equal(got.ExpiresAt))
+	assert.Equal(t, q.Status, got.Status)
+	assert.Equal(t, q.CustomerRef, got.CustomerRef)
+	assert.Equal(t, q.FeeMode, got.FeeMode)
+	assert.Equal(t, q.FeeCurrency, got.FeeCurrency)
+	assert.Equal(t, q.Spread.String(), got.Spread.String())
+	assert.True(t, q.ValueDate.Equal(got.ValueDate))
+	assert.Equal(t, q.Flags, got.Flags)
+	assert.Equal(t, q.CancelReason, got.CancelReason)
+	assert.Equal(t, q.FromPrecision, got.FromPrecision)
+	assert.Equal(t, q.ToPrecision, got.ToPrecision)
+	assert.Equal(t, q.Send.Currency, got.Send.Currency)
+	assert.Equal(t, q.Send.Amount.String(), got.Send.Amount.String())
+	assert.Equal(t, q.Receive.Fee.String(), got.Receive.Fee.String())
+	assert.Equal(t, q.EffectiveRate.String(), got.EffectiveRate.String())
+
+	// Empty message decodes to zero values
+	got, err = QuoteFromProto(&Quote{})
+	assert.NoError(t, err)
+	assert.True(t, got.FromAmount.IsZero())
+	assert.True(t, got.Date.IsZero())
+	assert.True(t, got.ExpiresAt.IsZero())
+	assert.True(t, got.ValueDate.IsZero())
+	assert.Empty(t, got.Flags)
+	assert.True(t, got.Send.Amount.IsZero())
+
+	// Invalid amount
+	_, err = QuoteFromProto(&Quote{FromAmount: "1,000"})
+	assert.Error(t, err)
+}
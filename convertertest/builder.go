@@ -0,0 +1,63 @@
+package convertertest
+
+import (
+	"strings"
+
+	"github.com/otyang/converter"
+	"github.com/shopspring/decimal"
+)
+
+// DefaultPrecision is the precision given to currencies added to a Builder.
+const DefaultPrecision = 2
+
+// Builder builds fixed-rate currency tables for tests.
+//
+//	currencies := convertertest.NewBuilder("USD").
+//		Add("EUR", "0.9", "0.95").
+//		Add("NGN", "1500", "1550").
+//		List()
+type Builder struct {
+	currencies []converter.Currency
+}
+
+// NewBuilder starts a table with base at a rate of 1.
+func NewBuilder(base string) *Builder {
+	return (&Builder{}).Add(base, "1", "1")
+}
+
+// Add adds a currency with the given buy and sell rates. It panics on an
+// invalid rate, which is a bug in the test.
+func (b *Builder) Add(code, buyRate, sellRate string) *Builder {
+	b.currencies = append(b.currencies, converter.Currency{
+		ISOCode:   strings.ToUpper(code),
+		Precision: DefaultPrecision,
+		BuyRate:   decimal.RequireFromString(buyRate),
+		SellRate:  decimal.RequireFromString(sellRate),
+	})
+	return b
+}
+
+// Precision sets the precision of a currency already added.
+func (b *Builder) Precision(code string, precision int) *Builder {
+	for i := range b.currencies {
+		if strings.EqualFold(b.currencies[i].ISOCode, code) {
+			b.currencies[i].Precision = precision
+		}
+	}
+	return b
+}
+
+// List returns the built currencies.
+func (b *Builder) List() []converter.Currency {
+	return append([]converter.Currency(nil), b.currencies...)
+}
+
+// Table returns the built currencies as a live table.
+func (b *Builder) Table() *converter.Currencies {
+	return converter.NewTable(b.List())
+}
+
+// Provider returns a fake provider serving the built currencies.
+func (b *Builder) Provider() *Provider {
+	return NewProvider(b.List())
+}
@@ -0,0 +1,110 @@
+package convertertest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/otyang/converter"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilder(t *testing.T) {
+	b := NewBuilder("usd").
+		Add("EUR", "0.9", "0.95").
+		Add("JPY", "150", "151").
+		Precision("jpy", 0)
+
+	currencies := b.List()
+	assert.Len(t, currencies, 3)
+	assert.Equal(t, "USD", currencies[0].ISOCode)
+	assert.Equal(t, 0, currencies[2].Precision)
+
+	rate, err := b.Table().CalculateRate("USD", "USD", "EUR")
+	assert.NoError(t, err)
+	assert.Equal(t, "0.95", rate.String())
+}
+
+func TestProvider(t *testing.T) {
+	ctx := context.Background()
+	p := NewBuilder("USD").Add("EUR", "0.9", "0.95").Provider()
+
+	currencies, err := p.FetchRates(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, currencies, 2)
+
+	boom := errors.New("boom")
+	p.SetError(boom)
+	_, err = p.FetchRates(ctx)
+	assert.ErrorIs(t, err, boom)
+
+	p.SetRates(NewBuilder("USD").List())
+	currencies, err = p.FetchRates(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, currencies, 1)
+	assert.Equal(t, 3, p.Calls())
+
+	// Drives a real refresher
+	table := converter.NewTable(nil)
+	r, err := converter.NewRefresher(table, p, time.Minute)
+	assert.NoError(t, err)
+	assert.NoError(t, r.Refresh(ctx))
+	assert.Equal(t, 1, table.Len())
+}
+
+func TestClock(t *testing.T) {
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	clock := NewClock(start)
+	assert.Equal(t, start, clock.Now())
+
+	clock.Advance(time.Hour)
+	assert.Equal(t, start.Add(time.Hour), clock.Now())
+
+	clock.Set(start)
+	assert.Equal(t, start, clock.Now())
+
+	// Usable as the quote clock
+	q, err := NewBuilder("USD").Add("EUR", "0.9", "0.95").Table().
+		NewQuote("USD", "USD", "EUR", decimal.NewFromInt(10), decimal.Zero, converter.WithClock(clock), converter.WithTTL(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, start, q.Date)
+
+	clock.Advance(time.Minute)
+	assert.True(t, q.IsExpired(clock.Now()))
+}
+
+func TestAssertQuote(t *testing.T) {
+	q, err := NewBuilder("USD").Add("EUR", "0.9", "0.95").Table().
+		NewQuote("USD", "USD", "EUR", decimal.NewFromInt(100), decimal.NewFromInt(2))
+	assert.NoError(t, err)
+
+	assert.True(t, AssertQuote(t, q,
+		HasPair("usd", "eur"),
+		HasRate("0.950"),
+		HasFinalAmount("95"),
+		HasAmountToDeduct("102"),
+		HasFee("2"),
+		HasStatus(converter.QuoteStatusPending),
+	))
+
+	// Mismatches are reported
+	rec := &recorder{TB: t}
+	assert.False(t, AssertQuote(rec, q, HasRate("1"), HasPair("USD", "NGN"), HasStatus(converter.QuoteStatusExecuted)))
+	assert.Len(t, rec.errors, 3)
+	assert.False(t, AssertQuote(rec, nil))
+}
+
+// recorder captures test errors instead of failing the test.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,4 @@
+// Package convertertest provides fakes and helpers for testing code that uses
+// the converter package: a scriptable RateProvider, a fixed-rate table
+// builder, a controllable clock and quote matchers.
+package convertertest
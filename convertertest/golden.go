@@ -0,0 +1,105 @@
+package convertertest
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/otyang/converter"
+)
+
+// UpdateGoldenEnv is the environment variable that makes AssertGolden
+// rewrite golden files instead of comparing against them:
+//
+//	UPDATE_GOLDEN=1 go test ./...
+//
+// An environment variable is used rather than a test flag, since registering
+// a flag from an importable package clashes with other packages defining the
+// same flag.
+const UpdateGoldenEnv = "UPDATE_GOLDEN"
+
+// UpdatingGolden reports whether UpdateGoldenEnv is set to a true value.
+func UpdatingGolden() bool {
+	update, _ := strconv.ParseBool(os.Getenv(UpdateGoldenEnv))
+	return update
+}
+
+// GoldenTime is the fixed date applied to quotes before they are compared
+// against golden files.
+var GoldenTime = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// MarshalGolden serializes quotes deterministically: fields in declaration
+// order, indented, with every time-dependent or random field (ID, reference,
+// date, expiry, value date) normalized so only pricing changes show up in a diff.
+func MarshalGolden(quotes ...*converter.Quote) ([]byte, error) {
+	normalized := make([]converter.Quote, len(quotes))
+	for i, q := range quotes {
+		n := *q
+		n.ID = ""
+		n.Reference = ""
+		n.Date = GoldenTime
+		if !n.ExpiresAt.IsZero() {
+			n.ExpiresAt = GoldenTime.Add(q.ExpiresAt.Sub(q.Date))
+		}
+		if !n.ValueDate.IsZero() {
+			// Keep how many days the value date is after the quote date.
+			days := civilDay(q.ValueDate).Sub(civilDay(q.Date)) / (24 * time.Hour)
+			n.ValueDate = GoldenTime.AddDate(0, 0, int(days))
+		}
+		normalized[i] = n
+	}
+
+	b, err := json.MarshalIndent(normalized, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// civilDay returns t's date at midnight UTC, so days between dates can be
+// counted across daylight saving changes.
+func civilDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// AssertGolden compares quotes against testdata/<name>.golden. Run the tests
+// with UPDATE_GOLDEN=1 to create or refresh the file after an intended change.
+func AssertGolden(t testing.TB, name string, quotes ...*converter.Quote) bool {
+	t.Helper()
+
+	got, err := MarshalGolden(quotes...)
+	if err != nil {
+		t.Errorf("marshal golden %s: %v", name, err)
+		return false
+	}
+
+	path := filepath.Join("testdata", name+".golden")
+	if UpdatingGolden() {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Errorf("update golden %s: %v", name, err)
+			return false
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Errorf("update golden %s: %v", name, err)
+			return false
+		}
+		return true
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("read golden %s: %v (run with UPDATE_GOLDEN=1 to create it)", name, err)
+		return false
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("quotes differ from %s (run with UPDATE_GOLDEN=1 to accept)\n--- want\n%s\n+++ got\n%s", path, want, got)
+		return false
+	}
+	return true
+}
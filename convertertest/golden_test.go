@@ -0,0 +1,82 @@
+package convertertest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/otyang/converter"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func goldenQuotes(t *testing.T) []*converter.Quote {
+	t.Helper()
+
+	table := NewBuilder("USD").
+		Add("EUR", "0.9", "0.95").
+		Add("NGN", "1500", "1550").
+		Table()
+
+	var quotes []*converter.Quote
+	for _, pair := range [][2]string{{"USD", "NGN"}, {"EUR", "USD"}, {"EUR", "NGN"}} {
+		q, err := table.NewQuote("USD", pair[0], pair[1], decimal.RequireFromString("123.45"), decimal.RequireFromString("1.5"), converter.WithTTL(time.Minute))
+		assert.NoError(t, err)
+		quotes = append(quotes, q)
+	}
+	return quotes
+}
+
+func TestAssertGolden(t *testing.T) {
+	AssertGolden(t, "quotes", goldenQuotes(t)...)
+}
+
+func TestMarshalGolden_Deterministic(t *testing.T) {
+	a, err := MarshalGolden(goldenQuotes(t)...)
+	assert.NoError(t, err)
+	b, err := MarshalGolden(goldenQuotes(t)...)
+	assert.NoError(t, err)
+
+	// IDs and dates differ between runs but the output does not
+	assert.Equal(t, string(a), string(b))
+	assert.Contains(t, string(a), `"expiresAt": "2000-01-01T00:01:00Z"`)
+}
+
+func TestAssertGolden_Mismatch(t *testing.T) {
+	if UpdatingGolden() {
+		t.Skip("golden files are being updated")
+	}
+
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	assert.NoError(t, os.Chdir(dir))
+	defer os.Chdir(wd)
+
+	rec := &recorder{TB: t}
+	assert.False(t, AssertGolden(rec, "missing", goldenQuotes(t)...))
+
+	assert.NoError(t, os.MkdirAll("testdata", 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join("testdata", "changed.golden"), []byte("[]\n"), 0o644))
+	assert.False(t, AssertGolden(rec, "changed", goldenQuotes(t)...))
+	assert.Len(t, rec.errors, 2)
+}
+
+func TestAssertGolden_Update(t *testing.T) {
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	assert.NoError(t, os.Chdir(dir))
+	defer os.Chdir(wd)
+
+	// Update mode writes the golden file
+	t.Setenv(UpdateGoldenEnv, "1")
+	assert.True(t, UpdatingGolden())
+	assert.True(t, AssertGolden(t, "created", goldenQuotes(t)...))
+
+	// Compare mode then matches it
+	t.Setenv(UpdateGoldenEnv, "false")
+	assert.False(t, UpdatingGolden())
+	assert.True(t, AssertGolden(t, "created", goldenQuotes(t)...))
+}
@@ -0,0 +1,84 @@
+package convertertest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/otyang/converter"
+	"github.com/shopspring/decimal"
+)
+
+// QuoteMatcher checks one aspect of a quote, returning a description of the
+// mismatch or nil.
+type QuoteMatcher func(q *converter.Quote) error
+
+// AssertQuote reports a test error for every matcher the quote fails and
+// returns whether all matched.
+func AssertQuote(t testing.TB, q *converter.Quote, matchers ...QuoteMatcher) bool {
+	t.Helper()
+
+	if q == nil {
+		t.Errorf("quote is nil")
+		return false
+	}
+
+	ok := true
+	for _, match := range matchers {
+		if err := match(q); err != nil {
+			t.Errorf("quote %s: %v", q.ID, err)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// HasPair matches the quote's source and target currencies.
+func HasPair(from, to string) QuoteMatcher {
+	return func(q *converter.Quote) error {
+		if !strings.EqualFold(q.FromCurrency, from) || !strings.EqualFold(q.ToCurrency, to) {
+			return fmt.Errorf("pair is %s/%s, want %s/%s", q.FromCurrency, q.ToCurrency, from, to)
+		}
+		return nil
+	}
+}
+
+// HasRate matches the quote rate numerically.
+func HasRate(rate string) QuoteMatcher {
+	return decimalMatcher("rate", rate, func(q *converter.Quote) decimal.Decimal { return q.Rate })
+}
+
+// HasFinalAmount matches the converted amount numerically.
+func HasFinalAmount(amount string) QuoteMatcher {
+	return decimalMatcher("final amount", amount, func(q *converter.Quote) decimal.Decimal { return q.FinalAmount })
+}
+
+// HasAmountToDeduct matches the total source-side deduction numerically.
+func HasAmountToDeduct(amount string) QuoteMatcher {
+	return decimalMatcher("amount to deduct", amount, func(q *converter.Quote) decimal.Decimal { return q.AmountToDeduct })
+}
+
+// HasFee matches the fee numerically.
+func HasFee(fee string) QuoteMatcher {
+	return decimalMatcher("fee", fee, func(q *converter.Quote) decimal.Decimal { return q.Fee })
+}
+
+// HasStatus matches the quote status.
+func HasStatus(status converter.QuoteStatus) QuoteMatcher {
+	return func(q *converter.Quote) error {
+		if q.Status != status {
+			return fmt.Errorf("status is %s, want %s", q.Status, status)
+		}
+		return nil
+	}
+}
+
+func decimalMatcher(name, want string, get func(*converter.Quote) decimal.Decimal) QuoteMatcher {
+	expected := decimal.RequireFromString(want)
+	return func(q *converter.Quote) error {
+		if got := get(q); !got.Equal(expected) {
+			return fmt.Errorf("%s is %s, want %s", name, got, expected)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,61 @@
+package convertertest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/otyang/converter"
+)
+
+// Provider is a fake converter.RateProvider returning scripted rates.
+type Provider struct {
+	mu         sync.Mutex
+	currencies []converter.Currency
+	err        error
+	calls      int
+}
+
+// NewProvider creates a provider returning currencies.
+func NewProvider(currencies []converter.Currency) *Provider {
+	return &Provider{currencies: currencies}
+}
+
+// FetchRates implements converter.RateProvider.
+func (p *Provider) FetchRates(ctx context.Context) ([]converter.Currency, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.calls++
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	return append([]converter.Currency(nil), p.currencies...), nil
+}
+
+// SetRates changes the rates returned by later fetches and clears any error.
+func (p *Provider) SetRates(currencies []converter.Currency) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.currencies = currencies
+	p.err = nil
+}
+
+// SetError makes later fetches fail with err.
+func (p *Provider) SetError(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.err = err
+}
+
+// Calls returns how many times FetchRates has been called.
+func (p *Provider) Calls() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.calls
+}
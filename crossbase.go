@@ -0,0 +1,93 @@
+package converter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// hasOwnBases reports whether any currency names its own base currency.
+func hasOwnBases(currencies []Currency) bool {
+	for _, c := range currencies {
+		if c.BaseISOCode != "" && !strings.EqualFold(c.BaseISOCode, c.ISOCode) {
+			return true
+		}
+	}
+	return false
+}
+
+// crossBasePath is ratePath for tables whose currencies carry their own
+// bases. Each currency's rates are carried along its chain of bases to a
+// common anchor, multiplying the rates at each step, and the rate is the
+// target's anchored sell rate over the source's anchored buy rate. Currencies
+// anchored to different currencies have no rate between them.
+func crossBasePath(currencies []Currency, baseCurrency, from, to string) (sell, buy decimal.Decimal, err error) {
+	if strings.EqualFold(from, to) {
+		return decimalOne, decimalOne, nil
+	}
+	if _, err := FindCurrency(currencies, baseCurrency); err != nil {
+		return decimal.Zero, decimal.Zero, ErrBaseCurrencyNotFound
+	}
+
+	anchor := anchoredRates{currencies: currencies, base: baseCurrency}
+	buy, _, fromAnchor, err := anchor.rates(from)
+	if err != nil {
+		return decimal.Zero, decimal.Zero, err
+	}
+	if !buy.IsPositive() {
+		return decimal.Zero, decimal.Zero, fmt.Errorf("%w: %s buy rate is not positive", ErrInvalidRate, strings.ToUpper(from))
+	}
+	_, sell, toAnchor, err := anchor.rates(to)
+	if err != nil {
+		return decimal.Zero, decimal.Zero, err
+	}
+	if fromAnchor != toAnchor {
+		return decimal.Zero, decimal.Zero, fmt.Errorf("%w: %s is anchored to %s and %s to %s",
+			ErrBaseCurrencyNotFound, strings.ToUpper(from), fromAnchor, strings.ToUpper(to), toAnchor)
+	}
+	return sell, buy, nil
+}
+
+// anchoredRates resolves currencies' rates against one anchor. A currency
+// whose BaseISOCode is itself is an anchor. One without a BaseISOCode is
+// quoted per base, so its rates are carried on along the base currency's
+// own chain; the base currency itself is the anchor in a table of such
+// currencies.
+type anchoredRates struct {
+	currencies []Currency
+	base       string
+}
+
+// rates returns the buy and sell rates of a currency against its anchor,
+// and the anchor's ISO code.
+func (a anchoredRates) rates(code string) (buy, sell decimal.Decimal, anchor string, err error) {
+	buy, sell = decimalOne, decimalOne
+	seen := map[string]bool{}
+
+	for {
+		c, err := FindCurrency(a.currencies, code)
+		if err != nil {
+			return decimal.Zero, decimal.Zero, "", err
+		}
+		code = strings.ToUpper(c.ISOCode)
+		if seen[code] {
+			return decimal.Zero, decimal.Zero, "", fmt.Errorf("%w: bases of %s form a cycle", ErrBaseCurrencyNotFound, code)
+		}
+		seen[code] = true
+
+		buy, sell = buy.Mul(c.BuyRate), sell.Mul(c.SellRate)
+
+		next := c.BaseISOCode
+		if next == "" {
+			next = a.base
+		}
+		if strings.EqualFold(next, code) {
+			return buy, sell, code, nil
+		}
+		if _, err := FindCurrency(a.currencies, next); err != nil {
+			return decimal.Zero, decimal.Zero, "", fmt.Errorf("%w: %s, the base of %s", ErrBaseCurrencyNotFound, strings.ToUpper(next), code)
+		}
+		code = next
+	}
+}
@@ -0,0 +1,77 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateRate_OwnBases(t *testing.T) {
+	currencies := []Currency{
+		{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+		{ISOCode: "EUR", Precision: 2, BuyRate: decimal.NewFromFloat(0.9), SellRate: decimal.NewFromFloat(0.95)},
+		{ISOCode: "NGN", Precision: 2, BuyRate: decimal.NewFromInt(1500), SellRate: decimal.NewFromInt(1550)},
+		// A EUR-based feed.
+		{ISOCode: "GBP", Precision: 2, BuyRate: decimal.NewFromFloat(0.8), SellRate: decimal.NewFromFloat(0.85), BaseISOCode: "eur"},
+		{ISOCode: "CHF", Precision: 2, BuyRate: decimal.NewFromFloat(0.95), SellRate: decimal.NewFromFloat(0.96), BaseISOCode: "EUR"},
+	}
+
+	testCases := []struct {
+		from, to, rate string
+	}{
+		// Single-base pairs price as before.
+		{from: "USD", to: "NGN", rate: "1550"},
+		{from: "EUR", to: "NGN", rate: "1722.222222222222205"},
+		// USD to GBP goes through EUR: 0.95 * 0.85.
+		{from: "USD", to: "GBP", rate: "0.8075"},
+		// GBP to USD: 1 / (0.8 * 0.9).
+		{from: "GBP", to: "USD", rate: "1.3888888888888889"},
+		// Within the EUR feed the EUR legs cancel: 0.96 * 0.95 / (0.8 * 0.9).
+		{from: "GBP", to: "CHF", rate: "1.2666666666666666768"},
+		{from: "GBP", to: "GBP", rate: "1"},
+	}
+	for _, tc := range testCases {
+		rate, err := CalculateRate(currencies, "USD", tc.from, tc.to)
+		assert.NoError(t, err, tc.from+tc.to)
+		assert.Equal(t, tc.rate, rate.String(), tc.from+tc.to)
+	}
+
+	exact, err := CalculateRateExact(currencies, "USD", "USD", "GBP")
+	assert.NoError(t, err)
+	assert.Equal(t, "323/400", exact.String())
+}
+
+func TestCalculateRate_OwnBaseErrors(t *testing.T) {
+	currencies := []Currency{
+		{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+		{ISOCode: "GBP", Precision: 2, BuyRate: decimal.NewFromFloat(0.8), SellRate: decimal.NewFromFloat(0.85), BaseISOCode: "EUR"},
+		{ISOCode: "AAA", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1), BaseISOCode: "BBB"},
+		{ISOCode: "BBB", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1), BaseISOCode: "AAA"},
+	}
+
+	_, err := CalculateRate(currencies, "USD", "USD", "GBP")
+	assert.ErrorIs(t, err, ErrBaseCurrencyNotFound)
+	assert.ErrorContains(t, err, "EUR, the base of GBP")
+
+	_, err = CalculateRate(currencies, "USD", "USD", "AAA")
+	assert.ErrorIs(t, err, ErrBaseCurrencyNotFound)
+	assert.ErrorContains(t, err, "cycle")
+
+	_, err = CalculateRate(currencies, "EUR", "USD", "GBP")
+	assert.ErrorIs(t, err, ErrBaseCurrencyNotFound)
+
+	// Two self-anchored currencies share no base to cross through.
+	anchors := []Currency{
+		{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1), BaseISOCode: "USD"},
+		{ISOCode: "EUR", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1), BaseISOCode: "EUR"},
+		{ISOCode: "NGN", Precision: 2, BuyRate: decimal.NewFromInt(1500), SellRate: decimal.NewFromInt(1550), BaseISOCode: "USD"},
+		{ISOCode: "GBP", Precision: 2, BuyRate: decimal.NewFromFloat(0.8), SellRate: decimal.NewFromFloat(0.85), BaseISOCode: "EUR"},
+	}
+	rate, err := CalculateRate(anchors, "USD", "USD", "NGN")
+	assert.NoError(t, err)
+	assert.Equal(t, "1550", rate.String())
+	_, err = CalculateRate(anchors, "USD", "NGN", "GBP")
+	assert.ErrorIs(t, err, ErrBaseCurrencyNotFound)
+	assert.ErrorContains(t, err, "NGN is anchored to USD and GBP to EUR")
+}
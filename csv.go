@@ -0,0 +1,132 @@
+package converter
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// NewCurrenciesFromCSV reads currencies from CSV. The first record is a header
+// naming the columns isoCode, precision, buyRate and sellRate (in any order and
+// case); other columns are ignored.
+func NewCurrenciesFromCSV(r io.Reader) ([]Currency, error) {
+	return readCSV(r, DefaultFieldMapping)
+}
+
+// WriteCSV writes currencies as CSV readable by NewCurrenciesFromCSV, sorted
+// by ISO code so the same rates always produce the same file.
+func WriteCSV(w io.Writer, currencies []Currency) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"isoCode", "precision", "buyRate", "sellRate"}); err != nil {
+		return err
+	}
+	for _, c := range sortedCurrencies(currencies) {
+		record := []string{c.ISOCode, strconv.Itoa(c.Precision), c.BuyRate.String(), c.SellRate.String()}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func readCSV(r io.Reader, mapping FieldMapping) ([]Currency, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, ErrEmptyCurrencySource
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := columnIndex(header, mapping)
+	if err != nil {
+		return nil, fmt.Errorf("csv: %w", err)
+	}
+
+	var currencies []Currency
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		c, err := currencyFromRecord(record, index)
+		if err != nil {
+			return nil, fmt.Errorf("csv: line %d: %w", line, err)
+		}
+		if err := c.Validate(); err != nil {
+			return nil, fmt.Errorf("csv: line %d: %w", line, err)
+		}
+		currencies = append(currencies, c)
+	}
+
+	if len(currencies) == 0 {
+		return nil, ErrEmptyCurrencySource
+	}
+
+	return currencies, nil
+}
+
+// columnIndex finds the columns of header named by mapping, ignoring case and
+// surrounding space. The result is keyed by iso, precision, buy and sell.
+func columnIndex(header []string, mapping FieldMapping) (map[string]int, error) {
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	index := map[string]int{}
+	for _, column := range [][2]string{
+		{"iso", mapping.ISO},
+		{"precision", mapping.Precision},
+		{"buy", mapping.Buy},
+		{"sell", mapping.Sell},
+	} {
+		i, ok := columns[strings.ToLower(column[1])]
+		if !ok {
+			return nil, fmt.Errorf("missing column %s", column[1])
+		}
+		index[column[0]] = i
+	}
+	return index, nil
+}
+
+// currencyFromRecord reads a currency from a record, with the columns holding
+// iso, precision, buy and sell given by index.
+func currencyFromRecord(record []string, index map[string]int) (Currency, error) {
+	field := func(name string) string {
+		return strings.TrimSpace(record[index[name]])
+	}
+
+	precision, err := strconv.Atoi(field("precision"))
+	if err != nil {
+		return Currency{}, fmt.Errorf("%w: %q", ErrInvalidPrecision, field("precision"))
+	}
+
+	buyRate, err := ParseRate(field("buy"))
+	if err != nil {
+		return Currency{}, fmt.Errorf("%w: %q", ErrInvalidRate, field("buy"))
+	}
+
+	sellRate, err := ParseRate(field("sell"))
+	if err != nil {
+		return Currency{}, fmt.Errorf("%w: %q", ErrInvalidRate, field("sell"))
+	}
+
+	return Currency{
+		ISOCode:   strings.ToUpper(field("iso")),
+		Precision: precision,
+		BuyRate:   buyRate,
+		SellRate:  sellRate,
+	}, nil
+}
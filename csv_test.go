@@ -0,0 +1,64 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCurrenciesFromCSV(t *testing.T) {
+	currencies, err := NewCurrenciesFromCSV(strings.NewReader(`isoCode,precision,buyRate,sellRate,source
+usd,2,1,1,manual
+EUR, 2, 0.9, 0.95,manual
+BTC,8,0.0000152345678901234567,1.5e-5,feed
+`))
+	assert.NoError(t, err)
+	assert.Len(t, currencies, 3)
+	assert.Equal(t, "USD", currencies[0].ISOCode)
+	assert.Equal(t, "0.0000152345678901234567", currencies[2].BuyRate.String())
+	assert.Equal(t, "0.000015", currencies[2].SellRate.String())
+
+	// Column order and case do not matter
+	currencies, err = NewCurrenciesFromCSV(strings.NewReader("SELLRATE,BuyRate,ISOCODE,Precision\n0.95,0.9,EUR,2\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "EUR", currencies[0].ISOCode)
+	assert.Equal(t, "0.95", currencies[0].SellRate.String())
+
+	testCases := []struct {
+		name string
+		csv  string
+		err  error
+	}{
+		{name: "Empty input", csv: "", err: ErrEmptyCurrencySource},
+		{name: "Header only", csv: "isoCode,precision,buyRate,sellRate\n", err: ErrEmptyCurrencySource},
+		{name: "Missing column", csv: "isoCode,precision,buyRate\nUSD,2,1\n"},
+		{name: "Bad precision", csv: "isoCode,precision,buyRate,sellRate\nUSD,two,1,1\n", err: ErrInvalidPrecision},
+		{name: "Bad rate", csv: "isoCode,precision,buyRate,sellRate\nUSD,2,one,1\n", err: ErrInvalidRate},
+		{name: "Negative rate", csv: "isoCode,precision,buyRate,sellRate\nUSD,2,-1,1\n", err: ErrInvalidRate},
+		{name: "Huge exponent", csv: "isoCode,precision,buyRate,sellRate\nUSD,2,1e2000000000,1\n", err: ErrInvalidRate},
+		{name: "Ragged record", csv: "isoCode,precision,buyRate,sellRate\nUSD,2,1\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewCurrenciesFromCSV(strings.NewReader(tc.csv))
+			assert.Error(t, err)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf strings.Builder
+	assert.NoError(t, WriteCSV(&buf, testCurrencies()))
+	assert.Equal(t, "isoCode,precision,buyRate,sellRate\nEUR,2,0.9,0.95\nNGN,2,1500,1550\nUSD,2,1,1\n", buf.String())
+
+	currencies, err := NewCurrenciesFromCSV(strings.NewReader(buf.String()))
+	assert.NoError(t, err)
+	var again strings.Builder
+	assert.NoError(t, WriteCSV(&again, currencies))
+	assert.Equal(t, buf.String(), again.String())
+}
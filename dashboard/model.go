@@ -0,0 +1,228 @@
+// Package dashboard is a terminal dashboard over a live rate table: the
+// current rates with their movement and a sparkline of recent updates, the
+// table's age, and the most recent quotes. It follows the model, update,
+// view architecture of Elm and bubbletea: Model folds messages — table
+// updates from Currencies.Subscribe, clock ticks, quote polls and key
+// presses — into its state, and View renders that state as text that Run
+// redraws.
+package dashboard
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/otyang/converter"
+	"github.com/shopspring/decimal"
+)
+
+// Defaults of a Model's limits.
+const (
+	DefaultPoints       = 20
+	DefaultRecentQuotes = 5
+)
+
+// Msg is a message a Model handles: a SnapshotMsg, TickMsg, QuotesMsg or
+// KeyMsg. Other messages are ignored.
+type Msg any
+
+// SnapshotMsg delivers a new table snapshot.
+type SnapshotMsg struct {
+	Snapshot *converter.Snapshot
+}
+
+// TickMsg advances the time the table's age is measured at.
+type TickMsg struct {
+	Time time.Time
+}
+
+// QuotesMsg delivers the most recent quotes, newest first, or the error
+// fetching them.
+type QuotesMsg struct {
+	Quotes []*converter.Quote
+	Err    error
+}
+
+// KeyMsg is a key press. q and Ctrl-C quit.
+type KeyMsg rune
+
+// Model is the dashboard state.
+type Model struct {
+	// Base is the currency the table's rates are quoted against; it is
+	// left out of the rates.
+	Base string
+	// Points is the number of updates each sparkline covers.
+	Points int
+	// RecentQuotes is the number of quotes listed.
+	RecentQuotes int
+
+	snapshot  *converter.Snapshot
+	previous  []converter.Currency
+	history   map[string][]decimal.Decimal
+	quotes    []*converter.Quote
+	quotesErr error
+	now       time.Time
+}
+
+// NewModel creates a model of a table quoted against base, with the
+// default limits.
+func NewModel(base string) *Model {
+	return &Model{
+		Base:         strings.ToUpper(base),
+		Points:       DefaultPoints,
+		RecentQuotes: DefaultRecentQuotes,
+		history:      map[string][]decimal.Decimal{},
+	}
+}
+
+// Update applies msg to the model and reports whether the dashboard should
+// quit.
+func (m *Model) Update(msg Msg) bool {
+	switch msg := msg.(type) {
+	case SnapshotMsg:
+		if msg.Snapshot == nil {
+			return false
+		}
+		if m.snapshot != nil {
+			m.previous = m.snapshot.Currencies
+		}
+		m.snapshot = msg.Snapshot
+		for _, c := range msg.Snapshot.Currencies {
+			code := strings.ToUpper(c.ISOCode)
+			points := append(m.history[code], mid(c))
+			if len(points) > m.Points {
+				points = points[len(points)-m.Points:]
+			}
+			m.history[code] = points
+		}
+	case TickMsg:
+		m.now = msg.Time
+	case QuotesMsg:
+		m.quotes, m.quotesErr = msg.Quotes, msg.Err
+		if len(m.quotes) > m.RecentQuotes {
+			m.quotes = m.quotes[:m.RecentQuotes]
+		}
+	case KeyMsg:
+		return msg == 'q' || msg == 'Q' || msg == 0x03
+	}
+	return false
+}
+
+// View renders the model.
+func (m *Model) View() string {
+	var b strings.Builder
+	if m.snapshot == nil {
+		b.WriteString("Waiting for rates…\n")
+	} else {
+		m.viewRates(&b)
+	}
+	b.WriteString("\n")
+	m.viewQuotes(&b)
+	b.WriteString("\nq quit\n")
+	return b.String()
+}
+
+func (m *Model) viewRates(b *strings.Builder) {
+	fmt.Fprintf(b, "Rates against %s · updated %s", m.Base, m.snapshot.TakenAt.Format(time.DateTime))
+	if !m.now.IsZero() {
+		fmt.Fprintf(b, " · %s ago", age(m.now.Sub(m.snapshot.TakenAt)))
+	}
+	b.WriteString("\n\n")
+
+	previous := map[string]converter.Currency{}
+	for _, c := range m.previous {
+		previous[strings.ToUpper(c.ISOCode)] = c
+	}
+
+	tw := tabwriter.NewWriter(b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CODE\tBUY\tSELL\tCHANGE\tTREND")
+	for _, c := range m.snapshot.Currencies {
+		code := strings.ToUpper(c.ISOCode)
+		if code == m.Base {
+			continue
+		}
+		change := ""
+		if prev, ok := previous[code]; ok {
+			change = percent(mid(prev), mid(c))
+		} else if m.previous != nil {
+			change = "new"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", code, c.BuyRate, c.SellRate, change, sparkline(m.history[code]))
+	}
+	tw.Flush()
+}
+
+func (m *Model) viewQuotes(b *strings.Builder) {
+	b.WriteString("Recent quotes\n\n")
+	switch {
+	case m.quotesErr != nil:
+		fmt.Fprintf(b, "error: %v\n", m.quotesErr)
+		return
+	case len(m.quotes) == 0:
+		b.WriteString("none\n")
+		return
+	}
+
+	tw := tabwriter.NewWriter(b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tPAIR\tAMOUNT\tRATE\tRECEIVE\tSTATUS")
+	for _, q := range m.quotes {
+		fmt.Fprintf(tw, "%s\t%s/%s\t%s\t%s\t%s\t%s\n",
+			q.Date.Format(time.TimeOnly), q.FromCurrency, q.ToCurrency, q.FromAmount, q.Rate, q.FinalAmount, q.Status)
+	}
+	tw.Flush()
+}
+
+// mid returns a currency's mid rate.
+func mid(c converter.Currency) decimal.Decimal {
+	return c.BuyRate.Add(c.SellRate).Div(decimal.NewFromInt(2))
+}
+
+// percent formats the movement from old to new in percent, to two places.
+func percent(old, new decimal.Decimal) string {
+	if old.IsZero() {
+		return ""
+	}
+	change := new.Sub(old).Mul(decimal.NewFromInt(100)).DivRound(old, 2)
+	if change.IsPositive() {
+		return "+" + change.StringFixed(2) + "%"
+	}
+	return change.StringFixed(2) + "%"
+}
+
+// age formats a duration to the second, or to the minute past an hour.
+func age(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	if d >= time.Hour {
+		return d.Truncate(time.Minute).String()
+	}
+	return d.Truncate(time.Second).String()
+}
+
+// sparkBars are the sparkline levels, lowest first.
+var sparkBars = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline draws values scaled between their minimum and maximum, at the
+// middle level if they are all equal.
+func sparkline(values []decimal.Decimal) string {
+	if len(values) == 0 {
+		return ""
+	}
+	sorted := append([]decimal.Decimal(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].LessThan(sorted[j]) })
+	low, spread := sorted[0], sorted[len(sorted)-1].Sub(sorted[0])
+
+	top := decimal.NewFromInt(int64(len(sparkBars) - 1))
+	line := make([]rune, len(values))
+	for i, v := range values {
+		level := (len(sparkBars) - 1) / 2
+		if !spread.IsZero() {
+			level = int(v.Sub(low).Mul(top).DivRound(spread, 0).IntPart())
+		}
+		line[i] = sparkBars[level]
+	}
+	return string(line)
+}
@@ -0,0 +1,96 @@
+package dashboard
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/otyang/converter"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func currencies(ngnBuy, ngnSell int64) []converter.Currency {
+	return []converter.Currency{
+		{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+		{ISOCode: "EUR", Precision: 2, BuyRate: decimal.RequireFromString("0.9"), SellRate: decimal.RequireFromString("0.95")},
+		{ISOCode: "NGN", Precision: 2, BuyRate: decimal.NewFromInt(ngnBuy), SellRate: decimal.NewFromInt(ngnSell)},
+	}
+}
+
+func TestModel(t *testing.T) {
+	at := time.Date(2024, 3, 28, 12, 0, 0, 0, time.UTC)
+	m := NewModel("usd")
+	assert.Equal(t, "Waiting for rates…\n\nRecent quotes\n\nnone\n\nq quit\n", m.View())
+
+	assert.False(t, m.Update(SnapshotMsg{Snapshot: converter.NewSnapshot(currencies(1500, 1550), at)}))
+	assert.False(t, m.Update(SnapshotMsg{Snapshot: converter.NewSnapshot(currencies(1600, 1650), at.Add(time.Minute))}))
+	m.Update(SnapshotMsg{Snapshot: converter.NewSnapshot(currencies(1550, 1600), at.Add(2*time.Minute))})
+	m.Update(TickMsg{Time: at.Add(2*time.Minute + 5500*time.Millisecond)})
+	m.Update(QuotesMsg{Quotes: []*converter.Quote{{
+		FromCurrency: "USD",
+		ToCurrency:   "NGN",
+		FromAmount:   decimal.NewFromInt(100),
+		Rate:         decimal.NewFromInt(1600),
+		FinalAmount:  decimal.NewFromInt(160000),
+		Date:         at.Add(90 * time.Second),
+		Status:       converter.QuoteStatusPending,
+	}}})
+
+	assert.Equal(t, `Rates against USD · updated 2024-03-28 12:02:00 · 5s ago
+
+CODE  BUY   SELL  CHANGE  TREND
+EUR   0.9   0.95  0.00%   ▄▄▄
+NGN   1550  1600  -3.08%  ▁█▅
+
+Recent quotes
+
+TIME      PAIR     AMOUNT  RATE  RECEIVE  STATUS
+12:01:30  USD/NGN  100     1600  160000   pending
+
+q quit
+`, m.View())
+
+	m.Update(QuotesMsg{Err: errors.New("store down")})
+	assert.Contains(t, m.View(), "Recent quotes\n\nerror: store down\n")
+
+	assert.False(t, m.Update(KeyMsg('x')))
+	assert.True(t, m.Update(KeyMsg('q')))
+	assert.True(t, m.Update(KeyMsg(0x03)))
+}
+
+func TestModel_Limits(t *testing.T) {
+	at := time.Date(2024, 3, 28, 12, 0, 0, 0, time.UTC)
+	m := NewModel("USD")
+	m.Points, m.RecentQuotes = 2, 1
+
+	m.Update(SnapshotMsg{Snapshot: converter.NewSnapshot(currencies(1500, 1550)[:2], at)})
+	for _, rate := range []int64{1500, 1600, 1700} {
+		m.Update(SnapshotMsg{Snapshot: converter.NewSnapshot(currencies(rate, rate), at)})
+	}
+	assert.Len(t, m.history["NGN"], 2)
+
+	m.Update(QuotesMsg{Quotes: []*converter.Quote{{Status: converter.QuoteStatusPending}, {Status: converter.QuoteStatusExpired}}})
+	assert.Len(t, m.quotes, 1)
+	assert.Equal(t, converter.QuoteStatusPending, m.quotes[0].Status)
+
+	// A currency missing from the previous table is marked new.
+	m = NewModel("USD")
+	m.Update(SnapshotMsg{Snapshot: converter.NewSnapshot(currencies(1500, 1550)[:2], at)})
+	m.Update(SnapshotMsg{Snapshot: converter.NewSnapshot(currencies(1500, 1550), at)})
+	assert.Contains(t, m.View(), "NGN   1500  1550  new     ▄\n")
+}
+
+func TestSparkline(t *testing.T) {
+	d := decimal.NewFromInt
+	assert.Equal(t, "", sparkline(nil))
+	assert.Equal(t, "▄▄", sparkline([]decimal.Decimal{d(5), d(5)}))
+	assert.Equal(t, "▁▂▃▄▅▆▇█", sparkline([]decimal.Decimal{d(0), d(1), d(2), d(3), d(4), d(5), d(6), d(7)}))
+	assert.Equal(t, "█▁▅", sparkline([]decimal.Decimal{d(10), d(-4), d(4)}))
+}
+
+func TestAge(t *testing.T) {
+	assert.Equal(t, "0s", age(-time.Second))
+	assert.Equal(t, "59m59s", age(time.Hour-time.Millisecond))
+	assert.Equal(t, "2h5m0s", age(2*time.Hour+5*time.Minute+30*time.Second))
+}
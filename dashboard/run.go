@@ -0,0 +1,147 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/otyang/converter"
+)
+
+// ErrNoTable is returned by Run without a table.
+var ErrNoTable = errors.New("dashboard: no rate table")
+
+// DefaultRefresh is how often Run redraws the table's age and polls
+// quotes by default.
+const DefaultRefresh = time.Second
+
+// Screen control sequences written by Run.
+const (
+	clearScreen = "\x1b[H\x1b[2J"
+	// EnterScreen switches a terminal to its alternate screen and hides
+	// the cursor, for full-screen programs; ExitScreen undoes it.
+	EnterScreen = "\x1b[?1049h\x1b[?25l"
+	ExitScreen  = "\x1b[?25h\x1b[?1049l"
+)
+
+// Config configures Run.
+type Config struct {
+	Table *converter.Currencies
+	// Base is the currency the table's rates are quoted against.
+	Base string
+	// Quotes, if set, is polled for the most recent quotes.
+	Quotes converter.QuoteStore
+	// Points and RecentQuotes override the model's defaults if positive.
+	Points       int
+	RecentQuotes int
+	// Refresh defaults to DefaultRefresh.
+	Refresh time.Duration
+	// Clock defaults to converter.SystemClock.
+	Clock converter.Clock
+}
+
+// Run draws the dashboard to out, redrawing on every table update and
+// every Refresh, until ctx is cancelled or q is read from keys. keys may be
+// nil, and should be a terminal in raw mode to read single key presses; the
+// view is written with CRLF line endings accordingly.
+func Run(ctx context.Context, cfg Config, keys io.Reader, out io.Writer) error {
+	if cfg.Table == nil {
+		return ErrNoTable
+	}
+	if cfg.Refresh <= 0 {
+		cfg.Refresh = DefaultRefresh
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = converter.SystemClock
+	}
+	m := NewModel(cfg.Base)
+	if cfg.Points > 0 {
+		m.Points = cfg.Points
+	}
+	if cfg.RecentQuotes > 0 {
+		m.RecentQuotes = cfg.RecentQuotes
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	snapshots := cfg.Table.Subscribe(ctx)
+	if cfg.Table.Len() > 0 {
+		m.Update(SnapshotMsg{Snapshot: cfg.Table.Snapshot()})
+	}
+
+	pressed := make(chan KeyMsg)
+	if keys != nil {
+		go readKeys(ctx, keys, pressed)
+	}
+	ticker := time.NewTicker(cfg.Refresh)
+	defer ticker.Stop()
+
+	tick := func() {
+		m.Update(TickMsg{Time: cfg.Clock.Now()})
+		if cfg.Quotes != nil {
+			m.Update(recentQuotes(ctx, cfg.Quotes, m.RecentQuotes))
+		}
+	}
+	tick()
+	for {
+		if _, err := io.WriteString(out, clearScreen+strings.ReplaceAll(m.View(), "\n", "\r\n")); err != nil {
+			return err
+		}
+
+		var quit bool
+		select {
+		case <-ctx.Done():
+			return nil
+		case s, ok := <-snapshots:
+			if !ok {
+				return nil
+			}
+			m.Update(SnapshotMsg{Snapshot: s})
+			m.Update(TickMsg{Time: cfg.Clock.Now()})
+		case <-ticker.C:
+			tick()
+		case key := <-pressed:
+			quit = m.Update(key)
+		}
+		if quit {
+			return nil
+		}
+	}
+}
+
+// readKeys sends the bytes read from r as key presses until ctx is done or
+// r fails.
+func readKeys(ctx context.Context, r io.Reader, pressed chan<- KeyMsg) {
+	buf := make([]byte, 1)
+	for {
+		if _, err := r.Read(buf); err != nil {
+			return
+		}
+		select {
+		case pressed <- KeyMsg(buf[0]):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// recentQuotes fetches the last n quotes of store, newest first.
+func recentQuotes(ctx context.Context, store converter.QuoteStore, n int) QuotesMsg {
+	page, err := store.List(ctx, converter.QuoteFilter{Limit: 1})
+	if err != nil {
+		return QuotesMsg{Err: err}
+	}
+	if page.Total > 1 {
+		page, err = store.List(ctx, converter.QuoteFilter{Offset: max(page.Total-n, 0), Limit: n})
+		if err != nil {
+			return QuotesMsg{Err: err}
+		}
+	}
+	quotes := make([]*converter.Quote, len(page.Quotes))
+	for i, q := range page.Quotes {
+		quotes[len(quotes)-1-i] = q
+	}
+	return QuotesMsg{Quotes: quotes}
+}
@@ -0,0 +1,96 @@
+package dashboard
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/otyang/converter"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// screen is a buffer safe to read while Run writes to it.
+type screen struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (s *screen) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buf.Write(p)
+}
+
+// last returns the last frame drawn.
+func (s *screen) last() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	frames := strings.Split(s.buf.String(), clearScreen)
+	return frames[len(frames)-1]
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	table := converter.NewTable(currencies(1500, 1550))
+	store := converter.NewMemoryQuoteStore()
+	for _, amount := range []int64{10, 20, 30} {
+		q, err := table.NewQuote("USD", "USD", "NGN", decimal.NewFromInt(amount), decimal.Zero)
+		require.NoError(t, err)
+		require.NoError(t, store.Save(ctx, q))
+	}
+
+	keys, press := io.Pipe()
+	out := &screen{}
+	done := make(chan error)
+	go func() {
+		done <- Run(ctx, Config{Table: table, Base: "USD", Quotes: store, RecentQuotes: 2, Refresh: time.Millisecond}, keys, out)
+	}()
+
+	assert.Eventually(t, func() bool {
+		return strings.Contains(out.last(), "NGN   1500  1550          ▄\r\n")
+	}, time.Second, time.Millisecond)
+	frame := out.last()
+	assert.Contains(t, frame, "USD/NGN  30 ")
+	assert.Contains(t, frame, "USD/NGN  20 ")
+	assert.NotContains(t, frame, "USD/NGN  10 ")
+	assert.Less(t, strings.Index(frame, "USD/NGN  30 "), strings.Index(frame, "USD/NGN  20 "))
+
+	table.Update(currencies(1600, 1650), time.Now())
+	assert.Eventually(t, func() bool {
+		return strings.Contains(out.last(), "NGN   1600  1650  +6.56%")
+	}, time.Second, time.Millisecond)
+
+	_, err := press.Write([]byte("q"))
+	require.NoError(t, err)
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Run did not quit on q")
+	}
+}
+
+func TestRun_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	out := &screen{}
+	done := make(chan error)
+	go func() {
+		done <- Run(ctx, Config{Table: converter.NewTable(nil)}, nil, out)
+	}()
+
+	assert.Eventually(t, func() bool {
+		return strings.Contains(out.last(), "Waiting for rates…\r\n")
+	}, time.Second, time.Millisecond)
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestRun_NoTable(t *testing.T) {
+	assert.ErrorIs(t, Run(context.Background(), Config{}, nil, io.Discard), ErrNoTable)
+}
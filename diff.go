@@ -0,0 +1,129 @@
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/shopspring/decimal"
+)
+
+// percentDivision rounds the percent movements in a TableDiff.
+var percentDivision = Division{Precision: 4, Rounding: RoundHalfEven}
+
+// RateDiff is the change of one currency between two tables. Previous is nil
+// for an added currency and Current is nil for a removed one.
+type RateDiff struct {
+	// Pair names the quoted pair, e.g. "USD/NGN".
+	Pair     string    `json:"pair"`
+	ISOCode  string    `json:"isoCode"`
+	Previous *Currency `json:"previous,omitempty"`
+	Current  *Currency `json:"current,omitempty"`
+	// BuyChange and SellChange are the percent movements of the rates. They
+	// are nil unless both tables quote the currency at a nonzero rate.
+	BuyChange  *decimal.Decimal `json:"buyChange,omitempty"`
+	SellChange *decimal.Decimal `json:"sellChange,omitempty"`
+}
+
+// TableDiff lists the currencies that differ between two versions of a rate
+// table, sorted by ISO code, for refresh notifications and rate reviews.
+type TableDiff struct {
+	Base    string     `json:"base"`
+	Changes []RateDiff `json:"changes"`
+}
+
+// DiffTables compares two versions of a table quoted against base.
+func DiffTables(base string, previous, current []Currency) *TableDiff {
+	base = strings.ToUpper(base)
+	d := &TableDiff{Base: base, Changes: []RateDiff{}}
+
+	for _, change := range currencyChanges(previous, current) {
+		rd := RateDiff{
+			Pair:     base + "/" + change.code,
+			ISOCode:  change.code,
+			Previous: change.previous,
+			Current:  change.current,
+		}
+		if change.previous != nil && change.current != nil {
+			rd.BuyChange = percentChange(change.previous.BuyRate, change.current.BuyRate)
+			rd.SellChange = percentChange(change.previous.SellRate, change.current.SellRate)
+		}
+		d.Changes = append(d.Changes, rd)
+	}
+	return d
+}
+
+// percentChange returns the movement from old to new in percent, or nil if
+// old is zero.
+func percentChange(old, new decimal.Decimal) *decimal.Decimal {
+	if old.IsZero() {
+		return nil
+	}
+	change := percentDivision.Div(new.Sub(old).Mul(decimal.NewFromInt(100)), old)
+	return &change
+}
+
+// Empty reports whether the tables are the same.
+func (d *TableDiff) Empty() bool {
+	return len(d.Changes) == 0
+}
+
+// WriteJSON writes the diff as indented JSON.
+func (d *TableDiff) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(d)
+}
+
+// WriteText writes the diff as an aligned, human-readable report, one line
+// per changed pair:
+//
+//	USD/EUR  changed  buy 0.9 → 0.91 (+1.11%)  sell 0.95 (0.00%)
+//	USD/GBP  added    buy 0.8                  sell 0.82
+//	USD/NGN  removed  buy 1500                 sell 1550
+func (d *TableDiff) WriteText(w io.Writer) error {
+	if d.Empty() {
+		_, err := fmt.Fprintln(w, "no changes")
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, c := range d.Changes {
+		var line string
+		switch {
+		case c.Previous == nil:
+			line = fmt.Sprintf("%s\tadded\tbuy %s\tsell %s", c.Pair, c.Current.BuyRate, c.Current.SellRate)
+		case c.Current == nil:
+			line = fmt.Sprintf("%s\tremoved\tbuy %s\tsell %s", c.Pair, c.Previous.BuyRate, c.Previous.SellRate)
+		default:
+			line = fmt.Sprintf("%s\tchanged\tbuy %s\tsell %s", c.Pair,
+				movement(c.Previous.BuyRate, c.Current.BuyRate, c.BuyChange),
+				movement(c.Previous.SellRate, c.Current.SellRate, c.SellChange))
+			if c.Previous.Precision != c.Current.Precision {
+				line += fmt.Sprintf("\tprecision %d → %d", c.Previous.Precision, c.Current.Precision)
+			}
+		}
+		if _, err := fmt.Fprintln(tw, line); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+// movement formats a rate change such as "0.9 → 0.91 (+1.11%)".
+func movement(old, new decimal.Decimal, change *decimal.Decimal) string {
+	s := old.String()
+	if !old.Equal(new) {
+		s += " → " + new.String()
+	}
+	if change != nil {
+		sign := ""
+		if change.IsPositive() {
+			sign = "+"
+		}
+		s += fmt.Sprintf(" (%s%s%%)", sign, change.StringFixed(2))
+	}
+	return s
+}
@@ -0,0 +1,53 @@
+package converter
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffTables(t *testing.T) {
+	previous := testCurrencies()
+	current := []Currency{
+		{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+		{ISOCode: "EUR", Precision: 2, BuyRate: decimal.RequireFromString("0.91"), SellRate: decimal.RequireFromString("0.95")},
+		{ISOCode: "GBP", Precision: 2, BuyRate: decimal.RequireFromString("0.8"), SellRate: decimal.RequireFromString("0.82")},
+	}
+
+	diff := DiffTables("usd", previous, current)
+	assert.False(t, diff.Empty())
+	assert.Len(t, diff.Changes, 3)
+	eur := diff.Changes[0]
+	assert.Equal(t, "USD/EUR", eur.Pair)
+	assert.Equal(t, "1.1111", eur.BuyChange.String())
+	assert.Equal(t, "0", eur.SellChange.String())
+	assert.Nil(t, diff.Changes[1].Previous)
+	assert.Nil(t, diff.Changes[2].Current)
+
+	var text bytes.Buffer
+	assert.NoError(t, diff.WriteText(&text))
+	assert.Equal(t, `USD/EUR  changed  buy 0.9 → 0.91 (+1.11%)  sell 0.95 (0.00%)
+USD/GBP  added    buy 0.8                  sell 0.82
+USD/NGN  removed  buy 1500                 sell 1550
+`, text.String())
+
+	var buf bytes.Buffer
+	assert.NoError(t, diff.WriteJSON(&buf))
+	var decoded TableDiff
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, "USD", decoded.Base)
+	assert.Equal(t, "1.1111", decoded.Changes[0].BuyChange.String())
+	assert.Contains(t, buf.String(), `"sellChange": "0"`)
+
+	same := DiffTables("USD", previous, previous)
+	assert.True(t, same.Empty())
+	text.Reset()
+	assert.NoError(t, same.WriteText(&text))
+	assert.Equal(t, "no changes\n", text.String())
+	buf.Reset()
+	assert.NoError(t, same.WriteJSON(&buf))
+	assert.JSONEq(t, `{"base":"USD","changes":[]}`, buf.String())
+}
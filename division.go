@@ -0,0 +1,162 @@
+package converter
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// RoundingMode selects how a value is rounded to a number of decimal places.
+type RoundingMode int
+
+// Rounding modes
+const (
+	// RoundHalfUp rounds to the nearest value, ties away from zero.
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds to the nearest value, ties to the even digit
+	// (banker's rounding).
+	RoundHalfEven
+	// RoundDown rounds towards zero (truncates).
+	RoundDown
+	// RoundUp rounds away from zero.
+	RoundUp
+	// RoundCeiling rounds towards positive infinity.
+	RoundCeiling
+	// RoundFloor rounds towards negative infinity.
+	RoundFloor
+)
+
+// String returns the name of the rounding mode.
+func (m RoundingMode) String() string {
+	switch m {
+	case RoundHalfUp:
+		return "half-up"
+	case RoundHalfEven:
+		return "half-even"
+	case RoundDown:
+		return "down"
+	case RoundUp:
+		return "up"
+	case RoundCeiling:
+		return "ceiling"
+	case RoundFloor:
+		return "floor"
+	default:
+		return "unknown"
+	}
+}
+
+// Round rounds d to places decimal places.
+func (m RoundingMode) Round(d decimal.Decimal, places int32) decimal.Decimal {
+	switch m {
+	case RoundHalfEven:
+		return d.RoundBank(places)
+	case RoundDown:
+		return d.RoundDown(places)
+	case RoundUp:
+		return d.RoundUp(places)
+	case RoundCeiling:
+		return d.RoundCeil(places)
+	case RoundFloor:
+		return d.RoundFloor(places)
+	default:
+		return d.Round(places)
+	}
+}
+
+// Division configures the divisions made when computing rates. Without one,
+// divisions use shopspring/decimal's global DivisionPrecision, which any
+// other code in the process can change.
+type Division struct {
+	// Precision is the number of decimal places kept in quotients.
+	Precision int32
+	Rounding  RoundingMode
+}
+
+// Div returns a / b rounded to the configured precision. The rounding is
+// decided from the truncated quotient and its exact remainder, so ties are
+// detected exactly and the result is never rounded twice.
+func (d Division) Div(a, b decimal.Decimal) decimal.Decimal {
+	q, r := a.QuoRem(b, d.Precision)
+	if r.IsZero() {
+		return q
+	}
+
+	// Away from zero by one unit in the last place.
+	unit := decimal.New(1, -d.Precision)
+	negative := a.Sign()*b.Sign() < 0
+	if negative {
+		unit = unit.Neg()
+	}
+
+	// Compare the remainder with half a unit of the divisor.
+	half := r.Abs().Mul(decimal.NewFromInt(2)).Cmp(b.Abs().Mul(decimal.New(1, -d.Precision)))
+
+	switch d.Rounding {
+	case RoundDown:
+		return q
+	case RoundUp:
+		return q.Add(unit)
+	case RoundCeiling:
+		if negative {
+			return q
+		}
+		return q.Add(unit)
+	case RoundFloor:
+		if negative {
+			return q.Add(unit)
+		}
+		return q
+	case RoundHalfEven:
+		if half > 0 || half == 0 && q.Shift(d.Precision).BigInt().Bit(0) == 1 {
+			return q.Add(unit)
+		}
+		return q
+	default:
+		if half >= 0 {
+			return q.Add(unit)
+		}
+		return q
+	}
+}
+
+// round rounds x to the division's precision with its rounding mode.
+func (d Division) round(x decimal.Decimal) decimal.Decimal {
+	return d.Rounding.Round(x, d.Precision)
+}
+
+// divide divides with div, or with the global precision if div is nil.
+func divide(div *Division, a, b decimal.Decimal) decimal.Decimal {
+	if div == nil {
+		return a.Div(b)
+	}
+	return div.Div(a, b)
+}
+
+// decimalOne is 1, shared rather than built on every call: decimals are
+// immutable.
+var decimalOne = decimal.NewFromInt(1)
+
+// unitDecimals holds 1 at each exponent from 0 down to -18.
+var unitDecimals = func() (units [19]decimal.Decimal) {
+	coefficient := int64(1)
+	for i := range units {
+		units[i] = decimal.New(coefficient, int32(-i))
+		coefficient *= 10
+	}
+	return units
+}()
+
+// isOne reports whether d is 1. d.Equal(decimalOne) rescales, and so
+// allocates, whenever d's exponent is not 0; isOne compares d with 1 at its
+// own exponent instead.
+func isOne(d decimal.Decimal) bool {
+	exp := d.Exponent()
+	switch {
+	case exp > 0:
+		// A whole coefficient times a power of ten is 0 or at least 10.
+		return false
+	case int(-exp) < len(unitDecimals):
+		return d.Cmp(unitDecimals[-exp]) == 0
+	default:
+		return d.Equal(decimalOne)
+	}
+}
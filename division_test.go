@@ -0,0 +1,85 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDivision_Div(t *testing.T) {
+	testCases := []struct {
+		a, b     string
+		places   int32
+		rounding RoundingMode
+		want     string
+	}{
+		{a: "1", b: "3", places: 4, rounding: RoundHalfUp, want: "0.3333"},
+		{a: "2", b: "3", places: 4, rounding: RoundHalfUp, want: "0.6667"},
+		{a: "2", b: "3", places: 4, rounding: RoundDown, want: "0.6666"},
+		{a: "1", b: "3", places: 4, rounding: RoundUp, want: "0.3334"},
+		{a: "1", b: "8", places: 2, rounding: RoundHalfUp, want: "0.13"},
+		{a: "1", b: "8", places: 2, rounding: RoundHalfEven, want: "0.12"},
+		{a: "3", b: "8", places: 2, rounding: RoundHalfEven, want: "0.38"},
+		{a: "-1", b: "8", places: 2, rounding: RoundHalfUp, want: "-0.13"},
+		{a: "-1", b: "8", places: 2, rounding: RoundHalfEven, want: "-0.12"},
+		{a: "1", b: "-3", places: 2, rounding: RoundUp, want: "-0.34"},
+		{a: "1", b: "4", places: 2, rounding: RoundUp, want: "0.25"},
+		{a: "1", b: "1500", places: 20, rounding: RoundHalfUp, want: "0.00066666666666666667"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.a+"/"+tc.b+" "+tc.rounding.String(), func(t *testing.T) {
+			d := Division{Precision: tc.places, Rounding: tc.rounding}
+			got := d.Div(decimal.RequireFromString(tc.a), decimal.RequireFromString(tc.b))
+			assert.Equal(t, tc.want, got.String())
+		})
+	}
+}
+
+func TestRoundingMode_Round(t *testing.T) {
+	d := decimal.RequireFromString("2.345")
+	assert.Equal(t, "2.35", RoundHalfUp.Round(d, 2).String())
+	assert.Equal(t, "2.34", RoundHalfEven.Round(d, 2).String())
+	assert.Equal(t, "2.34", RoundDown.Round(d, 2).String())
+	assert.Equal(t, "2.35", RoundUp.Round(decimal.RequireFromString("2.341"), 2).String())
+	assert.Equal(t, "unknown", RoundingMode(99).String())
+}
+
+func TestCurrencies_WithDivision(t *testing.T) {
+	defer func(p int) { decimal.DivisionPrecision = p }(decimal.DivisionPrecision)
+
+	table := NewTable(testCurrencies(), WithDivision(Division{Precision: 8, Rounding: RoundHalfEven}))
+
+	rate, err := table.CalculateRate("USD", "NGN", "EUR")
+	assert.NoError(t, err)
+	assert.Equal(t, "0.0006333365", rate.String())
+
+	// Other code changing the global precision does not affect the table
+	decimal.DivisionPrecision = 2
+	again, err := table.CalculateRate("USD", "NGN", "EUR")
+	assert.NoError(t, err)
+	assert.True(t, rate.Equal(again))
+
+	q, err := table.NewQuote("USD", "NGN", "USD", decimal.NewFromInt(1500), decimal.Zero)
+	assert.NoError(t, err)
+	assert.Equal(t, "0.00066667", q.Rate.String())
+
+	// The package function follows the global
+	global, err := CalculateRate(testCurrencies(), "USD", "NGN", "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, "0", global.String())
+}
+
+func TestDivision_CeilingFloor(t *testing.T) {
+	ceil := Division{Precision: 2, Rounding: RoundCeiling}
+	floor := Division{Precision: 2, Rounding: RoundFloor}
+	one, three := decimal.NewFromInt(1), decimal.NewFromInt(3)
+
+	assert.Equal(t, "0.34", ceil.Div(one, three).String())
+	assert.Equal(t, "-0.33", ceil.Div(one.Neg(), three).String())
+	assert.Equal(t, "0.33", floor.Div(one, three).String())
+	assert.Equal(t, "-0.34", floor.Div(one.Neg(), three).String())
+	assert.Equal(t, "-2.35", RoundFloor.Round(decimal.RequireFromString("-2.341"), 2).String())
+	assert.Equal(t, "-2.34", RoundCeiling.Round(decimal.RequireFromString("-2.341"), 2).String())
+}
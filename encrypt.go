@@ -0,0 +1,150 @@
+package converter
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Encryption errors
+var (
+	ErrKeyNotFound      = errors.New("encryption key not found")
+	ErrInvalidEnvelope  = errors.New("invalid encrypted envelope")
+	ErrDecryptionFailed = errors.New("decryption failed")
+)
+
+// KeyProvider supplies AES keys for quote encryption. Implementations can
+// wrap a KMS: CurrentKey is used when encrypting and Key when decrypting,
+// which allows keys to be rotated without re-encrypting stored quotes.
+type KeyProvider interface {
+	// CurrentKey returns the ID and key used for new encryptions.
+	CurrentKey(ctx context.Context) (keyID string, key []byte, err error)
+	// Key returns the key with the given ID.
+	Key(ctx context.Context, keyID string) ([]byte, error)
+}
+
+// StaticKeyProvider is a KeyProvider backed by an in-memory set of keys.
+// It is safe for concurrent use.
+type StaticKeyProvider struct {
+	mu        sync.RWMutex
+	currentID string
+	keys      map[string][]byte
+}
+
+// NewStaticKeyProvider creates a key provider that encrypts with key under
+// keyID. Keys must be 16, 24 or 32 bytes long.
+func NewStaticKeyProvider(keyID string, key []byte) *StaticKeyProvider {
+	return &StaticKeyProvider{
+		currentID: keyID,
+		keys:      map[string][]byte{keyID: key},
+	}
+}
+
+// AddKey registers an older key that is still needed for decryption.
+func (p *StaticKeyProvider) AddKey(keyID string, key []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.keys[keyID] = key
+}
+
+// CurrentKey implements KeyProvider.
+func (p *StaticKeyProvider) CurrentKey(ctx context.Context) (string, []byte, error) {
+	key, err := p.Key(ctx, p.currentID)
+	return p.currentID, key, err
+}
+
+// Key implements KeyProvider.
+func (p *StaticKeyProvider) Key(_ context.Context, keyID string) ([]byte, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	key, ok := p.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, keyID)
+	}
+	return key, nil
+}
+
+// envelope is the stored form of an encrypted quote.
+type envelope struct {
+	KeyID      string `json:"keyId"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// EncryptQuote encrypts a quote with AES-GCM using the provider's current key.
+// The result is safe to hand to any storage backend.
+func EncryptQuote(ctx context.Context, keys KeyProvider, quote *Quote) ([]byte, error) {
+	plaintext, err := json.Marshal(quote)
+	if err != nil {
+		return nil, err
+	}
+
+	keyID, key, err := keys.CurrentKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(envelope{
+		KeyID:      keyID,
+		Nonce:      nonce,
+		Ciphertext: aead.Seal(nil, nonce, plaintext, []byte(keyID)),
+	})
+}
+
+// DecryptQuote decrypts a quote produced by EncryptQuote.
+func DecryptQuote(ctx context.Context, keys KeyProvider, data []byte) (*Quote, error) {
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEnvelope, err)
+	}
+
+	key, err := keys.Key(ctx, env.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(env.Nonce) != aead.NonceSize() {
+		return nil, ErrInvalidEnvelope
+	}
+
+	plaintext, err := aead.Open(nil, env.Nonce, env.Ciphertext, []byte(env.KeyID))
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+
+	var quote Quote
+	if err := json.Unmarshal(plaintext, &quote); err != nil {
+		return nil, err
+	}
+
+	return &quote, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,64 @@
+package converter
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncryptQuote(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		key1  = bytes.Repeat([]byte{1}, 32)
+		key2  = bytes.Repeat([]byte{2}, 32)
+		quote = &Quote{
+			BaseCurrency:   "USD",
+			FromCurrency:   "USD",
+			FromAmount:     decimal.NewFromInt(100),
+			Fee:            decimal.NewFromInt(5),
+			AmountToDeduct: decimal.NewFromInt(105),
+			Rate:           decimal.NewFromFloat(0.95),
+			ToCurrency:     "EUR",
+			FinalAmount:    decimal.NewFromInt(95),
+			Date:           time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		}
+	)
+
+	keys := NewStaticKeyProvider("k1", key1)
+
+	data, err := EncryptQuote(ctx, keys, quote)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "EUR")
+
+	got, err := DecryptQuote(ctx, keys, data)
+	assert.NoError(t, err)
+	assert.Equal(t, quote.FinalAmount.String(), got.FinalAmount.String())
+	assert.Equal(t, quote.ToCurrency, got.ToCurrency)
+	assert.True(t, quote.Date.Equal(got.Date))
+
+	// Rotated keys still decrypt older quotes
+	rotated := NewStaticKeyProvider("k2", key2)
+	rotated.AddKey("k1", key1)
+	_, err = DecryptQuote(ctx, rotated, data)
+	assert.NoError(t, err)
+
+	// Unknown key
+	_, err = DecryptQuote(ctx, NewStaticKeyProvider("k2", key2), data)
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+
+	// Wrong key material
+	_, err = DecryptQuote(ctx, NewStaticKeyProvider("k1", key2), data)
+	assert.ErrorIs(t, err, ErrDecryptionFailed)
+
+	// Garbage input
+	_, err = DecryptQuote(ctx, keys, []byte("not json"))
+	assert.ErrorIs(t, err, ErrInvalidEnvelope)
+
+	// Invalid key size
+	_, err = EncryptQuote(ctx, NewStaticKeyProvider("bad", []byte("short")), quote)
+	assert.Error(t, err)
+}
@@ -0,0 +1,112 @@
+package converter
+
+import (
+	"context"
+
+	"github.com/shopspring/decimal"
+)
+
+// EncryptedQuoteStore is a QuoteStore that encrypts quotes at rest. Each
+// quote is sealed with EncryptQuote into a BlobStore, while a redacted copy
+// (amounts, fees and rates zeroed) is saved to an index QuoteStore so that
+// filtering and paging keep working without decrypting every quote.
+//
+// The index still holds the ID, reference, currencies, dates, status and
+// customer reference. Aggregate decrypts the matching quotes to total them.
+type EncryptedQuoteStore struct {
+	index QuoteStore
+	blobs BlobStore
+	keys  KeyProvider
+}
+
+// NewEncryptedQuoteStore creates a store sealing quotes into blobs with keys
+// and indexing their redacted copies in index.
+func NewEncryptedQuoteStore(index QuoteStore, blobs BlobStore, keys KeyProvider) *EncryptedQuoteStore {
+	return &EncryptedQuoteStore{index: index, blobs: blobs, keys: keys}
+}
+
+// Save implements QuoteStore.
+func (s *EncryptedQuoteStore) Save(ctx context.Context, quote *Quote) error {
+	data, err := EncryptQuote(ctx, s.keys, quote)
+	if err != nil {
+		return err
+	}
+	// The blob goes first so the index never points at a missing blob.
+	if err := s.blobs.Put(ctx, quote.ID, data); err != nil {
+		return err
+	}
+	return s.index.Save(ctx, redactQuote(quote))
+}
+
+// Get implements QuoteStore.
+func (s *EncryptedQuoteStore) Get(ctx context.Context, id string) (*Quote, error) {
+	meta, err := s.index.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.open(ctx, meta)
+}
+
+// UpdateStatus implements QuoteStore. Only the index is updated, since it is
+// authoritative for the status.
+func (s *EncryptedQuoteStore) UpdateStatus(ctx context.Context, id string, from, to QuoteStatus) error {
+	return s.index.UpdateStatus(ctx, id, from, to)
+}
+
+// List implements QuoteStore.
+func (s *EncryptedQuoteStore) List(ctx context.Context, filter QuoteFilter) (*QuotePage, error) {
+	page, err := s.index.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, meta := range page.Quotes {
+		if page.Quotes[i], err = s.open(ctx, meta); err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
+
+// Aggregate implements QuoteStore. Amounts are only held encrypted, so every
+// matching quote is decrypted.
+func (s *EncryptedQuoteStore) Aggregate(ctx context.Context, filter QuoteFilter) ([]QuoteAggregate, error) {
+	filter.Offset, filter.Limit = 0, 0
+	page, err := s.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return aggregateQuotes(page.Quotes), nil
+}
+
+// open decrypts the quote behind an index entry. The index is authoritative
+// for the status, which changes after the quote is sealed.
+func (s *EncryptedQuoteStore) open(ctx context.Context, meta *Quote) (*Quote, error) {
+	data, err := s.blobs.Get(ctx, meta.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	quote, err := DecryptQuote(ctx, s.keys, data)
+	if err != nil {
+		return nil, err
+	}
+	quote.Status = meta.Status
+	return quote, nil
+}
+
+// redactQuote returns a copy of quote without its amounts, fees and rates,
+// on either side.
+func redactQuote(quote *Quote) *Quote {
+	redacted := *quote
+	redacted.FromAmount = decimal.Zero
+	redacted.Fee = decimal.Zero
+	redacted.AmountToDeduct = decimal.Zero
+	redacted.Rate = decimal.Zero
+	redacted.Spread = decimal.Zero
+	redacted.FinalAmount = decimal.Zero
+	redacted.EffectiveRate = decimal.Zero
+	redacted.Send = QuoteSide{Currency: quote.Send.Currency}
+	redacted.Receive = QuoteSide{Currency: quote.Receive.Currency}
+	return &redacted
+}
@@ -0,0 +1,101 @@
+package converter
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncryptedQuoteStore(t *testing.T) {
+	ctx := context.Background()
+	seed, err := seedQuoteStore(t).List(ctx, QuoteFilter{})
+	assert.NoError(t, err)
+
+	index := NewMemoryQuoteStore()
+	blobs := NewMemoryBlobStore()
+	store := NewEncryptedQuoteStore(index, blobs, NewStaticKeyProvider("k1", bytes.Repeat([]byte{1}, 32)))
+	for _, q := range seed.Quotes {
+		assert.NoError(t, store.Save(ctx, q))
+	}
+
+	// Amounts are only stored encrypted
+	meta, err := index.Get(ctx, "q1")
+	assert.NoError(t, err)
+	assert.True(t, meta.FinalAmount.IsZero())
+	blob, err := blobs.Get(ctx, "q1")
+	assert.NoError(t, err)
+	assert.NotContains(t, string(blob), "150000")
+
+	q, err := store.Get(ctx, "q1")
+	assert.NoError(t, err)
+	assert.Equal(t, "150000", q.FinalAmount.String())
+
+	_, err = store.Get(ctx, "missing")
+	assert.ErrorIs(t, err, ErrQuoteNotFound)
+
+	// Filtering and paging run on the index, results are decrypted
+	page, err := store.List(ctx, QuoteFilter{ToCurrency: "NGN", Limit: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, page.Total)
+	assert.Len(t, page.Quotes, 2)
+	assert.Equal(t, "100", page.Quotes[0].FromAmount.String())
+
+	// Aggregates match the plaintext store
+	want, err := seedQuoteStore(t).Aggregate(ctx, QuoteFilter{})
+	assert.NoError(t, err)
+	got, err := store.Aggregate(ctx, QuoteFilter{Limit: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	// Status changes go to the index, which is authoritative
+	assert.NoError(t, store.UpdateStatus(ctx, "q1", QuoteStatusPending, QuoteStatusCancelled))
+	q, err = store.Get(ctx, "q1")
+	assert.NoError(t, err)
+	assert.Equal(t, QuoteStatusCancelled, q.Status)
+	assert.Equal(t, "150000", q.FinalAmount.String())
+
+	// A wrong key fails instead of returning garbage
+	wrong := NewEncryptedQuoteStore(index, blobs, NewStaticKeyProvider("k1", bytes.Repeat([]byte{2}, 32)))
+	_, err = wrong.Get(ctx, "q1")
+	assert.ErrorIs(t, err, ErrDecryptionFailed)
+}
+
+func TestRedactQuote(t *testing.T) {
+	// Every decimal of the quote is set, so a field added later that
+	// redactQuote misses fails here instead of leaking into the index.
+	quote := &Quote{ID: "q1", FromCurrency: "USD", ToCurrency: "NGN"}
+	seven := decimal.NewFromInt(7)
+	walkDecimals(reflect.ValueOf(quote).Elem(), "Quote", func(_ string, v reflect.Value) {
+		v.Set(reflect.ValueOf(seven))
+	})
+	quote.Send.Currency, quote.Receive.Currency = "USD", "NGN"
+
+	redacted := redactQuote(quote)
+	walkDecimals(reflect.ValueOf(redacted).Elem(), "Quote", func(name string, v reflect.Value) {
+		assert.True(t, v.Interface().(decimal.Decimal).IsZero(), "%s is not redacted", name)
+	})
+	assert.Equal(t, "q1", redacted.ID)
+	assert.Equal(t, "USD", redacted.Send.Currency)
+	assert.Equal(t, "NGN", redacted.Receive.Currency)
+	assert.Equal(t, "7", quote.FinalAmount.String(), "the quote itself is left alone")
+}
+
+// walkDecimals calls fn with every decimal.Decimal field of v, a struct,
+// descending into nested structs.
+func walkDecimals(v reflect.Value, path string, fn func(name string, v reflect.Value)) {
+	decimalType := reflect.TypeOf(decimal.Decimal{})
+	for i := 0; i < v.NumField(); i++ {
+		field, name := v.Field(i), path+"."+v.Type().Field(i).Name
+		switch {
+		case !v.Type().Field(i).IsExported():
+		case field.Type() == decimalType:
+			fn(name, field)
+		case field.Kind() == reflect.Struct:
+			walkDecimals(field, name, fn)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package converter
+
+import (
+	"context"
+	"time"
+)
+
+// EventType identifies the kind of an Event.
+type EventType string
+
+// Event types
+const (
+	EventQuoteCreated     EventType = "quote.created"
+	EventQuoteExpired     EventType = "quote.expired"
+	EventQuoteBlocked     EventType = "quote.blocked"
+	EventQuoteAccepted    EventType = "quote.accepted"
+	EventQuoteLocked      EventType = "quote.locked"
+	EventQuoteAmended     EventType = "quote.amended"
+	EventQuoteExecuted    EventType = "quote.executed"
+	EventQuoteCancelled   EventType = "quote.cancelled"
+	EventGuaranteeExpired EventType = "guarantee.expired"
+	EventRateRejected     EventType = "rate.rejected"
+	EventRateQuarantined  EventType = "rate.quarantined"
+	EventRateDisagreement EventType = "rate.disagreement"
+	EventLeaderElected    EventType = "leader.elected"
+	EventLeaderLost       EventType = "leader.lost"
+)
+
+// Event describes something that happened inside the converter, for
+// notifications, auditing and monitoring.
+type Event struct {
+	Type    EventType `json:"type"`
+	Time    time.Time `json:"time"`
+	QuoteID string    `json:"quoteId,omitempty"`
+	// Pair and Reason describe a blocked quote, e.g. "USD/RUB" and
+	// "sanctions". Pair is also set on guarantee and rate events, and
+	// Reason on cancellations and rate events.
+	Pair   string `json:"pair,omitempty"`
+	Reason string `json:"reason,omitempty"`
+	// Actor is who caused the event, taken from the context of the call
+	// (see ContextWithActor), or the instance of a leader event.
+	Actor string `json:"actor,omitempty"`
+}
+
+type actorKey struct{}
+
+// ContextWithActor returns a context naming who acts through it, e.g. a
+// user or service ID, for the events recorded in quote trails.
+func ContextWithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor set with ContextWithActor, or "".
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+
+// EventHandler receives events. Handlers are called synchronously and should
+// not block.
+type EventHandler func(Event)
@@ -0,0 +1,44 @@
+package converter
+
+import (
+	"math/big"
+
+	"github.com/shopspring/decimal"
+)
+
+// CalculateRateExact calculates the exchange rate between two currencies as
+// an exact rational number, following the rules of CalculateRate. No
+// division is rounded, so inverse and cross rates carry no error until they
+// are converted with RatToDecimal.
+func CalculateRateExact(currencies []Currency, baseCurrency, from, to string) (*big.Rat, error) {
+	sell, buy, err := ratePath(currencies, baseCurrency, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Rat).Quo(sell.Rat(), buy.Rat()), nil
+}
+
+// RatToDecimal converts an exact rational to a decimal with d's precision and
+// rounding.
+func RatToDecimal(r *big.Rat, d Division) decimal.Decimal {
+	return d.Div(decimal.NewFromBigInt(r.Num(), 0), decimal.NewFromBigInt(r.Denom(), 0))
+}
+
+// WithExactArithmetic makes the table compute rates with CalculateRateExact
+// and convert them to decimals only at output: rates are rounded with the
+// table's Division (or the global DivisionPrecision, half up), and a quote's
+// final amount is computed from the exact rate and rounded up to the target
+// currency's precision, as with decimal arithmetic.
+func WithExactArithmetic() TableOption {
+	return func(c *Currencies) {
+		c.exact = true
+	}
+}
+
+// exactDivision is the division used to output exact rates.
+func exactDivision(div *Division) Division {
+	if div != nil {
+		return *div
+	}
+	return Division{Precision: int32(decimal.DivisionPrecision), Rounding: RoundHalfUp}
+}
@@ -0,0 +1,68 @@
+package converter
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateRateExact(t *testing.T) {
+	currencies := testCurrencies()
+
+	r, err := CalculateRateExact(currencies, "USD", "EUR", "NGN")
+	assert.NoError(t, err)
+	assert.Equal(t, "15500/9", r.String())
+
+	r, err = CalculateRateExact(currencies, "USD", "NGN", "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, "1/1500", r.String())
+
+	r, err = CalculateRateExact(currencies, "USD", "USD", "usd")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", r.RatString())
+
+	_, err = CalculateRateExact(currencies, "GBP", "USD", "EUR")
+	assert.ErrorIs(t, err, ErrBaseCurrencyNotFound)
+}
+
+func TestRatToDecimal(t *testing.T) {
+	third := big.NewRat(1, 3)
+	assert.Equal(t, "0.3333", RatToDecimal(third, Division{Precision: 4}).String())
+	assert.Equal(t, "0.3334", RatToDecimal(third, Division{Precision: 4, Rounding: RoundCeiling}).String())
+	assert.Equal(t, "-0.3333", RatToDecimal(new(big.Rat).Neg(third), Division{Precision: 4, Rounding: RoundCeiling}).String())
+	assert.Equal(t, "2.5", RatToDecimal(big.NewRat(5, 2), Division{Precision: 8}).String())
+}
+
+func TestCurrencies_WithExactArithmetic(t *testing.T) {
+	currencies := []Currency{
+		{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+		{ISOCode: "XYZ", Precision: 2, BuyRate: decimal.NewFromInt(3), SellRate: decimal.NewFromInt(3)},
+	}
+	amount := decimal.RequireFromString("300000000000000000")
+
+	// With decimal arithmetic the rounded 1/3 leaks into large amounts
+	q, err := NewTable(currencies).NewQuote("USD", "XYZ", "USD", amount, decimal.Zero)
+	assert.NoError(t, err)
+	assert.Equal(t, "99999999999999990", q.FinalAmount.String())
+
+	// Exact arithmetic only rounds the output
+	exact := NewTable(currencies, WithExactArithmetic(), WithDivision(Division{Precision: 10}))
+	q, err = exact.NewQuote("USD", "XYZ", "USD", amount, decimal.Zero)
+	assert.NoError(t, err)
+	assert.Equal(t, "100000000000000000", q.FinalAmount.String())
+	assert.Equal(t, "0.3333333333", q.Rate.String())
+
+	rate, err := exact.CalculateRate("USD", "XYZ", "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, "0.3333333333", rate.String())
+
+	// Without a division the global precision is used for output
+	rate, err = NewTable(currencies, WithExactArithmetic()).CalculateRate("USD", "XYZ", "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, "0.3333333333333333", rate.String())
+
+	_, err = exact.NewQuote("USD", "XYZ", "GBP", amount, decimal.Zero)
+	assert.Error(t, err)
+}
@@ -0,0 +1,141 @@
+package converter
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// RateStep is one step of a rate derivation.
+type RateStep struct {
+	// Kind names the step: "closed-market", "peg", "same-currency",
+	// "sell-leg", "buy-leg", "invert", "multiply", "exact" or "spread".
+	Kind        string          `json:"kind"`
+	Description string          `json:"description"`
+	Value       decimal.Decimal `json:"value"`
+}
+
+// RateExplanation is the step-by-step derivation of a rate, for answering
+// "why did the customer get this rate".
+type RateExplanation struct {
+	Base  string          `json:"base"`
+	From  string          `json:"from"`
+	To    string          `json:"to"`
+	Rate  decimal.Decimal `json:"rate"`
+	Steps []RateStep      `json:"steps"`
+}
+
+// WriteText writes the derivation as numbered lines, then the rate.
+func (e *RateExplanation) WriteText(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s to %s (base %s)\n", e.From, e.To, e.Base)
+	for i, step := range e.Steps {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, step.Description)
+	}
+	fmt.Fprintf(&b, "rate: %s\n", e.Rate)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (e *RateExplanation) add(kind string, value decimal.Decimal, format string, args ...any) {
+	e.Steps = append(e.Steps, RateStep{Kind: kind, Description: fmt.Sprintf(format, args...), Value: value})
+}
+
+// ExplainRate derives the rate from one currency to another as CalculateRate
+// does, recording each step: closed-market and peg adjustments, which leg
+// uses which side of the spread, the divisions and their rounding, and the
+// spread policy. The explained rate is the one CalculateRate returns.
+func (c *Currencies) ExplainRate(base, from, to string) (*RateExplanation, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	e := &RateExplanation{Base: strings.ToUpper(base), From: strings.ToUpper(from), To: strings.ToUpper(to)}
+
+	items := c.pricingItems(base, from, to)
+	for _, code := range []string{e.From, e.To} {
+		if adjusted, changed := changedRates(c.items, items, code); changed {
+			e.add("closed-market", adjusted.SellRate,
+				"%s market is closed: priced at buy %s, sell %s", code, adjusted.BuyRate, adjusted.SellRate)
+		}
+	}
+	pegged, broken := c.applyPegs(items, base, from, to)
+	for _, code := range []string{e.From, e.To} {
+		if adjusted, changed := changedRates(items, pegged, code); changed {
+			e.add("peg", adjusted.SellRate,
+				"%s priced at its peg: buy %s, sell %s", code, adjusted.BuyRate, adjusted.SellRate)
+		}
+	}
+	if broken {
+		e.add("peg", decimal.Zero, "a peg is outside its tolerance: priced at market")
+	}
+	items = pegged
+
+	sell, buy, err := ratePath(items, base, from, to)
+	if err != nil {
+		return nil, err
+	}
+	one := decimal.NewFromInt(1)
+
+	switch {
+	case e.From == e.To:
+		e.add("same-currency", one, "%s to itself: rate 1", e.From)
+	case hasOwnBases(items):
+		e.add("buy-leg", buy, "buy leg: %s buy rate carried to the common anchor = %s", e.From, buy)
+		e.add("sell-leg", sell, "sell leg: %s sell rate carried to the common anchor = %s", e.To, sell)
+	default:
+		if e.From != e.Base {
+			e.add("buy-leg", buy, "buy leg: %s to %s at the %s buy rate %s", e.From, e.Base, e.From, buy)
+		}
+		if e.To != e.Base {
+			e.add("sell-leg", sell, "sell leg: %s to %s at the %s sell rate %s", e.Base, e.To, e.To, sell)
+		}
+	}
+
+	rate := sell
+	switch {
+	case c.exact:
+		div := exactDivision(c.division)
+		rate = RatToDecimal(new(big.Rat).Quo(sell.Rat(), buy.Rat()), div)
+		if !buy.Equal(one) {
+			e.add("exact", rate, "exact %s / %s = %s, rounded %s to %d places", sell, buy, rate, div.Rounding, div.Precision)
+		}
+	case !buy.Equal(one):
+		inverse := divide(c.division, one, buy)
+		if c.division != nil {
+			e.add("invert", inverse, "1 / %s = %s, rounded %s to %d places", buy, inverse, c.division.Rounding, c.division.Precision)
+		} else {
+			e.add("invert", inverse, "1 / %s = %s, rounded half-up to %d places (global DivisionPrecision)", buy, inverse, decimal.DivisionPrecision)
+		}
+		rate = inverse.Mul(sell)
+		e.add("multiply", rate, "%s × %s = %s", inverse, sell, rate)
+	}
+
+	if spread := c.spreadFor(from, to); !spread.IsZero() && e.From != e.To {
+		if c.exact {
+			rate = RatToDecimal(applySpreadExact(new(big.Rat).Quo(sell.Rat(), buy.Rat()), spread), exactDivision(c.division))
+		} else {
+			rate = applySpread(rate, spread)
+		}
+		e.add("spread", rate, "spread %s: rate × (1 − %s) = %s", spread, spread, rate)
+	}
+
+	e.Rate = rate
+	return e, nil
+}
+
+// changedRates reports whether code has other rates in after than in
+// before, and returns them.
+func changedRates(before, after []Currency, code string) (Currency, bool) {
+	b, err := FindCurrency(before, code)
+	if err != nil {
+		return Currency{}, false
+	}
+	a, err := FindCurrency(after, code)
+	if err != nil {
+		return Currency{}, false
+	}
+	return *a, !a.BuyRate.Equal(b.BuyRate) || !a.SellRate.Equal(b.SellRate)
+}
@@ -0,0 +1,80 @@
+package converter
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurrencies_ExplainRate(t *testing.T) {
+	tables := map[string]*Currencies{
+		"default":  NewTable(testCurrencies()),
+		"division": NewTable(testCurrencies(), WithDivision(Division{Precision: 6, Rounding: RoundDown})),
+		"exact":    NewTable(testCurrencies(), WithExactArithmetic()),
+		"spread": NewTable(testCurrencies(), WithSpreadPolicy(SpreadPolicy{
+			Currencies: map[string]decimal.Decimal{"NGN": decimal.RequireFromString("0.01")},
+		})),
+	}
+	pairs := [][2]string{{"USD", "NGN"}, {"NGN", "USD"}, {"EUR", "NGN"}, {"NGN", "NGN"}}
+
+	for name, table := range tables {
+		for _, pair := range pairs {
+			e, err := table.ExplainRate("usd", pair[0], pair[1])
+			assert.NoError(t, err)
+			rate, err := table.CalculateRate("usd", pair[0], pair[1])
+			assert.NoError(t, err)
+			assert.Equal(t, rate.String(), e.Rate.String(), "%s %v", name, pair)
+			assert.NotEmpty(t, e.Steps, "%s %v", name, pair)
+		}
+	}
+}
+
+func TestCurrencies_ExplainRateSteps(t *testing.T) {
+	table := NewTable(testCurrencies(), WithDivision(Division{Precision: 6, Rounding: RoundDown}))
+
+	e, err := table.ExplainRate("USD", "eur", "NGN")
+	assert.NoError(t, err)
+
+	var kinds []string
+	for _, s := range e.Steps {
+		kinds = append(kinds, s.Kind)
+	}
+	assert.Equal(t, []string{"buy-leg", "sell-leg", "invert", "multiply"}, kinds)
+	assert.Equal(t, "1.111111", e.Steps[2].Value.String())
+	assert.Equal(t, "1722.22205", e.Rate.String())
+
+	var buf bytes.Buffer
+	assert.NoError(t, e.WriteText(&buf))
+	assert.Equal(t, `EUR to NGN (base USD)
+1. buy leg: EUR to USD at the EUR buy rate 0.9
+2. sell leg: USD to NGN at the NGN sell rate 1550
+3. 1 / 0.9 = 1.111111, rounded down to 6 places
+4. 1.111111 × 1550 = 1722.22205
+rate: 1722.22205
+`, buf.String())
+
+	spread := NewTable(testCurrencies(), WithSpreadPolicy(SpreadPolicy{
+		Pairs: map[string]decimal.Decimal{"USD/NGN": decimal.RequireFromString("0.02")},
+	}))
+	e, err = spread.ExplainRate("USD", "USD", "NGN")
+	assert.NoError(t, err)
+	assert.Len(t, e.Steps, 2)
+	assert.Equal(t, "sell-leg", e.Steps[0].Kind)
+	assert.Equal(t, "spread 0.02: rate × (1 − 0.02) = 1519", e.Steps[1].Description)
+
+	saturday := time.Date(2024, 3, 30, 16, 0, 0, 0, time.UTC)
+	clock := fixedClock(saturday)
+	closed := NewTable(nil, WithClosedMarketPolicy(ClosedMarketPolicy{Spread: decimal.RequireFromString("0.01"), Clock: &clock}))
+	closed.Update(testCurrencies(), saturday)
+	e, err = closed.ExplainRate("USD", "USD", "NGN")
+	assert.NoError(t, err)
+	assert.Equal(t, "closed-market", e.Steps[0].Kind)
+	assert.Equal(t, "NGN market is closed: priced at buy 1515, sell 1534.5", e.Steps[0].Description)
+	assert.Equal(t, "1534.5", e.Rate.String())
+
+	_, err = table.ExplainRate("USD", "USD", "GBP")
+	assert.ErrorIs(t, err, ErrUnknownCurrency)
+}
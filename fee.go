@@ -0,0 +1,128 @@
+package converter
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Fee errors
+var (
+	ErrInvalidFeeMode   = errors.New("invalid fee mode")
+	ErrFeeExceedsAmount = errors.New("fee exceeds amount")
+)
+
+// FeeMode is how a quote charges its fee.
+type FeeMode string
+
+// Fee modes
+const (
+	// FeeAdditive charges the fee, in the source currency, on top of the
+	// amount: AmountToDeduct is FromAmount plus Fee and FinalAmount is
+	// FromAmount converted. It is the default.
+	FeeAdditive FeeMode = "additive"
+	// FeeInDestination charges the fee in the destination currency, out
+	// of the converted amount: AmountToDeduct is FromAmount and FinalAmount
+	// is FromAmount converted less Fee. The fee passed to NewQuote is in
+	// the destination currency; a fee schedule is priced in the
+	// source currency and converted at the quote's rate.
+	FeeInDestination FeeMode = "destination"
+	// FeeDeducted takes the fee, in the source currency, out of the
+	// amount before converting it: AmountToDeduct is FromAmount and
+	// FinalAmount is FromAmount less Fee, converted. The sender pays
+	// FromAmount all in and the recipient bears the fee.
+	FeeDeducted FeeMode = "deducted"
+)
+
+// valid reports whether m is a known fee mode.
+func (m FeeMode) valid() bool {
+	switch m {
+	case FeeAdditive, FeeInDestination, FeeDeducted:
+		return true
+	}
+	return false
+}
+
+// WithFeeMode sets how the quote charges its fee. It defaults to
+// FeeAdditive.
+func WithFeeMode(mode FeeMode) QuoteOption {
+	return func(o *quoteOptions) {
+		o.feeMode = mode
+	}
+}
+
+// FeeSchedule prices a fee as a fixed part plus a share of the amount, as
+// card and remittance pricing usually is, e.g. 2.9% + 0.30. Each part is
+// rounded up to the source currency's precision before they are added.
+type FeeSchedule struct {
+	// Fixed is charged on every quote, in units of the base currency.
+	Fixed decimal.Decimal
+	// FixedCurrencies sets the fixed part of a source currency in its own
+	// units, e.g. 0.30 for "USD", instead of converting Fixed. Codes are
+	// matched case-insensitively.
+	FixedCurrencies map[string]decimal.Decimal
+	// Rate is the share of the amount charged, e.g. 0.01 for 1%.
+	Rate decimal.Decimal
+}
+
+// WithFeeSchedule prices the quote's fee with s instead of the fee passed to
+// NewQuote. A tier's fee schedule takes precedence.
+func WithFeeSchedule(s FeeSchedule) QuoteOption {
+	return func(o *quoteOptions) {
+		o.fees = &s
+	}
+}
+
+// Parts returns the fixed and the percentage parts of the fee of converting
+// amount from a currency, in units of that currency, each rounded up to its
+// precision. The fee is their sum.
+func (s FeeSchedule) Parts(amount decimal.Decimal, from Currency) (fixed, percentage decimal.Decimal) {
+	return s.parts(amount, &from, Division{Precision: int32(from.Precision), Rounding: RoundCeiling})
+}
+
+// parts implements Parts, rounding each part with rounding.
+func (s FeeSchedule) parts(amount decimal.Decimal, from *Currency, rounding Division) (fixed, percentage decimal.Decimal) {
+	fixed = s.Fixed.Mul(from.BuyRate)
+	for code, f := range s.FixedCurrencies {
+		if strings.EqualFold(code, from.ISOCode) {
+			fixed = f
+			break
+		}
+	}
+	return rounding.round(fixed), rounding.round(amount.Mul(s.Rate))
+}
+
+// fee returns the fee of converting amount from a currency, in units of
+// that currency, with each part rounded with rounding.
+func (s FeeSchedule) fee(amount decimal.Decimal, from *Currency, rounding Division) decimal.Decimal {
+	fixed, percentage := s.parts(amount, from, rounding)
+	return fixed.Add(percentage)
+}
+
+// WithFeeRounding rounds the quote's fee, and each part of a fee schedule,
+// with its own precision and mode instead of up to the fee currency's
+// precision, e.g. Division{Precision: 2, Rounding: RoundCeiling} to always
+// round up to the cent. The principal is rounded as before and
+// AmountToDeduct is the rounded principal plus the rounded fee, exactly.
+func WithFeeRounding(rounding Division) QuoteOption {
+	return func(o *quoteOptions) {
+		o.feeRounds = &rounding
+	}
+}
+
+// feeRounding returns how fees in the source currency and fees in the fee
+// currency are rounded. A fee schedule's parts are priced in the source
+// currency, so a FeeInDestination fee only takes the configured rounding
+// once converted.
+func (o *quoteOptions) feeRounding(from, feeCurrency *Currency) (source, fee Division) {
+	source = Division{Precision: int32(from.Precision), Rounding: RoundCeiling}
+	fee = Division{Precision: int32(feeCurrency.Precision), Rounding: RoundCeiling}
+	if o.feeRounds != nil {
+		fee = *o.feeRounds
+		if feeCurrency == from {
+			source = fee
+		}
+	}
+	return source, fee
+}
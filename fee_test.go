@@ -0,0 +1,119 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewQuote_FeeMode(t *testing.T) {
+	amount, fee := decimal.NewFromInt(100), decimal.NewFromInt(2)
+
+	quote, err := NewQuote(testCurrencies(), "USD", "USD", "NGN", amount, fee)
+	assert.NoError(t, err)
+	assert.Equal(t, FeeAdditive, quote.FeeMode)
+	assert.Equal(t, "USD", quote.FeeCurrency)
+	assert.Equal(t, "102", quote.AmountToDeduct.String())
+	assert.Equal(t, "155000", quote.FinalAmount.String())
+
+	quote, err = NewQuote(testCurrencies(), "USD", "USD", "NGN", amount, decimal.NewFromInt(3100), WithFeeMode(FeeInDestination))
+	assert.NoError(t, err)
+	assert.Equal(t, FeeInDestination, quote.FeeMode)
+	assert.Equal(t, "NGN", quote.FeeCurrency)
+	assert.Equal(t, "3100", quote.Fee.String())
+	assert.Equal(t, "100", quote.AmountToDeduct.String())
+	assert.Equal(t, "151900", quote.FinalAmount.String())
+
+	_, err = NewQuote(testCurrencies(), "USD", "USD", "NGN", amount, decimal.NewFromInt(200000), WithFeeMode(FeeInDestination))
+	assert.ErrorIs(t, err, ErrFeeExceedsAmount)
+
+	_, err = NewQuote(testCurrencies(), "USD", "USD", "NGN", amount, fee, WithFeeMode("sideways"))
+	assert.ErrorIs(t, err, ErrInvalidFeeMode)
+}
+
+func TestNewQuote_FeeInDestinationTier(t *testing.T) {
+	tier := Tier{Name: "basic", Fees: &FeeSchedule{Fixed: decimal.NewFromInt(1), Rate: decimal.RequireFromString("0.01")}}
+
+	quote, err := NewQuote(testCurrencies(), "USD", "USD", "NGN", decimal.NewFromInt(100), decimal.Zero, WithTier(tier), WithFeeMode(FeeInDestination))
+	assert.NoError(t, err)
+	assert.Equal(t, "3100", quote.Fee.String(), "2 USD priced in NGN")
+	assert.Equal(t, "151900", quote.FinalAmount.String())
+}
+
+func TestNewQuote_FeeDeducted(t *testing.T) {
+	quote, err := NewQuote(testCurrencies(), "USD", "USD", "NGN", decimal.NewFromInt(100), decimal.NewFromInt(2), WithFeeMode(FeeDeducted))
+	assert.NoError(t, err)
+	assert.Equal(t, FeeDeducted, quote.FeeMode)
+	assert.Equal(t, "USD", quote.FeeCurrency)
+	assert.Equal(t, "100", quote.AmountToDeduct.String())
+	assert.Equal(t, "151900", quote.FinalAmount.String())
+
+	exact := NewTable(testCurrencies(), WithExactArithmetic(), WithDivision(Division{Precision: 8}))
+	quote, err = exact.NewQuote("USD", "USD", "NGN", decimal.NewFromInt(100), decimal.NewFromInt(2), WithFeeMode(FeeDeducted))
+	assert.NoError(t, err)
+	assert.Equal(t, "151900", quote.FinalAmount.String())
+
+	_, err = NewQuote(testCurrencies(), "USD", "USD", "NGN", decimal.NewFromInt(1), decimal.NewFromInt(2), WithFeeMode(FeeDeducted))
+	assert.ErrorIs(t, err, ErrFeeExceedsAmount)
+}
+
+func TestFeeSchedule_Parts(t *testing.T) {
+	card := FeeSchedule{
+		Fixed:           decimal.RequireFromString("0.5"),
+		FixedCurrencies: map[string]decimal.Decimal{"usd": decimal.RequireFromString("0.30")},
+		Rate:            decimal.RequireFromString("0.029"),
+	}
+	usd := Currency{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1)}
+	jpy := Currency{ISOCode: "JPY", Precision: 0, BuyRate: decimal.RequireFromString("151.37")}
+
+	fixed, percentage := card.Parts(decimal.RequireFromString("10.01"), usd)
+	assert.Equal(t, "0.3", fixed.String())
+	assert.Equal(t, "0.3", percentage.String(), "0.29029 rounds up to the cent")
+
+	fixed, percentage = card.Parts(decimal.NewFromInt(1001), jpy)
+	assert.Equal(t, "76", fixed.String(), "75.685 rounds up to the yen")
+	assert.Equal(t, "30", percentage.String())
+}
+
+func TestNewQuote_WithFeeSchedule(t *testing.T) {
+	schedule := FeeSchedule{Fixed: decimal.NewFromInt(1), Rate: decimal.RequireFromString("0.015")}
+
+	quote, err := NewQuote(testCurrencies(), "USD", "EUR", "USD", decimal.RequireFromString("99.99"), decimal.NewFromInt(7), WithFeeSchedule(schedule))
+	assert.NoError(t, err)
+	assert.Equal(t, "2.4", quote.Fee.String(), "0.90 fixed + 1.49985 rounded up to 1.50")
+	assert.Equal(t, "102.39", quote.AmountToDeduct.String())
+
+	tier := Tier{Name: "gold", Fees: &FeeSchedule{}}
+	quote, err = NewQuote(testCurrencies(), "USD", "EUR", "USD", decimal.RequireFromString("99.99"), decimal.NewFromInt(7), WithFeeSchedule(schedule), WithTier(tier))
+	assert.NoError(t, err)
+	assert.True(t, quote.Fee.IsZero(), "the tier's schedule wins")
+}
+
+func TestNewQuote_FeeRounding(t *testing.T) {
+	amount := decimal.RequireFromString("100.001")
+	schedule := FeeSchedule{Fixed: decimal.RequireFromString("0.301"), Rate: decimal.RequireFromString("0.0123")}
+
+	quote, err := NewQuote(testCurrencies(), "USD", "USD", "NGN", amount, decimal.Zero, WithFeeSchedule(schedule))
+	assert.NoError(t, err)
+	assert.Equal(t, "1.55", quote.Fee.String(), "0.31 + 1.24 by default")
+	assert.Equal(t, "101.56", quote.AmountToDeduct.String())
+
+	quote, err = NewQuote(testCurrencies(), "USD", "USD", "NGN", amount, decimal.Zero, WithFeeSchedule(schedule),
+		WithFeeRounding(Division{Precision: 2, Rounding: RoundHalfEven}))
+	assert.NoError(t, err)
+	assert.Equal(t, "1.53", quote.Fee.String(), "0.30 + 1.23")
+	assert.Equal(t, "101.54", quote.AmountToDeduct.String())
+
+	quote, err = NewQuote(testCurrencies(), "USD", "USD", "NGN", amount, decimal.RequireFromString("0.12345"),
+		WithFeeRounding(Division{Precision: 4, Rounding: RoundDown}))
+	assert.NoError(t, err)
+	assert.Equal(t, "0.1234", quote.Fee.String())
+	assert.Equal(t, "100.1334", quote.AmountToDeduct.String())
+	assert.True(t, quote.AmountToDeduct.Equal(amount.RoundCeil(2).Add(quote.Fee)))
+
+	quote, err = NewQuote(testCurrencies(), "USD", "USD", "NGN", decimal.NewFromInt(100), decimal.Zero, WithFeeSchedule(schedule),
+		WithFeeMode(FeeInDestination), WithFeeRounding(Division{Precision: -2, Rounding: RoundUp}))
+	assert.NoError(t, err)
+	assert.Equal(t, "2400", quote.Fee.String(), "1.54 USD is 2387 NGN, rounded up to the hundred")
+}
@@ -0,0 +1,76 @@
+package converter
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func FuzzNewCurrencies(f *testing.F) {
+	f.Add([]byte(`{"isoCode":"USD","precision":2,"buyRate":"1","sellRate":"1"}`))
+	f.Add([]byte(`{"isoCode":"EUR","precision":2,"buyRate":0.9,"sellRate":0.95}`))
+	f.Add([]byte(`{"isoCode":"BTC","precision":8,"buyRate":"1e-5","sellRate":"0"}`))
+	f.Add([]byte(`{"isoCode":"X","precision":-1,"buyRate":"1e2000000000"}`))
+	f.Add([]byte(`[]`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		currencies, err := NewCurrencies([]json.RawMessage{data})
+		if err != nil {
+			return
+		}
+
+		// Accepted input must be safe to price with.
+		for _, c := range currencies {
+			if err := c.Validate(); err != nil {
+				t.Fatalf("accepted invalid currency: %v", err)
+			}
+			_, _ = CalculateRate(append(currencies, Currency{ISOCode: "BASE", BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)}), "BASE", c.ISOCode, "BASE")
+			_, _ = NewQuote(currencies, c.ISOCode, c.ISOCode, c.ISOCode, decimal.NewFromInt(100), decimal.NewFromInt(1))
+		}
+	})
+}
+
+func FuzzParseMoney(f *testing.F) {
+	f.Add("100.50 USD")
+	f.Add("-0.000001 BTC")
+	f.Add("1e10 NGN")
+	f.Add("1e2000000000 USD")
+	f.Add("")
+
+	f.Fuzz(func(t *testing.T, s string) {
+		m, err := ParseMoney(s)
+		if err != nil {
+			return
+		}
+
+		// Valid money round-trips through its text form.
+		again, err := ParseMoney(m.String())
+		if err != nil {
+			t.Fatalf("re-parsing %q: %v", m.String(), err)
+		}
+		if !again.Amount.Equal(m.Amount) || again.Currency != m.Currency {
+			t.Fatalf("round trip changed %q to %q", m, again)
+		}
+	})
+}
+
+func FuzzNewCurrenciesFromCSV(f *testing.F) {
+	f.Add("isoCode,precision,buyRate,sellRate\nUSD,2,1,1\nEUR,2,0.9,0.95\n")
+	f.Add("isoCode,precision,buyRate,sellRate\nUSD,2,\"1\",1\n")
+	f.Add("isoCode,precision,buyRate,sellRate\nUSD,40,1,1\n")
+	f.Add("a,b\n1,2\n")
+
+	f.Fuzz(func(t *testing.T, s string) {
+		currencies, err := NewCurrenciesFromCSV(strings.NewReader(s))
+		if err != nil {
+			return
+		}
+		for _, c := range currencies {
+			if err := c.Validate(); err != nil {
+				t.Fatalf("accepted invalid currency: %v", err)
+			}
+		}
+	})
+}
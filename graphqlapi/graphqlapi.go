@@ -0,0 +1,253 @@
+// Package graphqlapi serves the converter over GraphQL: queries for the
+// rate table, rates and rate history, and mutations to create and accept
+// quotes, backed by the core types. Errors carry the same code and status
+// as the HTTP API in their extensions.
+package graphqlapi
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/otyang/converter"
+	"github.com/otyang/converter/httpapi"
+	"github.com/shopspring/decimal"
+)
+
+// Schema is the GraphQL schema served.
+//
+//go:embed schema.graphql
+var Schema string
+
+// Config errors
+var (
+	ErrNoTable       = errors.New("graphqlapi: no rate table")
+	ErrNoQuoteStore  = errors.New("graphqlapi: no quote store")
+	ErrNoGuarantees  = errors.New("graphqlapi: no guarantees")
+	ErrNoRateHistory = errors.New("graphqlapi: no rate history")
+)
+
+// ErrInvalidArgument is returned for malformed field arguments, e.g. a time
+// that is not RFC 3339.
+var ErrInvalidArgument = errors.New("invalid argument")
+
+// Config holds what the API is backed by. Only Table is required; the
+// fields using the others fail without them.
+type Config struct {
+	Table *converter.Currencies
+	// Base is the currency rates and quotes are priced against.
+	Base string
+	// Quotes stores created quotes; quote and createQuote need it.
+	Quotes converter.QuoteStore
+	// Guarantees accepts quotes; acceptQuote needs it, and it should share
+	// Quotes.
+	Guarantees *converter.Guarantees
+	// History backs rateHistory.
+	History *converter.AuditLog
+	// QuoteOptions are applied to every created quote, e.g. a TTL.
+	QuoteOptions []converter.QuoteOption
+}
+
+// NewSchema parses Schema against resolvers backed by cfg.
+func NewSchema(cfg Config) (*graphql.Schema, error) {
+	if cfg.Table == nil {
+		return nil, ErrNoTable
+	}
+	return graphql.ParseSchema(Schema, &resolver{cfg: cfg})
+}
+
+// NewHandler serves the schema over HTTP: POST requests with a JSON body
+// holding the query, operation name and variables.
+func NewHandler(cfg Config) (http.Handler, error) {
+	schema, err := NewSchema(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &relay.Handler{Schema: schema}, nil
+}
+
+// apiError is a resolver error described as the HTTP API would. Server
+// errors only show their status text, so internals do not leak to clients.
+type apiError struct {
+	problem httpapi.Problem
+	err     error
+}
+
+func newError(err error) error {
+	if err == nil {
+		return nil
+	}
+	p := httpapi.NewProblem(err)
+	if errors.Is(err, ErrInvalidArgument) {
+		p.Status = http.StatusBadRequest
+		p.Title = http.StatusText(http.StatusBadRequest)
+		p.Code = "invalid_argument"
+		p.Detail = err.Error()
+	}
+	return &apiError{problem: p, err: err}
+}
+
+func (e *apiError) Error() string {
+	if e.problem.Detail != "" {
+		return e.problem.Detail
+	}
+	return e.problem.Title
+}
+
+func (e *apiError) Unwrap() error {
+	return e.err
+}
+
+// Extensions implements graphql-go's error extensions.
+func (e *apiError) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":   e.problem.Code,
+		"status": e.problem.Status,
+	}
+}
+
+// resolver resolves Query and Mutation.
+type resolver struct {
+	cfg Config
+}
+
+func (r *resolver) Currencies() []*currencyResolver {
+	list := r.cfg.Table.List()
+	currencies := make([]*currencyResolver, len(list))
+	for i := range list {
+		currencies[i] = &currencyResolver{c: &list[i]}
+	}
+	return currencies
+}
+
+func (r *resolver) Rates(args struct{ From, To *string }) ([]*rateResolver, error) {
+	m, err := r.cfg.Table.ExportMatrix(r.cfg.Base)
+	if err != nil {
+		return nil, newError(err)
+	}
+
+	var rates []*rateResolver
+	for _, from := range m.Currencies {
+		if args.From != nil && !strings.EqualFold(*args.From, from) {
+			continue
+		}
+		for _, to := range m.Currencies {
+			if args.To != nil && !strings.EqualFold(*args.To, to) {
+				continue
+			}
+			rate, _ := m.Rate(from, to)
+			rates = append(rates, &rateResolver{from: from, to: to, rate: rate})
+		}
+	}
+	return rates, nil
+}
+
+func (r *resolver) RateHistory(args struct {
+	Code  string
+	Since *string
+	Limit *int32
+}) ([]*rateChangeResolver, error) {
+	if r.cfg.History == nil {
+		return nil, newError(ErrNoRateHistory)
+	}
+	var since time.Time
+	if args.Since != nil {
+		t, err := time.Parse(time.RFC3339, *args.Since)
+		if err != nil {
+			return nil, newError(fmt.Errorf("%w: since %q is not an RFC 3339 time", ErrInvalidArgument, *args.Since))
+		}
+		since = t
+	}
+	if args.Limit != nil && *args.Limit < 0 {
+		return nil, newError(fmt.Errorf("%w: negative limit", ErrInvalidArgument))
+	}
+
+	var changes []*rateChangeResolver
+	for _, e := range r.cfg.History.Entries() {
+		if strings.EqualFold(e.ISOCode, args.Code) && !e.Time.Before(since) {
+			changes = append(changes, &rateChangeResolver{e: e})
+		}
+	}
+	if args.Limit != nil && int(*args.Limit) < len(changes) {
+		changes = changes[len(changes)-int(*args.Limit):]
+	}
+	return changes, nil
+}
+
+func (r *resolver) Quote(ctx context.Context, args struct{ ID graphql.ID }) (*quoteResolver, error) {
+	if r.cfg.Quotes == nil {
+		return nil, newError(ErrNoQuoteStore)
+	}
+	q, err := r.cfg.Quotes.Get(ctx, string(args.ID))
+	if errors.Is(err, converter.ErrQuoteNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, newError(err)
+	}
+	return &quoteResolver{q: q}, nil
+}
+
+func (r *resolver) CreateQuote(ctx context.Context, args struct {
+	Input struct {
+		From, To, Amount          string
+		Fee, FeeMode, CustomerRef *string
+	}
+}) (*quoteResolver, error) {
+	if r.cfg.Quotes == nil {
+		return nil, newError(ErrNoQuoteStore)
+	}
+	in := args.Input
+
+	amount, err := parseDecimal("amount", in.Amount)
+	if err != nil {
+		return nil, newError(err)
+	}
+	fee := decimal.Zero
+	if in.Fee != nil {
+		if fee, err = parseDecimal("fee", *in.Fee); err != nil {
+			return nil, newError(err)
+		}
+	}
+	opts := append([]converter.QuoteOption{}, r.cfg.QuoteOptions...)
+	if in.FeeMode != nil {
+		opts = append(opts, converter.WithFeeMode(converter.FeeMode(*in.FeeMode)))
+	}
+	if in.CustomerRef != nil {
+		opts = append(opts, converter.WithCustomerRef(*in.CustomerRef))
+	}
+
+	q, err := r.cfg.Table.NewQuote(r.cfg.Base, in.From, in.To, amount, fee, opts...)
+	if err != nil {
+		return nil, newError(err)
+	}
+	if err := r.cfg.Quotes.Save(ctx, q); err != nil {
+		return nil, newError(err)
+	}
+	return &quoteResolver{q: q}, nil
+}
+
+func (r *resolver) AcceptQuote(ctx context.Context, args struct{ ID graphql.ID }) (*guaranteeResolver, error) {
+	if r.cfg.Guarantees == nil {
+		return nil, newError(ErrNoGuarantees)
+	}
+	g, err := r.cfg.Guarantees.Accept(ctx, string(args.ID))
+	if err != nil {
+		return nil, newError(err)
+	}
+	return &guaranteeResolver{g: g}, nil
+}
+
+func parseDecimal(name, s string) (decimal.Decimal, error) {
+	d, err := decimal.NewFromString(strings.TrimSpace(s))
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("%w: %s %q is not a decimal number", converter.ErrInvalidAmount, name, s)
+	}
+	return d, nil
+}
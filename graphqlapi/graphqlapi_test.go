@@ -0,0 +1,161 @@
+package graphqlapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/otyang/converter"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+type clock time.Time
+
+func (c clock) Now() time.Time { return time.Time(c) }
+
+func testConfig(t *testing.T) Config {
+	t.Helper()
+	now := time.Date(2024, 3, 28, 12, 0, 0, 0, time.UTC)
+	rates := []converter.Currency{
+		{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+		{ISOCode: "NGN", Precision: 2, BuyRate: decimal.NewFromInt(1550), SellRate: decimal.NewFromInt(1500)},
+	}
+	table := converter.NewTable(rates)
+
+	history := converter.NewAuditLog()
+	history.Record(nil, rates, now.Add(-time.Hour))
+	requoted := append([]converter.Currency(nil), rates...)
+	requoted[1].SellRate = decimal.NewFromInt(1510)
+	history.Record(rates, requoted, now)
+
+	store := converter.NewMemoryQuoteStore()
+	guarantees, err := converter.NewGuarantees(store, time.Minute)
+	assert.NoError(t, err)
+	guarantees.Clock = clock(now)
+
+	return Config{
+		Table:        table,
+		Base:         "USD",
+		Quotes:       store,
+		Guarantees:   guarantees,
+		History:      history,
+		QuoteOptions: []converter.QuoteOption{converter.WithClock(clock(now)), converter.WithTTL(time.Minute)},
+	}
+}
+
+// exec runs a query and returns its data and errors as generic JSON.
+func exec(t *testing.T, cfg Config, query string, variables map[string]any) (map[string]any, []map[string]any) {
+	t.Helper()
+	schema, err := NewSchema(cfg)
+	assert.NoError(t, err)
+
+	resp := schema.Exec(context.Background(), query, "", variables)
+	b, err := json.Marshal(resp)
+	assert.NoError(t, err)
+	var out struct {
+		Data   map[string]any   `json:"data"`
+		Errors []map[string]any `json:"errors"`
+	}
+	assert.NoError(t, json.Unmarshal(b, &out))
+	return out.Data, out.Errors
+}
+
+func TestQueries(t *testing.T) {
+	cfg := testConfig(t)
+
+	data, errs := exec(t, cfg, `{
+		currencies { isoCode precision buyRate sellRate }
+		rates(from: "usd") { from to rate }
+		rateHistory(code: "NGN", limit: 1) { sequence time previous { sellRate } current { sellRate } }
+	}`, nil)
+	assert.Empty(t, errs)
+	assert.Equal(t, []any{
+		map[string]any{"isoCode": "NGN", "precision": float64(2), "buyRate": "1550", "sellRate": "1500"},
+		map[string]any{"isoCode": "USD", "precision": float64(2), "buyRate": "1", "sellRate": "1"},
+	}, data["currencies"])
+	assert.Equal(t, []any{
+		map[string]any{"from": "USD", "to": "NGN", "rate": "1500"},
+		map[string]any{"from": "USD", "to": "USD", "rate": "1"},
+	}, data["rates"])
+	assert.Equal(t, []any{
+		map[string]any{
+			"sequence": float64(3),
+			"time":     "2024-03-28T12:00:00Z",
+			"previous": map[string]any{"sellRate": "1500"},
+			"current":  map[string]any{"sellRate": "1510"},
+		},
+	}, data["rateHistory"])
+
+	data, errs = exec(t, cfg, `{ rateHistory(code: "NGN", since: "2024-03-28T11:30:00Z") { sequence } }`, nil)
+	assert.Empty(t, errs)
+	assert.Len(t, data["rateHistory"], 1)
+
+	_, errs = exec(t, cfg, `{ rateHistory(code: "NGN", since: "yesterday") { sequence } }`, nil)
+	assert.Equal(t, "invalid_argument", errs[0]["extensions"].(map[string]any)["code"])
+}
+
+func TestMutations(t *testing.T) {
+	cfg := testConfig(t)
+
+	data, errs := exec(t, cfg, `mutation($input: QuoteInput!) {
+		createQuote(input: $input) { id fromAmount fee feeMode rate totalAmount status expiresAt customerRef }
+	}`, map[string]any{"input": map[string]any{"from": "USD", "to": "NGN", "amount": "100", "fee": "2", "customerRef": "cust-1"}})
+	assert.Empty(t, errs)
+	quote := data["createQuote"].(map[string]any)
+	assert.Equal(t, "100", quote["fromAmount"])
+	assert.Equal(t, "additive", quote["feeMode"])
+	assert.Equal(t, "150000", quote["totalAmount"])
+	assert.Equal(t, "pending", quote["status"])
+	assert.Equal(t, "2024-03-28T12:01:00Z", quote["expiresAt"])
+	assert.Equal(t, "cust-1", quote["customerRef"])
+	id := quote["id"].(string)
+
+	data, errs = exec(t, cfg, `mutation($id: ID!) { acceptQuote(id: $id) { quoteId pair rate expiresAt } }`, map[string]any{"id": id})
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]any{"quoteId": id, "pair": "USD/NGN", "rate": "1500", "expiresAt": "2024-03-28T12:01:00Z"}, data["acceptQuote"])
+
+	data, errs = exec(t, cfg, `query($id: ID!) { quote(id: $id) { status } }`, map[string]any{"id": id})
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]any{"status": "accepted"}, data["quote"])
+
+	data, errs = exec(t, cfg, `{ quote(id: "missing") { status } }`, nil)
+	assert.Empty(t, errs)
+	assert.Nil(t, data["quote"])
+}
+
+func TestErrors(t *testing.T) {
+	cfg := testConfig(t)
+
+	_, errs := exec(t, cfg, `mutation { createQuote(input: {from: "USD", to: "GBP", amount: "1"}) { id } }`, nil)
+	assert.Equal(t, "currency GBP not found", errs[0]["message"])
+	assert.Equal(t, map[string]any{"code": "currency_not_found", "status": float64(http.StatusNotFound)}, errs[0]["extensions"])
+
+	_, errs = exec(t, cfg, `mutation { createQuote(input: {from: "USD", to: "NGN", amount: "lots"}) { id } }`, nil)
+	assert.Equal(t, "invalid_amount", errs[0]["extensions"].(map[string]any)["code"])
+
+	_, errs = exec(t, cfg, `mutation { acceptQuote(id: "missing") { quoteId } }`, nil)
+	assert.Equal(t, "quote_not_found", errs[0]["extensions"].(map[string]any)["code"])
+
+	_, errs = exec(t, Config{Table: cfg.Table, Base: "USD"}, `{ rateHistory(code: "NGN") { sequence } }`, nil)
+	assert.Equal(t, "Internal Server Error", errs[0]["message"])
+	assert.Equal(t, "internal", errs[0]["extensions"].(map[string]any)["code"])
+
+	_, err := NewSchema(Config{})
+	assert.ErrorIs(t, err, ErrNoTable)
+}
+
+func TestNewHandler(t *testing.T) {
+	handler, err := NewHandler(testConfig(t))
+	assert.NoError(t, err)
+
+	body, _ := json.Marshal(map[string]any{"query": `{ rates(from: "NGN", to: "USD") { rate } }`})
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"data":{"rates":[{"rate":"0.0006451612903226"}]}}`, rec.Body.String())
+}
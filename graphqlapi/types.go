@@ -0,0 +1,105 @@
+package graphqlapi
+
+import (
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/otyang/converter"
+	"github.com/shopspring/decimal"
+)
+
+type currencyResolver struct {
+	c *converter.Currency
+}
+
+func (r *currencyResolver) ISOCode() string  { return r.c.ISOCode }
+func (r *currencyResolver) Precision() int32 { return int32(r.c.Precision) }
+func (r *currencyResolver) BuyRate() string  { return r.c.BuyRate.String() }
+func (r *currencyResolver) SellRate() string { return r.c.SellRate.String() }
+
+type rateResolver struct {
+	from, to string
+	rate     decimal.Decimal
+}
+
+func (r *rateResolver) From() string { return r.from }
+func (r *rateResolver) To() string   { return r.to }
+func (r *rateResolver) Rate() string { return r.rate.String() }
+
+type rateChangeResolver struct {
+	e converter.AuditEntry
+}
+
+func (r *rateChangeResolver) Sequence() int32 { return int32(r.e.Sequence) }
+func (r *rateChangeResolver) Time() string    { return formatTime(r.e.Time) }
+func (r *rateChangeResolver) ISOCode() string { return r.e.ISOCode }
+
+func (r *rateChangeResolver) Previous() *currencyResolver {
+	if r.e.Previous == nil {
+		return nil
+	}
+	return &currencyResolver{c: r.e.Previous}
+}
+
+func (r *rateChangeResolver) Current() *currencyResolver {
+	if r.e.Current == nil {
+		return nil
+	}
+	return &currencyResolver{c: r.e.Current}
+}
+
+type quoteResolver struct {
+	q *converter.Quote
+}
+
+func (r *quoteResolver) ID() graphql.ID         { return graphql.ID(r.q.ID) }
+func (r *quoteResolver) Reference() string      { return r.q.Reference }
+func (r *quoteResolver) BaseCurrency() string   { return r.q.BaseCurrency }
+func (r *quoteResolver) FromCurrency() string   { return r.q.FromCurrency }
+func (r *quoteResolver) ToCurrency() string     { return r.q.ToCurrency }
+func (r *quoteResolver) FromAmount() string     { return r.q.FromAmount.String() }
+func (r *quoteResolver) Fee() string            { return r.q.Fee.String() }
+func (r *quoteResolver) AmountToDeduct() string { return r.q.AmountToDeduct.String() }
+func (r *quoteResolver) Rate() string           { return r.q.Rate.String() }
+func (r *quoteResolver) TotalAmount() string    { return r.q.FinalAmount.String() }
+func (r *quoteResolver) EffectiveRate() string  { return r.q.EffectiveRate.String() }
+func (r *quoteResolver) Date() string           { return formatTime(r.q.Date) }
+func (r *quoteResolver) Status() string         { return string(r.q.Status) }
+
+func (r *quoteResolver) FeeMode() string {
+	if r.q.FeeMode == "" {
+		return string(converter.FeeAdditive)
+	}
+	return string(r.q.FeeMode)
+}
+
+func (r *quoteResolver) ExpiresAt() *string {
+	if r.q.ExpiresAt.IsZero() {
+		return nil
+	}
+	s := formatTime(r.q.ExpiresAt)
+	return &s
+}
+
+func (r *quoteResolver) CustomerRef() *string {
+	if r.q.CustomerRef == "" {
+		return nil
+	}
+	return &r.q.CustomerRef
+}
+
+type guaranteeResolver struct {
+	g *converter.Guarantee
+}
+
+func (r *guaranteeResolver) QuoteID() graphql.ID { return graphql.ID(r.g.QuoteID) }
+func (r *guaranteeResolver) Pair() string        { return r.g.Pair }
+func (r *guaranteeResolver) Rate() string        { return r.g.Rate.String() }
+func (r *guaranteeResolver) AcceptedAt() string  { return formatTime(r.g.AcceptedAt) }
+func (r *guaranteeResolver) ExpiresAt() string   { return formatTime(r.g.ExpiresAt) }
+func (r *guaranteeResolver) Amount() string      { return r.g.Amount.String() }
+func (r *guaranteeResolver) FinalAmount() string { return r.g.FinalAmount.String() }
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
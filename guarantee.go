@@ -0,0 +1,229 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Guarantee errors
+var (
+	ErrQuoteExpired     = errors.New("quote expired")
+	ErrNoGuarantee      = errors.New("no rate guarantee")
+	ErrGuaranteeExpired = errors.New("rate guarantee expired")
+	ErrInvalidWindow    = errors.New("guarantee window must be positive")
+)
+
+// defaultGuaranteeInterval is how often Guarantees.Run expires guarantees
+// when Interval is zero.
+const defaultGuaranteeInterval = time.Second
+
+// Guarantee is the rate honored for an accepted quote until it expires.
+type Guarantee struct {
+	QuoteID    string          `json:"quoteId"`
+	Pair       string          `json:"pair"`
+	Rate       decimal.Decimal `json:"rate"`
+	AcceptedAt time.Time       `json:"acceptedAt"`
+	ExpiresAt  time.Time       `json:"expiresAt"`
+	// Amount and FinalAmount are the quote's source and final amounts, as
+	// amended. Executed and Delivered are the parts of them executed so
+	// far, by Executions.
+	Amount      decimal.Decimal `json:"amount"`
+	FinalAmount decimal.Decimal `json:"finalAmount"`
+	Executed    decimal.Decimal `json:"executed"`
+	Delivered   decimal.Decimal `json:"delivered"`
+	Executions  []Execution     `json:"executions,omitempty"`
+
+	// toPrecision is the precision the quote's final amount was priced at,
+	// which executions' shares are rounded to.
+	toPrecision int
+}
+
+// clone returns a copy of g sharing nothing with it.
+func (g *Guarantee) clone() *Guarantee {
+	c := *g
+	c.Executions = slices.Clone(g.Executions)
+	return &c
+}
+
+// Guarantees accepts quotes and guarantees their rates for a window,
+// independently of rate table refreshes, so execution systems have a single
+// source of truth for the rates they must honor. Guarantees are held in
+// memory.
+type Guarantees struct {
+	Store  QuoteStore
+	Window time.Duration
+	// Interval is how often Run expires guarantees. It defaults to a
+	// second.
+	Interval time.Duration
+	// Clock defaults to SystemClock.
+	Clock Clock
+	// OnEvent, if set, receives an EventQuoteAccepted and an
+	// EventQuoteLocked per accepted quote, an event per amendment,
+	// execution and cancellation, and an EventGuaranteeExpired per expired
+	// guarantee. Events carry the actor of their context (see
+	// ContextWithActor).
+	OnEvent EventHandler
+
+	mu         sync.Mutex
+	guarantees map[string]*Guarantee
+}
+
+// NewGuarantees creates a tracker accepting the quotes of store and
+// guaranteeing their rates for window. It returns ErrInvalidWindow if
+// window is not positive.
+func NewGuarantees(store QuoteStore, window time.Duration) (*Guarantees, error) {
+	if window <= 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidWindow, window)
+	}
+	return &Guarantees{
+		Store:  store,
+		Window: window,
+		Clock:  SystemClock,
+	}, nil
+}
+
+// Accept moves a pending quote to accepted and guarantees its rate for the
+// window. It returns an ErrQuoteExpired error if the quote has expired and
+// ErrQuoteStatusChanged if it is no longer pending.
+func (g *Guarantees) Accept(ctx context.Context, id string) (*Guarantee, error) {
+	if g.Window <= 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidWindow, g.Window)
+	}
+	now := g.clock().Now()
+
+	q, err := g.Store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if q.IsExpired(now) {
+		return nil, fmt.Errorf("%w: %s at %s", ErrQuoteExpired, id, q.ExpiresAt.Format(time.RFC3339))
+	}
+	if err := g.Store.UpdateStatus(ctx, id, QuoteStatusPending, QuoteStatusAccepted); err != nil {
+		return nil, err
+	}
+
+	guarantee := &Guarantee{
+		QuoteID:     id,
+		Pair:        q.FromCurrency + "/" + q.ToCurrency,
+		Rate:        q.Rate,
+		AcceptedAt:  now,
+		ExpiresAt:   now.Add(g.Window),
+		Amount:      q.FromAmount,
+		FinalAmount: q.FinalAmount,
+	}
+	_, guarantee.toPrecision = quotePrecisions(q)
+	g.mu.Lock()
+	if g.guarantees == nil {
+		g.guarantees = make(map[string]*Guarantee)
+	}
+	g.guarantees[id] = guarantee
+	g.mu.Unlock()
+
+	actor := ActorFromContext(ctx)
+	g.emit(Event{Type: EventQuoteAccepted, Time: now, QuoteID: id, Pair: guarantee.Pair, Actor: actor})
+	g.emit(Event{Type: EventQuoteLocked, Time: now, QuoteID: id, Pair: guarantee.Pair, Actor: actor})
+	return guarantee.clone(), nil
+}
+
+// Get returns the guarantee of an accepted quote. It returns
+// ErrNoGuarantee for quotes it does not guarantee and an
+// ErrGuaranteeExpired error once the window has passed, even before Expire
+// drops the guarantee.
+func (g *Guarantees) Get(id string) (*Guarantee, error) {
+	now := g.clock().Now()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	guarantee, err := g.active(id, now)
+	if err != nil {
+		return nil, err
+	}
+	return guarantee.clone(), nil
+}
+
+// active returns the guarantee of a quote if it has not expired by now. The
+// caller must hold g.mu.
+func (g *Guarantees) active(id string, now time.Time) (*Guarantee, error) {
+	guarantee, ok := g.guarantees[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNoGuarantee, id)
+	}
+	if !now.Before(guarantee.ExpiresAt) {
+		return nil, fmt.Errorf("%w: %s at %s", ErrGuaranteeExpired, id, guarantee.ExpiresAt.Format(time.RFC3339))
+	}
+	return guarantee, nil
+}
+
+// Release drops the guarantee of a quote, e.g. once it is executed or
+// cancelled. It reports whether there was one.
+func (g *Guarantees) Release(id string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	_, ok := g.guarantees[id]
+	delete(g.guarantees, id)
+	return ok
+}
+
+// Expire drops the guarantees whose window has passed, emitting an
+// EventGuaranteeExpired for each, and returns how many it dropped.
+func (g *Guarantees) Expire() int {
+	now := g.clock().Now()
+
+	g.mu.Lock()
+	var expired []*Guarantee
+	for id, guarantee := range g.guarantees {
+		if !now.Before(guarantee.ExpiresAt) {
+			expired = append(expired, guarantee)
+			delete(g.guarantees, id)
+		}
+	}
+	g.mu.Unlock()
+
+	sort.Slice(expired, func(i, j int) bool { return expired[i].QuoteID < expired[j].QuoteID })
+	for _, guarantee := range expired {
+		g.emit(Event{Type: EventGuaranteeExpired, Time: now, QuoteID: guarantee.QuoteID, Pair: guarantee.Pair})
+	}
+	return len(expired)
+}
+
+// Run expires guarantees every interval until ctx is cancelled.
+func (g *Guarantees) Run(ctx context.Context) error {
+	interval := g.Interval
+	if interval <= 0 {
+		interval = defaultGuaranteeInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			g.Expire()
+		}
+	}
+}
+
+func (g *Guarantees) emit(e Event) {
+	if g.OnEvent != nil {
+		g.OnEvent(e)
+	}
+}
+
+func (g *Guarantees) clock() Clock {
+	if g.Clock == nil {
+		return SystemClock
+	}
+	return g.Clock
+}
@@ -0,0 +1,85 @@
+package converter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGuarantees(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		now    = time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+		store  = NewMemoryQuoteStore()
+		events []Event
+	)
+	quote, err := NewQuote(testCurrencies(), "USD", "USD", "NGN", decimal.NewFromInt(100), decimal.Zero, WithClock(fixedClock(now)), WithTTL(time.Minute))
+	assert.NoError(t, err)
+	assert.NoError(t, store.Save(ctx, quote))
+
+	_, err = NewGuarantees(store, 0)
+	assert.ErrorIs(t, err, ErrInvalidWindow)
+
+	g, err := NewGuarantees(store, 10*time.Minute)
+	assert.NoError(t, err)
+	g.Clock = fixedClock(now.Add(30 * time.Second))
+	g.OnEvent = func(e Event) { events = append(events, e) }
+
+	guarantee, err := g.Accept(ctx, quote.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "1550", guarantee.Rate.String())
+	assert.Equal(t, "USD/NGN", guarantee.Pair)
+	assert.Equal(t, now.Add(10*time.Minute+30*time.Second), guarantee.ExpiresAt)
+
+	stored, err := store.Get(ctx, quote.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, QuoteStatusAccepted, stored.Status)
+
+	_, err = g.Accept(ctx, quote.ID)
+	assert.ErrorIs(t, err, ErrQuoteStatusChanged)
+
+	g.Clock = fixedClock(now.Add(5 * time.Minute))
+	got, err := g.Get(quote.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, guarantee, got, "honored past the quote's own expiry")
+	assert.Equal(t, 0, g.Expire())
+
+	g.Clock = fixedClock(guarantee.ExpiresAt)
+	_, err = g.Get(quote.ID)
+	assert.ErrorIs(t, err, ErrGuaranteeExpired)
+	assert.Equal(t, 1, g.Expire())
+	_, err = g.Get(quote.ID)
+	assert.ErrorIs(t, err, ErrNoGuarantee)
+
+	assert.Equal(t, []Event{
+		{Type: EventQuoteAccepted, Time: now.Add(30 * time.Second), QuoteID: quote.ID, Pair: "USD/NGN"},
+		{Type: EventQuoteLocked, Time: now.Add(30 * time.Second), QuoteID: quote.ID, Pair: "USD/NGN"},
+		{Type: EventGuaranteeExpired, Time: guarantee.ExpiresAt, QuoteID: quote.ID, Pair: "USD/NGN"},
+	}, events)
+}
+
+func TestGuarantees_AcceptExpiredQuote(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryQuoteStore()
+	quote, err := NewQuote(testCurrencies(), "USD", "USD", "NGN", decimal.NewFromInt(100), decimal.Zero, WithClock(fixedClock(now)), WithTTL(time.Minute))
+	assert.NoError(t, err)
+	assert.NoError(t, store.Save(ctx, quote))
+
+	g, err := NewGuarantees(store, time.Minute)
+	assert.NoError(t, err)
+	g.Clock = fixedClock(now.Add(time.Minute))
+
+	_, err = g.Accept(ctx, quote.ID)
+	assert.ErrorIs(t, err, ErrQuoteExpired)
+	_, err = g.Accept(ctx, "missing")
+	assert.ErrorIs(t, err, ErrQuoteNotFound)
+
+	stored, err := store.Get(ctx, quote.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, QuoteStatusPending, stored.Status)
+	assert.False(t, g.Release(quote.ID))
+}
@@ -0,0 +1,321 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrNoHealthyProviders is returned by BlendProvider when no provider is
+// included or none of the included ones return rates.
+var ErrNoHealthyProviders = errors.New("no healthy rate providers")
+
+// DefaultHealthWindow is the number of fetches provider health is scored
+// over by default.
+const DefaultHealthWindow = 20
+
+// WeightedProvider is a provider blended with the given weight.
+type WeightedProvider struct {
+	// Name identifies the provider's health; it must be unique.
+	Name     string
+	Provider RateProvider
+	// Weight is the provider's share of the blend at full health; it must
+	// be positive.
+	Weight decimal.Decimal
+}
+
+// BlendProvider is a RateProvider blending the rates of several providers
+// into their weighted mean. It scores each provider's health over its last
+// fetches — error rate, latency and deviation from the consensus — and
+// scales its weight by the score, excluding it for a cooldown when it fails
+// too often or strays too far, so an unhealthy feed fades out of the blend
+// on its own.
+type BlendProvider struct {
+	Providers []WeightedProvider
+	// Window is the number of fetches health is scored over,
+	// DefaultHealthWindow if zero.
+	Window int
+	// MaxErrorRate, if positive, excludes providers failing more than this
+	// share of fetches, e.g. 0.5.
+	MaxErrorRate float64
+	// MaxLatency, if positive, down-weights providers slower on average, in
+	// proportion.
+	MaxLatency time.Duration
+	// MaxDeviation, if positive, down-weights providers by their mean
+	// relative deviation from the consensus and excludes those beyond it,
+	// e.g. 0.02.
+	MaxDeviation decimal.Decimal
+	// Cooldown is how long excluded providers are left out. Their health is
+	// scored afresh when they return.
+	Cooldown time.Duration
+	// Clock defaults to SystemClock. Latency is measured with it too.
+	Clock Clock
+
+	mu     sync.Mutex
+	health map[string]*providerHealth
+}
+
+// ProviderHealth is a provider's health as scored by a BlendProvider.
+type ProviderHealth struct {
+	Name      string  `json:"name"`
+	Fetches   int     `json:"fetches"`
+	Errors    int     `json:"errors"`
+	ErrorRate float64 `json:"errorRate"`
+	// Latency is the mean latency of the fetches.
+	Latency time.Duration `json:"latency"`
+	// Deviation is the mean over the fetches of the largest relative
+	// deviation of the provider's rates from the consensus.
+	Deviation decimal.Decimal `json:"deviation"`
+	// Score scales the provider's weight, from 0 to 1.
+	Score         float64         `json:"score"`
+	Weight        decimal.Decimal `json:"weight"`
+	ExcludedUntil time.Time       `json:"excludedUntil,omitempty"`
+}
+
+// providerHealth holds the recent fetches of a provider, oldest first.
+type providerHealth struct {
+	fetches       []fetchResult
+	excludedUntil time.Time
+}
+
+type fetchResult struct {
+	failed    bool
+	latency   time.Duration
+	deviation *decimal.Decimal
+}
+
+// FetchRates implements RateProvider. It fetches from the included
+// providers concurrently, scores each against the consensus — the median
+// of their rates — and returns, per currency, the mean of their rates
+// weighted by their health-scaled weights, so a provider is down-weighted
+// or excluded in the same fetch it strays in.
+func (b *BlendProvider) FetchRates(ctx context.Context) ([]Currency, error) {
+	clock := b.clock()
+	now := clock.Now()
+
+	b.mu.Lock()
+	included := make([]bool, len(b.Providers))
+	for i, p := range b.Providers {
+		h := b.providerHealth(p.Name)
+		if !h.excludedUntil.IsZero() && !now.Before(h.excludedUntil) {
+			*h = providerHealth{}
+		}
+		included[i] = h.excludedUntil.IsZero()
+	}
+	b.mu.Unlock()
+
+	results := make([][]Currency, len(b.Providers))
+	latencies := make([]time.Duration, len(b.Providers))
+	errs := make([]error, len(b.Providers))
+	var wg sync.WaitGroup
+	for i, p := range b.Providers {
+		if !included[i] {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, p RateProvider) {
+			defer wg.Done()
+			start := clock.Now()
+			results[i], errs[i] = p.FetchRates(ctx)
+			latencies[i] = clock.Now().Sub(start)
+			if errs[i] == nil && len(results[i]) == 0 {
+				errs[i] = ErrEmptyCurrencySource
+			}
+		}(i, p.Provider)
+	}
+	wg.Wait()
+
+	consensus := consensusRates(results)
+
+	b.mu.Lock()
+	weights := make([]decimal.Decimal, len(b.Providers))
+	for i, p := range b.Providers {
+		h := b.providerHealth(p.Name)
+		if included[i] {
+			result := fetchResult{failed: errs[i] != nil, latency: latencies[i]}
+			if !result.failed {
+				d := deviationFrom(results[i], consensus)
+				result.deviation = &d
+			}
+			h.fetches = append(h.fetches, result)
+			if n := b.window(); len(h.fetches) > n {
+				h.fetches = h.fetches[len(h.fetches)-n:]
+			}
+			if b.unhealthy(b.score(p, h)) {
+				h.excludedUntil = now.Add(b.Cooldown)
+			}
+		}
+		weights[i] = b.score(p, h).Weight
+	}
+	b.mu.Unlock()
+
+	blended := blend(results, weights)
+	if len(blended) == 0 {
+		return nil, errors.Join(append([]error{ErrNoHealthyProviders}, errs...)...)
+	}
+	return blended, nil
+}
+
+// Health returns the health of the providers, in the order of Providers.
+func (b *BlendProvider) Health() []ProviderHealth {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	health := make([]ProviderHealth, len(b.Providers))
+	for i, p := range b.Providers {
+		health[i] = b.score(p, b.providerHealth(p.Name))
+	}
+	return health
+}
+
+func (b *BlendProvider) providerHealth(name string) *providerHealth {
+	if b.health == nil {
+		b.health = map[string]*providerHealth{}
+	}
+	h, ok := b.health[name]
+	if !ok {
+		h = &providerHealth{}
+		b.health[name] = h
+	}
+	return h
+}
+
+// score sums up a provider's recent fetches.
+func (b *BlendProvider) score(p WeightedProvider, h *providerHealth) ProviderHealth {
+	s := ProviderHealth{Name: p.Name, Fetches: len(h.fetches), Score: 1, ExcludedUntil: h.excludedUntil}
+
+	var latency time.Duration
+	var deviations []decimal.Decimal
+	for _, f := range h.fetches {
+		if f.failed {
+			s.Errors++
+		}
+		latency += f.latency
+		if f.deviation != nil {
+			deviations = append(deviations, *f.deviation)
+		}
+	}
+	if s.Fetches > 0 {
+		s.ErrorRate = float64(s.Errors) / float64(s.Fetches)
+		s.Latency = latency / time.Duration(s.Fetches)
+		s.Score *= 1 - s.ErrorRate
+	}
+	if b.MaxLatency > 0 && s.Latency > b.MaxLatency {
+		s.Score *= float64(b.MaxLatency) / float64(s.Latency)
+	}
+	if len(deviations) > 0 {
+		s.Deviation, _ = meanStdDev(deviations)
+		if b.MaxDeviation.IsPositive() {
+			s.Score *= max(1-s.Deviation.Div(b.MaxDeviation).InexactFloat64(), 0)
+		}
+	}
+
+	if !s.ExcludedUntil.IsZero() {
+		s.Score = 0
+	}
+	s.Weight = p.Weight.Mul(decimal.NewFromFloat(s.Score))
+	return s
+}
+
+// unhealthy reports whether a provider should be excluded.
+func (b *BlendProvider) unhealthy(s ProviderHealth) bool {
+	return (b.MaxErrorRate > 0 && s.ErrorRate > b.MaxErrorRate) ||
+		(b.MaxDeviation.IsPositive() && s.Deviation.GreaterThan(b.MaxDeviation))
+}
+
+func (b *BlendProvider) window() int {
+	if b.Window > 0 {
+		return b.Window
+	}
+	return DefaultHealthWindow
+}
+
+func (b *BlendProvider) clock() Clock {
+	if b.Clock == nil {
+		return SystemClock
+	}
+	return b.Clock
+}
+
+// blend returns, per currency, the weighted mean of the rates of results,
+// taking other fields from the first result holding the currency. Results
+// with a zero weight are left out.
+func blend(results [][]Currency, weights []decimal.Decimal) []Currency {
+	type sum struct {
+		currency       Currency
+		buy, sell, all decimal.Decimal
+	}
+	sums := map[string]*sum{}
+	var codes []string
+	for i, currencies := range results {
+		if !weights[i].IsPositive() {
+			continue
+		}
+		for _, c := range currencies {
+			code := strings.ToUpper(c.ISOCode)
+			s, ok := sums[code]
+			if !ok {
+				s = &sum{currency: c}
+				sums[code] = s
+				codes = append(codes, code)
+			}
+			s.buy = s.buy.Add(c.BuyRate.Mul(weights[i]))
+			s.sell = s.sell.Add(c.SellRate.Mul(weights[i]))
+			s.all = s.all.Add(weights[i])
+		}
+	}
+	sort.Strings(codes)
+
+	blended := make([]Currency, len(codes))
+	for i, code := range codes {
+		s := sums[code]
+		c := s.currency
+		c.BuyRate = s.buy.Div(s.all)
+		c.SellRate = s.sell.Div(s.all)
+		blended[i] = c
+	}
+	return blended
+}
+
+// consensusRates returns, per currency, the median rates of results.
+func consensusRates(results [][]Currency) []Currency {
+	byCode := map[string][]Currency{}
+	for _, currencies := range results {
+		for _, c := range currencies {
+			code := strings.ToUpper(c.ISOCode)
+			byCode[code] = append(byCode[code], c)
+		}
+	}
+	consensus := make([]Currency, 0, len(byCode))
+	for _, candidates := range byCode {
+		c := candidates[0]
+		c.BuyRate = medianRate(candidates, func(c Currency) decimal.Decimal { return c.BuyRate })
+		c.SellRate = medianRate(candidates, func(c Currency) decimal.Decimal { return c.SellRate })
+		consensus = append(consensus, c)
+	}
+	return consensus
+}
+
+// deviationFrom returns the largest relative deviation of the buy and sell
+// rates of currencies from the consensus ones, so a single bad rate shows
+// however many good ones come with it.
+func deviationFrom(currencies, consensus []Currency) decimal.Decimal {
+	deviation := decimal.Zero
+	for _, c := range currencies {
+		ref, err := FindCurrency(consensus, c.ISOCode)
+		if err != nil {
+			continue
+		}
+		for _, pair := range [][2]decimal.Decimal{{c.BuyRate, ref.BuyRate}, {c.SellRate, ref.SellRate}} {
+			if pair[1].IsPositive() {
+				deviation = decimal.Max(deviation, pair[0].Sub(pair[1]).Abs().DivRound(pair[1], maxDigits))
+			}
+		}
+	}
+	return deviation
+}
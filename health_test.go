@@ -0,0 +1,95 @@
+package converter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlendProvider(t *testing.T) {
+	now := time.Date(2024, 3, 28, 12, 0, 0, 0, time.UTC)
+	one := decimal.NewFromInt(1)
+	b := &BlendProvider{
+		Providers: []WeightedProvider{
+			{Name: "a", Provider: feed(1550), Weight: one},
+			{Name: "b", Provider: feed(1552), Weight: one},
+			{Name: "c", Provider: feed(2000, 2000, 1551), Weight: one},
+			{Name: "d", Provider: feed(0), Weight: one},
+		},
+		MaxErrorRate: 0.5,
+		MaxDeviation: decimal.RequireFromString("0.1"),
+		Cooldown:     time.Minute,
+		Clock:        fixedClock(now),
+	}
+	ngnSell := func(currencies []Currency) decimal.Decimal {
+		ngn, err := FindCurrency(currencies, "NGN")
+		require.NoError(t, err)
+		return ngn.SellRate
+	}
+
+	// c strays from the consensus and d fails: both are excluded at once
+	// and a's small deviation weighs it slightly below b.
+	currencies, err := b.FetchRates(context.Background())
+	require.NoError(t, err)
+	sell := ngnSell(currencies)
+	assert.True(t, sell.GreaterThan(decimal.NewFromInt(1551)) && sell.LessThan(decimal.NewFromInt(1552)), sell.String())
+
+	health := b.Health()
+	require.Len(t, health, 4)
+	assert.Equal(t, "a", health[0].Name)
+	assert.InDelta(t, 1-2.0/1552/0.1, health[0].Score, 1e-9)
+	assert.Equal(t, 1.0, health[1].Score)
+	assert.Equal(t, 0.0, health[2].Score)
+	assert.Equal(t, now.Add(time.Minute), health[2].ExcludedUntil)
+	assert.Equal(t, "0.2887", health[2].Deviation.StringFixed(4))
+	assert.Equal(t, 1, health[3].Errors)
+	assert.Equal(t, 1.0, health[3].ErrorRate)
+	assert.Equal(t, now.Add(time.Minute), health[3].ExcludedUntil)
+
+	// Excluded providers are not fetched during their cooldown.
+	_, err = b.FetchRates(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, b.Health()[2].Fetches)
+
+	// After it, they are scored afresh: c still strays, then recovers.
+	b.Clock = fixedClock(now.Add(2 * time.Minute))
+	_, err = b.FetchRates(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(3*time.Minute), b.Health()[2].ExcludedUntil)
+
+	b.Clock = fixedClock(now.Add(4 * time.Minute))
+	currencies, err = b.FetchRates(context.Background())
+	require.NoError(t, err)
+	health = b.Health()
+	assert.True(t, health[2].ExcludedUntil.IsZero())
+	assert.Equal(t, 1, health[2].Fetches)
+	assert.True(t, health[2].Weight.IsPositive())
+	assert.Equal(t, "1551", ngnSell(currencies).Round(0).String())
+}
+
+func TestBlendProvider_NoHealthyProviders(t *testing.T) {
+	b := &BlendProvider{Providers: []WeightedProvider{{Name: "a", Provider: feed(0), Weight: decimal.NewFromInt(1)}}}
+	_, err := b.FetchRates(context.Background())
+	assert.ErrorIs(t, err, ErrNoHealthyProviders)
+	assert.ErrorContains(t, err, "feed down")
+}
+
+func TestBlendProvider_Score(t *testing.T) {
+	b := &BlendProvider{MaxLatency: time.Second}
+	p := WeightedProvider{Name: "slow", Weight: decimal.NewFromInt(2)}
+	h := &providerHealth{fetches: []fetchResult{{latency: 2 * time.Second}, {latency: 4 * time.Second}, {failed: true}}}
+
+	s := b.score(p, h)
+	assert.Equal(t, 3, s.Fetches)
+	assert.Equal(t, 2*time.Second, s.Latency)
+	assert.InDelta(t, 2.0/3*0.5, s.Score, 1e-9)
+	assert.Equal(t, "0.6667", s.Weight.StringFixed(4))
+
+	fresh := b.score(p, &providerHealth{})
+	assert.Equal(t, 1.0, fresh.Score)
+	assert.Equal(t, "2", fresh.Weight.String())
+}
@@ -0,0 +1,103 @@
+// Package httpapi maps converter errors to HTTP responses, so services
+// embedding the converter answer failures consistently: StatusFor picks the
+// status code and WriteProblem writes an RFC 9457 problem+json body. Its
+// handlers, such as MatrixHandler and StatsHandler, are mounted on a Router,
+// which documents them in an OpenAPI document.
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/otyang/converter"
+)
+
+// mapping maps the errors matching err to an HTTP status and a stable
+// machine-readable code.
+type mapping struct {
+	err    error
+	status int
+	code   string
+}
+
+// mappings are checked in order with errors.Is; the first match wins.
+var mappings = []mapping{
+	// Not found
+	{converter.ErrQuoteNotFound, http.StatusNotFound, "quote_not_found"},
+	{converter.ErrUnknownCurrency, http.StatusNotFound, "currency_not_found"},
+	{converter.ErrBaseCurrencyNotFound, http.StatusNotFound, "base_currency_not_found"},
+	{converter.ErrNoGuarantee, http.StatusNotFound, "guarantee_not_found"},
+	{converter.ErrSnapshotNotFound, http.StatusNotFound, "snapshot_not_found"},
+	{converter.ErrNoRateSamples, http.StatusNotFound, "no_rate_samples"},
+
+	// Gone
+	{converter.ErrQuoteExpired, http.StatusGone, "quote_expired"},
+	{converter.ErrGuaranteeExpired, http.StatusGone, "guarantee_expired"},
+
+	// Conflicts
+	{converter.ErrQuoteStatusChanged, http.StatusConflict, "quote_status_changed"},
+	{converter.ErrCurrencyConflict, http.StatusConflict, "currency_conflict"},
+
+	// Compliance
+	{converter.ErrBlocked, http.StatusForbidden, "blocked"},
+
+	// Limits and amounts the request is well-formed for but cannot be quoted
+	{converter.ErrTierLimitExceeded, http.StatusUnprocessableEntity, "tier_limit_exceeded"},
+	{converter.ErrAmountOutOfBounds, http.StatusUnprocessableEntity, "amount_out_of_bounds"},
+	{converter.ErrDustAmount, http.StatusUnprocessableEntity, "dust_amount"},
+	{converter.ErrFractionalAmount, http.StatusUnprocessableEntity, "fractional_amount"},
+	{converter.ErrFeeExceedsAmount, http.StatusUnprocessableEntity, "fee_exceeds_amount"},
+	{converter.ErrOverExecution, http.StatusUnprocessableEntity, "over_execution"},
+	{converter.ErrNoTradingDay, http.StatusUnprocessableEntity, "no_trading_day"},
+
+	// Malformed requests
+	{converter.ErrInvalidAmount, http.StatusBadRequest, "invalid_amount"},
+	{converter.ErrInvalidPair, http.StatusBadRequest, "invalid_pair"},
+	{converter.ErrInvalidMoney, http.StatusBadRequest, "invalid_money"},
+	{converter.ErrInvalidQuoteFilter, http.StatusBadRequest, "invalid_quote_filter"},
+	{converter.ErrInvalidFeeMode, http.StatusBadRequest, "invalid_fee_mode"},
+	{converter.ErrInvalidCancelReason, http.StatusBadRequest, "invalid_cancel_reason"},
+	{ErrUnsupportedFormat, http.StatusBadRequest, "unsupported_format"},
+	{converter.ErrInvalidSmoothing, http.StatusBadRequest, "invalid_smoothing"},
+	{ErrInvalidBuckets, http.StatusBadRequest, "invalid_buckets"},
+	{ErrMethodNotAllowed, http.StatusMethodNotAllowed, "method_not_allowed"},
+
+	// Rates that cannot be trusted or are missing right now
+	{converter.ErrSnapshotTooOld, http.StatusServiceUnavailable, "rates_stale"},
+	{converter.ErrRateDeviation, http.StatusServiceUnavailable, "rate_deviation"},
+	{converter.ErrRateSpike, http.StatusServiceUnavailable, "rate_spike"},
+	{converter.ErrNoQuorum, http.StatusServiceUnavailable, "no_quorum"},
+	{converter.ErrNoHealthyProviders, http.StatusServiceUnavailable, "no_healthy_providers"},
+	{converter.ErrRateOutOfBounds, http.StatusServiceUnavailable, "rate_out_of_bounds"},
+	{converter.ErrInvariantViolated, http.StatusServiceUnavailable, "rate_invariant_violated"},
+	{converter.ErrServiceNotStarted, http.StatusServiceUnavailable, "service_unavailable"},
+	{converter.ErrEmptyCurrencySource, http.StatusServiceUnavailable, "rates_unavailable"},
+
+	{context.DeadlineExceeded, http.StatusGatewayTimeout, "timeout"},
+}
+
+// StatusFor returns the HTTP status for an error returned by the converter:
+// 404 for missing quotes and currencies, 409 for conflicting updates, 422
+// for limits exceeded and amounts that cannot be quoted, 400 for malformed
+// input, 405 for unsupported methods, 503 for stale, untrusted or missing
+// rates and 500 for anything else. A nil error is 200.
+func StatusFor(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	if m, ok := lookup(err); ok {
+		return m.status
+	}
+	return http.StatusInternalServerError
+}
+
+// lookup returns the first mapping err matches.
+func lookup(err error) (mapping, bool) {
+	for _, m := range mappings {
+		if errors.Is(err, m.err) {
+			return m, true
+		}
+	}
+	return mapping{}, false
+}
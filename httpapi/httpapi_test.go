@@ -0,0 +1,41 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/otyang/converter"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusFor(t *testing.T) {
+	currencies := []converter.Currency{
+		{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+	}
+	_, notFound := converter.FindCurrency(currencies, "GBP")
+	_, tierErr := converter.NewQuote(append(currencies, converter.Currency{ISOCode: "NGN", Precision: 2, BuyRate: decimal.NewFromInt(1500), SellRate: decimal.NewFromInt(1550)}),
+		"USD", "USD", "NGN", decimal.NewFromInt(100), decimal.Zero, converter.WithTier(converter.Tier{Name: "basic", MaxAmount: decimal.NewFromInt(10)}))
+
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, http.StatusOK},
+		{fmt.Errorf("get: %w", converter.ErrQuoteNotFound), http.StatusNotFound},
+		{notFound, http.StatusNotFound},
+		{converter.ErrSnapshotTooOld, http.StatusServiceUnavailable},
+		{tierErr, http.StatusUnprocessableEntity},
+		{converter.ErrQuoteStatusChanged, http.StatusConflict},
+		{converter.ErrInvalidPair, http.StatusBadRequest},
+		{&converter.ComplianceError{Pair: "USD/RUB"}, http.StatusForbidden},
+		{context.DeadlineExceeded, http.StatusGatewayTimeout},
+		{errors.New("boom"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, StatusFor(tt.err), "%v", tt.err)
+	}
+}
@@ -0,0 +1,109 @@
+package httpapi
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/otyang/converter"
+)
+
+// VersionHeader carries the version of the table a response was built
+// from, see Currencies.Version.
+const VersionHeader = "X-Rates-Version"
+
+// Request errors
+var (
+	ErrMethodNotAllowed  = errors.New("method not allowed")
+	ErrUnsupportedFormat = errors.New("unsupported format: use json or csv")
+)
+
+// MatrixHandler serves the table's rate matrix, see Currencies.ExportMatrix,
+// priced against base or the request's "base" query parameter. It answers
+// GET and HEAD with JSON, or CSV if the "format" query parameter is "csv" or
+// the request accepts only text/csv.
+//
+// Responses carry a strong ETag over their body, the table version in
+// VersionHeader and the table's update time as Last-Modified, so caches
+// revalidate with If-None-Match and get 304 Not Modified until the rates
+// change.
+func MatrixHandler(table *converter.Currencies, base string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			WriteProblem(w, r, ErrMethodNotAllowed)
+			return
+		}
+
+		format, err := matrixFormat(r)
+		if err != nil {
+			WriteProblem(w, r, err)
+			return
+		}
+		b := base
+		if q := r.URL.Query().Get("base"); q != "" {
+			b = q
+		}
+
+		m, err := table.ExportMatrix(b)
+		if err != nil {
+			WriteProblem(w, r, err)
+			return
+		}
+
+		var body bytes.Buffer
+		if format == "csv" {
+			err = m.WriteCSV(&body)
+			w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		} else {
+			err = m.WriteJSON(&body)
+			w.Header().Set("Content-Type", "application/json")
+		}
+		if err != nil {
+			WriteProblem(w, r, err)
+			return
+		}
+
+		sum := sha256.Sum256(body.Bytes())
+		w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+		w.Header().Set(VersionHeader, strconv.FormatUint(m.Version, 10))
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Add("Vary", "Accept")
+		http.ServeContent(w, r, "", m.UpdatedAt, bytes.NewReader(body.Bytes()))
+	})
+}
+
+// matrixFormat picks the response format from the "format" query parameter,
+// else the Accept header.
+func matrixFormat(r *http.Request) (string, error) {
+	switch f := strings.ToLower(r.URL.Query().Get("format")); f {
+	case "json", "csv":
+		return f, nil
+	case "":
+	default:
+		return "", ErrUnsupportedFormat
+	}
+
+	csv := false
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case "text/csv":
+			csv = true
+		case "application/json", "application/*", "*/*":
+			return "json", nil
+		}
+	}
+	if csv {
+		return "csv", nil
+	}
+	return "json", nil
+}
@@ -0,0 +1,107 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/otyang/converter"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func matrixTable() *converter.Currencies {
+	table := converter.NewTable(nil)
+	table.Update([]converter.Currency{
+		{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+		{ISOCode: "NGN", Precision: 2, BuyRate: decimal.NewFromInt(1550), SellRate: decimal.NewFromInt(1500)},
+	}, time.Date(2024, 3, 28, 12, 0, 0, 0, time.UTC))
+	return table
+}
+
+func TestMatrixHandler(t *testing.T) {
+	table := matrixTable()
+	handler := MatrixHandler(table, "USD")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/rates/matrix", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "1", rec.Header().Get(VersionHeader))
+	assert.Equal(t, "Thu, 28 Mar 2024 12:00:00 GMT", rec.Header().Get("Last-Modified"))
+	etag := rec.Header().Get("ETag")
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, etag)
+
+	var m converter.RateMatrix
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &m))
+	assert.Equal(t, "1500", m.Rates["USD"]["NGN"].String())
+
+	// Revalidation with the ETag is answered with 304 until the rates change.
+	req := httptest.NewRequest(http.MethodGet, "/rates/matrix", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Empty(t, rec.Body.String())
+
+	table.Update([]converter.Currency{
+		{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+		{ISOCode: "NGN", Precision: 2, BuyRate: decimal.NewFromInt(1600), SellRate: decimal.NewFromInt(1550)},
+	}, time.Date(2024, 3, 28, 13, 0, 0, 0, time.UTC))
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get(VersionHeader))
+	assert.NotEqual(t, etag, rec.Header().Get("ETag"))
+}
+
+func TestMatrixHandlerCSV(t *testing.T) {
+	handler := MatrixHandler(matrixTable(), "USD")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/rates/matrix?format=csv&base=usd", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.True(t, strings.HasPrefix(rec.Body.String(), "from,NGN,USD\n"))
+
+	req := httptest.NewRequest(http.MethodGet, "/rates/matrix", nil)
+	req.Header.Set("Accept", "text/csv")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+
+	req.Header.Set("Accept", "text/csv;q=0.5, application/json")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+}
+
+func TestMatrixHandlerErrors(t *testing.T) {
+	handler := MatrixHandler(matrixTable(), "USD")
+
+	tests := []struct {
+		method, target string
+		status         int
+		code           string
+	}{
+		{http.MethodPost, "/rates/matrix", http.StatusMethodNotAllowed, "method_not_allowed"},
+		{http.MethodGet, "/rates/matrix?format=xml", http.StatusBadRequest, "unsupported_format"},
+		{http.MethodGet, "/rates/matrix?base=GBP", http.StatusNotFound, "base_currency_not_found"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, nil))
+		assert.Equal(t, tt.status, rec.Code, tt.target)
+		assert.Equal(t, ProblemContentType, rec.Header().Get("Content-Type"))
+		var p Problem
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+		assert.Equal(t, tt.code, p.Code)
+	}
+
+	rec := httptest.NewRecorder()
+	MatrixHandler(converter.NewTable(nil), "USD").ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/rates/matrix", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
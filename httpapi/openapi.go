@@ -0,0 +1,241 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/otyang/converter"
+	"github.com/shopspring/decimal"
+)
+
+// OpenAPIVersion is the version of the OpenAPI specification documents
+// are written in.
+const OpenAPIVersion = "3.0.3"
+
+// Route describes an endpoint for the OpenAPI document.
+type Route struct {
+	Method string
+	// Path is the URL path, e.g. "/rates/matrix".
+	Path        string
+	OperationID string
+	Summary     string
+	Query       []Param
+	// Response is a value of the type of a successful JSON response, e.g.
+	// converter.RateMatrix{}, or nil for a response without a body. Its
+	// schema is generated from the type's JSON encoding.
+	Response any
+	// Array reports whether the response is an array of Response.
+	Array bool
+	// Alternates lists other media types the success response is
+	// available in, e.g. "text/csv".
+	Alternates []string
+	// Errors lists the statuses the route answers with problem details.
+	Errors []int
+}
+
+// Param is a query parameter.
+type Param struct {
+	Name        string
+	Description string
+	Required    bool
+	// Enum lists the allowed values, if restricted.
+	Enum []string
+}
+
+// MatrixRoute describes MatrixHandler served at path.
+func MatrixRoute(path string) Route {
+	return Route{
+		Method:      http.MethodGet,
+		Path:        path,
+		OperationID: "getRateMatrix",
+		Summary:     "Rates of every pair of currencies",
+		Query: []Param{
+			{Name: "base", Description: "Currency to price against, overriding the server's."},
+			{Name: "format", Description: "Response format; defaults by the Accept header.", Enum: []string{"json", "csv"}},
+		},
+		Response:   converter.RateMatrix{},
+		Alternates: []string{"text/csv"},
+		Errors:     []int{http.StatusBadRequest, http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusServiceUnavailable},
+	}
+}
+
+// schemaTypes are documented in every OpenAPI document.
+var schemaTypes = []any{converter.Currency{}, converter.Quote{}, Problem{}}
+
+// OpenAPI writes an OpenAPI document describing routes, with the schemas of
+// their responses, the converter's Currency and Quote and Problem as
+// components.
+func OpenAPI(title, version string, routes []Route) ([]byte, error) {
+	s := schemas{}
+	for _, v := range schemaTypes {
+		s.of(reflect.TypeOf(v))
+	}
+
+	paths := map[string]map[string]any{}
+	for _, r := range routes {
+		responses := map[string]any{}
+
+		ok := map[string]any{"description": http.StatusText(http.StatusOK)}
+		if r.Response != nil {
+			schema := s.of(reflect.TypeOf(r.Response))
+			if r.Array {
+				schema = map[string]any{"type": "array", "items": schema}
+			}
+			content := map[string]any{"application/json": map[string]any{"schema": schema}}
+			for _, media := range r.Alternates {
+				content[media] = map[string]any{"schema": map[string]any{"type": "string"}}
+			}
+			ok["content"] = content
+		}
+		responses["200"] = ok
+
+		for _, status := range r.Errors {
+			responses[strconv.Itoa(status)] = map[string]any{
+				"description": http.StatusText(status),
+				"content": map[string]any{
+					ProblemContentType: map[string]any{"schema": ref("Problem")},
+				},
+			}
+		}
+
+		op := map[string]any{"responses": responses}
+		if r.OperationID != "" {
+			op["operationId"] = r.OperationID
+		}
+		if r.Summary != "" {
+			op["summary"] = r.Summary
+		}
+		if len(r.Query) > 0 {
+			params := make([]any, len(r.Query))
+			for i, p := range r.Query {
+				schema := map[string]any{"type": "string"}
+				if len(p.Enum) > 0 {
+					schema["enum"] = p.Enum
+				}
+				param := map[string]any{"name": p.Name, "in": "query", "required": p.Required, "schema": schema}
+				if p.Description != "" {
+					param["description"] = p.Description
+				}
+				params[i] = param
+			}
+			op["parameters"] = params
+		}
+
+		if paths[r.Path] == nil {
+			paths[r.Path] = map[string]any{}
+		}
+		paths[r.Path][strings.ToLower(r.Method)] = op
+	}
+
+	return json.MarshalIndent(map[string]any{
+		"openapi":    OpenAPIVersion,
+		"info":       map[string]any{"title": title, "version": version},
+		"paths":      paths,
+		"components": map[string]any{"schemas": s},
+	}, "", "  ")
+}
+
+func ref(name string) map[string]any {
+	return map[string]any{"$ref": "#/components/schemas/" + name}
+}
+
+var (
+	decimalType = reflect.TypeOf(decimal.Decimal{})
+	timeType    = reflect.TypeOf(time.Time{})
+)
+
+// schemas collects component schemas by type name.
+type schemas map[string]any
+
+// of returns the schema of t's JSON encoding, a reference for named
+// structs, whose schemas it adds to the components.
+func (s schemas) of(t reflect.Type) map[string]any {
+	switch t {
+	case decimalType:
+		return map[string]any{"type": "string", "format": "decimal"}
+	case timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema := s.of(t.Elem())
+		if _, isRef := schema["$ref"]; isRef {
+			return map[string]any{"allOf": []any{schema}, "nullable": true}
+		}
+		schema["nullable"] = true
+		return schema
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return map[string]any{"type": "integer", "format": "int32"}
+	case reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "format": "byte"}
+		}
+		return map[string]any{"type": "array", "items": s.of(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": s.of(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.object(t)
+		}
+		if _, done := s[t.Name()]; !done {
+			// Claim the name first, so recursive types terminate.
+			s[t.Name()] = nil
+			s[t.Name()] = s.object(t)
+		}
+		return ref(t.Name())
+	default:
+		return map[string]any{}
+	}
+}
+
+// object returns the schema of a struct's fields, following encoding/json's
+// rules for names, omitempty and embedded structs.
+func (s schemas) object(t reflect.Type) map[string]any {
+	properties := map[string]any{}
+	var required []string
+
+	var fields func(t reflect.Type)
+	fields = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag := f.Tag.Get("json")
+			if tag == "-" || !f.IsExported() && !f.Anonymous {
+				continue
+			}
+			name, opts, _ := strings.Cut(tag, ",")
+			if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+				fields(f.Type)
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			properties[name] = s.of(f.Type)
+			if !strings.Contains(","+opts+",", ",omitempty,") {
+				required = append(required, name)
+			}
+		}
+	}
+	fields(t)
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		sort.Strings(required)
+		schema["required"] = required
+	}
+	return schema
+}
@@ -0,0 +1,109 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/otyang/converter"
+	"github.com/stretchr/testify/assert"
+)
+
+// document decodes an OpenAPI document for assertions.
+func document(t *testing.T, b []byte) map[string]any {
+	t.Helper()
+	var doc map[string]any
+	assert.NoError(t, json.Unmarshal(b, &doc))
+	return doc
+}
+
+func TestOpenAPI(t *testing.T) {
+	b, err := OpenAPI("Converter", "1.2.0", []Route{
+		MatrixRoute("/rates/matrix"),
+		{Method: http.MethodGet, Path: "/quotes", Response: converter.Quote{}, Array: true},
+	})
+	assert.NoError(t, err)
+	doc := document(t, b)
+
+	assert.Equal(t, OpenAPIVersion, doc["openapi"])
+	assert.Equal(t, map[string]any{"title": "Converter", "version": "1.2.0"}, doc["info"])
+
+	matrix := doc["paths"].(map[string]any)["/rates/matrix"].(map[string]any)["get"].(map[string]any)
+	assert.Equal(t, "getRateMatrix", matrix["operationId"])
+	responses := matrix["responses"].(map[string]any)
+	ok := responses["200"].(map[string]any)["content"].(map[string]any)
+	assert.Equal(t, map[string]any{"$ref": "#/components/schemas/RateMatrix"}, ok["application/json"].(map[string]any)["schema"])
+	assert.Contains(t, ok, "text/csv")
+	assert.Equal(t, map[string]any{"$ref": "#/components/schemas/Problem"},
+		responses["404"].(map[string]any)["content"].(map[string]any)[ProblemContentType].(map[string]any)["schema"])
+	params := matrix["parameters"].([]any)
+	assert.Equal(t, map[string]any{
+		"name": "format", "in": "query", "required": false,
+		"description": "Response format; defaults by the Accept header.",
+		"schema":      map[string]any{"type": "string", "enum": []any{"json", "csv"}},
+	}, params[1])
+
+	quotes := doc["paths"].(map[string]any)["/quotes"].(map[string]any)["get"].(map[string]any)
+	schema := quotes["responses"].(map[string]any)["200"].(map[string]any)["content"].(map[string]any)["application/json"].(map[string]any)["schema"]
+	assert.Equal(t, map[string]any{"type": "array", "items": map[string]any{"$ref": "#/components/schemas/Quote"}}, schema)
+}
+
+func TestOpenAPISchemas(t *testing.T) {
+	b, err := OpenAPI("Converter", "1", nil)
+	assert.NoError(t, err)
+	schemas := document(t, b)["components"].(map[string]any)["schemas"].(map[string]any)
+	assert.Contains(t, schemas, "Currency")
+	assert.Contains(t, schemas, "Quote")
+	assert.Contains(t, schemas, "QuoteSide")
+	assert.Contains(t, schemas, "Problem")
+
+	currency := schemas["Currency"].(map[string]any)
+	assert.Equal(t, []any{"buyRate", "isoCode", "precision", "sellRate"}, currency["required"])
+	props := currency["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "string", "format": "decimal"}, props["buyRate"])
+	assert.Equal(t, map[string]any{"type": "integer", "format": "int32"}, props["precision"])
+	assert.Equal(t, map[string]any{"type": "string"}, props["baseIsoCode"])
+
+	quote := schemas["Quote"].(map[string]any)["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "string", "format": "date-time"}, quote["expiresAt"])
+	assert.Equal(t, map[string]any{"$ref": "#/components/schemas/QuoteSide"}, quote["send"])
+	assert.Equal(t, map[string]any{"type": "array", "items": map[string]any{"type": "string"}}, quote["flags"])
+	assert.Contains(t, quote, "totalAmount")
+	assert.NotContains(t, quote, "FinalAmount")
+}
+
+func TestOpenAPISchemaKinds(t *testing.T) {
+	type inner struct {
+		Name string `json:"name"`
+	}
+	type embedded struct {
+		Flag bool `json:"flag"`
+	}
+	type sample struct {
+		embedded
+		Inner   *inner            `json:"inner,omitempty"`
+		Count   *int64            `json:"count"`
+		Ratio   float64           `json:"ratio"`
+		Data    []byte            `json:"data"`
+		Tags    map[string]string `json:"tags"`
+		Skipped string            `json:"-"`
+		Plain   string
+		hidden  string
+	}
+	s := schemas{}
+	assert.Equal(t, map[string]any{"$ref": "#/components/schemas/sample"}, s.of(reflect.TypeOf(sample{})))
+
+	obj := s["sample"].(map[string]any)
+	props := obj["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "boolean"}, props["flag"])
+	assert.Equal(t, map[string]any{"allOf": []any{map[string]any{"$ref": "#/components/schemas/inner"}}, "nullable": true}, props["inner"])
+	assert.Equal(t, map[string]any{"type": "integer", "format": "int64", "nullable": true}, props["count"])
+	assert.Equal(t, map[string]any{"type": "number"}, props["ratio"])
+	assert.Equal(t, map[string]any{"type": "string", "format": "byte"}, props["data"])
+	assert.Equal(t, map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "string"}}, props["tags"])
+	assert.Contains(t, props, "Plain")
+	assert.NotContains(t, props, "Skipped")
+	assert.NotContains(t, props, "hidden")
+	assert.Equal(t, []string{"Plain", "count", "data", "flag", "ratio", "tags"}, obj["required"])
+}
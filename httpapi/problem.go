@@ -0,0 +1,60 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ProblemContentType is the media type of problem details.
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 9457 problem details object.
+type Problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	// Instance identifies the occurrence, e.g. the request path.
+	Instance string `json:"instance,omitempty"`
+	// Code is a stable machine-readable code for the error, e.g.
+	// "quote_not_found", or "internal" for unmapped errors.
+	Code string `json:"code,omitempty"`
+}
+
+// NewProblem describes err. The error's message is the detail, except for
+// server errors (5xx), whose detail is left out so internals do not leak to
+// clients.
+func NewProblem(err error) Problem {
+	status := StatusFor(err)
+	var code string
+	if m, ok := lookup(err); ok {
+		code = m.code
+	} else if err != nil {
+		code = "internal"
+	}
+
+	p := Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Code:   code,
+	}
+	if err != nil && status < http.StatusInternalServerError {
+		p.Detail = err.Error()
+	}
+	return p
+}
+
+// WriteProblem writes err as a problem+json response with the status of
+// StatusFor, naming the request's path as the instance if r is not nil.
+func WriteProblem(w http.ResponseWriter, r *http.Request, err error) {
+	p := NewProblem(err)
+	if r != nil {
+		p.Instance = r.URL.Path
+	}
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
@@ -0,0 +1,42 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/otyang/converter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteProblem(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/quotes/q1", nil)
+	WriteProblem(rec, req, fmt.Errorf("get q1: %w", converter.ErrQuoteNotFound))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, ProblemContentType, rec.Header().Get("Content-Type"))
+	var p Problem
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+	assert.Equal(t, Problem{
+		Type:     "about:blank",
+		Title:    "Not Found",
+		Status:   http.StatusNotFound,
+		Detail:   "get q1: quote not found",
+		Instance: "/quotes/q1",
+		Code:     "quote_not_found",
+	}, p)
+}
+
+func TestNewProblem_ServerErrors(t *testing.T) {
+	p := NewProblem(errors.New("dial tcp 10.0.0.7:5432: connection refused"))
+	assert.Equal(t, Problem{Type: "about:blank", Title: "Internal Server Error", Status: 500, Code: "internal"}, p)
+
+	p = NewProblem(converter.ErrSnapshotTooOld)
+	assert.Equal(t, http.StatusServiceUnavailable, p.Status)
+	assert.Equal(t, "rates_stale", p.Code)
+	assert.Empty(t, p.Detail)
+}
@@ -0,0 +1,118 @@
+package httpapi
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// OpenAPIPath is where a Router serves its OpenAPI document.
+const OpenAPIPath = "/openapi.json"
+
+// Router routes requests by path and method to handlers registered with
+// their Route, and serves an OpenAPI document describing them at
+// OpenAPIPath, so client SDKs can be generated against any deployment.
+// Requests with a method a path has no handler for get 405 Method Not
+// Allowed; HEAD requests are served by GET handlers.
+type Router struct {
+	title, version string
+
+	mu      sync.RWMutex
+	mux     *http.ServeMux
+	routes  []Route
+	methods map[string]map[string]http.Handler
+}
+
+// NewRouter creates a router whose document has title and version, the
+// version of the API rather than of the document format.
+func NewRouter(title, version string) *Router {
+	r := &Router{
+		title:   title,
+		version: version,
+		mux:     http.NewServeMux(),
+		methods: map[string]map[string]http.Handler{},
+	}
+	r.mux.HandleFunc(OpenAPIPath, r.serveOpenAPI)
+	return r
+}
+
+// Handle registers h for route's method and path. Registering the same
+// method and path twice panics, as http.ServeMux does.
+func (r *Router) Handle(route Route, h http.Handler) {
+	method := strings.ToUpper(route.Method)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	byMethod, ok := r.methods[route.Path]
+	if !ok {
+		byMethod = map[string]http.Handler{}
+		r.methods[route.Path] = byMethod
+		path := route.Path
+		r.mux.HandleFunc(path, func(w http.ResponseWriter, req *http.Request) {
+			r.dispatch(path, w, req)
+		})
+	}
+	if _, dup := byMethod[method]; dup {
+		panic("httpapi: multiple registrations for " + method + " " + route.Path)
+	}
+	byMethod[method] = h
+	r.routes = append(r.routes, route)
+}
+
+// Routes returns the registered routes.
+func (r *Router) Routes() []Route {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]Route(nil), r.routes...)
+}
+
+// OpenAPI returns the router's OpenAPI document.
+func (r *Router) OpenAPI() ([]byte, error) {
+	return OpenAPI(r.title, r.version, r.Routes())
+}
+
+// ServeHTTP implements http.Handler.
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mux.ServeHTTP(w, req)
+}
+
+func (r *Router) dispatch(path string, w http.ResponseWriter, req *http.Request) {
+	r.mu.RLock()
+	byMethod := r.methods[path]
+	h, ok := byMethod[req.Method]
+	if !ok && req.Method == http.MethodHead {
+		h, ok = byMethod[http.MethodGet]
+	}
+	var allowed []string
+	if !ok {
+		for m := range byMethod {
+			allowed = append(allowed, m)
+		}
+	}
+	r.mu.RUnlock()
+
+	if !ok {
+		sort.Strings(allowed)
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+		WriteProblem(w, req, ErrMethodNotAllowed)
+		return
+	}
+	h.ServeHTTP(w, req)
+}
+
+func (r *Router) serveOpenAPI(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		WriteProblem(w, req, ErrMethodNotAllowed)
+		return
+	}
+	doc, err := r.OpenAPI()
+	if err != nil {
+		WriteProblem(w, req, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(doc)
+}
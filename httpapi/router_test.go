@@ -0,0 +1,61 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouter(t *testing.T) {
+	router := NewRouter("Converter", "1.0.0")
+	router.Handle(MatrixRoute("/rates/matrix"), MatrixHandler(matrixTable(), "USD"))
+	created := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusCreated) })
+	router.Handle(Route{Method: "post", Path: "/rates/matrix"}, created)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/rates/matrix", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodHead, "/rates/matrix", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/rates/matrix", nil))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/rates/matrix", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, "GET, POST", rec.Header().Get("Allow"))
+	assert.Equal(t, ProblemContentType, rec.Header().Get("Content-Type"))
+
+	assert.Len(t, router.Routes(), 2)
+	assert.Panics(t, func() { router.Handle(MatrixRoute("/rates/matrix"), created) })
+}
+
+func TestRouterOpenAPI(t *testing.T) {
+	router := NewRouter("Converter", "1.0.0")
+	router.Handle(MatrixRoute("/rates/matrix"), MatrixHandler(matrixTable(), "USD"))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, OpenAPIPath, nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var doc map[string]any
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	assert.Contains(t, doc["paths"], "/rates/matrix")
+	assert.Contains(t, doc["components"].(map[string]any)["schemas"], "RateMatrix")
+
+	want, err := router.OpenAPI()
+	assert.NoError(t, err)
+	assert.Equal(t, string(want), rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, OpenAPIPath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
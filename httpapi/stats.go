@@ -0,0 +1,85 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/otyang/converter"
+)
+
+// Sparkline buckets of stats responses
+const (
+	DefaultStatsBuckets = 20
+	MaxStatsBuckets     = 500
+)
+
+// ErrInvalidBuckets is returned for a "buckets" query parameter that is not
+// an integer from 1 to MaxStatsBuckets.
+var ErrInvalidBuckets = errors.New("invalid buckets")
+
+// StatsHandler serves the statistics of the pair in the "from" and "to"
+// query parameters over history, see RateHistory.Stats, priced against base
+// or the request's "base" query parameter. The "buckets" query parameter
+// sets the number of sparkline points, DefaultStatsBuckets by default.
+func StatsHandler(history *converter.RateHistory, base string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			WriteProblem(w, r, ErrMethodNotAllowed)
+			return
+		}
+
+		query := r.URL.Query()
+		from, to := query.Get("from"), query.Get("to")
+		if from == "" || to == "" {
+			WriteProblem(w, r, fmt.Errorf("%w: from and to are required", converter.ErrInvalidPair))
+			return
+		}
+		buckets := DefaultStatsBuckets
+		if q := query.Get("buckets"); q != "" {
+			n, err := strconv.Atoi(q)
+			if err != nil || n < 1 || n > MaxStatsBuckets {
+				WriteProblem(w, r, fmt.Errorf("%w: %q is not an integer from 1 to %d", ErrInvalidBuckets, q, MaxStatsBuckets))
+				return
+			}
+			buckets = n
+		}
+		b := base
+		if q := query.Get("base"); q != "" {
+			b = q
+		}
+
+		stats, err := history.Stats(b, from, to, buckets)
+		if err != nil {
+			WriteProblem(w, r, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-cache")
+		if r.Method == http.MethodHead {
+			return
+		}
+		_ = json.NewEncoder(w).Encode(stats)
+	})
+}
+
+// StatsRoute describes StatsHandler served at path.
+func StatsRoute(path string) Route {
+	return Route{
+		Method:      http.MethodGet,
+		Path:        path,
+		OperationID: "getRateStats",
+		Summary:     "Rolling statistics and sparkline of a pair's rate",
+		Query: []Param{
+			{Name: "from", Description: "Currency converted from.", Required: true},
+			{Name: "to", Description: "Currency converted to.", Required: true},
+			{Name: "buckets", Description: "Number of sparkline points, from 1 to 500; 20 by default."},
+			{Name: "base", Description: "Currency to price against, overriding the server's."},
+		},
+		Response: converter.RateStats{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusMethodNotAllowed},
+	}
+}
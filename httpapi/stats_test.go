@@ -0,0 +1,82 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/otyang/converter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func statsHistory(t *testing.T) *converter.RateHistory {
+	history, err := converter.NewRateHistory(time.Hour)
+	require.NoError(t, err)
+	table := matrixTable()
+	history.Record(converter.NewSnapshot(table.List(), time.Now().Add(-time.Minute)))
+	history.Record(converter.NewSnapshot(table.List(), time.Now()))
+	return history
+}
+
+func TestStatsHandler(t *testing.T) {
+	handler := StatsHandler(statsHistory(t), "USD")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/rates/stats?from=usd&to=ngn&buckets=3", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var stats converter.RateStats
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &stats))
+	assert.Equal(t, "USD", stats.From)
+	assert.Equal(t, "NGN", stats.To)
+	assert.Equal(t, 2, stats.Samples)
+	assert.Equal(t, "1500", stats.Mean.String())
+	assert.Len(t, stats.Sparkline, 3)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/rates/stats?from=NGN&to=USD", nil))
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &stats))
+	assert.Len(t, stats.Sparkline, DefaultStatsBuckets)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodHead, "/rates/stats?from=USD&to=NGN", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Body.String())
+}
+
+func TestStatsHandler_Errors(t *testing.T) {
+	handler := StatsHandler(statsHistory(t), "USD")
+	tests := []struct {
+		method, target string
+		status         int
+		code           string
+	}{
+		{http.MethodGet, "/rates/stats?from=USD", http.StatusBadRequest, "invalid_pair"},
+		{http.MethodGet, "/rates/stats?from=USD&to=NGN&buckets=0", http.StatusBadRequest, "invalid_buckets"},
+		{http.MethodGet, "/rates/stats?from=USD&to=NGN&buckets=501", http.StatusBadRequest, "invalid_buckets"},
+		{http.MethodGet, "/rates/stats?from=USD&to=NGN&buckets=x", http.StatusBadRequest, "invalid_buckets"},
+		{http.MethodGet, "/rates/stats?from=USD&to=GBP", http.StatusNotFound, "no_rate_samples"},
+		{http.MethodPost, "/rates/stats?from=USD&to=NGN", http.StatusMethodNotAllowed, "method_not_allowed"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, nil))
+		assert.Equal(t, tt.status, rec.Code, tt.target)
+		var p Problem
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+		assert.Equal(t, tt.code, p.Code, tt.target)
+	}
+}
+
+func TestStatsRoute(t *testing.T) {
+	doc, err := OpenAPI("converter", "1.0.0", []Route{StatsRoute("/rates/stats")})
+	require.NoError(t, err)
+	var parsed map[string]any
+	require.NoError(t, json.Unmarshal(doc, &parsed))
+	get := parsed["paths"].(map[string]any)["/rates/stats"].(map[string]any)["get"].(map[string]any)
+	assert.Equal(t, "getRateStats", get["operationId"])
+	assert.Contains(t, parsed["components"].(map[string]any)["schemas"], "RateStats")
+}
@@ -0,0 +1,128 @@
+package converter
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// crockford is the Crockford base32 alphabet used by ULIDs. It leaves out
+// I, L, O and U so identifiers are easy to read out over the phone.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// IDGenerator generates unique quote IDs.
+type IDGenerator interface {
+	NewID(t time.Time) string
+}
+
+// ULIDGenerator generates ULIDs: 26 character IDs that sort by creation time.
+// IDs generated within the same millisecond are monotonically increasing.
+type ULIDGenerator struct {
+	mu      sync.Mutex
+	entropy io.Reader
+	hasLast bool
+	lastMs  uint64
+	last    [10]byte
+}
+
+// NewULIDGenerator creates a ULID generator reading randomness from entropy.
+// A nil entropy uses crypto/rand. If entropy fails, crypto/rand is used
+// instead, and if that fails too the previous random part is incremented, so
+// IDs stay unique rather than generation failing.
+func NewULIDGenerator(entropy io.Reader) *ULIDGenerator {
+	if entropy == nil {
+		entropy = rand.Reader
+	}
+	return &ULIDGenerator{entropy: entropy}
+}
+
+// NewID implements IDGenerator.
+func (g *ULIDGenerator) NewID(t time.Time) string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ms := uint64(t.UnixMilli())
+	if !g.hasLast || ms != g.lastMs || !incrementBytes(g.last[:]) {
+		g.readEntropy()
+	}
+	g.hasLast = true
+	g.lastMs = ms
+
+	var id [16]byte
+	for i := 0; i < 6; i++ {
+		id[i] = byte(ms >> (40 - 8*i))
+	}
+	copy(id[6:], g.last[:])
+
+	return encodeULID(id)
+}
+
+// readEntropy refills the random part of the ID.
+func (g *ULIDGenerator) readEntropy() {
+	var buf [10]byte
+	if _, err := io.ReadFull(g.entropy, buf[:]); err == nil {
+		g.last = buf
+		return
+	}
+	if _, err := io.ReadFull(rand.Reader, buf[:]); err == nil {
+		g.last = buf
+		return
+	}
+	incrementBytes(g.last[:])
+}
+
+// QuoteReference derives a short, human-friendly reference from a quote ID,
+// e.g. "7K3M-9XPA", for use in support tickets and customer communication.
+func QuoteReference(id string) string {
+	sum := sha256.Sum256([]byte(id))
+
+	var b strings.Builder
+	bits, n := uint64(0), 0
+	for _, c := range sum[:5] {
+		bits = bits<<8 | uint64(c)
+		n += 8
+		for n >= 5 {
+			n -= 5
+			b.WriteByte(crockford[(bits>>n)&31])
+			if b.Len() == 4 {
+				b.WriteByte('-')
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// encodeULID encodes 128 bits as 26 Crockford base32 characters.
+func encodeULID(id [16]byte) string {
+	var out [26]byte
+	// 130 bits of output for 128 bits of input: the first character carries
+	// only the top 3 bits.
+	var acc uint32
+	bits := 2
+	pos := 0
+	for _, c := range id {
+		acc = acc<<8 | uint32(c)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			out[pos] = crockford[(acc>>bits)&31]
+			pos++
+		}
+	}
+	return string(out[:])
+}
+
+// incrementBytes adds one to a big-endian number, reporting false on overflow.
+func incrementBytes(b []byte) bool {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i]++
+		if b[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
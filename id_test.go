@@ -0,0 +1,102 @@
+package converter
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestULIDGenerator(t *testing.T) {
+	gen := NewULIDGenerator(nil)
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	// Known encoding of the timestamp part
+	zero := NewULIDGenerator(bytes.NewReader(make([]byte, 10)))
+	assert.Equal(t, "00000000000000000000000000", zero.NewID(time.UnixMilli(0)))
+
+	ids := []string{
+		gen.NewID(at),
+		gen.NewID(at),
+		gen.NewID(at),
+		gen.NewID(at.Add(time.Millisecond)),
+		gen.NewID(at.Add(time.Hour)),
+	}
+
+	valid := regexp.MustCompile(`^[0-9A-HJKMNP-TV-Z]{26}$`)
+	for _, id := range ids {
+		assert.Regexp(t, valid, id)
+	}
+
+	// IDs are unique and sort in generation order
+	assert.True(t, sort.StringsAreSorted(ids))
+	seen := map[string]bool{}
+	for _, id := range ids {
+		assert.False(t, seen[id])
+		seen[id] = true
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) { return 0, errors.New("no entropy") }
+
+func TestULIDGenerator_EntropyFailure(t *testing.T) {
+	gen := NewULIDGenerator(failingReader{})
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	// Falls back instead of panicking
+	var id1, id2 string
+	assert.NotPanics(t, func() {
+		id1 = gen.NewID(at)
+		id2 = gen.NewID(at.Add(time.Second))
+	})
+	assert.Len(t, id1, 26)
+	assert.NotEqual(t, id1, id2)
+	assert.Less(t, id1, id2)
+}
+
+func TestQuoteReference(t *testing.T) {
+	ref := QuoteReference("01HKA0B5R8Z3J2XQW7N6T4M1C9")
+	assert.Regexp(t, `^[0-9A-HJKMNP-TV-Z]{4}-[0-9A-HJKMNP-TV-Z]{4}$`, ref)
+
+	// Deterministic and distinct per ID
+	assert.Equal(t, ref, QuoteReference("01HKA0B5R8Z3J2XQW7N6T4M1C9"))
+	assert.NotEqual(t, ref, QuoteReference("01HKA0B5R8Z3J2XQW7N6T4M1CA"))
+}
+
+type fixedIDGenerator string
+
+func (g fixedIDGenerator) NewID(time.Time) string { return string(g) }
+
+func TestNewQuote_IDs(t *testing.T) {
+	currencies := []Currency{
+		{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+		{ISOCode: "EUR", Precision: 2, BuyRate: decimal.NewFromFloat(0.95), SellRate: decimal.NewFromFloat(0.95)},
+	}
+
+	q1, err := NewQuote(currencies, "USD", "USD", "EUR", decimal.NewFromInt(100), decimal.Zero)
+	assert.NoError(t, err)
+	q2, err := NewQuote(currencies, "USD", "USD", "EUR", decimal.NewFromInt(100), decimal.Zero)
+	assert.NoError(t, err)
+
+	assert.Len(t, q1.ID, 26)
+	assert.NotEqual(t, q1.ID, q2.ID)
+	assert.Equal(t, QuoteReference(q1.ID), q1.Reference)
+
+	// Pluggable generator
+	q3, err := NewQuote(currencies, "USD", "USD", "EUR", decimal.NewFromInt(100), decimal.Zero, WithIDGenerator(fixedIDGenerator("quote-1")))
+	assert.NoError(t, err)
+	assert.Equal(t, "quote-1", q3.ID)
+	assert.Equal(t, QuoteReference("quote-1"), q3.Reference)
+
+	// A nil generator keeps the default
+	q4, err := NewQuote(currencies, "USD", "USD", "EUR", decimal.NewFromInt(100), decimal.Zero, WithIDGenerator(nil))
+	assert.NoError(t, err)
+	assert.Len(t, q4.ID, 26)
+}
@@ -0,0 +1,198 @@
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Symbol errors
+var (
+	ErrUnknownSymbol   = errors.New("unknown currency symbol")
+	ErrAmbiguousSymbol = errors.New("ambiguous currency symbol")
+)
+
+// CurrencyInfo holds presentation extras for a currency, so front-ends need
+// not keep their own symbol and flag maps.
+type CurrencyInfo struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+	// Symbol is the symbol used where the currency is domestic, e.g. "$".
+	Symbol string `json:"symbol"`
+	// Symbols lists Symbol and its common variants, e.g. "$" and "US$".
+	Symbols []string `json:"symbols"`
+	// Region is the ISO 3166-1 code of the issuing country, "EU" for the
+	// euro, or empty for currencies without a single issuer.
+	Region string `json:"region,omitempty"`
+	// Flag is the flag emoji of Region, or empty.
+	Flag string `json:"flag,omitempty"`
+	// MinorUnits is the number of decimal places of the currency's minor
+	// unit, e.g. 2 for USD, 0 for JPY and 3 for KWD. See MinorUnits.
+	MinorUnits int `json:"minorUnits"`
+}
+
+// currencyInfos is keyed by ISO code. Flags are derived from regions.
+var currencyInfos = map[string]CurrencyInfo{
+	"AED":  {Name: "UAE Dirham", Symbols: []string{"د.إ", "AED"}, Region: "AE"},
+	"AUD":  {Name: "Australian Dollar", Symbols: []string{"$", "A$", "AU$"}, Region: "AU"},
+	"BHD":  {Name: "Bahraini Dinar", Symbols: []string{"BD", ".د.ب"}, Region: "BH"},
+	"BRL":  {Name: "Brazilian Real", Symbols: []string{"R$"}, Region: "BR"},
+	"BTC":  {Name: "Bitcoin", Symbols: []string{"₿", "BTC"}},
+	"CAD":  {Name: "Canadian Dollar", Symbols: []string{"$", "CA$", "C$"}, Region: "CA"},
+	"CHF":  {Name: "Swiss Franc", Symbols: []string{"CHF", "Fr."}, Region: "CH"},
+	"CNY":  {Name: "Chinese Yuan", Symbols: []string{"¥", "CN¥", "元"}, Region: "CN"},
+	"DKK":  {Name: "Danish Krone", Symbols: []string{"kr.", "kr", "DKK"}, Region: "DK"},
+	"EGP":  {Name: "Egyptian Pound", Symbols: []string{"E£", "£", "ج.م"}, Region: "EG"},
+	"ETH":  {Name: "Ether", Symbols: []string{"Ξ", "ETH"}},
+	"EUR":  {Name: "Euro", Symbols: []string{"€"}, Region: "EU"},
+	"GBP":  {Name: "Pound Sterling", Symbols: []string{"£"}, Region: "GB"},
+	"GHS":  {Name: "Ghanaian Cedi", Symbols: []string{"₵", "GH₵"}, Region: "GH"},
+	"HKD":  {Name: "Hong Kong Dollar", Symbols: []string{"$", "HK$"}, Region: "HK"},
+	"ILS":  {Name: "Israeli New Shekel", Symbols: []string{"₪"}, Region: "IL"},
+	"INR":  {Name: "Indian Rupee", Symbols: []string{"₹", "Rs"}, Region: "IN"},
+	"JPY":  {Name: "Japanese Yen", Symbols: []string{"¥", "JP¥", "円"}, Region: "JP"},
+	"JOD":  {Name: "Jordanian Dinar", Symbols: []string{"JD", "د.ا"}, Region: "JO"},
+	"KES":  {Name: "Kenyan Shilling", Symbols: []string{"KSh", "Ksh"}, Region: "KE"},
+	"KRW":  {Name: "South Korean Won", Symbols: []string{"₩"}, Region: "KR"},
+	"KWD":  {Name: "Kuwaiti Dinar", Symbols: []string{"KD", "د.ك"}, Region: "KW"},
+	"MXN":  {Name: "Mexican Peso", Symbols: []string{"$", "MX$", "Mex$"}, Region: "MX"},
+	"NGN":  {Name: "Nigerian Naira", Symbols: []string{"₦"}, Region: "NG"},
+	"NOK":  {Name: "Norwegian Krone", Symbols: []string{"kr", "NOK"}, Region: "NO"},
+	"NZD":  {Name: "New Zealand Dollar", Symbols: []string{"$", "NZ$"}, Region: "NZ"},
+	"OMR":  {Name: "Omani Rial", Symbols: []string{"OMR", "ر.ع."}, Region: "OM"},
+	"PHP":  {Name: "Philippine Peso", Symbols: []string{"₱"}, Region: "PH"},
+	"PLN":  {Name: "Polish Zloty", Symbols: []string{"zł"}, Region: "PL"},
+	"RUB":  {Name: "Russian Ruble", Symbols: []string{"₽"}, Region: "RU"},
+	"SAR":  {Name: "Saudi Riyal", Symbols: []string{"ر.س", "SAR"}, Region: "SA"},
+	"SEK":  {Name: "Swedish Krona", Symbols: []string{"kr", "SEK"}, Region: "SE"},
+	"SGD":  {Name: "Singapore Dollar", Symbols: []string{"$", "S$"}, Region: "SG"},
+	"THB":  {Name: "Thai Baht", Symbols: []string{"฿"}, Region: "TH"},
+	"TND":  {Name: "Tunisian Dinar", Symbols: []string{"DT", "د.ت"}, Region: "TN"},
+	"TRY":  {Name: "Turkish Lira", Symbols: []string{"₺"}, Region: "TR"},
+	"UAH":  {Name: "Ukrainian Hryvnia", Symbols: []string{"₴"}, Region: "UA"},
+	"USD":  {Name: "US Dollar", Symbols: []string{"$", "US$"}, Region: "US"},
+	"USDT": {Name: "Tether", Symbols: []string{"₮", "USDT"}},
+	"VND":  {Name: "Vietnamese Dong", Symbols: []string{"₫"}, Region: "VN"},
+	"XAF":  {Name: "Central African CFA Franc", Symbols: []string{"FCFA", "F CFA"}},
+	"XOF":  {Name: "West African CFA Franc", Symbols: []string{"CFA", "F CFA"}},
+	"ZAR":  {Name: "South African Rand", Symbols: []string{"R"}, Region: "ZA"},
+}
+
+// LookupCurrencyInfo returns the presentation extras for an ISO code.
+func LookupCurrencyInfo(code string) (CurrencyInfo, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	info, ok := currencyInfos[code]
+	if !ok {
+		return CurrencyInfo{}, false
+	}
+	return completeInfo(code, info), true
+}
+
+// CurrencyInfos returns the presentation extras of every known currency,
+// sorted by ISO code.
+func CurrencyInfos() []CurrencyInfo {
+	infos := make([]CurrencyInfo, 0, len(currencyInfos))
+	for code, info := range currencyInfos {
+		infos = append(infos, completeInfo(code, info))
+	}
+	slices.SortFunc(infos, func(a, b CurrencyInfo) int {
+		return strings.Compare(a.Code, b.Code)
+	})
+	return infos
+}
+
+// completeInfo fills the derived fields of an entry, copying its symbols so
+// callers cannot modify the registry.
+func completeInfo(code string, info CurrencyInfo) CurrencyInfo {
+	info.Code = code
+	info.Symbols = slices.Clone(info.Symbols)
+	info.Symbol = info.Symbols[0]
+	info.Flag = FlagEmoji(info.Region)
+	info.MinorUnits, _ = MinorUnits(code)
+	return info
+}
+
+// FlagEmoji returns the flag emoji of a two-letter region code, e.g. "🇳🇬" for
+// "NG", or an empty string if region is not two letters.
+func FlagEmoji(region string) string {
+	region = strings.ToUpper(region)
+	if len(region) != 2 {
+		return ""
+	}
+
+	var flag []rune
+	for _, ch := range region {
+		if ch < 'A' || ch > 'Z' {
+			return ""
+		}
+		// Regional indicator symbols start at U+1F1E6 for A.
+		flag = append(flag, 0x1F1E6+ch-'A')
+	}
+	return string(flag)
+}
+
+// AmbiguousSymbolError reports a symbol used by several currencies that the
+// hints given to ResolveSymbol did not narrow to one.
+type AmbiguousSymbolError struct {
+	Symbol string
+	// Candidates are the ISO codes using the symbol, sorted.
+	Candidates []string
+}
+
+// Error implements error.
+func (e *AmbiguousSymbolError) Error() string {
+	return fmt.Sprintf("%v: %q could be %s", ErrAmbiguousSymbol, e.Symbol, strings.Join(e.Candidates, ", "))
+}
+
+// Is makes every AmbiguousSymbolError match ErrAmbiguousSymbol.
+func (e *AmbiguousSymbolError) Is(target error) bool {
+	return target == ErrAmbiguousSymbol
+}
+
+// ResolveSymbol maps a currency symbol such as "₦" or "US$" to its ISO code;
+// a known ISO code resolves to itself. A symbol shared by several currencies,
+// such as "$", is narrowed by hints naming a region ("CA") or locale
+// ("en-CA", "en_AU"); if it remains ambiguous the error is an
+// *AmbiguousSymbolError listing the candidates.
+func ResolveSymbol(symbol string, hints ...string) (string, error) {
+	symbol = strings.TrimSpace(symbol)
+	if _, ok := currencyInfos[strings.ToUpper(symbol)]; ok {
+		return strings.ToUpper(symbol), nil
+	}
+
+	var candidates []string
+	for code, info := range currencyInfos {
+		if slices.ContainsFunc(info.Symbols, func(s string) bool { return strings.EqualFold(s, symbol) }) {
+			candidates = append(candidates, code)
+		}
+	}
+	slices.Sort(candidates)
+
+	switch len(candidates) {
+	case 0:
+		return "", fmt.Errorf("%w: %q", ErrUnknownSymbol, symbol)
+	case 1:
+		return candidates[0], nil
+	}
+
+	for _, hint := range hints {
+		region := hintRegion(hint)
+		for _, code := range candidates {
+			if currencyInfos[code].Region == region {
+				return code, nil
+			}
+		}
+	}
+	return "", &AmbiguousSymbolError{Symbol: symbol, Candidates: candidates}
+}
+
+// hintRegion returns the region of a hint: the hint itself, or the part of a
+// locale after the language, e.g. "CA" for "en-CA".
+func hintRegion(hint string) string {
+	hint = strings.TrimSpace(hint)
+	if i := strings.LastIndexAny(hint, "-_"); i >= 0 {
+		hint = hint[i+1:]
+	}
+	return strings.ToUpper(hint)
+}
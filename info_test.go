@@ -0,0 +1,92 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupCurrencyInfo(t *testing.T) {
+	info, ok := LookupCurrencyInfo(" ngn")
+	assert.True(t, ok)
+	assert.Equal(t, CurrencyInfo{
+		Code:       "NGN",
+		Name:       "Nigerian Naira",
+		Symbol:     "₦",
+		Symbols:    []string{"₦"},
+		Region:     "NG",
+		Flag:       "🇳🇬",
+		MinorUnits: 2,
+	}, info)
+
+	info, ok = LookupCurrencyInfo("EUR")
+	assert.True(t, ok)
+	assert.Equal(t, "🇪🇺", info.Flag)
+
+	info, ok = LookupCurrencyInfo("BTC")
+	assert.True(t, ok)
+	assert.Equal(t, "₿", info.Symbol)
+	assert.Empty(t, info.Flag)
+	assert.Equal(t, 8, info.MinorUnits)
+
+	info, ok = LookupCurrencyInfo("KWD")
+	assert.True(t, ok)
+	assert.Equal(t, 3, info.MinorUnits)
+
+	_, ok = LookupCurrencyInfo("XXX")
+	assert.False(t, ok)
+
+	// Returned symbols do not alias the registry.
+	info, _ = LookupCurrencyInfo("USD")
+	info.Symbols[0] = "X"
+	info, _ = LookupCurrencyInfo("USD")
+	assert.Equal(t, []string{"$", "US$"}, info.Symbols)
+}
+
+func TestCurrencyInfos(t *testing.T) {
+	infos := CurrencyInfos()
+	assert.Equal(t, "AED", infos[0].Code)
+	for i, info := range infos {
+		assert.NotEmpty(t, info.Symbol, info.Code)
+		if i > 0 {
+			assert.Less(t, infos[i-1].Code, info.Code)
+		}
+	}
+}
+
+func TestFlagEmoji(t *testing.T) {
+	assert.Equal(t, "🇺🇸", FlagEmoji("us"))
+	assert.Empty(t, FlagEmoji(""))
+	assert.Empty(t, FlagEmoji("USA"))
+	assert.Empty(t, FlagEmoji("1A"))
+}
+
+func TestResolveSymbol(t *testing.T) {
+	testCases := []struct {
+		symbol string
+		hints  []string
+		code   string
+	}{
+		{symbol: "₦", code: "NGN"},
+		{symbol: " US$ ", code: "USD"},
+		{symbol: "ngn", code: "NGN"},
+		{symbol: "$", hints: []string{"CA"}, code: "CAD"},
+		{symbol: "$", hints: []string{"fr-FR", "en_AU"}, code: "AUD"},
+		{symbol: "¥", hints: []string{"ja-JP"}, code: "JPY"},
+		{symbol: "KR", hints: []string{"se"}, code: "SEK"},
+	}
+	for _, tc := range testCases {
+		code, err := ResolveSymbol(tc.symbol, tc.hints...)
+		assert.NoError(t, err, tc.symbol)
+		assert.Equal(t, tc.code, code, tc.symbol)
+	}
+
+	_, err := ResolveSymbol("$", "FR")
+	var ambiguous *AmbiguousSymbolError
+	assert.ErrorAs(t, err, &ambiguous)
+	assert.ErrorIs(t, err, ErrAmbiguousSymbol)
+	assert.Equal(t, []string{"AUD", "CAD", "HKD", "MXN", "NZD", "SGD", "USD"}, ambiguous.Candidates)
+
+	_, err = ResolveSymbol("¤")
+	assert.ErrorIs(t, err, ErrUnknownSymbol)
+}
@@ -0,0 +1,105 @@
+package converter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// currencyJSON is the wire form of a Currency. Rates are kept as raw JSON so
+// they are parsed from their original text and never pass through float64.
+type currencyJSON struct {
+	ISOCode   string          `json:"isoCode"`
+	Precision int             `json:"precision"`
+	BuyRate   json.RawMessage `json:"buyRate"`
+	SellRate  json.RawMessage `json:"sellRate"`
+	// BaseISOCode is optional; see Currency.BaseISOCode.
+	BaseISOCode string `json:"baseIsoCode,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Rates are decoded as json.Number
+// (or JSON strings) directly into decimals, so feeds with more digits than a
+// float64 holds keep every digit.
+func (c *Currency) UnmarshalJSON(data []byte) error {
+	var raw currencyJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	currency, err := raw.currency()
+	if err != nil {
+		return err
+	}
+	*c = currency
+	return nil
+}
+
+// currency decodes the rates of raw.
+func (raw currencyJSON) currency() (Currency, error) {
+	buy, err := decodeRate(raw.BuyRate)
+	if err != nil {
+		return Currency{}, fmt.Errorf("%w: %s buy rate: %v", ErrInvalidRate, raw.ISOCode, err)
+	}
+	sell, err := decodeRate(raw.SellRate)
+	if err != nil {
+		return Currency{}, fmt.Errorf("%w: %s sell rate: %v", ErrInvalidRate, raw.ISOCode, err)
+	}
+	return Currency{ISOCode: raw.ISOCode, Precision: raw.Precision, BuyRate: buy, SellRate: sell, BaseISOCode: raw.BaseISOCode}, nil
+}
+
+// ParseRate parses a rate formatted as a string, as many upstream APIs send
+// them: plain ("0.000123", "+1.5") or scientific ("1.23e-4", "1.5E+3")
+// notation, with surrounding whitespace ignored. The input is not echoed in
+// the error, since feeds can send arbitrarily long values.
+func ParseRate(s string) (decimal.Decimal, error) {
+	d, err := parseRate(s)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("%w: %v", ErrInvalidRate, err)
+	}
+	return d, nil
+}
+
+func parseRate(s string) (decimal.Decimal, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return decimal.Zero, errors.New("empty")
+	}
+
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		return decimal.Zero, errors.New("not a decimal number")
+	}
+	return d, nil
+}
+
+// decodeRate parses a JSON number or string into a decimal. A missing or null
+// rate, or an empty string, is zero.
+func decodeRate(raw json.RawMessage) (decimal.Decimal, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || string(raw) == "null" {
+		return decimal.Zero, nil
+	}
+
+	if raw[0] == '"' {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return decimal.Zero, err
+		}
+		if strings.TrimSpace(s) == "" {
+			return decimal.Zero, nil
+		}
+		return parseRate(s)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var n json.Number
+	if err := dec.Decode(&n); err != nil {
+		return decimal.Zero, errors.New("not a number")
+	}
+	return decimal.NewFromString(n.String())
+}
@@ -0,0 +1,113 @@
+package converter
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCurrencies_PreservesDigits(t *testing.T) {
+	const rate = "0.000012345678901234567890123456789"
+
+	// Raw JSON numbers
+	currencies, err := NewCurrencies([]json.RawMessage{
+		json.RawMessage(`{"isoCode":"BTC","precision":8,"buyRate":` + rate + `,"sellRate":"` + rate + `"}`),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, rate, currencies[0].BuyRate.String())
+	assert.Equal(t, rate, currencies[0].SellRate.String())
+
+	// Provider feeds decoded with UseNumber
+	dec := json.NewDecoder(strings.NewReader(`[{"isoCode":"BTC","precision":8,"buyRate":` + rate + `,"sellRate":` + rate + `}]`))
+	dec.UseNumber()
+	var feed []map[string]any
+	assert.NoError(t, dec.Decode(&feed))
+
+	currencies, err = NewCurrencies(feed)
+	assert.NoError(t, err)
+	assert.Equal(t, rate, currencies[0].BuyRate.String())
+
+	// A float64 source has lost digits before ingestion
+	var floats []map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(`[{"isoCode":"BTC","precision":8,"buyRate":`+rate+`,"sellRate":1}]`), &floats))
+	currencies, err = NewCurrencies(floats)
+	assert.NoError(t, err)
+	assert.NotEqual(t, rate, currencies[0].BuyRate.String())
+}
+
+func TestCurrency_UnmarshalJSON(t *testing.T) {
+	var c Currency
+	assert.NoError(t, json.Unmarshal([]byte(`{"isoCode":"USD","precision":2,"buyRate":1,"sellRate":null}`), &c))
+	assert.Equal(t, "1", c.BuyRate.String())
+	assert.True(t, c.SellRate.IsZero())
+
+	// Errors name the currency and the rate
+	err := json.Unmarshal([]byte(`{"isoCode":"EUR","buyRate":true}`), &c)
+	assert.ErrorIs(t, err, ErrInvalidRate)
+	assert.ErrorContains(t, err, "EUR buy rate")
+
+	err = json.Unmarshal([]byte(`{"isoCode":"EUR","buyRate":1,"sellRate":"abc"}`), &c)
+	assert.ErrorIs(t, err, ErrInvalidRate)
+	assert.ErrorContains(t, err, "EUR sell rate")
+
+	// Marshalling round-trips
+	b, err := json.Marshal(Currency{ISOCode: "USD", Precision: 2, BuyRate: c.BuyRate, SellRate: c.BuyRate})
+	assert.NoError(t, err)
+	assert.NotContains(t, string(b), "baseIsoCode")
+	var again Currency
+	assert.NoError(t, json.Unmarshal(b, &again))
+	assert.Equal(t, "1", again.SellRate.String())
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"isoCode":"GBP","buyRate":0.8,"sellRate":0.85,"baseIsoCode":"EUR"}`), &c))
+	assert.Equal(t, "EUR", c.BaseISOCode)
+}
+
+func TestParseRate(t *testing.T) {
+	testCases := []struct {
+		in   string
+		want string
+	}{
+		{in: "0.000123", want: "0.000123"},
+		{in: " 1.5 ", want: "1.5"},
+		{in: "+1.5", want: "1.5"},
+		{in: "1.23e-4", want: "0.000123"},
+		{in: "1.5E+3", want: "1500"},
+		{in: ".5", want: "0.5"},
+	}
+	for _, tc := range testCases {
+		got, err := ParseRate(tc.in)
+		assert.NoError(t, err, tc.in)
+		assert.Equal(t, tc.want, got.String(), tc.in)
+	}
+
+	for _, in := range []string{"", "  ", "NaN", "Inf", "1e", "0x10", "1,5", strings.Repeat("9", 100) + "x"} {
+		_, err := ParseRate(in)
+		assert.ErrorIs(t, err, ErrInvalidRate, in)
+		assert.NotContains(t, err.Error(), "9999", in)
+	}
+}
+
+func TestNewCurrencies_StringRates(t *testing.T) {
+	type feedRate struct {
+		Code string `json:"isoCode"`
+		Buy  string `json:"buyRate"`
+		Sell string `json:"sellRate"`
+	}
+
+	currencies, err := NewCurrencies([]feedRate{
+		{Code: "USD", Buy: "1", Sell: "1"},
+		{Code: "BTC", Buy: "1.523e-5", Sell: " 0.0000153 "},
+		{Code: "XYZ", Buy: "", Sell: "2E0"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "0.00001523", currencies[1].BuyRate.String())
+	assert.Equal(t, "0.0000153", currencies[1].SellRate.String())
+	assert.True(t, currencies[2].BuyRate.IsZero())
+	assert.Equal(t, "2", currencies[2].SellRate.String())
+
+	_, err = NewCurrencies([]feedRate{{Code: "BTC", Buy: "1.5e", Sell: "1"}})
+	assert.ErrorIs(t, err, ErrInvalidRate)
+	assert.ErrorContains(t, err, "BTC buy rate: not a decimal number")
+}
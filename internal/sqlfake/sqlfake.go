@@ -0,0 +1,122 @@
+// Package sqlfake is a minimal database/sql driver for tests. Statements are
+// answered by handler functions, so tests can script results without a real
+// database.
+package sqlfake
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Handler answers statements sent to a fake database.
+type Handler struct {
+	// Exec handles statements that do not return rows. A nil Exec accepts
+	// every statement.
+	Exec func(query string, args []driver.Value) error
+	// Query handles statements that return rows.
+	Query func(query string, args []driver.Value) (columns []string, rows [][]driver.Value, err error)
+}
+
+var (
+	mu       sync.Mutex
+	handlers = map[string]*Handler{}
+	seq      int
+)
+
+func init() {
+	sql.Register("sqlfake", fakeDriver{})
+}
+
+// Open opens a database answered by h.
+func Open(h *Handler) *sql.DB {
+	mu.Lock()
+	seq++
+	name := fmt.Sprintf("db%d", seq)
+	handlers[name] = h
+	mu.Unlock()
+
+	db, _ := sql.Open("sqlfake", name)
+	return db
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	h, ok := handlers[name]
+	if !ok {
+		return nil, fmt.Errorf("sqlfake: unknown database %q", name)
+	}
+	return &conn{h: h}, nil
+}
+
+type conn struct {
+	h *Handler
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return &stmt{c: c, query: query}, nil
+}
+
+func (c *conn) Close() error { return nil }
+
+func (c *conn) Begin() (driver.Tx, error) { return tx{}, nil }
+
+func (c *conn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) { return tx{}, nil }
+
+type tx struct{}
+
+func (tx) Commit() error   { return nil }
+func (tx) Rollback() error { return nil }
+
+type stmt struct {
+	c     *conn
+	query string
+}
+
+func (s *stmt) Close() error  { return nil }
+func (s *stmt) NumInput() int { return -1 }
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	if s.c.h.Exec != nil {
+		if err := s.c.h.Exec(s.query, args); err != nil {
+			return nil, err
+		}
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	if s.c.h.Query == nil {
+		return nil, fmt.Errorf("sqlfake: unexpected query %q", s.query)
+	}
+	columns, values, err := s.c.h.Query(s.query, args)
+	if err != nil {
+		return nil, err
+	}
+	return &rows{columns: columns, values: values}, nil
+}
+
+type rows struct {
+	columns []string
+	values  [][]driver.Value
+	pos     int
+}
+
+func (r *rows) Columns() []string { return r.columns }
+func (r *rows) Close() error      { return nil }
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.values) {
+		return io.EOF
+	}
+	copy(dest, r.values[r.pos])
+	r.pos++
+	return nil
+}
@@ -0,0 +1,165 @@
+package converter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrSnapshotBehind is reported when the shared snapshot store holds an
+// older table than was announced, e.g. behind a lagging replica.
+var ErrSnapshotBehind = errors.New("snapshot older than announced")
+
+// InvalidationBus carries table announcements between instances, e.g.
+// over Redis pub/sub or NATS.
+type InvalidationBus interface {
+	// Publish sends msg to every subscriber.
+	Publish(ctx context.Context, msg []byte) error
+	// Subscribe sends each message published until ctx is done or the
+	// subscription fails. It returns the error ending the subscription.
+	Subscribe(ctx context.Context, msgs chan<- []byte) error
+}
+
+// Announcement tells the instances of a fleet that a new table was
+// published to their shared snapshot store.
+type Announcement struct {
+	// Instance identifies the publisher, so it skips its own
+	// announcements.
+	Instance string    `json:"instance"`
+	TakenAt  time.Time `json:"takenAt"`
+}
+
+// Invalidation reloads a table as soon as another instance announces a
+// newer one on a bus, instead of waiting for its own refresh, and drops
+// whatever was derived from the old rates through OnInvalidate.
+type Invalidation struct {
+	Table *Currencies
+	Bus   InvalidationBus
+	// Snapshots is the store shared by the fleet, announced tables are
+	// reloaded from.
+	Snapshots SnapshotStore
+	// Instance identifies this instance in announcements.
+	Instance string
+	// OnInvalidate, if set, is called after each reload, to drop memoized
+	// cross rates and other data derived from the previous table.
+	OnInvalidate func()
+	// Retry is the wait before subscribing again after the subscription
+	// fails; DefaultWatchRetry if zero.
+	Retry time.Duration
+	// OnError, if set, receives subscription and reload failures.
+	OnError func(err error)
+}
+
+// NewInvalidation creates an invalidation of table over bus, reloading
+// from snapshots.
+func NewInvalidation(table *Currencies, bus InvalidationBus, snapshots SnapshotStore, instance string) *Invalidation {
+	return &Invalidation{Table: table, Bus: bus, Snapshots: snapshots, Instance: instance}
+}
+
+// Announce tells the other instances that the table's current contents
+// were published.
+func (i *Invalidation) Announce(ctx context.Context) error {
+	return i.announce(ctx, i.Table.UpdatedAt())
+}
+
+func (i *Invalidation) announce(ctx context.Context, takenAt time.Time) error {
+	msg, err := json.Marshal(Announcement{Instance: i.Instance, TakenAt: takenAt})
+	if err != nil {
+		return err
+	}
+	return i.Bus.Publish(ctx, msg)
+}
+
+// Publisher returns a SnapshotStore saving to Snapshots and announcing
+// each saved table, for the Refresher of the publishing instance.
+func (i *Invalidation) Publisher() SnapshotStore {
+	return announcingStore{SnapshotStore: i.Snapshots, invalidation: i}
+}
+
+// announcingStore announces each snapshot saved.
+type announcingStore struct {
+	SnapshotStore
+	invalidation *Invalidation
+}
+
+func (s announcingStore) Save(ctx context.Context, snapshot *Snapshot) error {
+	if err := s.SnapshotStore.Save(ctx, snapshot); err != nil {
+		return err
+	}
+	return s.invalidation.announce(ctx, snapshot.TakenAt)
+}
+
+// Run reloads the table on announcements from other instances until ctx
+// is cancelled, subscribing again after Retry whenever the subscription
+// fails. Announcements of tables no newer than the one held are ignored.
+func (i *Invalidation) Run(ctx context.Context) error {
+	retry := i.Retry
+	if retry <= 0 {
+		retry = DefaultWatchRetry
+	}
+
+	for {
+		msgs := make(chan []byte)
+		done := make(chan error, 1)
+		go func() {
+			done <- i.Bus.Subscribe(ctx, msgs)
+		}()
+
+		var err error
+	subscription:
+		for {
+			select {
+			case msg := <-msgs:
+				if reloadErr := i.reload(ctx, msg); reloadErr != nil {
+					i.report(fmt.Errorf("reload announced table: %w", reloadErr))
+				}
+			case err = <-done:
+				break subscription
+			}
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			i.report(fmt.Errorf("subscribe to table announcements: %w", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(retry):
+		}
+	}
+}
+
+// reload loads the announced table if it is newer than the one held.
+func (i *Invalidation) reload(ctx context.Context, msg []byte) error {
+	var a Announcement
+	if err := json.Unmarshal(msg, &a); err != nil {
+		return err
+	}
+	if a.Instance == i.Instance || !a.TakenAt.After(i.Table.UpdatedAt()) {
+		return nil
+	}
+
+	snapshot, err := loadValidSnapshot(ctx, i.Snapshots)
+	if err != nil {
+		return err
+	}
+	if snapshot.TakenAt.Before(a.TakenAt) {
+		return fmt.Errorf("%w: %s before %s", ErrSnapshotBehind, snapshot.TakenAt.Format(time.RFC3339), a.TakenAt.Format(time.RFC3339))
+	}
+	i.Table.Restore(snapshot)
+	if i.OnInvalidate != nil {
+		i.OnInvalidate()
+	}
+	return nil
+}
+
+func (i *Invalidation) report(err error) {
+	if i.OnError != nil {
+		i.OnError(err)
+	}
+}
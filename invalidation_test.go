@@ -0,0 +1,99 @@
+package converter
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryBus fans published messages out to its subscribers.
+type memoryBus struct {
+	mu          sync.Mutex
+	subscribers []chan []byte
+}
+
+func (b *memoryBus) Publish(_ context.Context, msg []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, ch := range b.subscribers {
+		ch <- msg
+	}
+	return nil
+}
+
+func (b *memoryBus) Subscribe(ctx context.Context, msgs chan<- []byte) error {
+	ch := make(chan []byte, 16)
+	b.mu.Lock()
+	b.subscribers = append(b.subscribers, ch)
+	b.mu.Unlock()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg := <-ch:
+			msgs <- msg
+		}
+	}
+}
+
+func (b *memoryBus) subscribed() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subscribers)
+}
+
+func TestInvalidation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	takenAt := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	bus := &memoryBus{}
+	shared := NewBlobSnapshotStore(NewMemoryBlobStore(), "rates")
+
+	// The publishing instance refreshes into the shared store and announces
+	publisher := NewInvalidation(NewTable(nil), bus, shared, "a")
+	provider := RateProviderFunc(func(context.Context) ([]Currency, error) {
+		return testCurrencies(), nil
+	})
+	r, err := NewRefresher(publisher.Table, provider, time.Hour)
+	require.NoError(t, err)
+	r.Snapshots = publisher.Publisher()
+	r.Clock = fixedClock(takenAt)
+
+	var invalidated atomic.Int32
+	errs := make(chan error, 10)
+	follower := NewInvalidation(NewTable(nil), bus, shared, "b")
+	follower.OnInvalidate = func() { invalidated.Add(1) }
+	follower.OnError = func(err error) { errs <- err }
+	go follower.Run(ctx)
+	go publisher.Run(ctx)
+	require.Eventually(t, func() bool { return bus.subscribed() == 2 }, time.Second, time.Millisecond)
+
+	require.NoError(t, r.Refresh(ctx))
+	require.Eventually(t, func() bool { return invalidated.Load() == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, publisher.Table.List(), follower.Table.List())
+	assert.Equal(t, takenAt, follower.Table.UpdatedAt())
+
+	// Announcing the same table again changes nothing
+	require.NoError(t, publisher.Announce(ctx))
+
+	// A store lagging behind the announcement is reported
+	msg, err := json.Marshal(Announcement{Instance: "c", TakenAt: takenAt.Add(time.Minute)})
+	require.NoError(t, err)
+	require.NoError(t, bus.Publish(ctx, msg))
+	assert.ErrorIs(t, <-errs, ErrSnapshotBehind)
+
+	updated := testCurrencies()
+	updated[2].SellRate = decimal.NewFromInt(1600)
+	require.NoError(t, shared.Save(ctx, NewSnapshot(updated, takenAt.Add(time.Minute))))
+	require.NoError(t, bus.Publish(ctx, msg))
+	require.Eventually(t, func() bool { return invalidated.Load() == 2 }, time.Second, time.Millisecond)
+	assert.Equal(t, "1600", follower.Table.List()[1].SellRate.String())
+	assert.Empty(t, errs)
+}
@@ -0,0 +1,153 @@
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Invariant errors
+var (
+	ErrInvariantViolated = errors.New("rate invariant violated")
+)
+
+// defaultInvariantTolerance is the relative difference allowed between rates
+// that must agree, covering division rounding.
+var defaultInvariantTolerance = decimal.New(1, -9)
+
+// Invariants checks properties every consistent rate table has:
+//
+//   - the base currency is in the table and its rates are 1
+//   - every currency converts to itself at rate 1
+//   - a round trip A→B→A stays within MaxSpread of 1
+//   - a cross rate A→B equals A→base→B
+//
+// Check has the signature of Refresher.Verify, so the same rules can gate
+// refreshed rates in production and be asserted in tests.
+type Invariants struct {
+	Base string
+	// MaxSpread bounds |rate(A→B) * rate(B→A) - 1|. Zero skips the check.
+	MaxSpread decimal.Decimal
+	// Tolerance is the relative difference allowed between rates that must
+	// agree. It defaults to 1e-9.
+	Tolerance decimal.Decimal
+}
+
+// InvariantError describes one violated invariant.
+type InvariantError struct {
+	Invariant string
+	From, To  string
+	Detail    string
+	// Err is the underlying error when a rate could not be computed.
+	Err error
+}
+
+// Error implements error.
+func (e *InvariantError) Error() string {
+	if e.To == "" {
+		return fmt.Sprintf("%s: %s: %s", e.Invariant, e.From, e.Detail)
+	}
+	return fmt.Sprintf("%s: %s→%s: %s", e.Invariant, e.From, e.To, e.Detail)
+}
+
+// Is makes every InvariantError match ErrInvariantViolated.
+func (e *InvariantError) Is(target error) bool {
+	return target == ErrInvariantViolated
+}
+
+// Unwrap returns the underlying error, if any.
+func (e *InvariantError) Unwrap() error {
+	return e.Err
+}
+
+// Check verifies the invariants over currencies. It reports every violation,
+// joined into one error, so a failing table can be diagnosed in one pass.
+func (inv Invariants) Check(currencies []Currency) error {
+	var errs []error
+	fail := func(invariant, from, to, format string, args ...any) {
+		errs = append(errs, &InvariantError{Invariant: invariant, From: from, To: to, Detail: fmt.Sprintf(format, args...)})
+	}
+	failErr := func(invariant, from, to string, err error) {
+		errs = append(errs, &InvariantError{Invariant: invariant, From: from, To: to, Detail: err.Error(), Err: err})
+	}
+
+	base := strings.ToUpper(inv.Base)
+	baseCurrency, err := FindCurrency(currencies, base)
+	if err != nil {
+		fail("base", base, "", "not in table")
+		return errors.Join(errs...)
+	}
+	one := decimal.NewFromInt(1)
+	if !baseCurrency.BuyRate.Equal(one) || !baseCurrency.SellRate.Equal(one) {
+		fail("base", base, "", "rates are %s/%s, want 1/1", baseCurrency.BuyRate, baseCurrency.SellRate)
+	}
+
+	for _, c := range currencies {
+		if err := c.Validate(); err != nil {
+			failErr("valid", c.ISOCode, "", err)
+		}
+	}
+	if len(errs) > 0 {
+		// Rates cannot be computed from an invalid table.
+		return errors.Join(errs...)
+	}
+
+	rate := func(from, to string) (decimal.Decimal, bool) {
+		r, err := CalculateRate(currencies, base, from, to)
+		if err != nil {
+			failErr("rate", from, to, err)
+			return decimal.Zero, false
+		}
+		return r, true
+	}
+
+	for i, a := range currencies {
+		if r, ok := rate(a.ISOCode, a.ISOCode); ok && !r.Equal(one) {
+			fail("identity", a.ISOCode, a.ISOCode, "rate is %s, want 1", r)
+		}
+
+		for _, b := range currencies[i+1:] {
+			ab, ok1 := rate(a.ISOCode, b.ISOCode)
+			ba, ok2 := rate(b.ISOCode, a.ISOCode)
+			if !ok1 || !ok2 {
+				continue
+			}
+
+			if inv.MaxSpread.IsPositive() {
+				if spread := ab.Mul(ba).Sub(one).Abs(); spread.GreaterThan(inv.MaxSpread) {
+					fail("round-trip", a.ISOCode, b.ISOCode, "spread %s exceeds %s", spread.StringFixed(6), inv.MaxSpread)
+				}
+			}
+
+			if strings.EqualFold(a.ISOCode, base) || strings.EqualFold(b.ISOCode, base) {
+				continue
+			}
+			for _, pair := range [][2]string{{a.ISOCode, b.ISOCode}, {b.ISOCode, a.ISOCode}} {
+				direct := ab
+				if pair[0] == b.ISOCode {
+					direct = ba
+				}
+				toBase, ok1 := rate(pair[0], base)
+				fromBase, ok2 := rate(base, pair[1])
+				if ok1 && ok2 && !inv.agree(direct, toBase.Mul(fromBase)) {
+					fail("cross", pair[0], pair[1], "rate %s differs from %s via %s", direct, toBase.Mul(fromBase), base)
+				}
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// agree reports whether a and b are equal within the relative tolerance.
+func (inv Invariants) agree(a, b decimal.Decimal) bool {
+	tolerance := inv.Tolerance
+	if !tolerance.IsPositive() {
+		tolerance = defaultInvariantTolerance
+	}
+
+	scale := decimal.Max(a.Abs(), b.Abs())
+	return a.Sub(b).Abs().LessThanOrEqual(scale.Mul(tolerance))
+}
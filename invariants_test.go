@@ -0,0 +1,67 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvariants_Check(t *testing.T) {
+	inv := Invariants{Base: "usd", MaxSpread: decimal.NewFromFloat(0.1)}
+	assert.NoError(t, inv.Check(testCurrencies()))
+
+	// A spread wider than allowed
+	wide := testCurrencies()
+	wide[2].SellRate = decimal.NewFromInt(2000)
+	err := inv.Check(wide)
+	assert.ErrorIs(t, err, ErrInvariantViolated)
+	var violation *InvariantError
+	assert.True(t, errors.As(err, &violation))
+	assert.Equal(t, "round-trip", violation.Invariant)
+	assert.Equal(t, "USD", violation.From)
+	assert.Equal(t, "NGN", violation.To)
+
+	// Zero MaxSpread skips the spread check
+	assert.NoError(t, Invariants{Base: "USD"}.Check(wide))
+
+	// The base must be present and at par
+	assert.ErrorContains(t, Invariants{Base: "GBP"}.Check(testCurrencies()), "not in table")
+	offPar := testCurrencies()
+	offPar[0].SellRate = decimal.NewFromFloat(1.01)
+	assert.ErrorContains(t, inv.Check(offPar), "want 1/1")
+
+	// Rates that cannot be priced with are reported
+	zero := testCurrencies()
+	zero[1].BuyRate = decimal.Zero
+	assert.ErrorIs(t, inv.Check(zero), ErrInvalidRate)
+}
+
+func TestInvariants_Agree(t *testing.T) {
+	inv := Invariants{}
+	assert.True(t, inv.agree(decimal.RequireFromString("1.0000000001"), decimal.NewFromInt(1)))
+	assert.False(t, inv.agree(decimal.RequireFromString("1.001"), decimal.NewFromInt(1)))
+
+	inv.Tolerance = decimal.NewFromFloat(0.01)
+	assert.True(t, inv.agree(decimal.RequireFromString("1.001"), decimal.NewFromInt(1)))
+}
+
+func TestRefresher_Verify(t *testing.T) {
+	rates := testCurrencies()
+	table := NewTable(testCurrencies())
+	r, err := NewRefresher(table, RateProviderFunc(func(context.Context) ([]Currency, error) {
+		return rates, nil
+	}), time.Minute)
+	assert.NoError(t, err)
+	r.Verify = Invariants{Base: "USD", MaxSpread: decimal.NewFromFloat(0.1)}.Check
+
+	// A table failing the gate is not applied
+	rates[2].SellRate = decimal.NewFromInt(3000)
+	assert.ErrorIs(t, r.Refresh(context.Background()), ErrInvariantViolated)
+	found, _ := table.FindCurrency("NGN")
+	assert.Equal(t, "1550", found.SellRate.String())
+	assert.Equal(t, int64(1), r.Stats().Failures)
+}
@@ -0,0 +1,64 @@
+package converter
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// Invert returns a copy of the table with reciprocal rates, i.e. base units
+// per unit of each currency instead of units per base, for systems quoting
+// the other way round. Buy and sell swap sides, so the spread still favours
+// the table: a currency's inverted buy rate is 1/sell rounded up, and its
+// inverted sell rate is 1/buy rounded down, to the table's division
+// precision (the global DivisionPrecision without one). It returns an
+// ErrInvalidRate error if a rate is not positive. Tables whose currencies
+// carry their own bases (see Currency.BaseISOCode) cannot be inverted.
+func (c *Currencies) Invert() (*Currencies, error) {
+	s := c.Snapshot()
+	if hasOwnBases(s.Currencies) {
+		return nil, errors.New("cannot invert a table whose currencies carry their own bases")
+	}
+
+	c.mu.RLock()
+	precision := int32(decimal.DivisionPrecision)
+	if c.division != nil {
+		precision = c.division.Precision
+	}
+	closes := make(map[string]Currency, len(c.closes))
+	for code, item := range c.closes {
+		inverted, err := invertCurrency(item, precision)
+		if err != nil {
+			c.mu.RUnlock()
+			return nil, err
+		}
+		closes[code] = inverted
+	}
+	c.mu.RUnlock()
+
+	items := make([]Currency, len(s.Currencies))
+	for i, item := range s.Currencies {
+		inverted, err := invertCurrency(item, precision)
+		if err != nil {
+			return nil, err
+		}
+		items[i] = inverted
+	}
+
+	inverted := c.derive(items, s.TakenAt)
+	inverted.closes = closes
+	return inverted, nil
+}
+
+// invertCurrency returns the currency with reciprocal, swapped rates.
+func invertCurrency(c Currency, precision int32) (Currency, error) {
+	if !c.BuyRate.IsPositive() || !c.SellRate.IsPositive() {
+		return Currency{}, fmt.Errorf("%w: cannot invert %s buy %s sell %s", ErrInvalidRate, c.ISOCode, c.BuyRate, c.SellRate)
+	}
+	one := decimal.NewFromInt(1)
+	buy := Division{Precision: precision, Rounding: RoundCeiling}.Div(one, c.SellRate)
+	sell := Division{Precision: precision, Rounding: RoundFloor}.Div(one, c.BuyRate)
+	c.BuyRate, c.SellRate = buy, sell
+	return c, nil
+}
@@ -0,0 +1,54 @@
+package converter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurrencies_Invert(t *testing.T) {
+	at := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	table := NewTable(nil, WithDivision(Division{Precision: 6, Rounding: RoundHalfEven}))
+	table.Update(testCurrencies(), at)
+
+	inverted, err := table.Invert()
+	assert.NoError(t, err)
+	assert.Equal(t, at, inverted.UpdatedAt())
+
+	rates := map[string][2]string{}
+	for _, c := range inverted.List() {
+		rates[c.ISOCode] = [2]string{c.BuyRate.String(), c.SellRate.String()}
+	}
+	assert.Equal(t, map[string][2]string{
+		"EUR": {"1.052632", "1.111111"},
+		"NGN": {"0.000646", "0.000666"},
+		"USD": {"1", "1"},
+	}, rates)
+
+	// Inverting twice gives back rates within the rounding.
+	again, err := inverted.Invert()
+	assert.NoError(t, err)
+	eur, err := FindCurrency(again.List(), "EUR")
+	assert.NoError(t, err)
+	assert.Equal(t, "0.9", eur.BuyRate.StringFixed(1))
+	assert.Equal(t, "0.95", eur.SellRate.StringFixed(2))
+
+	// The original is untouched.
+	eur, err = FindCurrency(table.List(), "EUR")
+	assert.NoError(t, err)
+	assert.Equal(t, "0.9", eur.BuyRate.String())
+
+	zero := NewTable([]Currency{{ISOCode: "XXX", Precision: 2, BuyRate: decimal.Zero, SellRate: decimal.NewFromInt(1)}})
+	_, err = zero.Invert()
+	assert.ErrorIs(t, err, ErrInvalidRate)
+
+	ownBases := NewTable([]Currency{
+		{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+		{ISOCode: "GBP", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1), BaseISOCode: "USD"},
+		{ISOCode: "EUR", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1), BaseISOCode: "GBP"},
+	})
+	_, err = ownBases.Invert()
+	assert.ErrorContains(t, err, "carry their own bases")
+}
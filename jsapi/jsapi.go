@@ -0,0 +1,79 @@
+// Package jsapi exposes the converter's quoting path to JavaScript, so web
+// front-ends built with GOOS=js GOARCH=wasm validate amounts with the same
+// logic as the backend. Functions take and return strings (rates as the JSON
+// read by NewCurrenciesFromJSON, amounts as decimal strings) and return a
+// Result rather than an error, since errors do not cross into JavaScript.
+// Register, built only for js/wasm, installs them as a global object.
+package jsapi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/otyang/converter"
+	"github.com/otyang/converter/httpapi"
+	"github.com/shopspring/decimal"
+)
+
+// Result is the outcome of a call: Value on success, else Error describing
+// the failure with the same status and code the HTTP API uses.
+type Result struct {
+	Value any              `json:"value,omitempty"`
+	Error *httpapi.Problem `json:"error,omitempty"`
+}
+
+// result wraps value, or err if it is not nil.
+func result(value any, err error) Result {
+	if err != nil {
+		p := httpapi.NewProblem(err)
+		return Result{Error: &p}
+	}
+	return Result{Value: value}
+}
+
+// CalculateRate returns the rate from one currency to another as a decimal
+// string, see converter.CalculateRate.
+func CalculateRate(rates, base, from, to string) Result {
+	currencies, err := converter.NewCurrenciesFromJSON(strings.NewReader(rates))
+	if err != nil {
+		return result(nil, fmt.Errorf("rates: %w", err))
+	}
+	rate, err := converter.CalculateRate(currencies, base, from, to)
+	if err != nil {
+		return result(nil, err)
+	}
+	return result(rate.String(), nil)
+}
+
+// NewQuote prices a quote, see converter.NewQuote. feeMode is one of the
+// converter's fee modes, or empty for the default.
+func NewQuote(rates, base, from, to, amount, fee, feeMode string) Result {
+	currencies, err := converter.NewCurrenciesFromJSON(strings.NewReader(rates))
+	if err != nil {
+		return result(nil, fmt.Errorf("rates: %w", err))
+	}
+	fromAmount, err := parseAmount("amount", amount)
+	if err != nil {
+		return result(nil, err)
+	}
+	feeAmount := decimal.Zero
+	if fee != "" {
+		if feeAmount, err = parseAmount("fee", fee); err != nil {
+			return result(nil, err)
+		}
+	}
+
+	var opts []converter.QuoteOption
+	if feeMode != "" {
+		opts = append(opts, converter.WithFeeMode(converter.FeeMode(feeMode)))
+	}
+	return result(converter.NewQuote(currencies, base, from, to, fromAmount, feeAmount, opts...))
+}
+
+func parseAmount(name, s string) (decimal.Decimal, error) {
+	d, err := decimal.NewFromString(strings.TrimSpace(s))
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("%w: %s %q is not a decimal number", converter.ErrInvalidAmount, name, s)
+	}
+	return d, nil
+}
@@ -0,0 +1,58 @@
+package jsapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/otyang/converter"
+	"github.com/stretchr/testify/assert"
+)
+
+const rates = `[
+	{"isoCode": "USD", "precision": 2, "buyRate": "1", "sellRate": "1"},
+	{"isoCode": "NGN", "precision": 2, "buyRate": "1550", "sellRate": "1500"}
+]`
+
+func TestCalculateRate(t *testing.T) {
+	r := CalculateRate(rates, "USD", "USD", "NGN")
+	assert.Nil(t, r.Error)
+	assert.Equal(t, "1500", r.Value)
+
+	r = CalculateRate(rates, "USD", "USD", "GBP")
+	assert.Nil(t, r.Value)
+	assert.Equal(t, http.StatusNotFound, r.Error.Status)
+	assert.Equal(t, "currency_not_found", r.Error.Code)
+
+	r = CalculateRate(`not json`, "USD", "USD", "NGN")
+	assert.NotNil(t, r.Error)
+}
+
+func TestNewQuote(t *testing.T) {
+	r := NewQuote(rates, "USD", "USD", "NGN", "100", "2", "deducted")
+	assert.Nil(t, r.Error)
+	quote := r.Value.(*converter.Quote)
+	assert.Equal(t, converter.FeeDeducted, quote.FeeMode)
+	assert.Equal(t, "147000", quote.FinalAmount.String())
+
+	b, err := json.Marshal(r)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `{"value":{`)
+
+	r = NewQuote(rates, "USD", "USD", "NGN", "100", "", "")
+	assert.Nil(t, r.Error)
+
+	r = NewQuote(rates, "USD", "USD", "NGN", "abc", "", "")
+	assert.Equal(t, "invalid_amount", r.Error.Code)
+	assert.Equal(t, `invalid amount: amount "abc" is not a decimal number`, r.Error.Detail)
+
+	r = NewQuote(rates, "USD", "USD", "NGN", "100", "x", "")
+	assert.Equal(t, "invalid_amount", r.Error.Code)
+
+	r = NewQuote(rates, "USD", "USD", "NGN", "100", "0", "sideways")
+	assert.Equal(t, "invalid_fee_mode", r.Error.Code)
+
+	b, err = json.Marshal(r)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `{"error":{`)
+}
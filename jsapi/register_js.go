@@ -0,0 +1,53 @@
+//go:build js && wasm
+
+package jsapi
+
+import (
+	"encoding/json"
+	"strconv"
+	"syscall/js"
+)
+
+// Register installs the bindings as a global JavaScript object, e.g.
+// Register("converter") for:
+//
+//	converter.calculateRate(rates, "USD", "USD", "NGN")
+//	converter.newQuote(rates, "USD", "USD", "NGN", "100", "2", "")
+//
+// Each function returns an object with a value, or an error problem with a
+// status and code. Numbers are accepted for amounts; missing arguments are
+// empty strings.
+func Register(name string) {
+	js.Global().Set(name, js.ValueOf(map[string]any{
+		"calculateRate": binding(4, func(args []string) Result {
+			return CalculateRate(args[0], args[1], args[2], args[3])
+		}),
+		"newQuote": binding(7, func(args []string) Result {
+			return NewQuote(args[0], args[1], args[2], args[3], args[4], args[5], args[6])
+		}),
+	}))
+}
+
+// binding adapts a function of n string arguments to JavaScript.
+func binding(n int, f func(args []string) Result) js.Func {
+	return js.FuncOf(func(_ js.Value, values []js.Value) any {
+		args := make([]string, n)
+		for i := range args {
+			if i >= len(values) {
+				continue
+			}
+			switch values[i].Type() {
+			case js.TypeString:
+				args[i] = values[i].String()
+			case js.TypeNumber:
+				args[i] = strconv.FormatFloat(values[i].Float(), 'f', -1, 64)
+			}
+		}
+
+		b, err := json.Marshal(f(args))
+		if err != nil {
+			b, _ = json.Marshal(result(nil, err))
+		}
+		return js.Global().Get("JSON").Call("parse", string(b))
+	})
+}
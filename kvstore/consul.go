@@ -0,0 +1,175 @@
+package kvstore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/otyang/converter"
+)
+
+// DefaultConsulAddress is the address of the local Consul agent.
+const DefaultConsulAddress = "http://127.0.0.1:8500"
+
+// DefaultConsulWait is how long a Consul watch blocks for a change before
+// asking again.
+const DefaultConsulWait = 5 * time.Minute
+
+// maxValueSize bounds the values read. Consul's own limit is 512 KiB and
+// etcd's 1.5 MiB by default.
+const maxValueSize = 16 << 20
+
+// Consul stores blobs in Consul's KV store, watching them with blocking
+// queries.
+type Consul struct {
+	// Address is the agent's base URL; DefaultConsulAddress if empty.
+	Address string
+	// Token is the ACL token, if ACLs are enabled.
+	Token string
+	// Datacenter, if set, overrides the agent's datacenter.
+	Datacenter string
+	// Prefix is prepended to every key, e.g. "converter/prod/".
+	Prefix string
+	// Wait bounds each blocking query; DefaultConsulWait if zero.
+	Wait time.Duration
+	// Client sends requests; nil means http.DefaultClient.
+	Client *http.Client
+}
+
+// NewConsul creates a store in the Consul agent at address.
+func NewConsul(address string) *Consul {
+	return &Consul{Address: address}
+}
+
+// Put implements converter.BlobStore.
+func (c *Consul) Put(ctx context.Context, key string, data []byte) error {
+	resp, err := c.kv(ctx, http.MethodPut, key, nil, data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return nil
+}
+
+// Get implements converter.BlobStore.
+func (c *Consul) Get(ctx context.Context, key string) ([]byte, error) {
+	data, _, err := c.get(ctx, key, 0)
+	return data, err
+}
+
+// Watch implements converter.WatchableBlobStore with blocking queries,
+// sending the value each time Consul's modify index for the key moves.
+func (c *Consul) Watch(ctx context.Context, key string, blobs chan<- []byte) error {
+	var index uint64
+	for first := true; ; first = false {
+		data, next, err := c.get(ctx, key, index)
+		found := err == nil
+		if err != nil && !isNotFound(err) {
+			return err
+		}
+		if found && (first || next != index) {
+			select {
+			case blobs <- data:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		// Start over if the index went backwards, e.g. after a snapshot
+		// restore, and keep it at least 1 so the next query blocks, as
+		// Consul advises.
+		if next < index {
+			next = 0
+		}
+		index = max(next, 1)
+	}
+}
+
+// get reads key, blocking until its index passes index if positive, and
+// returns the value with the index of the response.
+func (c *Consul) get(ctx context.Context, key string, index uint64) ([]byte, uint64, error) {
+	query := url.Values{"raw": {""}}
+	if index > 0 {
+		wait := c.Wait
+		if wait <= 0 {
+			wait = DefaultConsulWait
+		}
+		query.Set("index", strconv.FormatUint(index, 10))
+		query.Set("wait", strconv.FormatInt(wait.Milliseconds(), 10)+"ms")
+	}
+
+	resp, err := c.kv(ctx, http.MethodGet, key, query, nil)
+	var next uint64
+	if resp != nil {
+		next, _ = strconv.ParseUint(resp.Header.Get("X-Consul-Index"), 10, 64)
+	}
+	if err != nil {
+		return nil, next, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxValueSize))
+	return data, next, err
+}
+
+// kv sends a request for key to the KV endpoint. A missing key is
+// converter.ErrBlobNotFound, returned with the response so its headers can
+// be read.
+func (c *Consul) kv(ctx context.Context, method, key string, query url.Values, body []byte) (*http.Response, error) {
+	resp, err := c.do(ctx, method, "/v1/kv/"+escapeKey(c.Prefix+key), query, body)
+	if isNotFound(err) {
+		return resp, fmt.Errorf("%w: consul key %s", converter.ErrBlobNotFound, c.Prefix+key)
+	}
+	return resp, err
+}
+
+// do sends a request to the agent. A 404 is converter.ErrBlobNotFound,
+// returned with the closed response.
+func (c *Consul) do(ctx context.Context, method, path string, query url.Values, body []byte) (*http.Response, error) {
+	address := c.Address
+	if address == "" {
+		address = DefaultConsulAddress
+	}
+	if query == nil {
+		query = url.Values{}
+	}
+	if c.Datacenter != "" {
+		query.Set("dc", c.Datacenter)
+	}
+	u := strings.TrimSuffix(address, "/") + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if c.Token != "" {
+		req.Header.Set("X-Consul-Token", c.Token)
+	}
+
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return resp, nil
+	case resp.StatusCode == http.StatusNotFound:
+		resp.Body.Close()
+		return resp, converter.ErrBlobNotFound
+	default:
+		defer resp.Body.Close()
+		return nil, responseError("consul", resp)
+	}
+}
@@ -0,0 +1,198 @@
+package kvstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/otyang/converter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeConsul serves Consul's KV API from memory, with blocking queries,
+// and sessions locking keys.
+type fakeConsul struct {
+	mu       sync.Mutex
+	index    uint64
+	values   map[string][]byte
+	changed  chan struct{}
+	sessions map[string]bool
+	locks    map[string]string
+}
+
+func newFakeConsul(t *testing.T) (*fakeConsul, *httptest.Server) {
+	f := &fakeConsul{
+		index:    1,
+		values:   map[string][]byte{},
+		changed:  make(chan struct{}),
+		sessions: map[string]bool{},
+		locks:    map[string]string{},
+	}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func (f *fakeConsul) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-Consul-Token") != "token" {
+		http.Error(w, "ACL not found", http.StatusForbidden)
+		return
+	}
+	if strings.HasPrefix(r.URL.Path, "/v1/session/") {
+		f.serveSession(w, r)
+		return
+	}
+	key := strings.TrimPrefix(r.URL.Path, "/v1/kv/")
+
+	f.mu.Lock()
+	if r.Method == http.MethodPut {
+		b, _ := io.ReadAll(r.Body)
+		ok := true
+		switch q := r.URL.Query(); {
+		case q.Has("acquire"):
+			holder := f.locks[key]
+			ok = f.sessions[q.Get("acquire")] && (holder == "" || holder == q.Get("acquire"))
+			if ok {
+				f.locks[key] = q.Get("acquire")
+			}
+		case q.Has("release"):
+			ok = f.locks[key] == q.Get("release")
+			if ok {
+				delete(f.locks, key)
+			}
+		}
+		if ok {
+			f.values[key] = b
+			f.bump()
+		}
+		f.mu.Unlock()
+		fmt.Fprint(w, ok)
+		return
+	}
+
+	wait, _ := strconv.ParseUint(r.URL.Query().Get("index"), 10, 64)
+	for wait > 0 && f.index <= wait {
+		changed := f.changed
+		f.mu.Unlock()
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+		f.mu.Lock()
+	}
+	value, ok := f.values[key]
+	w.Header().Set("X-Consul-Index", strconv.FormatUint(f.index, 10))
+	f.mu.Unlock()
+
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Write(value)
+}
+
+// bump moves the index and wakes blocking queries. f.mu must be held.
+func (f *fakeConsul) bump() {
+	f.index++
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+func (f *fakeConsul) serveSession(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	op, id, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/v1/session/"), "/")
+	switch op {
+	case "create":
+		id = "session-" + strconv.Itoa(len(f.sessions)+1)
+		f.sessions[id] = true
+		fmt.Fprintf(w, `{"ID":%q}`, id)
+	case "renew":
+		if !f.sessions[id] {
+			http.Error(w, "Session id '"+id+"' not found", http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, `[{"ID":%q}]`, id)
+	case "destroy":
+		f.sessions[id] = false
+		for key, holder := range f.locks {
+			if holder == id {
+				delete(f.locks, key)
+				f.bump()
+			}
+		}
+		fmt.Fprint(w, true)
+	}
+}
+
+func TestConsul(t *testing.T) {
+	ctx := context.Background()
+	_, srv := newFakeConsul(t)
+	c := NewConsul(srv.URL)
+	c.Token = "token"
+	c.Prefix = "converter/"
+
+	_, err := c.Get(ctx, "rates")
+	assert.ErrorIs(t, err, converter.ErrBlobNotFound)
+
+	require.NoError(t, c.Put(ctx, "rates", []byte("v1")))
+	b, err := c.Get(ctx, "rates")
+	require.NoError(t, err)
+	assert.Equal(t, "v1", string(b))
+
+	c.Token = "wrong"
+	err = c.Put(ctx, "rates", []byte("v2"))
+	var e *Error
+	require.ErrorAs(t, err, &e)
+	assert.Equal(t, http.StatusForbidden, e.StatusCode)
+	assert.EqualError(t, err, "consul: 403 Forbidden: ACL not found")
+}
+
+func TestConsul_Watch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, srv := newFakeConsul(t)
+	c := NewConsul(srv.URL)
+	c.Token = "token"
+
+	blobs := make(chan []byte)
+	done := make(chan error)
+	go func() { done <- c.Watch(ctx, "rates", blobs) }()
+
+	// A missing key is watched until it is created
+	require.NoError(t, c.Put(ctx, "other", []byte("x")))
+	require.NoError(t, c.Put(ctx, "rates", []byte("v1")))
+	assert.Equal(t, "v1", string(<-blobs))
+	require.NoError(t, c.Put(ctx, "rates", []byte("v2")))
+	assert.Equal(t, "v2", string(<-blobs))
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestConsul_SharedTable(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, srv := newFakeConsul(t)
+	c := NewConsul(srv.URL)
+	c.Token = "token"
+
+	leader := converter.NewSharedTable(converter.NewTable(nil), c, "rates")
+	leader.Table.Update(testCurrencies(), time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, leader.Publish(ctx))
+
+	follower := converter.NewSharedTable(converter.NewTable(nil), c, "rates")
+	go follower.Run(ctx)
+	require.Eventually(t, func() bool { return follower.Table.Len() == 2 }, time.Second, time.Millisecond)
+	assert.Equal(t, leader.Table.List(), follower.Table.List())
+}
@@ -0,0 +1,236 @@
+package kvstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// DefaultLeaderTTL is how long leadership outlives an instance that stopped
+// renewing it.
+const DefaultLeaderTTL = 15 * time.Second
+
+// releaseTimeout bounds giving leadership up once its context is done.
+const releaseTimeout = 5 * time.Second
+
+// hold returns a context for leadership just won. keepalive runs every
+// interval; once it fails or ctx is done the context is cancelled and
+// release gives leadership up.
+func hold(ctx context.Context, interval time.Duration, keepalive func(context.Context) error, release func(context.Context)) context.Context {
+	leadership, cancel := context.WithCancel(ctx)
+	go func() {
+		defer func() {
+			cancel()
+			releaseCtx, cancelRelease := context.WithTimeout(context.Background(), releaseTimeout)
+			defer cancelRelease()
+			release(releaseCtx)
+		}()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-leadership.Done():
+				return
+			case <-ticker.C:
+				if err := keepalive(leadership); err != nil {
+					return
+				}
+			}
+		}
+	}()
+	return leadership
+}
+
+// ConsulElector elects a leader with a Consul lock: a key acquired by a
+// session whose TTL the leader renews. It is a converter.Elector.
+type ConsulElector struct {
+	Consul *Consul
+	Key    string
+	// Value is stored in the lock key while held, e.g. the leader's host
+	// name.
+	Value []byte
+	// TTL is the session TTL, at least Consul's minimum of 10s;
+	// DefaultLeaderTTL if zero.
+	TTL time.Duration
+}
+
+// NewConsulElector creates an elector locking key in c.
+func NewConsulElector(c *Consul, key string, value []byte) *ConsulElector {
+	return &ConsulElector{Consul: c, Key: key, Value: value}
+}
+
+// Campaign implements converter.Elector. While another instance holds the
+// lock, it waits for the lock key to change with blocking queries.
+func (e *ConsulElector) Campaign(ctx context.Context) (context.Context, error) {
+	ttl := e.TTL
+	if ttl <= 0 {
+		ttl = DefaultLeaderTTL
+	}
+
+	for {
+		// The index is read before acquiring, so a release in between
+		// still wakes the wait below.
+		_, index, err := e.Consul.get(ctx, e.Key, 0)
+		if err != nil && !isNotFound(err) {
+			return nil, err
+		}
+
+		session, err := e.createSession(ctx, ttl)
+		if err != nil {
+			return nil, err
+		}
+		acquired, err := e.lock(ctx, "acquire", session)
+		if err != nil || !acquired {
+			e.destroySession(context.WithoutCancel(ctx), session)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if acquired {
+			renew := func(ctx context.Context) error {
+				resp, err := e.Consul.do(ctx, http.MethodPut, "/v1/session/renew/"+session, nil, nil)
+				if err != nil {
+					return err
+				}
+				resp.Body.Close()
+				return nil
+			}
+			release := func(ctx context.Context) {
+				_, _ = e.lock(ctx, "release", session)
+				e.destroySession(ctx, session)
+			}
+			return hold(ctx, ttl/2, renew, release), nil
+		}
+
+		if _, _, err := e.Consul.get(ctx, e.Key, max(index, 1)); err != nil && !isNotFound(err) {
+			return nil, err
+		}
+	}
+}
+
+func (e *ConsulElector) createSession(ctx context.Context, ttl time.Duration) (string, error) {
+	body := fmt.Sprintf(`{"Name":%q,"TTL":"%ds"}`, "converter leader "+e.Key, int(ttl.Seconds()))
+	resp, err := e.Consul.do(ctx, http.MethodPut, "/v1/session/create", nil, []byte(body))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var session struct{ ID string }
+	if err := decodeJSON(resp, &session); err != nil {
+		return "", err
+	}
+	if session.ID == "" {
+		return "", errors.New("consul: session create returned no ID")
+	}
+	return session.ID, nil
+}
+
+func (e *ConsulElector) destroySession(ctx context.Context, session string) {
+	resp, err := e.Consul.do(ctx, http.MethodPut, "/v1/session/destroy/"+session, nil, nil)
+	if err == nil {
+		resp.Body.Close()
+	}
+}
+
+// lock acquires or releases the lock key with session, reporting whether
+// Consul did so.
+func (e *ConsulElector) lock(ctx context.Context, op, session string) (bool, error) {
+	resp, err := e.Consul.kv(ctx, http.MethodPut, e.Key, url.Values{op: {session}}, e.Value)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	var ok bool
+	err = decodeJSON(resp, &ok)
+	return ok, err
+}
+
+// EtcdElector elects a leader by creating a key attached to a lease that
+// the leader keeps alive. It is a converter.Elector.
+type EtcdElector struct {
+	Etcd *Etcd
+	Key  string
+	// Value is stored in the key while leading, e.g. the leader's host
+	// name.
+	Value []byte
+	// TTL is the lease TTL; DefaultLeaderTTL if zero.
+	TTL time.Duration
+}
+
+// NewEtcdElector creates an elector creating key in e.
+func NewEtcdElector(e *Etcd, key string, value []byte) *EtcdElector {
+	return &EtcdElector{Etcd: e, Key: key, Value: value}
+}
+
+// Campaign implements converter.Elector. While another instance leads, it
+// tries again every third of the TTL.
+func (e *EtcdElector) Campaign(ctx context.Context) (context.Context, error) {
+	ttl := e.TTL
+	if ttl <= 0 {
+		ttl = DefaultLeaderTTL
+	}
+
+	for {
+		var lease struct {
+			ID int64 `json:"ID,string"`
+		}
+		if err := e.Etcd.call(ctx, "/v3/lease/grant", map[string]string{"TTL": strconv.Itoa(int(ttl.Seconds()))}, &lease); err != nil {
+			return nil, err
+		}
+		revoke := func(ctx context.Context) {
+			var out struct{}
+			_ = e.Etcd.call(ctx, "/v3/lease/revoke", map[string]string{"ID": strconv.FormatInt(lease.ID, 10)}, &out)
+		}
+
+		key := []byte(e.Etcd.Prefix + e.Key)
+		var txn struct {
+			Succeeded bool `json:"succeeded"`
+		}
+		err := e.Etcd.call(ctx, "/v3/kv/txn", map[string]any{
+			"compare": []any{map[string]any{"key": key, "result": "EQUAL", "target": "CREATE", "create_revision": "0"}},
+			"success": []any{map[string]any{"request_put": map[string]any{"key": key, "value": e.Value, "lease": strconv.FormatInt(lease.ID, 10)}}},
+		}, &txn)
+		if err != nil || !txn.Succeeded {
+			revoke(context.WithoutCancel(ctx))
+		}
+		if err != nil {
+			return nil, err
+		}
+		if txn.Succeeded {
+			keepalive := func(ctx context.Context) error {
+				var out struct {
+					Result struct {
+						TTL int64 `json:"TTL,string"`
+					} `json:"result"`
+				}
+				if err := e.Etcd.call(ctx, "/v3/lease/keepalive", map[string]string{"ID": strconv.FormatInt(lease.ID, 10)}, &out); err != nil {
+					return err
+				}
+				if out.Result.TTL <= 0 {
+					return errors.New("etcd: leader lease expired")
+				}
+				return nil
+			}
+			return hold(ctx, ttl/3, keepalive, revoke), nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(ttl / 3):
+		}
+	}
+}
+
+func decodeJSON(resp *http.Response, out any) error {
+	return json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(out)
+}
@@ -0,0 +1,86 @@
+package kvstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/otyang/converter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestElectors(t *testing.T) {
+	for name, newElector := range map[string]func(t *testing.T) (a, b converter.Elector, expire func()){
+		"consul": func(t *testing.T) (a, b converter.Elector, expire func()) {
+			fake, srv := newFakeConsul(t)
+			c := NewConsul(srv.URL)
+			c.Token = "token"
+			a = &ConsulElector{Consul: c, Key: "leader", Value: []byte("a"), TTL: 20 * time.Millisecond}
+			b = &ConsulElector{Consul: c, Key: "leader", Value: []byte("b"), TTL: 20 * time.Millisecond}
+			expire = func() {
+				fake.mu.Lock()
+				defer fake.mu.Unlock()
+				for id := range fake.sessions {
+					fake.sessions[id] = false
+				}
+			}
+			return a, b, expire
+		},
+		"etcd": func(t *testing.T) (a, b converter.Elector, expire func()) {
+			fake, srv := newFakeEtcd(t)
+			e := NewEtcd(srv.URL)
+			e.Username, e.Password = "converter", "secret"
+			a = &EtcdElector{Etcd: e, Key: "leader", Value: []byte("a"), TTL: 30 * time.Millisecond}
+			b = &EtcdElector{Etcd: e, Key: "leader", Value: []byte("b"), TTL: 30 * time.Millisecond}
+			expire = func() {
+				fake.mu.Lock()
+				defer fake.mu.Unlock()
+				for id := range fake.leases {
+					fake.revoke(id)
+				}
+			}
+			return a, b, expire
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			a, b, expire := newElector(t)
+
+			aCtx, aStop := context.WithCancel(ctx)
+			aLeads, err := a.Campaign(aCtx)
+			require.NoError(t, err)
+
+			// b waits while a leads
+			bLeads := make(chan context.Context)
+			go func() {
+				leadership, err := b.Campaign(ctx)
+				assert.NoError(t, err)
+				bLeads <- leadership
+			}()
+			select {
+			case <-bLeads:
+				t.Fatal("b elected while a leads")
+			case <-time.After(50 * time.Millisecond):
+			}
+			assert.NoError(t, aLeads.Err())
+
+			// a gives leadership up
+			aStop()
+			var leadership context.Context
+			select {
+			case leadership = <-bLeads:
+			case <-time.After(time.Second):
+				t.Fatal("b not elected after a gave up")
+			}
+
+			// Leadership is lost once it can no longer be renewed
+			expire()
+			select {
+			case <-leadership.Done():
+			case <-time.After(time.Second):
+				t.Fatal("b still leads after its lease expired")
+			}
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package kvstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/otyang/converter"
+)
+
+// DefaultEtcdEndpoint is the client URL of a local etcd member.
+const DefaultEtcdEndpoint = "http://127.0.0.1:2379"
+
+// ErrWatchClosed is returned when a watch stream ends while its context
+// is still live.
+var ErrWatchClosed = errors.New("kvstore: watch closed")
+
+// Etcd stores blobs in etcd through its v3 JSON gateway, watching them
+// with a watch stream.
+type Etcd struct {
+	// Endpoint is a member's client URL; DefaultEtcdEndpoint if empty.
+	Endpoint string
+	// Username and Password authenticate, if auth is enabled.
+	Username string
+	Password string
+	// Prefix is prepended to every key, e.g. "converter/prod/".
+	Prefix string
+	// Client sends requests; nil means http.DefaultClient.
+	Client *http.Client
+
+	mu    sync.Mutex
+	token string
+}
+
+// NewEtcd creates a store in the etcd member at endpoint.
+func NewEtcd(endpoint string) *Etcd {
+	return &Etcd{Endpoint: endpoint}
+}
+
+// etcdHeader is the response header of the gateway. int64 fields are
+// encoded as strings.
+type etcdHeader struct {
+	Revision int64 `json:"revision,string"`
+}
+
+type etcdKV struct {
+	Key   []byte `json:"key"`
+	Value []byte `json:"value"`
+}
+
+type etcdRangeResponse struct {
+	Header etcdHeader `json:"header"`
+	KVs    []etcdKV   `json:"kvs"`
+}
+
+type etcdWatchResponse struct {
+	Result struct {
+		Canceled        bool   `json:"canceled"`
+		CancelReason    string `json:"cancel_reason"`
+		CompactRevision int64  `json:"compact_revision,string"`
+		Events          []struct {
+			Type string `json:"type"`
+			KV   etcdKV `json:"kv"`
+		} `json:"events"`
+	} `json:"result"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Put implements converter.BlobStore.
+func (e *Etcd) Put(ctx context.Context, key string, data []byte) error {
+	resp, err := e.post(ctx, "/v3/kv/put", map[string]any{"key": []byte(e.Prefix + key), "value": data})
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Get implements converter.BlobStore.
+func (e *Etcd) Get(ctx context.Context, key string) ([]byte, error) {
+	r, err := e.get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if len(r.KVs) == 0 {
+		return nil, fmt.Errorf("%w: etcd key %s", converter.ErrBlobNotFound, e.Prefix+key)
+	}
+	return r.KVs[0].Value, nil
+}
+
+// Watch implements converter.WatchableBlobStore. It reads the key, then
+// watches it from the next revision, so no change is missed in between.
+// Deletions are not sent.
+func (e *Etcd) Watch(ctx context.Context, key string, blobs chan<- []byte) error {
+	r, err := e.get(ctx, key)
+	if err != nil {
+		return err
+	}
+	if len(r.KVs) > 0 {
+		select {
+		case blobs <- r.KVs[0].Value:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	resp, err := e.post(ctx, "/v3/watch", map[string]any{
+		"create_request": map[string]any{
+			"key":            []byte(e.Prefix + key),
+			"start_revision": fmt.Sprint(r.Header.Revision + 1),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var w etcdWatchResponse
+		if err := dec.Decode(&w); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if errors.Is(err, io.EOF) {
+				return ErrWatchClosed
+			}
+			return err
+		}
+		if w.Error != nil {
+			return &Error{Store: "etcd", StatusCode: http.StatusOK, Message: w.Error.Message}
+		}
+		if w.Result.Canceled {
+			reason := w.Result.CancelReason
+			if w.Result.CompactRevision > 0 {
+				reason = fmt.Sprintf("revision compacted to %d", w.Result.CompactRevision)
+			}
+			return fmt.Errorf("%w: %s", ErrWatchClosed, reason)
+		}
+		for _, ev := range w.Result.Events {
+			if ev.Type == "DELETE" {
+				continue
+			}
+			select {
+			case blobs <- ev.KV.Value:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+func (e *Etcd) get(ctx context.Context, key string) (*etcdRangeResponse, error) {
+	var r etcdRangeResponse
+	if err := e.call(ctx, "/v3/kv/range", map[string]any{"key": []byte(e.Prefix + key)}, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// call posts a JSON request to the gateway and decodes its response into
+// out.
+func (e *Etcd) call(ctx context.Context, path string, body, out any) error {
+	resp, err := e.post(ctx, path, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(io.LimitReader(resp.Body, maxValueSize)).Decode(out)
+}
+
+// post sends a JSON request to the gateway, authenticated if Username is
+// set. A rejected token is dropped, so the next request authenticates
+// again.
+func (e *Etcd) post(ctx context.Context, path string, body any) (*http.Response, error) {
+	token, err := e.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := e.send(ctx, path, body, token)
+	if err != nil {
+		var apiErr *Error
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized {
+			e.mu.Lock()
+			e.token = ""
+			e.mu.Unlock()
+		}
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (e *Etcd) authenticate(ctx context.Context) (string, error) {
+	if e.Username == "" {
+		return "", nil
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.token != "" {
+		return e.token, nil
+	}
+
+	resp, err := e.send(ctx, "/v3/auth/authenticate", map[string]string{"name": e.Username, "password": e.Password}, "")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	var auth struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&auth); err != nil {
+		return "", err
+	}
+	e.token = auth.Token
+	return e.token, nil
+}
+
+func (e *Etcd) send(ctx context.Context, path string, body any, token string) (*http.Response, error) {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := e.Endpoint
+	if endpoint == "" {
+		endpoint = DefaultEtcdEndpoint
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(endpoint, "/")+path, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", token)
+	}
+
+	client := e.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, responseError("etcd", resp)
+	}
+	return resp, nil
+}
@@ -0,0 +1,212 @@
+package kvstore
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/otyang/converter"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testCurrencies() []converter.Currency {
+	return []converter.Currency{
+		{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+		{ISOCode: "NGN", Precision: 2, BuyRate: decimal.NewFromInt(1500), SellRate: decimal.NewFromInt(1550)},
+	}
+}
+
+// fakeEtcd serves etcd's v3 JSON gateway from memory.
+type fakeEtcd struct {
+	mu       sync.Mutex
+	revision int64
+	values   map[string][]byte
+	// history holds every put, by revision, for watches.
+	history []etcdKV
+	changed chan struct{}
+	// leases maps live lease IDs to the keys attached to them.
+	leases    map[int64][]string
+	nextLease int64
+}
+
+func newFakeEtcd(t *testing.T) (*fakeEtcd, *httptest.Server) {
+	f := &fakeEtcd{revision: 1, values: map[string][]byte{}, changed: make(chan struct{}), leases: map[int64][]string{}}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func (f *fakeEtcd) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Key           []byte `json:"key"`
+		Value         []byte `json:"value"`
+		Name          string `json:"name"`
+		Password      string `json:"password"`
+		CreateRequest struct {
+			Key           []byte `json:"key"`
+			StartRevision int64  `json:"start_revision,string"`
+		} `json:"create_request"`
+		ID      int64 `json:"ID,string"`
+		Compare []struct {
+			Key []byte `json:"key"`
+		} `json:"compare"`
+		Success []struct {
+			RequestPut struct {
+				Key   []byte `json:"key"`
+				Value []byte `json:"value"`
+				Lease int64  `json:"lease,string"`
+			} `json:"request_put"`
+		} `json:"success"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if r.URL.Path == "/v3/auth/authenticate" {
+		if req.Name != "converter" || req.Password != "secret" {
+			http.Error(w, `{"message":"authentication failed, invalid user ID or password"}`, http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"token": "token"})
+		return
+	}
+	if r.Header.Get("Authorization") != "token" {
+		http.Error(w, `{"message":"etcdserver: invalid auth token"}`, http.StatusUnauthorized)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	header := map[string]string{"revision": strconv.FormatInt(f.revision, 10)}
+	switch r.URL.Path {
+	case "/v3/kv/put":
+		f.put(req.Key, req.Value)
+		json.NewEncoder(w).Encode(map[string]any{"header": header})
+	case "/v3/kv/txn":
+		// Only the election's transaction: put if the key does not exist.
+		_, exists := f.values[string(req.Compare[0].Key)]
+		put := req.Success[0].RequestPut
+		if !exists {
+			f.put(put.Key, put.Value)
+			f.leases[put.Lease] = append(f.leases[put.Lease], string(put.Key))
+		}
+		json.NewEncoder(w).Encode(map[string]any{"header": header, "succeeded": !exists})
+	case "/v3/lease/grant":
+		f.nextLease++
+		f.leases[f.nextLease] = nil
+		json.NewEncoder(w).Encode(map[string]string{"ID": strconv.FormatInt(f.nextLease, 10), "TTL": "15"})
+	case "/v3/lease/keepalive":
+		result := map[string]string{"ID": strconv.FormatInt(req.ID, 10)}
+		if _, ok := f.leases[req.ID]; ok {
+			result["TTL"] = "15"
+		}
+		json.NewEncoder(w).Encode(map[string]any{"result": result})
+	case "/v3/lease/revoke":
+		f.revoke(req.ID)
+		json.NewEncoder(w).Encode(map[string]any{"header": header})
+	case "/v3/kv/range":
+		resp := map[string]any{"header": header}
+		if v, ok := f.values[string(req.Key)]; ok {
+			resp["kvs"] = []etcdKV{{Key: req.Key, Value: v}}
+		}
+		json.NewEncoder(w).Encode(resp)
+	case "/v3/watch":
+		key := string(req.CreateRequest.Key)
+		// Revision 1 is the empty store; put n has revision n+1.
+		next := int(req.CreateRequest.StartRevision) - 2
+		json.NewEncoder(w).Encode(map[string]any{"result": map[string]any{"header": header, "created": true}})
+		w.(http.Flusher).Flush()
+		for {
+			for ; next < len(f.history); next++ {
+				if kv := f.history[next]; string(kv.Key) == key {
+					json.NewEncoder(w).Encode(map[string]any{"result": map[string]any{
+						"header": header,
+						"events": []any{map[string]any{"kv": kv}},
+					}})
+					w.(http.Flusher).Flush()
+				}
+			}
+			changed := f.changed
+			f.mu.Unlock()
+			select {
+			case <-changed:
+				f.mu.Lock()
+			case <-r.Context().Done():
+				f.mu.Lock()
+				return
+			}
+		}
+	}
+}
+
+// put stores a value and wakes watches. f.mu must be held.
+func (f *fakeEtcd) put(key, value []byte) {
+	f.revision++
+	f.values[string(key)] = value
+	f.history = append(f.history, etcdKV{Key: key, Value: value})
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+// revoke drops a lease and deletes its keys. f.mu must be held.
+func (f *fakeEtcd) revoke(id int64) {
+	for _, key := range f.leases[id] {
+		delete(f.values, key)
+	}
+	delete(f.leases, id)
+}
+
+func TestEtcd(t *testing.T) {
+	ctx := context.Background()
+	_, srv := newFakeEtcd(t)
+	e := NewEtcd(srv.URL)
+	e.Username, e.Password = "converter", "secret"
+	e.Prefix = "converter/"
+
+	_, err := e.Get(ctx, "rates")
+	assert.ErrorIs(t, err, converter.ErrBlobNotFound)
+
+	require.NoError(t, e.Put(ctx, "rates", []byte("v1")))
+	b, err := e.Get(ctx, "rates")
+	require.NoError(t, err)
+	assert.Equal(t, "v1", string(b))
+
+	// A rejected token is dropped and the next request authenticates again
+	e.token = "expired"
+	var apiErr *Error
+	require.ErrorAs(t, e.Put(ctx, "rates", []byte("v2")), &apiErr)
+	assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+	require.NoError(t, e.Put(ctx, "rates", []byte("v2")))
+
+	wrong := NewEtcd(srv.URL)
+	wrong.Username, wrong.Password = "converter", "wrong"
+	assert.Error(t, wrong.Put(ctx, "rates", []byte("v3")))
+}
+
+func TestEtcd_Watch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, srv := newFakeEtcd(t)
+	e := NewEtcd(srv.URL)
+	e.Username, e.Password = "converter", "secret"
+	require.NoError(t, e.Put(ctx, "rates", []byte("v1")))
+
+	blobs := make(chan []byte)
+	done := make(chan error)
+	go func() { done <- e.Watch(ctx, "rates", blobs) }()
+
+	// The current value, then every change after it
+	assert.Equal(t, "v1", string(<-blobs))
+	require.NoError(t, e.Put(ctx, "other", []byte("x")))
+	require.NoError(t, e.Put(ctx, "rates", []byte("v2")))
+	assert.Equal(t, "v2", string(<-blobs))
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
@@ -0,0 +1,53 @@
+// Package kvstore keeps blobs in distributed key-value stores, Consul and
+// etcd, over their HTTP APIs. Both are converter.WatchableBlobStores, so a
+// fleet of instances can share one authoritative rate table through a
+// converter.SharedTable, and both elect the one instance refreshing it with
+// a converter.Elector: ConsulElector and EtcdElector.
+package kvstore
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/otyang/converter"
+)
+
+// Error is an error response of a key-value store.
+type Error struct {
+	// Store is "consul" or "etcd".
+	Store      string
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("%s: %d %s", e.Store, e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// responseError reads an error response of store.
+func responseError(store string, resp *http.Response) error {
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	return &Error{Store: store, StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(b))}
+}
+
+func isNotFound(err error) bool {
+	return errors.Is(err, converter.ErrBlobNotFound)
+}
+
+// escapeKey escapes each segment of a key for a URL path, keeping the
+// slashes of hierarchical keys.
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
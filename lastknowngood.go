@@ -0,0 +1,156 @@
+package converter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// LastKnownGood keeps a table's last validated contents in a SnapshotStore:
+// it persists the table after every update that validates and restores it
+// at startup with its original timestamp, so a restart never regresses to
+// an empty table, however the table is updated.
+type LastKnownGood struct {
+	Table *Currencies
+	Store SnapshotStore
+	// Verify, if set, gates persistence and restoring on top of each
+	// currency's Validate. Invariants.Check fits here.
+	Verify func(currencies []Currency) error
+	// OnError, if set, receives the errors of persisting updates.
+	OnError func(err error)
+}
+
+// NewLastKnownGood creates a last-known-good keeper of table in store.
+func NewLastKnownGood(table *Currencies, store SnapshotStore) *LastKnownGood {
+	return &LastKnownGood{Table: table, Store: store}
+}
+
+// Restore loads the persisted table into the table if it is still empty.
+// It returns ErrSnapshotNotFound if nothing has been persisted yet, and the
+// validation error if the persisted table does not validate.
+func (l *LastKnownGood) Restore(ctx context.Context) error {
+	if l.Table.Len() > 0 {
+		return nil
+	}
+	snapshot, err := loadValidSnapshot(ctx, l.Store)
+	if err != nil {
+		return err
+	}
+	if l.Verify != nil {
+		if err := l.Verify(snapshot.Currencies); err != nil {
+			return err
+		}
+	}
+	if l.Table.Len() == 0 {
+		l.Table.Restore(snapshot)
+	}
+	return nil
+}
+
+// Save persists the table's contents if they validate. Degraded tables,
+// holding fallback rates, and empty ones are not persisted.
+func (l *LastKnownGood) Save(ctx context.Context) error {
+	s := l.Table.Snapshot()
+	if s.Degraded || len(s.Currencies) == 0 {
+		return nil
+	}
+	return l.save(ctx, s)
+}
+
+func (l *LastKnownGood) save(ctx context.Context, s *Snapshot) error {
+	for _, c := range s.Currencies {
+		if err := c.Validate(); err != nil {
+			return err
+		}
+	}
+	if l.Verify != nil {
+		if err := l.Verify(s.Currencies); err != nil {
+			return err
+		}
+	}
+	return l.Store.Save(ctx, s)
+}
+
+// Run restores the table, then persists every update of it until ctx is
+// cancelled. A missing or invalid persisted table only means the table
+// starts empty; errors persisting updates go to OnError.
+func (l *LastKnownGood) Run(ctx context.Context) error {
+	if err := l.Restore(ctx); err != nil && !errors.Is(err, ErrSnapshotNotFound) {
+		l.report(fmt.Errorf("restore last known good: %w", err))
+	}
+
+	for s := range l.Table.Subscribe(ctx) {
+		// The snapshot's own mark, not the table's, which may have moved
+		// on since.
+		if s.Degraded {
+			continue
+		}
+		if err := l.save(ctx, s); err != nil && ctx.Err() == nil {
+			l.report(fmt.Errorf("save last known good: %w", err))
+		}
+	}
+	return nil
+}
+
+func (l *LastKnownGood) report(err error) {
+	if l.OnError != nil {
+		l.OnError(err)
+	}
+}
+
+// loadValidSnapshot loads the snapshot in store and validates its
+// currencies. An empty snapshot counts as ErrSnapshotNotFound.
+func loadValidSnapshot(ctx context.Context, store SnapshotStore) (*Snapshot, error) {
+	snapshot, err := store.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshot.Currencies) == 0 {
+		return nil, ErrSnapshotNotFound
+	}
+	for _, c := range snapshot.Currencies {
+		if err := c.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return snapshot, nil
+}
+
+// BlobSnapshotStore stores a snapshot as JSON in a BlobStore under Key, so
+// any blob backend can hold a last-known-good table.
+type BlobSnapshotStore struct {
+	Blobs BlobStore
+	Key   string
+}
+
+// NewBlobSnapshotStore creates a snapshot store writing to key in blobs.
+func NewBlobSnapshotStore(blobs BlobStore, key string) *BlobSnapshotStore {
+	return &BlobSnapshotStore{Blobs: blobs, Key: key}
+}
+
+// Save implements SnapshotStore.
+func (s *BlobSnapshotStore) Save(ctx context.Context, snapshot *Snapshot) error {
+	b, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	return s.Blobs.Put(ctx, s.Key, b)
+}
+
+// Load implements SnapshotStore.
+func (s *BlobSnapshotStore) Load(ctx context.Context) (*Snapshot, error) {
+	b, err := s.Blobs.Get(ctx, s.Key)
+	if errors.Is(err, ErrBlobNotFound) {
+		return nil, ErrSnapshotNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(b, &snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
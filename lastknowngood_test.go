@@ -0,0 +1,127 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLastKnownGood_SaveRestore(t *testing.T) {
+	ctx := context.Background()
+	takenAt := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	store := NewBlobSnapshotStore(NewMemoryBlobStore(), "rates/last-known-good.json")
+
+	// Nothing persisted yet
+	restarted := NewLastKnownGood(NewTable(nil), store)
+	assert.ErrorIs(t, restarted.Restore(ctx), ErrSnapshotNotFound)
+
+	// Empty and degraded tables are not persisted
+	table := NewTable(nil)
+	l := NewLastKnownGood(table, store)
+	assert.NoError(t, l.Save(ctx))
+	table.UpdateFallback(testCurrencies(), takenAt)
+	assert.NoError(t, l.Save(ctx))
+	assert.ErrorIs(t, restarted.Restore(ctx), ErrSnapshotNotFound)
+
+	table.Update(testCurrencies(), takenAt)
+	assert.NoError(t, l.Save(ctx))
+	require.NoError(t, restarted.Restore(ctx))
+	assert.Equal(t, 3, restarted.Table.Len())
+	assert.Equal(t, takenAt, restarted.Table.UpdatedAt())
+	assert.False(t, restarted.Table.Degraded())
+
+	// A table that already has rates is left alone
+	partial := NewLastKnownGood(NewTable(testCurrencies()[:1]), store)
+	assert.NoError(t, partial.Restore(ctx))
+	assert.Equal(t, 1, partial.Table.Len())
+}
+
+func TestLastKnownGood_Verify(t *testing.T) {
+	ctx := context.Background()
+	store := NewBlobSnapshotStore(NewMemoryBlobStore(), "rates.json")
+	errTooFew := errors.New("too few currencies")
+	verify := func(currencies []Currency) error {
+		if len(currencies) < 2 {
+			return errTooFew
+		}
+		return nil
+	}
+
+	// Invalid rates are not persisted
+	invalid := testCurrencies()
+	invalid[1].SellRate = decimal.NewFromInt(-1)
+	l := NewLastKnownGood(NewTable(invalid), store)
+	assert.ErrorIs(t, l.Save(ctx), ErrInvalidRate)
+
+	l = NewLastKnownGood(NewTable(testCurrencies()[:1]), store)
+	l.Verify = verify
+	assert.ErrorIs(t, l.Save(ctx), errTooFew)
+
+	// Nor restored, if persisted by another writer
+	assert.NoError(t, store.Save(ctx, NewSnapshot(testCurrencies()[:1], time.Now())))
+	restarted := NewLastKnownGood(NewTable(nil), store)
+	restarted.Verify = verify
+	assert.ErrorIs(t, restarted.Restore(ctx), errTooFew)
+	assert.Zero(t, restarted.Table.Len())
+}
+
+func TestLastKnownGood_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	takenAt := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	store := NewBlobSnapshotStore(NewMemoryBlobStore(), "rates.json")
+	require.NoError(t, store.Save(ctx, NewSnapshot(testCurrencies()[:2], takenAt)))
+
+	table := NewTable(nil)
+	l := NewLastKnownGood(table, store)
+	done := make(chan error)
+	go func() { done <- l.Run(ctx) }()
+
+	// The persisted table is restored at startup
+	require.Eventually(t, func() bool { return table.Len() == 2 }, time.Second, time.Millisecond)
+	assert.Equal(t, takenAt, table.UpdatedAt())
+
+	// Updates are persisted as they happen
+	updatedAt := takenAt.Add(time.Minute)
+	table.Update(testCurrencies(), updatedAt)
+	require.Eventually(t, func() bool {
+		s, err := store.Load(ctx)
+		return err == nil && s.TakenAt.Equal(updatedAt)
+	}, time.Second, time.Millisecond)
+	s, err := store.Load(ctx)
+	require.NoError(t, err)
+	assert.Len(t, s.Currencies, 3)
+
+	// Fallback rates are skipped by the mark on their snapshot
+	table.UpdateFallback(testCurrencies()[:1], time.Time{})
+	recoveredAt := updatedAt.Add(time.Minute)
+	table.Update(testCurrencies()[:2], recoveredAt)
+	require.Eventually(t, func() bool {
+		s, err := store.Load(ctx)
+		return err == nil && s.TakenAt.Equal(recoveredAt)
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestLastKnownGood_SaveSkipsDegraded(t *testing.T) {
+	ctx := context.Background()
+	store := NewBlobSnapshotStore(NewMemoryBlobStore(), "rates.json")
+	table := NewTable(nil)
+	table.UpdateFallback(testCurrencies(), time.Time{})
+
+	assert.NoError(t, NewLastKnownGood(table, store).Save(ctx))
+	_, err := store.Load(ctx)
+	assert.ErrorIs(t, err, ErrSnapshotNotFound)
+
+	// A degraded snapshot restores a degraded table
+	restored := NewTable(nil)
+	restored.Restore(table.Snapshot())
+	assert.True(t, restored.Degraded())
+}
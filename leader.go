@@ -0,0 +1,91 @@
+package converter
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DefaultCampaignRetry is how long a LeaderRefresher waits before
+// campaigning again after a campaign fails.
+const DefaultCampaignRetry = 5 * time.Second
+
+// Elector elects a single leader among the instances campaigning through
+// it, e.g. with a lock in a shared etcd or Consul store.
+type Elector interface {
+	// Campaign blocks until this instance is elected or ctx is done. It
+	// returns a context cancelled once leadership is lost, and gives
+	// leadership up once ctx is done.
+	Campaign(ctx context.Context) (context.Context, error)
+}
+
+// LeaderRefresher runs a Refresher only while its instance is the elected
+// leader, so a fleet of replicas fetches from providers once rather than
+// once per replica. Pair it with a SharedTable: the leader's Refresher
+// publishes through the SharedTable's Snapshots and every replica follows
+// the shared table.
+type LeaderRefresher struct {
+	Refresher *Refresher
+	Elector   Elector
+	// Name identifies the instance in leader events, e.g. its host name.
+	Name string
+	// Retry is the wait before campaigning again after a failed campaign;
+	// DefaultCampaignRetry if zero.
+	Retry time.Duration
+	// OnError, if set, receives campaign failures.
+	OnError func(err error)
+	OnEvent EventHandler
+	Clock   Clock
+}
+
+// NewLeaderRefresher creates a runner of r while elected by elector.
+func NewLeaderRefresher(r *Refresher, elector Elector) *LeaderRefresher {
+	return &LeaderRefresher{Refresher: r, Elector: elector}
+}
+
+// Run campaigns for leadership and refreshes while elected, campaigning
+// again whenever leadership is lost, until ctx is cancelled.
+func (l *LeaderRefresher) Run(ctx context.Context) error {
+	retry := l.Retry
+	if retry <= 0 {
+		retry = DefaultCampaignRetry
+	}
+
+	for {
+		leadership, err := l.Elector.Campaign(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			if l.OnError != nil {
+				l.OnError(fmt.Errorf("campaign for leadership: %w", err))
+			}
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(retry):
+			}
+			continue
+		}
+
+		l.emit(EventLeaderElected)
+		if err := l.Refresher.Run(leadership); err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		l.emit(EventLeaderLost)
+	}
+}
+
+func (l *LeaderRefresher) emit(t EventType) {
+	if l.OnEvent == nil {
+		return
+	}
+	clock := l.Clock
+	if clock == nil {
+		clock = SystemClock
+	}
+	l.OnEvent(Event{Type: t, Time: clock.Now(), Actor: l.Name})
+}
@@ -0,0 +1,80 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// grantElector elects the campaigner when a leadership context, or a
+// campaign error, is sent on its channel.
+type grantElector chan any
+
+func (e grantElector) Campaign(ctx context.Context) (context.Context, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case grant := <-e:
+		if err, ok := grant.(error); ok {
+			return nil, err
+		}
+		return grant.(context.Context), nil
+	}
+}
+
+func TestLeaderRefresher(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var fetches atomic.Int32
+	provider := RateProviderFunc(func(context.Context) ([]Currency, error) {
+		fetches.Add(1)
+		return testCurrencies(), nil
+	})
+	r, err := NewRefresher(NewTable(nil), provider, time.Hour)
+	require.NoError(t, err)
+
+	elector := make(grantElector)
+	events := make(chan Event, 10)
+	errs := make(chan error, 10)
+	l := NewLeaderRefresher(r, elector)
+	l.Name = "replica-1"
+	l.Retry = time.Millisecond
+	l.OnEvent = func(e Event) { events <- e }
+	l.OnError = func(err error) { errs <- err }
+	done := make(chan error)
+	go func() { done <- l.Run(ctx) }()
+
+	// Followers do not fetch
+	time.Sleep(10 * time.Millisecond)
+	assert.Zero(t, fetches.Load())
+
+	// A failed campaign is retried
+	errDown := errors.New("store down")
+	elector <- errDown
+	assert.ErrorIs(t, <-errs, errDown)
+
+	leadership, lose := context.WithCancel(ctx)
+	elector <- leadership
+	e := <-events
+	assert.Equal(t, EventLeaderElected, e.Type)
+	assert.Equal(t, "replica-1", e.Actor)
+	require.Eventually(t, func() bool { return fetches.Load() == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, 3, r.Table.Len())
+
+	// Losing leadership stops refreshing until elected again
+	lose()
+	assert.Equal(t, EventLeaderLost, (<-events).Type)
+	elector <- ctx
+	assert.Equal(t, EventLeaderElected, (<-events).Type)
+	require.Eventually(t, func() bool { return fetches.Load() == 2 }, time.Second, time.Millisecond)
+
+	cancel()
+	assert.NoError(t, <-done)
+	assert.Empty(t, events)
+}
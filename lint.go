@@ -0,0 +1,188 @@
+package converter
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// LintSeverity grades a lint issue.
+type LintSeverity string
+
+// Lint severities
+const (
+	// LintError marks a rate file that must not ship.
+	LintError LintSeverity = "error"
+	// LintWarning marks something worth a look.
+	LintWarning LintSeverity = "warning"
+)
+
+// LintIssue is one problem found in a rate table.
+type LintIssue struct {
+	Severity LintSeverity `json:"severity"`
+	// Check names the rule, e.g. "duplicate" or "inverted-spread".
+	Check string `json:"check"`
+	// Index is the 0-based position of the entry in the table.
+	Index   int    `json:"index"`
+	ISOCode string `json:"isoCode,omitempty"`
+	Message string `json:"message"`
+}
+
+// LintReport lists the issues of a rate table, in table order.
+type LintReport struct {
+	Issues   []LintIssue `json:"issues"`
+	Errors   int         `json:"errors"`
+	Warnings int         `json:"warnings"`
+}
+
+// OK reports whether the table has no errors.
+func (r *LintReport) OK() bool {
+	return r.Errors == 0
+}
+
+func (r *LintReport) add(severity LintSeverity, check string, index int, code, format string, args ...any) {
+	r.Issues = append(r.Issues, LintIssue{
+		Severity: severity,
+		Check:    check,
+		Index:    index,
+		ISOCode:  code,
+		Message:  fmt.Sprintf(format, args...),
+	})
+	if severity == LintError {
+		r.Errors++
+	} else {
+		r.Warnings++
+	}
+}
+
+// Lint runs the full validation suite over a rate table quoted against
+// base, for release pipelines that ship rate files. Errors are entries
+// without an ISO code, duplicated codes, invalid entries (see
+// Currency.Validate), zero rates, inverted spreads (a sell rate above the
+// buy rate, so a round trip gains) and a base currency missing or not at
+// 1/1. A precision below the currency's ISO minor units is a warning. An
+// empty base skips the base checks.
+func Lint(currencies []Currency, base string) *LintReport {
+	return lint(currencies, base, nil)
+}
+
+// lint implements Lint, skipping the entries in skip.
+func lint(currencies []Currency, base string, skip map[int]bool) *LintReport {
+	report := &LintReport{Issues: []LintIssue{}}
+	one := decimal.NewFromInt(1)
+	seen := make(map[string]int, len(currencies))
+	baseFound := false
+
+	for i, c := range currencies {
+		if skip[i] {
+			continue
+		}
+		code := strings.ToUpper(strings.TrimSpace(c.ISOCode))
+		if code == "" {
+			report.add(LintError, "missing-iso-code", i, "", "entry has no ISO code")
+			continue
+		}
+		if first, ok := seen[code]; ok {
+			report.add(LintError, "duplicate", i, code, "%s already listed at entry %d", code, first)
+			continue
+		}
+		seen[code] = i
+
+		if err := c.Validate(); err != nil {
+			report.add(LintError, "invalid", i, code, "%v", err)
+			continue
+		}
+
+		if base != "" && strings.EqualFold(code, base) {
+			baseFound = true
+			if !c.BuyRate.Equal(one) || !c.SellRate.Equal(one) {
+				report.add(LintError, "base-rate", i, code, "base rates are %s/%s, want 1/1", c.BuyRate, c.SellRate)
+			}
+			continue
+		}
+
+		if c.BuyRate.IsZero() || c.SellRate.IsZero() {
+			report.add(LintError, "zero-rate", i, code, "%s has a zero rate: buy %s, sell %s", code, c.BuyRate, c.SellRate)
+		} else if c.SellRate.GreaterThan(c.BuyRate) {
+			report.add(LintError, "inverted-spread", i, code, "%s sell rate %s is above its buy rate %s", code, c.SellRate, c.BuyRate)
+		}
+
+		if units, ok := MinorUnits(code); ok && c.Precision < units {
+			report.add(LintWarning, "precision", i, code, "%s precision %d is below its %d minor units", code, c.Precision, units)
+		}
+	}
+
+	if base != "" && !baseFound {
+		report.add(LintError, "base-missing", -1, strings.ToUpper(base), "base currency %s is not in the table", strings.ToUpper(base))
+	}
+	return report
+}
+
+// LintJSON lints a JSON array of rate entries, as read by NewCurrencies.
+// Besides the checks of Lint, entries without a precision field are
+// errors: precision 0 is only right for zero-decimal currencies and must be
+// explicit. A file that is not a JSON array of entries is an error.
+func LintJSON(r io.Reader, base string) (*LintReport, error) {
+	var raws []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raws); err != nil {
+		return nil, fmt.Errorf("decode rates: %w", err)
+	}
+
+	currencies := make([]Currency, len(raws))
+	unreadable := map[int]error{}
+	var missing []int
+	for i, raw := range raws {
+		var fields struct {
+			ISOCode   string          `json:"isoCode"`
+			Precision json.RawMessage `json:"precision"`
+		}
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return nil, fmt.Errorf("decode rates: entry %d: %w", i, err)
+		}
+		if fields.Precision == nil {
+			missing = append(missing, i)
+		}
+		if err := currencies[i].UnmarshalJSON(raw); err != nil {
+			currencies[i] = Currency{ISOCode: fields.ISOCode}
+			unreadable[i] = err
+		}
+	}
+
+	skip := make(map[int]bool, len(unreadable))
+	for i := range unreadable {
+		skip[i] = true
+	}
+	report := lint(currencies, base, skip)
+	for i, err := range unreadable {
+		report.add(LintError, "invalid", i, strings.ToUpper(currencies[i].ISOCode), "%v", err)
+	}
+	for _, i := range missing {
+		code := strings.ToUpper(currencies[i].ISOCode)
+		report.add(LintError, "missing-precision", i, code, "%s has no precision", code)
+	}
+	sortLintIssues(report.Issues)
+	return report, nil
+}
+
+// sortLintIssues orders issues by entry, then check, table-wide issues
+// last.
+func sortLintIssues(issues []LintIssue) {
+	key := func(i LintIssue) int {
+		if i.Index < 0 {
+			return math.MaxInt
+		}
+		return i.Index
+	}
+	slices.SortStableFunc(issues, func(a, b LintIssue) int {
+		if c := cmp.Compare(key(a), key(b)); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Check, b.Check)
+	})
+}
@@ -0,0 +1,62 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLint(t *testing.T) {
+	currencies := []Currency{
+		{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+		{ISOCode: "NGN", Precision: 2, BuyRate: decimal.NewFromInt(1550), SellRate: decimal.NewFromInt(1500)},
+		{ISOCode: "EUR", Precision: 2, BuyRate: decimal.RequireFromString("0.9"), SellRate: decimal.RequireFromString("0.95")},
+		{ISOCode: "ngn", Precision: 2, BuyRate: decimal.NewFromInt(1550), SellRate: decimal.NewFromInt(1500)},
+		{ISOCode: "KWD", Precision: 2, BuyRate: decimal.RequireFromString("0.31"), SellRate: decimal.RequireFromString("0.30")},
+		{ISOCode: "GBP", Precision: 2, BuyRate: decimal.Zero, SellRate: decimal.RequireFromString("0.8")},
+		{Precision: 2},
+		{ISOCode: "JPY", Precision: -1},
+	}
+
+	report := Lint(currencies, "USD")
+	assert.False(t, report.OK())
+	var checks []string
+	for _, issue := range report.Issues {
+		checks = append(checks, issue.ISOCode+":"+issue.Check)
+	}
+	assert.Equal(t, []string{
+		"EUR:inverted-spread",
+		"NGN:duplicate",
+		"KWD:precision",
+		"GBP:zero-rate",
+		":missing-iso-code",
+		"JPY:invalid",
+	}, checks)
+	assert.Equal(t, 5, report.Errors)
+	assert.Equal(t, 1, report.Warnings)
+
+	report = Lint(currencies[1:2], "usd")
+	assert.Equal(t, "base-missing", report.Issues[0].Check)
+	assert.Equal(t, -1, report.Issues[0].Index)
+
+	assert.True(t, Lint(currencies[:2], "USD").OK())
+}
+
+func TestLintJSON(t *testing.T) {
+	report, err := LintJSON(strings.NewReader(`[
+		{"isoCode": "USD", "precision": 2, "buyRate": 1, "sellRate": 1},
+		{"isoCode": "NGN", "buyRate": "1550", "sellRate": "1500"},
+		{"isoCode": "EUR", "precision": 2, "buyRate": "abc", "sellRate": 1}
+	]`), "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, []LintIssue{
+		{Severity: LintError, Check: "missing-precision", Index: 1, ISOCode: "NGN", Message: "NGN has no precision"},
+		{Severity: LintWarning, Check: "precision", Index: 1, ISOCode: "NGN", Message: "NGN precision 0 is below its 2 minor units"},
+		{Severity: LintError, Check: "invalid", Index: 2, ISOCode: "EUR", Message: "invalid rate: EUR buy rate: not a decimal number"},
+	}, report.Issues)
+
+	_, err = LintJSON(strings.NewReader(`{"USD": 1}`), "USD")
+	assert.Error(t, err)
+}
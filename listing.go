@@ -0,0 +1,124 @@
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Listing errors
+var (
+	ErrInvalidListing = errors.New("invalid currency listing")
+)
+
+// CurrencySort selects the order of a currency listing.
+type CurrencySort int
+
+// Currency sort orders
+const (
+	// SortByCode orders currencies by ISO code.
+	SortByCode CurrencySort = iota
+	// SortByRate orders currencies by sell rate, the units of the currency
+	// quoted per unit of base.
+	SortByRate
+	// SortByUpdatedAt orders currencies by when their rates last changed.
+	SortByUpdatedAt
+)
+
+// String returns the name of the sort order.
+func (s CurrencySort) String() string {
+	switch s {
+	case SortByCode:
+		return "code"
+	case SortByRate:
+		return "rate"
+	case SortByUpdatedAt:
+		return "updated-at"
+	default:
+		return "unknown"
+	}
+}
+
+// CurrencyListing selects the page and order of a currency listing. Ties are
+// broken by ascending ISO code, so pages are stable.
+type CurrencyListing struct {
+	SortBy     CurrencySort
+	Descending bool
+	// Offset and Limit page through the results. A zero Limit returns all.
+	// A negative Offset is treated as zero; a negative Limit is invalid.
+	Offset int
+	Limit  int
+}
+
+// Validate checks the sort order and paging fields of the listing.
+func (l CurrencyListing) Validate() error {
+	if l.SortBy < SortByCode || l.SortBy > SortByUpdatedAt {
+		return fmt.Errorf("%w: unknown sort order %d", ErrInvalidListing, l.SortBy)
+	}
+	if l.Limit < 0 {
+		return fmt.Errorf("%w: negative limit %d", ErrInvalidListing, l.Limit)
+	}
+	return nil
+}
+
+// CurrencyEntry is a currency in a listing with when its rates last changed.
+type CurrencyEntry struct {
+	Currency  Currency  `json:"currency"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// CurrencyPage is one page of a currency listing.
+type CurrencyPage struct {
+	Currencies []CurrencyEntry `json:"currencies"`
+	// Total is the number of currencies across all pages.
+	Total int `json:"total"`
+	// NextOffset is the offset of the next page, or zero on the last page.
+	NextOffset int `json:"nextOffset"`
+}
+
+// ListCurrencies returns one page of the table's currencies in the order
+// listing selects, for admin UIs over large tables.
+func (c *Currencies) ListCurrencies(listing CurrencyListing) (*CurrencyPage, error) {
+	if err := listing.Validate(); err != nil {
+		return nil, err
+	}
+
+	c.mu.RLock()
+	entries := make([]CurrencyEntry, len(c.items))
+	for i, item := range c.items {
+		entries[i] = CurrencyEntry{Currency: item, UpdatedAt: c.changedAt[strings.ToUpper(item.ISOCode)]}
+	}
+	c.mu.RUnlock()
+
+	slices.SortStableFunc(entries, func(a, b CurrencyEntry) int {
+		byCode := strings.Compare(strings.ToUpper(a.Currency.ISOCode), strings.ToUpper(b.Currency.ISOCode))
+
+		cmp := byCode
+		switch listing.SortBy {
+		case SortByRate:
+			cmp = a.Currency.SellRate.Cmp(b.Currency.SellRate)
+		case SortByUpdatedAt:
+			cmp = a.UpdatedAt.Compare(b.UpdatedAt)
+		}
+		if listing.Descending {
+			cmp = -cmp
+		}
+		if cmp == 0 {
+			return byCode
+		}
+		return cmp
+	})
+
+	page := &CurrencyPage{Total: len(entries)}
+	start := min(max(listing.Offset, 0), len(entries))
+	end := len(entries)
+	if listing.Limit > 0 && start+listing.Limit < end {
+		end = start + listing.Limit
+		page.NextOffset = end
+	}
+	page.Currencies = entries[start:end]
+
+	return page, nil
+}
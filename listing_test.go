@@ -0,0 +1,62 @@
+package converter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurrencies_ListCurrencies(t *testing.T) {
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	table := NewTable(nil)
+	table.Update(testCurrencies(), start)
+
+	// Only the requoted currency moves its updated-at.
+	currencies := testCurrencies()
+	currencies[2].SellRate = decimal.NewFromInt(1560)
+	table.Update(currencies, start.Add(time.Hour))
+
+	entryCodes := func(page *CurrencyPage) []string {
+		var result []string
+		for _, e := range page.Currencies {
+			result = append(result, e.Currency.ISOCode)
+		}
+		return result
+	}
+
+	page, err := table.ListCurrencies(CurrencyListing{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"EUR", "NGN", "USD"}, entryCodes(page))
+	assert.Equal(t, 3, page.Total)
+	assert.Equal(t, 0, page.NextOffset)
+	assert.Equal(t, start, page.Currencies[0].UpdatedAt)
+	assert.Equal(t, start.Add(time.Hour), page.Currencies[1].UpdatedAt)
+
+	page, err = table.ListCurrencies(CurrencyListing{SortBy: SortByRate, Descending: true, Limit: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"NGN", "USD"}, entryCodes(page))
+	assert.Equal(t, 3, page.Total)
+	assert.Equal(t, 2, page.NextOffset)
+
+	page, err = table.ListCurrencies(CurrencyListing{SortBy: SortByRate, Descending: true, Offset: page.NextOffset, Limit: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"EUR"}, entryCodes(page))
+	assert.Equal(t, 0, page.NextOffset)
+
+	// Ties are broken by code.
+	page, err = table.ListCurrencies(CurrencyListing{SortBy: SortByUpdatedAt, Descending: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"NGN", "EUR", "USD"}, entryCodes(page))
+
+	page, err = table.ListCurrencies(CurrencyListing{Offset: 10})
+	assert.NoError(t, err)
+	assert.Empty(t, page.Currencies)
+	assert.Equal(t, 3, page.Total)
+
+	_, err = table.ListCurrencies(CurrencyListing{Limit: -1})
+	assert.ErrorIs(t, err, ErrInvalidListing)
+	_, err = table.ListCurrencies(CurrencyListing{SortBy: CurrencySort(9)})
+	assert.ErrorIs(t, err, ErrInvalidListing)
+}
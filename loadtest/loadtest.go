@@ -0,0 +1,237 @@
+// Package loadtest fires configurable mixes of operations, such as rate
+// lookups and quote creations, at a rate table or a server running the
+// converter, and reports their throughput and latency percentiles.
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"slices"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// Config errors
+var (
+	ErrNoOps   = errors.New("loadtest: no operations")
+	ErrNoLimit = errors.New("loadtest: no duration or request limit")
+)
+
+// DefaultWorkers is the number of concurrent workers when a Config sets
+// none.
+const DefaultWorkers = 8
+
+// Op is an operation in the mix.
+type Op struct {
+	Name string
+	// Weight is the operation's share of the mix relative to the others',
+	// e.g. 9 rate lookups to 1 quote; zero counts as 1.
+	Weight int
+	// Do performs the operation once. It must be safe for concurrent use.
+	Do func(ctx context.Context) error
+}
+
+// weight returns the op's weight, 1 if unset.
+func (o Op) weight() int {
+	return max(o.Weight, 1)
+}
+
+// Config configures a load test. It runs until Duration has passed or
+// Requests operations are done, whichever comes first; at least one must
+// be set.
+type Config struct {
+	Ops      []Op
+	Workers  int
+	Duration time.Duration
+	Requests int
+	// Seed seeds the choice of operations, so runs make the same mix.
+	Seed int64
+}
+
+// Result is an operation's, or the whole run's, share of a load test.
+type Result struct {
+	Name   string        `json:"name"`
+	Count  int           `json:"count"`
+	Errors int           `json:"errors"`
+	P50    time.Duration `json:"p50"`
+	P90    time.Duration `json:"p90"`
+	P99    time.Duration `json:"p99"`
+	Max    time.Duration `json:"max"`
+	// Throughput is operations per second over the whole run.
+	Throughput float64 `json:"throughput"`
+	// Err is the first error, if any.
+	Err string `json:"error,omitempty"`
+}
+
+// Report is the result of a load test.
+type Report struct {
+	Elapsed time.Duration `json:"elapsed"`
+	Workers int           `json:"workers"`
+	// Ops holds a result per operation, in Config order.
+	Ops   []Result `json:"ops"`
+	Total Result   `json:"total"`
+}
+
+// sample is the outcome of one operation.
+type sample struct {
+	latency time.Duration
+	err     error
+}
+
+// Run runs the load test. It returns early, with the report so far, when
+// ctx is done.
+func Run(ctx context.Context, cfg Config) (*Report, error) {
+	if len(cfg.Ops) == 0 {
+		return nil, ErrNoOps
+	}
+	if cfg.Duration <= 0 && cfg.Requests <= 0 {
+		return nil, ErrNoLimit
+	}
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	if cfg.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Duration)
+		defer cancel()
+	}
+
+	total := 0
+	for _, op := range cfg.Ops {
+		total += op.weight()
+	}
+
+	// Each worker keeps its own samples, so recording takes no lock.
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		started int
+		samples = make([][][]sample, workers)
+	)
+	take := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		if cfg.Requests > 0 && started >= cfg.Requests {
+			return false
+		}
+		started++
+		return true
+	}
+
+	start := time.Now()
+	for w := 0; w < workers; w++ {
+		samples[w] = make([][]sample, len(cfg.Ops))
+		rnd := rand.New(rand.NewSource(cfg.Seed + int64(w)))
+		wg.Add(1)
+		go func(own [][]sample) {
+			defer wg.Done()
+			for ctx.Err() == nil && take() {
+				i := pick(cfg.Ops, rnd.Intn(total))
+				began := time.Now()
+				err := cfg.Ops[i].Do(ctx)
+				if err != nil && ctx.Err() != nil {
+					// Cut short by the end of the run, not a failure.
+					return
+				}
+				own[i] = append(own[i], sample{latency: time.Since(began), err: err})
+			}
+		}(samples[w])
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	report := &Report{Elapsed: elapsed, Workers: workers}
+	var all []sample
+	for i, op := range cfg.Ops {
+		var own []sample
+		for w := range samples {
+			own = append(own, samples[w][i]...)
+		}
+		report.Ops = append(report.Ops, summarize(op.Name, own, elapsed))
+		all = append(all, own...)
+	}
+	report.Total = summarize("total", all, elapsed)
+	return report, nil
+}
+
+// pick returns the index of the op whose share of the weights holds n.
+func pick(ops []Op, n int) int {
+	for i, op := range ops {
+		if n < op.weight() {
+			return i
+		}
+		n -= op.weight()
+	}
+	return len(ops) - 1
+}
+
+// summarize computes the result of samples over elapsed.
+func summarize(name string, samples []sample, elapsed time.Duration) Result {
+	r := Result{Name: name, Count: len(samples)}
+	if len(samples) == 0 {
+		return r
+	}
+	latencies := make([]time.Duration, len(samples))
+	for i, s := range samples {
+		latencies[i] = s.latency
+		if s.err != nil {
+			if r.Errors == 0 {
+				r.Err = s.err.Error()
+			}
+			r.Errors++
+		}
+	}
+	slices.Sort(latencies)
+	r.P50 = percentile(latencies, 50)
+	r.P90 = percentile(latencies, 90)
+	r.P99 = percentile(latencies, 99)
+	r.Max = latencies[len(latencies)-1]
+	if elapsed > 0 {
+		r.Throughput = float64(len(samples)) / elapsed.Seconds()
+	}
+	return r
+}
+
+// percentile returns the nearest-rank pth percentile of sorted latencies.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
+
+// WriteText writes the report as a text table, an operation per row.
+func (r *Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "op\tcount\terrors\tops/s\tp50\tp90\tp99\tmax\t\n")
+	for _, res := range append(slices.Clone(r.Ops), r.Total) {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.1f\t%s\t%s\t%s\t%s\t\n",
+			res.Name, res.Count, res.Errors, res.Throughput,
+			round(res.P50), round(res.P90), round(res.P99), round(res.Max))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%d workers, %s\n", r.Workers, round(r.Elapsed))
+	for _, res := range r.Ops {
+		if res.Err != "" && err == nil {
+			_, err = fmt.Fprintf(w, "%s: first error: %s\n", res.Name, res.Err)
+		}
+	}
+	return err
+}
+
+// round rounds latencies for display.
+func round(d time.Duration) time.Duration {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond)
+	case d >= time.Millisecond:
+		return d.Round(10 * time.Microsecond)
+	default:
+		return d.Round(100 * time.Nanosecond)
+	}
+}
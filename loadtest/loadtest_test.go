@@ -0,0 +1,93 @@
+package loadtest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func counting(name string, weight int, calls *atomic.Int64, err error) Op {
+	return Op{Name: name, Weight: weight, Do: func(context.Context) error {
+		calls.Add(1)
+		return err
+	}}
+}
+
+func TestRun(t *testing.T) {
+	var reads, writes atomic.Int64
+	report, err := Run(context.Background(), Config{
+		Ops: []Op{
+			counting("read", 9, &reads, nil),
+			counting("write", 1, &writes, errors.New("rejected")),
+		},
+		Workers:  4,
+		Requests: 1000,
+		Seed:     1,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, report.Workers)
+	assert.Equal(t, 1000, report.Total.Count)
+	assert.Equal(t, int64(1000), reads.Load()+writes.Load())
+	require.Len(t, report.Ops, 2)
+	assert.Equal(t, "read", report.Ops[0].Name)
+	assert.Equal(t, int(reads.Load()), report.Ops[0].Count)
+	assert.InDelta(t, 900, report.Ops[0].Count, 60)
+	assert.Zero(t, report.Ops[0].Errors)
+	assert.Equal(t, report.Ops[1].Count, report.Ops[1].Errors)
+	assert.Equal(t, "rejected", report.Ops[1].Err)
+	assert.Equal(t, report.Ops[1].Errors, report.Total.Errors)
+	assert.LessOrEqual(t, report.Total.P50, report.Total.P99)
+	assert.LessOrEqual(t, report.Total.P99, report.Total.Max)
+	assert.Positive(t, report.Total.Throughput)
+
+	var out bytes.Buffer
+	require.NoError(t, report.WriteText(&out))
+	assert.Contains(t, out.String(), "op     count")
+	assert.Contains(t, out.String(), "total  1000")
+	assert.Contains(t, out.String(), "4 workers, ")
+	assert.Contains(t, out.String(), "write: first error: rejected\n")
+}
+
+func TestRun_Duration(t *testing.T) {
+	report, err := Run(context.Background(), Config{
+		Ops: []Op{{Name: "sleep", Do: func(ctx context.Context) error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Millisecond):
+				return nil
+			}
+		}}},
+		Workers:  2,
+		Duration: 50 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	assert.Positive(t, report.Total.Count)
+	// Operations cut short by the end of the run are not errors.
+	assert.Zero(t, report.Total.Errors)
+	assert.GreaterOrEqual(t, report.Total.P50, time.Millisecond)
+}
+
+func TestRun_Invalid(t *testing.T) {
+	_, err := Run(context.Background(), Config{Requests: 1})
+	assert.ErrorIs(t, err, ErrNoOps)
+	_, err = Run(context.Background(), Config{Ops: []Op{{Name: "noop", Do: func(context.Context) error { return nil }}}})
+	assert.ErrorIs(t, err, ErrNoLimit)
+}
+
+func TestPercentile(t *testing.T) {
+	var latencies []time.Duration
+	for i := 1; i <= 100; i++ {
+		latencies = append(latencies, time.Duration(i))
+	}
+	assert.Equal(t, time.Duration(50), percentile(latencies, 50))
+	assert.Equal(t, time.Duration(99), percentile(latencies, 99))
+	assert.Equal(t, time.Duration(7), percentile([]time.Duration{7}, 99))
+}
@@ -0,0 +1,147 @@
+package loadtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/otyang/converter"
+	"github.com/shopspring/decimal"
+)
+
+// ErrNoPairs is returned for operations given no currency pairs.
+var ErrNoPairs = errors.New("loadtest: no currency pairs")
+
+// Pair is a currency pair to convert from and to.
+type Pair struct {
+	From, To string
+}
+
+// pairs cycles through pairs, so operations spread over all of them.
+type pairs struct {
+	list []Pair
+	next atomic.Uint64
+}
+
+func newPairs(list []Pair) (*pairs, error) {
+	if len(list) == 0 {
+		return nil, ErrNoPairs
+	}
+	return &pairs{list: append([]Pair(nil), list...)}, nil
+}
+
+func (p *pairs) pick() Pair {
+	return p.list[(p.next.Add(1)-1)%uint64(len(p.list))]
+}
+
+// RateOp looks up the rate of one of pairs in source, priced against base,
+// per operation.
+func RateOp(source converter.RateSource, base string, list ...Pair) (Op, error) {
+	p, err := newPairs(list)
+	if err != nil {
+		return Op{}, err
+	}
+	return Op{Name: "rate", Do: func(context.Context) error {
+		pair := p.pick()
+		_, err := source.CalculateRate(base, pair.From, pair.To)
+		return err
+	}}, nil
+}
+
+// QuoteOp creates a quote for amount of one of pairs from table, priced
+// against base, per operation.
+func QuoteOp(table *converter.Currencies, base string, amount decimal.Decimal, list ...Pair) (Op, error) {
+	p, err := newPairs(list)
+	if err != nil {
+		return Op{}, err
+	}
+	return Op{Name: "quote", Do: func(context.Context) error {
+		pair := p.pick()
+		_, err := table.NewQuote(base, pair.From, pair.To, amount, decimal.Zero)
+		return err
+	}}, nil
+}
+
+// HTTPRateOp fetches url, a rate endpoint such as the one
+// httpapi.MatrixHandler serves, per operation. Any status but 200 OK is
+// an error. A nil client uses http.DefaultClient.
+func HTTPRateOp(client *http.Client, url string) Op {
+	return Op{Name: "rate", Do: func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		return send(client, req)
+	}}
+}
+
+// createQuote is the mutation GraphQLQuoteOp sends.
+const createQuote = `mutation($input: QuoteInput!) { createQuote(input: $input) { id } }`
+
+// GraphQLQuoteOp creates a quote for amount of one of pairs per operation,
+// with the createQuote mutation of the GraphQL API at url, see package
+// graphqlapi. A nil client uses http.DefaultClient.
+func GraphQLQuoteOp(client *http.Client, url string, amount decimal.Decimal, list ...Pair) (Op, error) {
+	p, err := newPairs(list)
+	if err != nil {
+		return Op{}, err
+	}
+	return Op{Name: "quote", Do: func(ctx context.Context) error {
+		pair := p.pick()
+		body, err := json.Marshal(map[string]any{
+			"query": createQuote,
+			"variables": map[string]any{
+				"input": map[string]string{"from": pair.From, "to": pair.To, "amount": amount.String()},
+			},
+		})
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return send(client, req)
+	}}, nil
+}
+
+// send sends req and checks the response is 200 OK and, for GraphQL
+// responses, free of errors.
+func send(client *http.Client, req *http.Request) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s %s: %s", req.Method, req.URL, resp.Status)
+	}
+	if req.Method != http.MethodPost {
+		return nil
+	}
+	var result struct {
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("%s %s: %w", req.Method, req.URL, err)
+	}
+	if len(result.Errors) > 0 {
+		return fmt.Errorf("%s %s: %s", req.Method, req.URL, result.Errors[0].Message)
+	}
+	return nil
+}
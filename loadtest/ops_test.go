@@ -0,0 +1,66 @@
+package loadtest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/otyang/converter"
+	"github.com/otyang/converter/graphqlapi"
+	"github.com/otyang/converter/httpapi"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTable() *converter.Currencies {
+	return converter.NewTable([]converter.Currency{
+		{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+		{ISOCode: "NGN", Precision: 2, BuyRate: decimal.NewFromInt(1500), SellRate: decimal.NewFromInt(1550)},
+	})
+}
+
+func TestTableOps(t *testing.T) {
+	table := testTable()
+	rate, err := RateOp(table, "USD", Pair{"USD", "NGN"}, Pair{"NGN", "GBP"})
+	require.NoError(t, err)
+	quote, err := QuoteOp(table, "USD", decimal.NewFromInt(100), Pair{"USD", "NGN"})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	assert.NoError(t, rate.Do(ctx))
+	assert.ErrorIs(t, rate.Do(ctx), converter.ErrUnknownCurrency)
+	assert.NoError(t, quote.Do(ctx))
+
+	_, err = RateOp(table, "USD")
+	assert.ErrorIs(t, err, ErrNoPairs)
+	_, err = QuoteOp(table, "USD", decimal.NewFromInt(1))
+	assert.ErrorIs(t, err, ErrNoPairs)
+}
+
+func TestHTTPOps(t *testing.T) {
+	table := testTable()
+	graphql, err := graphqlapi.NewHandler(graphqlapi.Config{Table: table, Base: "USD", Quotes: converter.NewMemoryQuoteStore()})
+	require.NoError(t, err)
+	mux := http.NewServeMux()
+	mux.Handle("/rates/matrix", httpapi.MatrixHandler(table, "USD"))
+	mux.Handle("/graphql", graphql)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ctx := context.Background()
+	assert.NoError(t, HTTPRateOp(srv.Client(), srv.URL+"/rates/matrix").Do(ctx))
+	err = HTTPRateOp(nil, srv.URL+"/missing").Do(ctx)
+	assert.ErrorContains(t, err, "404 Not Found")
+
+	quote, err := GraphQLQuoteOp(srv.Client(), srv.URL+"/graphql", decimal.NewFromInt(100), Pair{"USD", "NGN"}, Pair{"USD", "GBP"})
+	require.NoError(t, err)
+	assert.NoError(t, quote.Do(ctx))
+	assert.ErrorContains(t, quote.Do(ctx), "GBP")
+
+	report, err := Run(ctx, Config{Ops: []Op{HTTPRateOp(srv.Client(), srv.URL+"/rates/matrix")}, Workers: 2, Requests: 20})
+	require.NoError(t, err)
+	assert.Equal(t, 20, report.Total.Count)
+	assert.Zero(t, report.Total.Errors)
+}
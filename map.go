@@ -0,0 +1,90 @@
+package converter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// DefaultMapPrecision is the precision given to currencies read from a map,
+// which carries rates only.
+const DefaultMapPrecision = 2
+
+// NewCurrenciesFromMap creates currencies from a map of rates per unit of
+// base, the shape most public rate APIs return, e.g. {"EUR":0.91,"NGN":1500}.
+// Each rate is used as both the buy and the sell rate, the base currency is
+// added at 1 if missing, every currency gets DefaultMapPrecision, and the
+// result is sorted by ISO code.
+//
+// Rates are converted with decimal.NewFromFloat; prefer
+// NewCurrenciesFromStringMap when the feed can be decoded to strings.
+func NewCurrenciesFromMap(base string, rates map[string]float64) ([]Currency, error) {
+	decimals := make(map[string]decimal.Decimal, len(rates))
+	for code, rate := range rates {
+		decimals[code] = decimal.NewFromFloat(rate)
+	}
+	return NewCurrenciesFromDecimalMap(base, decimals)
+}
+
+// NewCurrenciesFromStringMap is NewCurrenciesFromMap for string rates, parsed
+// with ParseRate.
+func NewCurrenciesFromStringMap(base string, rates map[string]string) ([]Currency, error) {
+	decimals := make(map[string]decimal.Decimal, len(rates))
+	for code, rate := range rates {
+		d, err := ParseRate(rate)
+		if err != nil {
+			return nil, fmt.Errorf("%w (%s)", err, code)
+		}
+		decimals[code] = d
+	}
+	return NewCurrenciesFromDecimalMap(base, decimals)
+}
+
+// NewCurrenciesFromDecimalMap is NewCurrenciesFromMap for decimal rates.
+func NewCurrenciesFromDecimalMap(base string, rates map[string]decimal.Decimal) ([]Currency, error) {
+	if len(rates) == 0 {
+		return nil, ErrEmptyCurrencySource
+	}
+
+	base = strings.ToUpper(strings.TrimSpace(base))
+	if base == "" {
+		return nil, ErrBaseCurrencyNotFound
+	}
+
+	one := decimal.NewFromInt(1)
+	byCode := map[string]Currency{
+		base: {ISOCode: base, Precision: DefaultMapPrecision, BuyRate: one, SellRate: one},
+	}
+	seen := map[string]bool{}
+
+	for code, rate := range rates {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if seen[code] {
+			return nil, fmt.Errorf("currency %s appears more than once", code)
+		}
+		seen[code] = true
+
+		if code == base {
+			if !rate.Equal(one) {
+				return nil, fmt.Errorf("%w: base currency %s must have rate 1", ErrInvalidRate, base)
+			}
+			continue
+		}
+
+		c := Currency{ISOCode: code, Precision: DefaultMapPrecision, BuyRate: rate, SellRate: rate}
+		if err := c.Validate(); err != nil {
+			return nil, err
+		}
+		byCode[code] = c
+	}
+
+	currencies := make([]Currency, 0, len(byCode))
+	for _, c := range byCode {
+		currencies = append(currencies, c)
+	}
+	sort.Slice(currencies, func(i, j int) bool { return currencies[i].ISOCode < currencies[j].ISOCode })
+
+	return currencies, nil
+}
@@ -0,0 +1,59 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCurrenciesFromMap(t *testing.T) {
+	currencies, err := NewCurrenciesFromMap("usd", map[string]float64{"EUR": 0.91, "ngn": 1500})
+	assert.NoError(t, err)
+	assert.Len(t, currencies, 3)
+
+	// Sorted, with the base added at par
+	assert.Equal(t, "EUR", currencies[0].ISOCode)
+	assert.Equal(t, "NGN", currencies[1].ISOCode)
+	assert.Equal(t, "USD", currencies[2].ISOCode)
+	assert.Equal(t, "1", currencies[2].BuyRate.String())
+	assert.Equal(t, "0.91", currencies[0].BuyRate.String())
+	assert.Equal(t, "0.91", currencies[0].SellRate.String())
+	assert.Equal(t, DefaultMapPrecision, currencies[1].Precision)
+
+	rate, err := CalculateRate(currencies, "USD", "USD", "NGN")
+	assert.NoError(t, err)
+	assert.Equal(t, "1500", rate.String())
+
+	// The base may be listed at par
+	currencies, err = NewCurrenciesFromMap("USD", map[string]float64{"USD": 1, "EUR": 0.91})
+	assert.NoError(t, err)
+	assert.Len(t, currencies, 2)
+
+	_, err = NewCurrenciesFromMap("USD", map[string]float64{"USD": 1.1})
+	assert.ErrorIs(t, err, ErrInvalidRate)
+	_, err = NewCurrenciesFromMap("USD", map[string]float64{"EUR": -1})
+	assert.ErrorIs(t, err, ErrInvalidRate)
+	_, err = NewCurrenciesFromMap("USD", map[string]float64{"eur": 1, "EUR": 1})
+	assert.ErrorContains(t, err, "more than once")
+	_, err = NewCurrenciesFromMap("USD", nil)
+	assert.ErrorIs(t, err, ErrEmptyCurrencySource)
+	_, err = NewCurrenciesFromMap("", map[string]float64{"EUR": 1})
+	assert.ErrorIs(t, err, ErrBaseCurrencyNotFound)
+}
+
+func TestNewCurrenciesFromStringMap(t *testing.T) {
+	currencies, err := NewCurrenciesFromStringMap("USD", map[string]string{"BTC": "0.000015234567890123456789"})
+	assert.NoError(t, err)
+	assert.Equal(t, "0.000015234567890123456789", currencies[0].BuyRate.String())
+
+	_, err = NewCurrenciesFromStringMap("USD", map[string]string{"BTC": "abc"})
+	assert.ErrorIs(t, err, ErrInvalidRate)
+	assert.ErrorContains(t, err, "BTC")
+}
+
+func TestNewCurrenciesFromDecimalMap(t *testing.T) {
+	currencies, err := NewCurrenciesFromDecimalMap("EUR", map[string]decimal.Decimal{"USD": decimal.RequireFromString("1.09")})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"EUR", "USD"}, []string{currencies[0].ISOCode, currencies[1].ISOCode})
+}
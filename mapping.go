@@ -0,0 +1,107 @@
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// FieldMapping names the source fields, or CSV columns, holding each part of
+// a Currency, so feeds such as {"ccy":"EUR","bid":0.9,"ask":0.95} can be
+// ingested without being transformed first. Empty names fall back to
+// DefaultFieldMapping. Names are matched case-insensitively.
+type FieldMapping struct {
+	ISO       string
+	Precision string
+	Buy       string
+	Sell      string
+}
+
+// DefaultFieldMapping is the mapping of Currency's own JSON names.
+var DefaultFieldMapping = FieldMapping{
+	ISO:       "isoCode",
+	Precision: "precision",
+	Buy:       "buyRate",
+	Sell:      "sellRate",
+}
+
+// withDefaults fills empty names from DefaultFieldMapping.
+func (m FieldMapping) withDefaults() FieldMapping {
+	if m.ISO == "" {
+		m.ISO = DefaultFieldMapping.ISO
+	}
+	if m.Precision == "" {
+		m.Precision = DefaultFieldMapping.Precision
+	}
+	if m.Buy == "" {
+		m.Buy = DefaultFieldMapping.Buy
+	}
+	if m.Sell == "" {
+		m.Sell = DefaultFieldMapping.Sell
+	}
+	return m
+}
+
+// decode reads a Currency from a JSON object using the mapped field names.
+func (m FieldMapping) decode(data []byte) (Currency, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return Currency{}, err
+	}
+
+	get := func(name string) json.RawMessage {
+		if v, ok := fields[name]; ok {
+			return v
+		}
+		for key, v := range fields {
+			if strings.EqualFold(key, name) {
+				return v
+			}
+		}
+		return nil
+	}
+
+	raw := currencyJSON{BuyRate: get(m.Buy), SellRate: get(m.Sell)}
+	if v := get(m.ISO); v != nil {
+		if err := json.Unmarshal(v, &raw.ISOCode); err != nil {
+			return Currency{}, fmt.Errorf("%s: %w", m.ISO, err)
+		}
+	}
+	if v := get(m.Precision); v != nil {
+		if err := json.Unmarshal(v, &raw.Precision); err != nil {
+			return Currency{}, fmt.Errorf("%w: %s: %v", ErrInvalidPrecision, raw.ISOCode, err)
+		}
+	}
+	return raw.currency()
+}
+
+// NewCurrenciesWithMapping is NewCurrencies reading the fields named by
+// mapping from each entry's JSON encoding. Converter struct tags are ignored.
+func NewCurrenciesWithMapping[T any](sourceRates []T, mapping FieldMapping) ([]Currency, error) {
+	mapping = mapping.withDefaults()
+
+	currencies := make([]Currency, 0, len(sourceRates))
+	for i := range sourceRates {
+		b, err := json.Marshal(sourceRates[i])
+		if err != nil {
+			return nil, err
+		}
+		c, err := mapping.decode(b)
+		if err != nil {
+			return nil, err
+		}
+		currencies = append(currencies, c)
+	}
+
+	if err := checkIngested(currencies); err != nil {
+		return nil, err
+	}
+	return currencies, nil
+}
+
+// NewCurrenciesFromCSVWithMapping is NewCurrenciesFromCSV with the header
+// columns named by mapping.
+func NewCurrenciesFromCSVWithMapping(r io.Reader, mapping FieldMapping) ([]Currency, error) {
+	return readCSV(r, mapping.withDefaults())
+}
@@ -0,0 +1,63 @@
+package converter
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCurrenciesWithMapping(t *testing.T) {
+	mapping := FieldMapping{ISO: "ccy", Buy: "bid", Sell: "ask"}
+
+	currencies, err := NewCurrenciesWithMapping([]json.RawMessage{
+		json.RawMessage(`{"ccy":"USD","precision":2,"bid":1,"ask":"1"}`),
+		json.RawMessage(`{"CCY":"EUR","precision":2,"Bid":0.90000000000000000001,"ask":"0.95"}`),
+	}, mapping)
+	assert.NoError(t, err)
+	assert.Equal(t, "EUR", currencies[1].ISOCode)
+	assert.Equal(t, "0.90000000000000000001", currencies[1].BuyRate.String())
+	assert.Equal(t, "0.95", currencies[1].SellRate.String())
+
+	type bankRate struct {
+		Currency string  `json:"ccy"`
+		Bid      float64 `json:"bid"`
+		Ask      float64 `json:"ask"`
+	}
+	currencies, err = NewCurrenciesWithMapping([]bankRate{{"NGN", 1500, 1550}}, mapping)
+	assert.NoError(t, err)
+	assert.Equal(t, "1550", currencies[0].SellRate.String())
+
+	// Default names still apply to unmapped fields.
+	currencies, err = NewCurrenciesWithMapping([]json.RawMessage{
+		json.RawMessage(`{"isoCode":"USD","buyRate":1,"sellRate":1}`),
+	}, FieldMapping{})
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", currencies[0].ISOCode)
+
+	_, err = NewCurrenciesWithMapping([]json.RawMessage{json.RawMessage(`{"code":"USD"}`)}, mapping)
+	assert.ErrorIs(t, err, ErrMissingISOCode)
+
+	_, err = NewCurrenciesWithMapping([]json.RawMessage{json.RawMessage(`{"ccy":"BTC","bid":"x"}`)}, mapping)
+	assert.ErrorIs(t, err, ErrInvalidRate)
+	assert.ErrorContains(t, err, "BTC buy rate")
+
+	_, err = NewCurrenciesWithMapping([]json.RawMessage{json.RawMessage(`{"ccy":"USD","precision":"2"}`)}, mapping)
+	assert.ErrorIs(t, err, ErrInvalidPrecision)
+
+	_, err = NewCurrenciesWithMapping([]bankRate{}, mapping)
+	assert.Equal(t, ErrEmptyCurrencySource, err)
+}
+
+func TestNewCurrenciesFromCSVWithMapping(t *testing.T) {
+	mapping := FieldMapping{ISO: "ccy", Precision: "dp", Buy: "bid", Sell: "ask"}
+
+	currencies, err := NewCurrenciesFromCSVWithMapping(strings.NewReader("CCY,dp,Bid,Ask\neur,2,0.9,0.95\n"), mapping)
+	assert.NoError(t, err)
+	assert.Equal(t, "EUR", currencies[0].ISOCode)
+	assert.Equal(t, "0.9", currencies[0].BuyRate.String())
+
+	_, err = NewCurrenciesFromCSVWithMapping(strings.NewReader("isoCode,precision,buyRate,sellRate\nEUR,2,0.9,0.95\n"), mapping)
+	assert.EqualError(t, err, "csv: missing column ccy")
+}
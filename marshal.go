@@ -0,0 +1,37 @@
+package converter
+
+import "encoding/json"
+
+// quoteJSON has the fields of Quote without its methods, so the JSON
+// encoding stays an object even though Quote is a TextMarshaler.
+type quoteJSON Quote
+
+// MarshalJSON implements json.Marshaler.
+func (q Quote) MarshalJSON() ([]byte, error) {
+	return json.Marshal(quoteJSON(q))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (q *Quote) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, (*quoteJSON)(q))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (q Quote) MarshalText() ([]byte, error) {
+	return q.MarshalJSON()
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (q *Quote) UnmarshalText(text []byte) error {
+	return q.UnmarshalJSON(text)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (q Quote) MarshalBinary() ([]byte, error) {
+	return q.MarshalJSON()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (q *Quote) UnmarshalBinary(data []byte) error {
+	return q.UnmarshalJSON(data)
+}
@@ -0,0 +1,55 @@
+package converter
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuote_Marshalers(t *testing.T) {
+	q := Quote{
+		ID:             "01HKA0B5R8Z3J2XQW7N6T4M1C9",
+		Reference:      "7K3M-9XPA",
+		BaseCurrency:   "USD",
+		FromCurrency:   "USD",
+		FromAmount:     decimal.NewFromInt(100),
+		Fee:            decimal.NewFromInt(5),
+		AmountToDeduct: decimal.NewFromInt(105),
+		Rate:           decimal.RequireFromString("0.95"),
+		ToCurrency:     "EUR",
+		FinalAmount:    decimal.NewFromInt(95),
+		Date:           time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	// JSON stays an object with the documented field names
+	b, err := json.Marshal(q)
+	assert.NoError(t, err)
+	var fields map[string]any
+	assert.NoError(t, json.Unmarshal(b, &fields))
+	assert.Equal(t, "EUR", fields["toCurrency"])
+	assert.Equal(t, "95", fields["totalAmount"])
+
+	// Text round trip
+	text, err := q.MarshalText()
+	assert.NoError(t, err)
+	var fromText Quote
+	assert.NoError(t, fromText.UnmarshalText(text))
+	assert.Equal(t, q.ID, fromText.ID)
+	assert.Equal(t, q.Rate.String(), fromText.Rate.String())
+
+	// Binary round trip through gob, as used by caches
+	var buf bytes.Buffer
+	assert.NoError(t, gob.NewEncoder(&buf).Encode(&q))
+	var fromGob Quote
+	assert.NoError(t, gob.NewDecoder(&buf).Decode(&fromGob))
+	assert.Equal(t, q.FinalAmount.String(), fromGob.FinalAmount.String())
+	assert.True(t, q.Date.Equal(fromGob.Date))
+
+	// Invalid input
+	assert.Error(t, new(Quote).UnmarshalBinary([]byte("{")))
+}
@@ -0,0 +1,102 @@
+package converter
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// RateMatrix holds the rate of every pair of a table's currencies, for
+// downstream caches that pull complete tables rather than pricing pair by
+// pair.
+type RateMatrix struct {
+	Base string `json:"base"`
+	// Currencies lists the ISO codes of the matrix, sorted.
+	Currencies []string `json:"currencies"`
+	// Rates holds the rate from one currency to another as
+	// Rates[from][to], the one currency to itself included.
+	Rates     map[string]map[string]decimal.Decimal `json:"rates"`
+	Version   uint64                                `json:"version"`
+	UpdatedAt time.Time                             `json:"updatedAt"`
+}
+
+// ExportMatrix prices every pair of the table's currencies against base, as
+// CalculateRate does, from one consistent view of the table: the matrix
+// records the table's version and update time. It fails if any pair cannot
+// be priced.
+func (c *Currencies) ExportMatrix(base string) (*RateMatrix, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if len(c.items) == 0 {
+		return nil, ErrEmptyCurrencySource
+	}
+	b, err := FindCurrency(c.items, base)
+	if err != nil {
+		return nil, ErrBaseCurrencyNotFound
+	}
+
+	m := &RateMatrix{
+		Base:       b.ISOCode,
+		Currencies: make([]string, len(c.items)),
+		Rates:      make(map[string]map[string]decimal.Decimal, len(c.items)),
+		Version:    c.version,
+		UpdatedAt:  c.updatedAt,
+	}
+	for i, from := range c.items {
+		m.Currencies[i] = from.ISOCode
+		row := make(map[string]decimal.Decimal, len(c.items))
+		for _, to := range c.items {
+			rate, err := c.calculateRate(base, from.ISOCode, to.ISOCode)
+			if err != nil {
+				return nil, fmt.Errorf("price %s/%s: %w", from.ISOCode, to.ISOCode, err)
+			}
+			row[to.ISOCode] = rate
+		}
+		m.Rates[from.ISOCode] = row
+	}
+	return m, nil
+}
+
+// Rate returns the rate from one currency to another, and whether the
+// matrix holds it. Codes are matched exactly.
+func (m *RateMatrix) Rate(from, to string) (decimal.Decimal, bool) {
+	rate, ok := m.Rates[from][to]
+	return rate, ok
+}
+
+// WriteJSON writes the matrix as JSON, with keys sorted so the same matrix
+// always produces the same bytes.
+func (m *RateMatrix) WriteJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(m)
+}
+
+// WriteCSV writes the matrix as CSV: a header of "from" and the currencies,
+// then one row per source currency with its rate to each currency.
+func (m *RateMatrix) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(append([]string{"from"}, m.Currencies...)); err != nil {
+		return err
+	}
+	for _, from := range m.Currencies {
+		record := make([]string, 0, len(m.Currencies)+1)
+		record = append(record, from)
+		for _, to := range m.Currencies {
+			rate, ok := m.Rate(from, to)
+			if !ok {
+				record = append(record, "")
+				continue
+			}
+			record = append(record, rate.String())
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,61 @@
+package converter
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurrencies_ExportMatrix(t *testing.T) {
+	at := time.Date(2024, 3, 28, 12, 0, 0, 0, time.UTC)
+	table := NewTable(nil, WithDivision(Division{Precision: 4, Rounding: RoundHalfUp}))
+	table.Update(testCurrencies(), at)
+
+	m, err := table.ExportMatrix("usd")
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", m.Base)
+	assert.Equal(t, []string{"EUR", "NGN", "USD"}, m.Currencies)
+	assert.Equal(t, table.Version(), m.Version)
+	assert.True(t, at.Equal(m.UpdatedAt))
+
+	for _, from := range m.Currencies {
+		for _, to := range m.Currencies {
+			want, err := table.CalculateRate("USD", from, to)
+			assert.NoError(t, err)
+			got, ok := m.Rate(from, to)
+			assert.True(t, ok)
+			assert.Equal(t, want.String(), got.String(), "%s/%s", from, to)
+		}
+	}
+	_, ok := m.Rate("USD", "GBP")
+	assert.False(t, ok)
+
+	var csv bytes.Buffer
+	assert.NoError(t, m.WriteCSV(&csv))
+	assert.Equal(t, `from,EUR,NGN,USD
+EUR,1,1722.205,1.1111
+NGN,0.000665,1,0.0007
+USD,0.95,1550,1
+`, csv.String())
+
+	var js bytes.Buffer
+	assert.NoError(t, m.WriteJSON(&js))
+	var decoded RateMatrix
+	assert.NoError(t, json.Unmarshal(js.Bytes(), &decoded))
+	assert.Equal(t, m.Currencies, decoded.Currencies)
+	assert.Equal(t, "1722.205", decoded.Rates["EUR"]["NGN"].String())
+
+	_, err = table.ExportMatrix("GBP")
+	assert.ErrorIs(t, err, ErrBaseCurrencyNotFound)
+	_, err = NewTable(nil).ExportMatrix("USD")
+	assert.ErrorIs(t, err, ErrEmptyCurrencySource)
+
+	rates := testCurrencies()
+	rates[1].BuyRate = decimal.Zero
+	_, err = NewTable(rates).ExportMatrix("USD")
+	assert.ErrorIs(t, err, ErrInvalidRate)
+}
@@ -0,0 +1,138 @@
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Merge errors
+var (
+	ErrCurrencyConflict = errors.New("currency conflict")
+)
+
+// ConflictStrategy decides which entry Merge keeps when tables quote the same
+// currency differently. Identical entries, such as the base currency at par
+// in every table, are not conflicts.
+type ConflictStrategy int
+
+// Conflict strategies
+const (
+	// MergeLastWins keeps the entry from the last table quoting the currency.
+	MergeLastWins ConflictStrategy = iota
+	// MergeBestRate keeps the entry with the narrowest spread between its buy
+	// and sell rates, the most competitive quote. Ties keep the earlier entry.
+	MergeBestRate
+	// MergeErrorOnConflict fails with ErrCurrencyConflict.
+	MergeErrorOnConflict
+)
+
+// String returns the name of the strategy.
+func (s ConflictStrategy) String() string {
+	switch s {
+	case MergeLastWins:
+		return "last-wins"
+	case MergeBestRate:
+		return "best-rate"
+	case MergeErrorOnConflict:
+		return "error-on-conflict"
+	default:
+		return "unknown"
+	}
+}
+
+// Merge returns a new table combining c with others, e.g. fiat and crypto
+// tables from different providers, resolving currencies quoted by more than
+// one table with strategy. All tables must quote rates against the same base.
+// The result is as recent as the most recently updated table and has c's
+// division settings. None of the tables is modified.
+func (c *Currencies) Merge(strategy ConflictStrategy, others ...*Currencies) (*Currencies, error) {
+	if strategy < MergeLastWins || strategy > MergeErrorOnConflict {
+		return nil, fmt.Errorf("unknown conflict strategy %d", strategy)
+	}
+
+	first := c.Snapshot()
+	items := append([]Currency(nil), first.Currencies...)
+	updatedAt := first.TakenAt
+
+	position := make(map[string]int, len(items))
+	for i, item := range items {
+		position[strings.ToUpper(item.ISOCode)] = i
+	}
+
+	for _, other := range others {
+		s := other.Snapshot()
+		if s.TakenAt.After(updatedAt) {
+			updatedAt = s.TakenAt
+		}
+
+		for _, item := range s.Currencies {
+			code := strings.ToUpper(item.ISOCode)
+			i, ok := position[code]
+			if !ok {
+				position[code] = len(items)
+				items = append(items, item)
+				continue
+			}
+			if sameCurrency(items[i], item) {
+				continue
+			}
+
+			switch strategy {
+			case MergeLastWins:
+				items[i] = item
+			case MergeBestRate:
+				if spread(item).LessThan(spread(items[i])) {
+					items[i] = item
+				}
+			default:
+				return nil, fmt.Errorf("%w: %s is quoted differently", ErrCurrencyConflict, code)
+			}
+		}
+	}
+
+	return c.derive(items, updatedAt), nil
+}
+
+// derive returns a new table holding items with the settings of c. Its
+// next-day book is c's, restricted to the currencies of items so a
+// filtered table cannot quote the others after a cut-off.
+func (c *Currencies) derive(items []Currency, updatedAt time.Time) *Currencies {
+	c.mu.RLock()
+	derived := &Currencies{
+		division:     c.division,
+		exact:        c.exact,
+		closedMarket: c.closedMarket,
+		closes:       maps.Clone(c.closes),
+		ttlPolicy:    c.ttlPolicy,
+		compliance:   c.compliance,
+		spreadPolicy: c.spreadPolicy,
+		pegs:         c.pegs,
+		sandbox:      c.sandbox,
+		pins:         c.pins,
+	}
+	if c.nextDay != nil {
+		derived.nextDay = slices.DeleteFunc(slices.Clone(c.nextDay), func(item Currency) bool {
+			return !slices.ContainsFunc(items, func(kept Currency) bool { return strings.EqualFold(kept.ISOCode, item.ISOCode) })
+		})
+	}
+	degraded := c.degraded
+	c.mu.RUnlock()
+
+	derived.update(items, updatedAt, degraded)
+	return derived
+}
+
+// spread returns the relative difference between a currency's buy and sell
+// rates. A currency without a buy rate has an unbounded spread.
+func spread(c Currency) decimal.Decimal {
+	if !c.BuyRate.IsPositive() {
+		return decimal.New(1, maxDigits)
+	}
+	return c.SellRate.Sub(c.BuyRate).Abs().Div(c.BuyRate)
+}
@@ -0,0 +1,64 @@
+package converter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurrencies_Merge(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	fiat := NewTable(nil)
+	fiat.Update(testCurrencies(), now)
+
+	crypto := NewTable(nil)
+	crypto.Update([]Currency{
+		{ISOCode: "usd", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+		{ISOCode: "BTC", Precision: 8, BuyRate: decimal.RequireFromString("0.000015"), SellRate: decimal.RequireFromString("0.0000152")},
+		{ISOCode: "NGN", Precision: 2, BuyRate: decimal.NewFromInt(1520), SellRate: decimal.NewFromInt(1530)},
+	}, now.Add(time.Minute))
+
+	merged, err := fiat.Merge(MergeLastWins, crypto)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"BTC", "EUR", "NGN", "USD"}, codes(merged.List()))
+	assert.Equal(t, now.Add(time.Minute), merged.UpdatedAt())
+	ngn, _ := merged.FindCurrency("NGN")
+	assert.Equal(t, "1520", ngn.BuyRate.String())
+
+	// The narrower spread wins whichever table comes last.
+	wide := NewTable([]Currency{{ISOCode: "NGN", Precision: 2, BuyRate: decimal.NewFromInt(1400), SellRate: decimal.NewFromInt(1600)}})
+	merged, err = crypto.Merge(MergeBestRate, fiat, wide)
+	assert.NoError(t, err)
+	ngn, _ = merged.FindCurrency("NGN")
+	assert.Equal(t, "1520", ngn.BuyRate.String())
+
+	_, err = fiat.Merge(MergeErrorOnConflict, crypto)
+	assert.ErrorIs(t, err, ErrCurrencyConflict)
+	assert.ErrorContains(t, err, "NGN")
+
+	// Identical entries are not conflicts.
+	merged, err = fiat.Merge(MergeErrorOnConflict, NewTable(testCurrencies()[:2]))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, merged.Len())
+
+	_, err = fiat.Merge(ConflictStrategy(42), crypto)
+	assert.Error(t, err)
+
+	// Inputs are untouched and settings are kept.
+	assert.Equal(t, 3, fiat.Len())
+	assert.Equal(t, "1500", fiat.List()[1].BuyRate.String())
+	div := Division{Precision: 4, Rounding: RoundDown}
+	merged, err = NewTable(testCurrencies(), WithDivision(div)).Merge(MergeLastWins)
+	assert.NoError(t, err)
+	assert.Equal(t, &div, merged.division)
+}
+
+func codes(currencies []Currency) []string {
+	var result []string
+	for _, c := range currencies {
+		result = append(result, c.ISOCode)
+	}
+	return result
+}
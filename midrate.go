@@ -0,0 +1,55 @@
+package converter
+
+import (
+	"context"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// TwoWay turns mid-only rates into two-way rates: each currency's buy rate
+// is its mid raised by its default spread (see SpreadPolicy.Currencies) and
+// its sell rate the mid lowered by it, in the table's favour. The mid is the
+// average of the buy and sell rates, so feeds setting both to the mid, such
+// as NewCurrenciesFromMap, work as is. The base currency is left unchanged,
+// as are currencies without a spread. Pair spreads are not used, since they
+// apply at calculation time.
+func (p SpreadPolicy) TwoWay(base string, mids []Currency) []Currency {
+	one := decimal.NewFromInt(1)
+	half := decimal.New(5, -1)
+
+	currencies := make([]Currency, len(mids))
+	for i, c := range mids {
+		spread := decimal.Zero
+		for code, s := range p.Currencies {
+			if strings.EqualFold(code, c.ISOCode) {
+				spread = s
+			}
+		}
+		if !strings.EqualFold(c.ISOCode, base) && !spread.IsZero() {
+			mid := c.BuyRate.Add(c.SellRate).Mul(half)
+			c.BuyRate = mid.Mul(one.Add(spread))
+			c.SellRate = mid.Mul(one.Sub(spread))
+		}
+		currencies[i] = c
+	}
+	return currencies
+}
+
+// TwoWayProvider is a RateProvider for providers that only publish mid
+// rates: it spreads the mids of Provider into two-way rates with
+// Spreads.TwoWay.
+type TwoWayProvider struct {
+	Provider RateProvider
+	Base     string
+	Spreads  SpreadPolicy
+}
+
+// FetchRates implements RateProvider.
+func (p *TwoWayProvider) FetchRates(ctx context.Context) ([]Currency, error) {
+	mids, err := p.Provider.FetchRates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return p.Spreads.TwoWay(p.Base, mids), nil
+}
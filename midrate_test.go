@@ -0,0 +1,65 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpreadPolicy_TwoWay(t *testing.T) {
+	mids, err := NewCurrenciesFromStringMap("USD", map[string]string{"NGN": "1500", "EUR": "0.9", "GBP": "0.8"})
+	assert.NoError(t, err)
+	policy := SpreadPolicy{Currencies: map[string]decimal.Decimal{
+		"ngn": decimal.NewFromFloat(0.01),
+		"EUR": decimal.NewFromFloat(0.02),
+		"USD": decimal.NewFromFloat(0.5),
+	}}
+
+	currencies := policy.TwoWay("usd", mids)
+	rates := map[string][2]string{}
+	for _, c := range currencies {
+		rates[c.ISOCode] = [2]string{c.BuyRate.String(), c.SellRate.String()}
+	}
+	assert.Equal(t, map[string][2]string{
+		"USD": {"1", "1"},
+		"NGN": {"1515", "1485"},
+		"EUR": {"0.918", "0.882"},
+		"GBP": {"0.8", "0.8"},
+	}, rates)
+
+	// The mids are not modified, and two-way feeds are spread around their
+	// midpoint.
+	for _, c := range mids {
+		assert.True(t, c.BuyRate.Equal(c.SellRate), c.ISOCode)
+	}
+	twoWay := policy.TwoWay("USD", []Currency{{ISOCode: "NGN", BuyRate: decimal.NewFromInt(1490), SellRate: decimal.NewFromInt(1510)}})
+	assert.Equal(t, "1515", twoWay[0].BuyRate.String())
+}
+
+func TestTwoWayProvider(t *testing.T) {
+	provider := &TwoWayProvider{
+		Provider: RateProviderFunc(func(ctx context.Context) ([]Currency, error) {
+			return NewCurrenciesFromStringMap("USD", map[string]string{"NGN": "1500"})
+		}),
+		Base:    "USD",
+		Spreads: SpreadPolicy{Currencies: map[string]decimal.Decimal{"NGN": decimal.NewFromFloat(0.01)}},
+	}
+
+	table := NewTable(nil)
+	refresher, err := NewRefresher(table, provider, 1)
+	assert.NoError(t, err)
+	assert.NoError(t, refresher.Refresh(context.Background()))
+
+	rate, err := table.CalculateRate("USD", "USD", "NGN")
+	assert.NoError(t, err)
+	assert.Equal(t, "1485", rate.String())
+
+	failing := &TwoWayProvider{Provider: RateProviderFunc(func(ctx context.Context) ([]Currency, error) {
+		return nil, errors.New("down")
+	})}
+	_, err = failing.FetchRates(context.Background())
+	assert.EqualError(t, err, "down")
+}
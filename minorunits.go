@@ -0,0 +1,121 @@
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Amount errors
+var (
+	ErrFractionalAmount = errors.New("amount finer than the currency's precision")
+	ErrUnknownCurrency  = errors.New("unknown currency")
+)
+
+// zeroDecimalCurrencies are the ISO 4217 currencies without minor units.
+var zeroDecimalCurrencies = map[string]bool{
+	"BIF": true, "CLP": true, "DJF": true, "GNF": true, "ISK": true,
+	"JPY": true, "KMF": true, "KRW": true, "PYG": true, "RWF": true,
+	"UGX": true, "UYI": true, "VND": true, "VUV": true, "XAF": true,
+	"XOF": true, "XPF": true,
+}
+
+// threeDecimalCurrencies are the ISO 4217 currencies with a thousandth minor
+// unit, e.g. the fils of the Kuwaiti dinar.
+var threeDecimalCurrencies = map[string]bool{
+	"BHD": true, "IQD": true, "JOD": true, "KWD": true, "LYD": true,
+	"OMR": true, "TND": true,
+}
+
+// IsZeroDecimal reports whether an ISO currency has no minor units, e.g.
+// JPY, KRW or VND.
+func IsZeroDecimal(code string) bool {
+	return zeroDecimalCurrencies[strings.ToUpper(strings.TrimSpace(code))]
+}
+
+// CheckAmount returns an ErrFractionalAmount error if amount has more
+// decimal places than the currency's precision, e.g. 100.5 JPY.
+func (c Currency) CheckAmount(amount decimal.Decimal) error {
+	if !amount.Equal(amount.Truncate(int32(c.Precision))) {
+		return fmt.Errorf("%w: %s %s has more than %d decimal places", ErrFractionalAmount, amount, c.ISOCode, c.Precision)
+	}
+	return nil
+}
+
+// RoundAmount rounds amount to the currency's precision, e.g. to whole yen.
+func (c Currency) RoundAmount(amount decimal.Decimal, mode RoundingMode) decimal.Decimal {
+	return mode.Round(amount, int32(c.Precision))
+}
+
+// IsThreeDecimal reports whether an ISO currency has a thousandth minor unit,
+// e.g. BHD, KWD, OMR or TND.
+func IsThreeDecimal(code string) bool {
+	return threeDecimalCurrencies[strings.ToUpper(strings.TrimSpace(code))]
+}
+
+// MinorUnits returns the number of decimal places of a currency's minor
+// unit: 0 for zero-decimal and 3 for three-decimal currencies, the native
+// unit's for well-known crypto assets (see DefaultAssetDecimals) and 2 for
+// the other currencies in the embedded metadata (see LookupCurrencyInfo). It
+// reports false for other codes.
+func MinorUnits(code string) (int, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	switch {
+	case zeroDecimalCurrencies[code]:
+		return 0, true
+	case threeDecimalCurrencies[code]:
+		return 3, true
+	}
+	if units, ok := defaultAssetDecimals[code]; ok {
+		return units, true
+	}
+	if _, ok := currencyInfos[code]; ok {
+		return 2, true
+	}
+	return 0, false
+}
+
+// isoPrecision returns c with the ISO precision of zero- and three-decimal
+// currencies, which feeds often get wrong by assuming two decimals.
+func isoPrecision(c Currency) Currency {
+	switch {
+	case IsZeroDecimal(c.ISOCode):
+		c.Precision = 0
+	case IsThreeDecimal(c.ISOCode):
+		c.Precision = 3
+	}
+	return c
+}
+
+// ToMinorUnits converts an amount of a currency to a whole number of its
+// minor units, e.g. 1.234 KWD to 1234 fils. It returns an ErrUnknownCurrency
+// error for currencies without known minor units and an ErrFractionalAmount
+// error for amounts finer than the minor unit.
+func ToMinorUnits(amount decimal.Decimal, code string) (decimal.Decimal, error) {
+	units, ok := MinorUnits(code)
+	if !ok {
+		return decimal.Zero, fmt.Errorf("%w: %s", ErrUnknownCurrency, code)
+	}
+	c := Currency{ISOCode: strings.ToUpper(code), Precision: units}
+	if err := c.CheckAmount(amount); err != nil {
+		return decimal.Zero, err
+	}
+	return amount.Shift(int32(units)), nil
+}
+
+// FromMinorUnits converts a whole number of a currency's minor units to an
+// amount, e.g. 1234 fils to 1.234 KWD. It returns an ErrUnknownCurrency error
+// for currencies without known minor units and an ErrFractionalAmount error
+// if minor is not whole.
+func FromMinorUnits(minor decimal.Decimal, code string) (decimal.Decimal, error) {
+	units, ok := MinorUnits(code)
+	if !ok {
+		return decimal.Zero, fmt.Errorf("%w: %s", ErrUnknownCurrency, code)
+	}
+	if !minor.IsInteger() {
+		return decimal.Zero, fmt.Errorf("%w: %s minor units of %s", ErrFractionalAmount, minor, code)
+	}
+	return minor.Shift(-int32(units)), nil
+}
@@ -0,0 +1,108 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsZeroDecimal(t *testing.T) {
+	assert.True(t, IsZeroDecimal("JPY"))
+	assert.True(t, IsZeroDecimal(" krw"))
+	assert.True(t, IsZeroDecimal("VND"))
+	assert.False(t, IsZeroDecimal("USD"))
+}
+
+func TestCurrency_CheckAmount(t *testing.T) {
+	jpy := Currency{ISOCode: "JPY", Precision: 0}
+	assert.NoError(t, jpy.CheckAmount(decimal.NewFromInt(100)))
+	assert.NoError(t, jpy.CheckAmount(decimal.RequireFromString("100.000")))
+	assert.ErrorIs(t, jpy.CheckAmount(decimal.RequireFromString("100.5")), ErrFractionalAmount)
+
+	usd := Currency{ISOCode: "USD", Precision: 2}
+	assert.NoError(t, usd.CheckAmount(decimal.RequireFromString("1.25")))
+	assert.ErrorIs(t, usd.CheckAmount(decimal.RequireFromString("1.255")), ErrFractionalAmount)
+}
+
+func TestCurrency_RoundAmount(t *testing.T) {
+	jpy := Currency{ISOCode: "JPY", Precision: 0}
+	assert.Equal(t, "101", jpy.RoundAmount(decimal.RequireFromString("100.5"), RoundHalfUp).String())
+	assert.Equal(t, "100", jpy.RoundAmount(decimal.RequireFromString("100.5"), RoundHalfEven).String())
+	assert.Equal(t, "100", jpy.RoundAmount(decimal.RequireFromString("100.9"), RoundDown).String())
+}
+
+func TestNewQuote_ZeroDecimal(t *testing.T) {
+	rateSource := []Currency{
+		{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+		{ISOCode: "JPY", Precision: 0, BuyRate: decimal.NewFromInt(150), SellRate: decimal.RequireFromString("149.5")},
+	}
+
+	// Whole yen in, whole yen out, and the fee is rounded up to a yen.
+	quote, err := NewQuote(rateSource, "USD", "JPY", "USD", decimal.NewFromInt(1000), decimal.RequireFromString("10.2"))
+	assert.NoError(t, err)
+	assert.Equal(t, "11", quote.Fee.String())
+	assert.Equal(t, "1011", quote.AmountToDeduct.String())
+
+	quote, err = NewQuote(rateSource, "USD", "USD", "JPY", decimal.RequireFromString("10.01"), decimal.Zero)
+	assert.NoError(t, err)
+	assert.Equal(t, "1497", quote.FinalAmount.String())
+	assert.True(t, quote.FinalAmount.IsInteger())
+
+	_, err = NewQuote(rateSource, "USD", "JPY", "USD", decimal.RequireFromString("1000.5"), decimal.Zero)
+	assert.ErrorIs(t, err, ErrFractionalAmount)
+}
+
+func TestMinorUnits(t *testing.T) {
+	testCases := map[string]int{"USD": 2, "jpy": 0, "KWD": 3, "BHD": 3, "OMR": 3, "TND": 3, "BTC": 8, "ETH": 18}
+	for code, want := range testCases {
+		units, ok := MinorUnits(code)
+		assert.True(t, ok, code)
+		assert.Equal(t, want, units, code)
+	}
+	_, ok := MinorUnits("XXX")
+	assert.False(t, ok)
+	assert.True(t, IsThreeDecimal("kwd"))
+	assert.False(t, IsThreeDecimal("USD"))
+}
+
+func TestToMinorUnits(t *testing.T) {
+	fils, err := ToMinorUnits(decimal.RequireFromString("1.234"), "KWD")
+	assert.NoError(t, err)
+	assert.Equal(t, "1234", fils.String())
+
+	yen, err := ToMinorUnits(decimal.NewFromInt(500), "JPY")
+	assert.NoError(t, err)
+	assert.Equal(t, "500", yen.String())
+
+	_, err = ToMinorUnits(decimal.RequireFromString("1.2345"), "KWD")
+	assert.ErrorIs(t, err, ErrFractionalAmount)
+	_, err = ToMinorUnits(decimal.NewFromInt(1), "XXX")
+	assert.ErrorIs(t, err, ErrUnknownCurrency)
+
+	amount, err := FromMinorUnits(decimal.NewFromInt(1234), "OMR")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.234", amount.String())
+
+	_, err = FromMinorUnits(decimal.RequireFromString("1.5"), "USD")
+	assert.ErrorIs(t, err, ErrFractionalAmount)
+	_, err = FromMinorUnits(decimal.NewFromInt(1), "XXX")
+	assert.ErrorIs(t, err, ErrUnknownCurrency)
+}
+
+func TestNewQuote_ThreeDecimal(t *testing.T) {
+	// The feed gives KWD two decimals; quotes use its three.
+	rateSource := []Currency{
+		{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+		{ISOCode: "KWD", Precision: 2, BuyRate: decimal.RequireFromString("0.3075"), SellRate: decimal.RequireFromString("0.3071")},
+	}
+
+	quote, err := NewQuote(rateSource, "USD", "USD", "KWD", decimal.NewFromInt(10), decimal.Zero)
+	assert.NoError(t, err)
+	assert.Equal(t, "3.071", quote.FinalAmount.String())
+
+	quote, err = NewQuote(rateSource, "USD", "KWD", "USD", decimal.RequireFromString("1.234"), decimal.RequireFromString("0.0101"))
+	assert.NoError(t, err)
+	assert.Equal(t, "0.011", quote.Fee.String())
+	assert.Equal(t, "1.245", quote.AmountToDeduct.String())
+}
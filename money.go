@@ -0,0 +1,71 @@
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Money errors
+var (
+	ErrInvalidMoney = errors.New("invalid money")
+)
+
+// Money is an amount in a specific currency. Its text form is the amount
+// followed by the ISO code, e.g. "100.5 USD".
+type Money struct {
+	Amount   decimal.Decimal
+	Currency string
+}
+
+// NewMoney creates a Money value.
+func NewMoney(amount decimal.Decimal, currency string) Money {
+	return Money{Amount: amount, Currency: strings.ToUpper(currency)}
+}
+
+// ParseMoney parses the text form of a Money value.
+func ParseMoney(s string) (Money, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidMoney, s)
+	}
+
+	amount, err := decimal.NewFromString(fields[0])
+	if err != nil || !saneDecimal(amount) {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidMoney, s)
+	}
+
+	return NewMoney(amount, fields[1]), nil
+}
+
+// String implements fmt.Stringer.
+func (m Money) String() string {
+	return m.Amount.String() + " " + m.Currency
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (m Money) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (m *Money) UnmarshalText(text []byte) error {
+	parsed, err := ParseMoney(string(text))
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (m Money) MarshalBinary() ([]byte, error) {
+	return m.MarshalText()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (m *Money) UnmarshalBinary(data []byte) error {
+	return m.UnmarshalText(data)
+}
@@ -0,0 +1,49 @@
+package converter
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMoney(t *testing.T) {
+	m, err := ParseMoney("100.50 usd")
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", m.Currency)
+	assert.True(t, decimal.RequireFromString("100.5").Equal(m.Amount))
+	assert.Equal(t, "100.5 USD", m.String())
+
+	for _, s := range []string{"", "100", "USD 100", "100 USD extra"} {
+		_, err := ParseMoney(s)
+		assert.ErrorIs(t, err, ErrInvalidMoney, s)
+	}
+}
+
+func TestMoney_Marshalers(t *testing.T) {
+	m := NewMoney(decimal.RequireFromString("0.000123456789012345"), "btc")
+
+	// Text round trip
+	text, err := m.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "0.000123456789012345 BTC", string(text))
+
+	var fromText Money
+	assert.NoError(t, fromText.UnmarshalText(text))
+	assert.Equal(t, m.String(), fromText.String())
+
+	// JSON uses the text form
+	b, err := json.Marshal(map[string]Money{"balance": m})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"balance":"0.000123456789012345 BTC"}`, string(b))
+
+	// Gob round trip through BinaryMarshaler
+	var buf bytes.Buffer
+	assert.NoError(t, gob.NewEncoder(&buf).Encode(m))
+	var fromGob Money
+	assert.NoError(t, gob.NewDecoder(&buf).Decode(&fromGob))
+	assert.Equal(t, m.String(), fromGob.String())
+}
@@ -0,0 +1,99 @@
+package converter
+
+import (
+	"fmt"
+
+	"github.com/otyang/converter/numeric"
+	"github.com/shopspring/decimal"
+)
+
+// DecimalArithmetic is the numeric.Arithmetic of shopspring decimals, with
+// which a numeric.Calculator's rates match CalculateRate's on plain tables,
+// those without currencies on their own bases.
+type DecimalArithmetic struct {
+	// Division rounds quotients; nil divides as CalculateRate does, with
+	// the global DivisionPrecision.
+	Division *Division
+}
+
+var _ numeric.Arithmetic[decimal.Decimal] = DecimalArithmetic{}
+
+// Parse implements numeric.Arithmetic.
+func (DecimalArithmetic) Parse(s string) (decimal.Decimal, error) {
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("%w: %q", numeric.ErrSyntax, s)
+	}
+	return d, nil
+}
+
+// Format implements numeric.Arithmetic.
+func (DecimalArithmetic) Format(n decimal.Decimal) string {
+	return n.String()
+}
+
+// One implements numeric.Arithmetic.
+func (DecimalArithmetic) One() decimal.Decimal {
+	return decimalOne
+}
+
+// Add implements numeric.Arithmetic.
+func (DecimalArithmetic) Add(a, b decimal.Decimal) (decimal.Decimal, error) {
+	return a.Add(b), nil
+}
+
+// Sub implements numeric.Arithmetic.
+func (DecimalArithmetic) Sub(a, b decimal.Decimal) (decimal.Decimal, error) {
+	return a.Sub(b), nil
+}
+
+// Mul implements numeric.Arithmetic.
+func (DecimalArithmetic) Mul(a, b decimal.Decimal) (decimal.Decimal, error) {
+	return a.Mul(b), nil
+}
+
+// Quo implements numeric.Arithmetic.
+func (d DecimalArithmetic) Quo(a, b decimal.Decimal) (decimal.Decimal, error) {
+	if b.IsZero() {
+		return decimal.Zero, numeric.ErrDivisionByZero
+	}
+	return divide(d.Division, a, b), nil
+}
+
+// Ceil implements numeric.Arithmetic.
+func (DecimalArithmetic) Ceil(n decimal.Decimal, places int32) (decimal.Decimal, error) {
+	return n.RoundCeil(places), nil
+}
+
+// Cmp implements numeric.Arithmetic.
+func (DecimalArithmetic) Cmp(a, b decimal.Decimal) int {
+	return a.Cmp(b)
+}
+
+// Sign implements numeric.Arithmetic.
+func (DecimalArithmetic) Sign(n decimal.Decimal) int {
+	return n.Sign()
+}
+
+// NumericRates converts currencies to rates in arith's numbers, for a
+// numeric.Calculator. A rate the backend cannot hold, such as one with more
+// decimal places than a numeric.Fixed scale, is an error. Currencies priced
+// against their own base are not supported: a Calculator has a single base.
+func NumericRates[N any](arith numeric.Arithmetic[N], currencies []Currency) ([]numeric.Rate[N], error) {
+	rates := make([]numeric.Rate[N], 0, len(currencies))
+	for _, c := range currencies {
+		if c.BaseISOCode != "" {
+			return nil, fmt.Errorf("%w: %s is priced against its own base %s", ErrInvalidRate, c.ISOCode, c.BaseISOCode)
+		}
+		buy, err := arith.Parse(c.BuyRate.String())
+		if err != nil {
+			return nil, fmt.Errorf("%s buy rate: %w", c.ISOCode, err)
+		}
+		sell, err := arith.Parse(c.SellRate.String())
+		if err != nil {
+			return nil, fmt.Errorf("%s sell rate: %w", c.ISOCode, err)
+		}
+		rates = append(rates, numeric.Rate[N]{Code: c.ISOCode, Precision: int32(c.Precision), Buy: buy, Sell: sell})
+	}
+	return rates, nil
+}
@@ -0,0 +1,204 @@
+package numeric
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// MaxFixedScale is the most decimal places Fixed holds: 10^18 is the
+// largest power of ten in an int64.
+const MaxFixedScale = 18
+
+// Fixed is fixed-point arithmetic on int64s holding Scale decimal places:
+// with Scale 2, 1550.25 is 155025, an amount in minor units. Products and
+// quotients are rounded half away from zero to Scale places; results
+// outside the int64 range are ErrOverflow.
+type Fixed struct {
+	Scale int32
+}
+
+var _ Arithmetic[int64] = Fixed{}
+
+// unit returns 10^Scale, the representation of 1.
+func (f Fixed) unit() int64 {
+	return pow10(f.scale())
+}
+
+// scale returns Scale clamped to the range Fixed supports.
+func (f Fixed) scale() int32 {
+	return min(max(f.Scale, 0), MaxFixedScale)
+}
+
+// pow10 returns 10^n for n from 0 to 18.
+func pow10(n int32) int64 {
+	p := int64(1)
+	for i := int32(0); i < n; i++ {
+		p *= 10
+	}
+	return p
+}
+
+// Parse implements Arithmetic. Digits beyond Scale are ErrPrecision unless
+// they are zeros.
+func (f Fixed) Parse(s string) (int64, error) {
+	text := strings.TrimSpace(s)
+	negative := strings.HasPrefix(text, "-")
+	if negative || strings.HasPrefix(text, "+") {
+		text = text[1:]
+	}
+	whole, frac, _ := strings.Cut(text, ".")
+	if whole == "" && frac == "" || !digits(whole) || !digits(frac) {
+		return 0, fmt.Errorf("%w: %q", ErrSyntax, s)
+	}
+	frac = strings.TrimRight(frac, "0")
+	if len(frac) > int(f.scale()) {
+		return 0, fmt.Errorf("%w: %q has %d decimal places, more than %d", ErrPrecision, s, len(frac), f.scale())
+	}
+	frac += strings.Repeat("0", int(f.scale())-len(frac))
+
+	significant := strings.TrimLeft(whole+frac, "0")
+	if significant == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(significant, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrOverflow, s)
+	}
+	if negative {
+		n = -n
+	}
+	return n, nil
+}
+
+// digits reports whether s holds only decimal digits.
+func digits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Format implements Arithmetic, without trailing zeros.
+func (f Fixed) Format(n int64) string {
+	sign := ""
+	u := uint64(n)
+	if n < 0 {
+		sign, u = "-", -u
+	}
+	s := strconv.FormatUint(u, 10)
+	scale := int(f.scale())
+	if scale == 0 {
+		return sign + s
+	}
+	if len(s) <= scale {
+		s = strings.Repeat("0", scale-len(s)+1) + s
+	}
+	whole, frac := s[:len(s)-scale], strings.TrimRight(s[len(s)-scale:], "0")
+	if frac == "" {
+		return sign + whole
+	}
+	return sign + whole + "." + frac
+}
+
+// One implements Arithmetic.
+func (f Fixed) One() int64 {
+	return f.unit()
+}
+
+// Add implements Arithmetic.
+func (f Fixed) Add(a, b int64) (int64, error) {
+	sum := a + b
+	if (sum > a) != (b > 0) {
+		return 0, ErrOverflow
+	}
+	return sum, nil
+}
+
+// Sub implements Arithmetic.
+func (f Fixed) Sub(a, b int64) (int64, error) {
+	diff := a - b
+	if (diff < a) != (b > 0) {
+		return 0, ErrOverflow
+	}
+	return diff, nil
+}
+
+// Mul implements Arithmetic.
+func (f Fixed) Mul(a, b int64) (int64, error) {
+	return mulDiv(a, b, f.unit())
+}
+
+// Quo implements Arithmetic.
+func (f Fixed) Quo(a, b int64) (int64, error) {
+	if b == 0 {
+		return 0, ErrDivisionByZero
+	}
+	return mulDiv(a, f.unit(), b)
+}
+
+// Ceil implements Arithmetic.
+func (f Fixed) Ceil(n int64, places int32) (int64, error) {
+	if places >= f.scale() {
+		return n, nil
+	}
+	step := pow10(f.scale() - max(places, 0))
+	rem := n % step
+	if rem <= 0 {
+		return n - rem, nil
+	}
+	return f.Add(n-rem, step)
+}
+
+// Cmp implements Arithmetic.
+func (f Fixed) Cmp(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// Sign implements Arithmetic.
+func (f Fixed) Sign(n int64) int {
+	return f.Cmp(n, 0)
+}
+
+// mulDiv returns a*b/c rounded half away from zero, computing the product
+// in 128 bits so it cannot overflow before the division.
+func mulDiv(a, b, c int64) (int64, error) {
+	negative := (a < 0) != (b < 0) != (c < 0)
+	hi, lo := bits.Mul64(abs(a), abs(b))
+	divisor := abs(c)
+	if hi >= divisor {
+		return 0, ErrOverflow
+	}
+	q, r := bits.Div64(hi, lo, divisor)
+	if r >= divisor-r {
+		if q == math.MaxUint64 {
+			return 0, ErrOverflow
+		}
+		q++
+	}
+	if q > math.MaxInt64 {
+		return 0, ErrOverflow
+	}
+	if negative {
+		return -int64(q), nil
+	}
+	return int64(q), nil
+}
+
+// abs returns |n| as a uint64, which holds even |math.MinInt64|.
+func abs(n int64) uint64 {
+	if n < 0 {
+		return -uint64(n)
+	}
+	return uint64(n)
+}
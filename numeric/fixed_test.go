@@ -0,0 +1,102 @@
+package numeric
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFixed_Parse(t *testing.T) {
+	f := Fixed{Scale: 2}
+
+	tests := []struct {
+		in   string
+		want int64
+	}{
+		{"1550.25", 155025},
+		{"-0.5", -50},
+		{"+3", 300},
+		{".75", 75},
+		{"12.300", 1230},
+		{"0.00", 0},
+	}
+	for _, tt := range tests {
+		n, err := f.Parse(tt.in)
+		assert.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, n, tt.in)
+	}
+
+	_, err := f.Parse("1.234")
+	assert.ErrorIs(t, err, ErrPrecision)
+	for _, in := range []string{"", ".", "1e3", "1,5", "--1"} {
+		_, err = f.Parse(in)
+		assert.ErrorIs(t, err, ErrSyntax, in)
+	}
+	_, err = f.Parse("92233720368547758.08")
+	assert.ErrorIs(t, err, ErrOverflow)
+}
+
+func TestFixed_Format(t *testing.T) {
+	f := Fixed{Scale: 4}
+	assert.Equal(t, "1550.25", f.Format(15502500))
+	assert.Equal(t, "-0.0005", f.Format(-5))
+	assert.Equal(t, "0", f.Format(0))
+	assert.Equal(t, "-922337203685477.5808", f.Format(math.MinInt64))
+	assert.Equal(t, "42", Fixed{}.Format(42))
+}
+
+func TestFixed_Arithmetic(t *testing.T) {
+	f := Fixed{Scale: 2}
+
+	n, err := f.Mul(150, 125) // 1.5 * 1.25 = 1.875
+	assert.NoError(t, err)
+	assert.Equal(t, int64(188), n)
+
+	n, err = f.Mul(-150, 125)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(-188), n)
+
+	n, err = f.Quo(100, 300) // 1 / 3
+	assert.NoError(t, err)
+	assert.Equal(t, int64(33), n)
+
+	_, err = f.Quo(100, 0)
+	assert.ErrorIs(t, err, ErrDivisionByZero)
+
+	_, err = f.Mul(math.MaxInt64, 200)
+	assert.ErrorIs(t, err, ErrOverflow)
+	_, err = f.Add(math.MaxInt64, 1)
+	assert.ErrorIs(t, err, ErrOverflow)
+	_, err = f.Sub(math.MinInt64, 1)
+	assert.ErrorIs(t, err, ErrOverflow)
+
+	n, err = f.Sub(100, 250)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(-150), n)
+
+	assert.Equal(t, -1, f.Cmp(1, 2))
+	assert.Equal(t, 1, f.Sign(5))
+	assert.Equal(t, int64(100), f.One())
+}
+
+func TestFixed_Ceil(t *testing.T) {
+	f := Fixed{Scale: 4}
+
+	tests := []struct {
+		n      int64
+		places int32
+		want   int64
+	}{
+		{12301, 2, 12400},
+		{12300, 2, 12300},
+		{-12399, 2, -12300},
+		{12345, 4, 12345},
+		{12345, 0, 20000},
+	}
+	for _, tt := range tests {
+		n, err := f.Ceil(tt.n, tt.places)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, n, "%d at %d places", tt.n, tt.places)
+	}
+}
@@ -0,0 +1,150 @@
+// Package numeric prices conversions with a pluggable arithmetic backend,
+// for consumers for whom shopspring/decimal is too heavy a dependency: it
+// imports only the standard library. Fixed computes with int64 fixed-point
+// numbers, such as minor units, and Rat with exact math/big rationals;
+// converter.DecimalArithmetic adapts shopspring/decimal, and another
+// decimal library only needs to implement Arithmetic.
+//
+// A Calculator is a standalone calculator for plain tables: every currency
+// quoted per unit of one base currency. It applies the base rules of
+// converter.CalculateRate, and with converter.DecimalArithmetic its rates
+// match CalculateRate's on such tables, but it knows nothing of the
+// converter's other pricing: currencies with their own bases, spreads,
+// pegs, sandbox pins or a table's Division. The converter itself keeps
+// computing with shopspring/decimal.
+package numeric
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Arithmetic errors
+var (
+	ErrSyntax         = errors.New("numeric: invalid number")
+	ErrPrecision      = errors.New("numeric: more decimal places than the backend holds")
+	ErrOverflow       = errors.New("numeric: overflow")
+	ErrDivisionByZero = errors.New("numeric: division by zero")
+)
+
+// Calculator errors
+var (
+	ErrUnknownCurrency      = errors.New("numeric: unknown currency")
+	ErrBaseCurrencyNotFound = errors.New("numeric: base currency not found")
+	ErrInvalidRate          = errors.New("numeric: invalid rate")
+)
+
+// Arithmetic is the arithmetic on numbers of type N a backend provides.
+// Operations that can fail in some backend, by overflowing or dividing by
+// zero, return an error.
+type Arithmetic[N any] interface {
+	// Parse parses a decimal number such as "-1550.25".
+	Parse(s string) (N, error)
+	// Format formats n as a decimal number.
+	Format(n N) string
+	One() N
+	Add(a, b N) (N, error)
+	Sub(a, b N) (N, error)
+	Mul(a, b N) (N, error)
+	// Quo divides a by b, rounded as the backend does.
+	Quo(a, b N) (N, error)
+	// Ceil rounds n up, towards positive infinity, to places decimal
+	// places.
+	Ceil(n N, places int32) (N, error)
+	Cmp(a, b N) int
+	Sign(n N) int
+}
+
+// Rate is a currency's rates in a backend's numbers, in units per unit of
+// the base, as a converter.Currency holds them.
+type Rate[N any] struct {
+	Code      string
+	Precision int32
+	Buy, Sell N
+}
+
+// Calculator prices conversions between rates against a base currency, with
+// the plain-table rules of converter.CalculateRate only.
+type Calculator[N any] struct {
+	Arith Arithmetic[N]
+	Base  string
+	Rates []Rate[N]
+}
+
+// NewCalculator creates a calculator of rates against base, computing with
+// arith.
+func NewCalculator[N any](arith Arithmetic[N], base string, rates []Rate[N]) *Calculator[N] {
+	return &Calculator[N]{Arith: arith, Base: base, Rates: rates}
+}
+
+// find returns the rates of code.
+func (c *Calculator[N]) find(code string) (*Rate[N], error) {
+	for i := range c.Rates {
+		if strings.EqualFold(c.Rates[i].Code, code) {
+			return &c.Rates[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownCurrency, strings.ToUpper(code))
+}
+
+// Rate returns the rate from one currency to another: 1 for the same
+// currency, the target's sell rate from the base, the inverse of the
+// source's buy rate to the base, and the two multiplied across.
+func (c *Calculator[N]) Rate(from, to string) (N, error) {
+	a := c.Arith
+	var zero N
+	if strings.EqualFold(from, to) {
+		return a.One(), nil
+	}
+	if strings.EqualFold(from, c.Base) {
+		target, err := c.find(to)
+		if err != nil {
+			return zero, err
+		}
+		return target.Sell, nil
+	}
+	if _, err := c.find(c.Base); err != nil {
+		return zero, ErrBaseCurrencyNotFound
+	}
+
+	source, err := c.find(from)
+	if err != nil {
+		return zero, err
+	}
+	if a.Sign(source.Buy) <= 0 {
+		return zero, fmt.Errorf("%w: %s buy rate is not positive", ErrInvalidRate, strings.ToUpper(source.Code))
+	}
+	inverse, err := a.Quo(a.One(), source.Buy)
+	if err != nil {
+		return zero, err
+	}
+	if strings.EqualFold(to, c.Base) {
+		return inverse, nil
+	}
+
+	target, err := c.find(to)
+	if err != nil {
+		return zero, err
+	}
+	return a.Mul(inverse, target.Sell)
+}
+
+// Convert converts amount from one currency to another at Rate, rounded up
+// to the target's precision as a converter quote's final amount is.
+func (c *Calculator[N]) Convert(amount N, from, to string) (N, error) {
+	var zero N
+	rate, err := c.Rate(from, to)
+	if err != nil {
+		return zero, err
+	}
+	target, err := c.find(to)
+	if err != nil {
+		return zero, err
+	}
+	converted, err := c.Arith.Mul(amount, rate)
+	if err != nil {
+		return zero, err
+	}
+	return c.Arith.Ceil(converted, target.Precision)
+}
@@ -0,0 +1,66 @@
+package numeric
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fixedRates(t *testing.T, f Fixed) []Rate[int64] {
+	t.Helper()
+	parse := func(s string) int64 {
+		n, err := f.Parse(s)
+		require.NoError(t, err)
+		return n
+	}
+	return []Rate[int64]{
+		{Code: "USD", Precision: 2, Buy: parse("1"), Sell: parse("1")},
+		{Code: "EUR", Precision: 2, Buy: parse("0.9"), Sell: parse("0.95")},
+		{Code: "NGN", Precision: 2, Buy: parse("1500"), Sell: parse("1550")},
+	}
+}
+
+func TestCalculator_Rate(t *testing.T) {
+	f := Fixed{Scale: 8}
+	calc := NewCalculator[int64](f, "USD", fixedRates(t, f))
+
+	tests := []struct {
+		from, to, want string
+	}{
+		{"NGN", "ngn", "1"},
+		{"USD", "NGN", "1550"},
+		{"NGN", "USD", "0.00066667"},
+		{"EUR", "NGN", "1722.2222205"},
+		{"EUR", "USD", "1.11111111"},
+	}
+	for _, tt := range tests {
+		r, err := calc.Rate(tt.from, tt.to)
+		assert.NoError(t, err, tt.from+"/"+tt.to)
+		assert.Equal(t, tt.want, f.Format(r), tt.from+"/"+tt.to)
+	}
+
+	_, err := calc.Rate("USD", "GBP")
+	assert.ErrorIs(t, err, ErrUnknownCurrency)
+	_, err = NewCalculator[int64](f, "GBP", fixedRates(t, f)).Rate("EUR", "NGN")
+	assert.ErrorIs(t, err, ErrBaseCurrencyNotFound)
+
+	rates := fixedRates(t, f)
+	rates[1].Buy = 0
+	_, err = NewCalculator[int64](f, "USD", rates).Rate("EUR", "NGN")
+	assert.ErrorIs(t, err, ErrInvalidRate)
+}
+
+func TestCalculator_Convert(t *testing.T) {
+	f := Fixed{Scale: 8}
+	calc := NewCalculator[int64](f, "USD", fixedRates(t, f))
+
+	amount, err := f.Parse("100")
+	require.NoError(t, err)
+	got, err := calc.Convert(amount, "EUR", "NGN")
+	assert.NoError(t, err)
+	assert.Equal(t, "172222.23", f.Format(got))
+
+	_, err = calc.Convert(amount, "EUR", "GBP")
+	assert.ErrorIs(t, err, ErrUnknownCurrency)
+}
@@ -0,0 +1,128 @@
+package numeric
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// DefaultRatPrecision is the decimal places Rat formats non-terminating
+// numbers to when its Precision is unset.
+const DefaultRatPrecision = 16
+
+// Rat is exact arithmetic on math/big rationals: no product or quotient is
+// rounded, as with converter.WithExactArithmetic. Numbers are never
+// modified, so they may be shared.
+type Rat struct {
+	// Precision is the decimal places Format rounds to, half away from
+	// zero, when a number has no exact decimal form, e.g. 1/3.
+	// Zero means DefaultRatPrecision.
+	Precision int32
+}
+
+var _ Arithmetic[*big.Rat] = Rat{}
+
+// Parse implements Arithmetic.
+func (Rat) Parse(s string) (*big.Rat, error) {
+	text := strings.TrimSpace(s)
+	// big.Rat also parses fractions and exponents, which are not decimal
+	// numbers.
+	if strings.ContainsAny(text, "/eE") {
+		return nil, fmt.Errorf("%w: %q", ErrSyntax, s)
+	}
+	r, ok := new(big.Rat).SetString(text)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrSyntax, s)
+	}
+	return r, nil
+}
+
+// Format implements Arithmetic, exactly when the number has a decimal form
+// and without trailing zeros.
+func (q Rat) Format(n *big.Rat) string {
+	places, exact := decimalPlaces(n)
+	if !exact {
+		places = int(q.Precision)
+		if places <= 0 {
+			places = DefaultRatPrecision
+		}
+	}
+	s := n.FloatString(places)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
+
+// decimalPlaces returns the decimal places n needs and whether it has an
+// exact decimal form, which it has when its denominator has no prime
+// factors but 2 and 5.
+func decimalPlaces(n *big.Rat) (int, bool) {
+	d := new(big.Int).Set(n.Denom())
+	places := 0
+	for _, p := range []int64{2, 5} {
+		count := 0
+		for prime, m := big.NewInt(p), new(big.Int); ; count++ {
+			q, r := new(big.Int).QuoRem(d, prime, m)
+			if r.Sign() != 0 {
+				break
+			}
+			d = q
+		}
+		places = max(places, count)
+	}
+	return places, d.IsInt64() && d.Int64() == 1
+}
+
+// One implements Arithmetic.
+func (Rat) One() *big.Rat {
+	return big.NewRat(1, 1)
+}
+
+// Add implements Arithmetic.
+func (Rat) Add(a, b *big.Rat) (*big.Rat, error) {
+	return new(big.Rat).Add(a, b), nil
+}
+
+// Sub implements Arithmetic.
+func (Rat) Sub(a, b *big.Rat) (*big.Rat, error) {
+	return new(big.Rat).Sub(a, b), nil
+}
+
+// Mul implements Arithmetic.
+func (Rat) Mul(a, b *big.Rat) (*big.Rat, error) {
+	return new(big.Rat).Mul(a, b), nil
+}
+
+// Quo implements Arithmetic.
+func (Rat) Quo(a, b *big.Rat) (*big.Rat, error) {
+	if b.Sign() == 0 {
+		return nil, ErrDivisionByZero
+	}
+	return new(big.Rat).Quo(a, b), nil
+}
+
+// Ceil implements Arithmetic.
+func (Rat) Ceil(n *big.Rat, places int32) (*big.Rat, error) {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(max(places, 0))), nil)
+	scaled := new(big.Rat).Mul(n, new(big.Rat).SetInt(scale))
+	q, m := new(big.Int).DivMod(scaled.Num(), scaled.Denom(), new(big.Int))
+	// DivMod floors; a remainder means rounding up one more.
+	if m.Sign() != 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	return new(big.Rat).SetFrac(q, scale), nil
+}
+
+// Cmp implements Arithmetic.
+func (Rat) Cmp(a, b *big.Rat) int {
+	return a.Cmp(b)
+}
+
+// Sign implements Arithmetic.
+func (Rat) Sign(n *big.Rat) int {
+	return n.Sign()
+}
@@ -0,0 +1,55 @@
+package numeric
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRat_Parse(t *testing.T) {
+	var q Rat
+	n, err := q.Parse("-1550.25")
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewRat(-62010, 40).String(), n.String())
+
+	for _, in := range []string{"", "1/3", "1e3", "abc"} {
+		_, err = q.Parse(in)
+		assert.ErrorIs(t, err, ErrSyntax, in)
+	}
+}
+
+func TestRat_Format(t *testing.T) {
+	assert.Equal(t, "1550.25", Rat{}.Format(big.NewRat(155025, 100)))
+	assert.Equal(t, "0.0625", Rat{}.Format(big.NewRat(1, 16)))
+	assert.Equal(t, "-3", Rat{}.Format(big.NewRat(-3, 1)))
+	assert.Equal(t, "0.3333333333333333", Rat{}.Format(big.NewRat(1, 3)))
+	assert.Equal(t, "0.67", Rat{Precision: 2}.Format(big.NewRat(2, 3)))
+	assert.Equal(t, "0", Rat{Precision: 2}.Format(big.NewRat(-1, 3000)))
+}
+
+func TestRat_Arithmetic(t *testing.T) {
+	var q Rat
+	third, err := q.Quo(q.One(), big.NewRat(3, 1))
+	require.NoError(t, err)
+	sum, err := q.Add(third, third)
+	assert.NoError(t, err)
+	sum, err = q.Add(sum, third)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, q.Cmp(sum, q.One()), "exact: 1/3 * 3 is 1")
+
+	diff, err := q.Sub(third, q.One())
+	assert.NoError(t, err)
+	assert.Equal(t, -1, q.Sign(diff))
+
+	_, err = q.Quo(q.One(), new(big.Rat))
+	assert.ErrorIs(t, err, ErrDivisionByZero)
+
+	c, err := q.Ceil(big.NewRat(2, 3), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "0.67", q.Format(c))
+	c, err = q.Ceil(big.NewRat(-2, 3), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "-0.66", q.Format(c))
+}
@@ -0,0 +1,118 @@
+package converter
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/otyang/converter/numeric"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// numericFixtures are plain tables a numeric.Calculator must price as
+// CalculateRate does.
+func numericFixtures() map[string][]Currency {
+	return map[string][]Currency{
+		"test": testCurrencies(),
+		"fine": {
+			{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+			{ISOCode: "JPY", Precision: 0, BuyRate: decimal.RequireFromString("149.8731"), SellRate: decimal.RequireFromString("150.1269")},
+			{ISOCode: "KWD", Precision: 3, BuyRate: decimal.RequireFromString("0.30712"), SellRate: decimal.RequireFromString("0.30788")},
+			{ISOCode: "BTC", Precision: 8, BuyRate: decimal.RequireFromString("0.0000152345678901"), SellRate: decimal.RequireFromString("0.0000152445678901")},
+		},
+		"foreign base": {
+			{ISOCode: "EUR", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+			{ISOCode: "USD", Precision: 2, BuyRate: decimal.RequireFromString("1.0712"), SellRate: decimal.RequireFromString("1.0846")},
+			{ISOCode: "GBP", Precision: 2, BuyRate: decimal.RequireFromString("0.8512"), SellRate: decimal.RequireFromString("0.8597")},
+		},
+	}
+}
+
+func TestDecimalArithmetic_MatchesCalculateRate(t *testing.T) {
+	for name, currencies := range numericFixtures() {
+		base := currencies[0].ISOCode
+		rates, err := NumericRates[decimal.Decimal](DecimalArithmetic{}, currencies)
+		require.NoError(t, err, name)
+		calc := numeric.NewCalculator[decimal.Decimal](DecimalArithmetic{}, base, rates)
+
+		exact, err := NumericRates[*big.Rat](numeric.Rat{}, currencies)
+		require.NoError(t, err, name)
+		exactCalc := numeric.NewCalculator(numeric.Arithmetic[*big.Rat](numeric.Rat{}), base, exact)
+
+		for _, from := range currencies {
+			for _, to := range currencies {
+				pair := name + ": " + from.ISOCode + "/" + to.ISOCode
+				want, err := CalculateRate(currencies, base, from.ISOCode, to.ISOCode)
+				require.NoError(t, err, pair)
+				got, err := calc.Rate(from.ISOCode, to.ISOCode)
+				require.NoError(t, err, pair)
+				assert.True(t, want.Equal(got), "%s: want %s, got %s", pair, want, got)
+
+				wantExact, err := CalculateRateExact(currencies, base, from.ISOCode, to.ISOCode)
+				require.NoError(t, err, pair)
+				gotExact, err := exactCalc.Rate(from.ISOCode, to.ISOCode)
+				require.NoError(t, err, pair)
+				assert.Equal(t, wantExact.String(), gotExact.String(), pair)
+			}
+		}
+
+		_, err = calc.Rate(base, "XXX")
+		assert.ErrorIs(t, err, numeric.ErrUnknownCurrency, name)
+	}
+}
+
+func TestDecimalArithmetic_Division(t *testing.T) {
+	div := &Division{Precision: 6, Rounding: RoundHalfUp}
+	currencies := testCurrencies()
+	table := NewTable(currencies, WithDivision(*div))
+	rates, err := NumericRates[decimal.Decimal](DecimalArithmetic{Division: div}, currencies)
+	require.NoError(t, err)
+	calc := numeric.NewCalculator[decimal.Decimal](DecimalArithmetic{Division: div}, "USD", rates)
+
+	want, err := table.CalculateRate("USD", "EUR", "NGN")
+	require.NoError(t, err)
+	got, err := calc.Rate("EUR", "NGN")
+	require.NoError(t, err)
+	assert.True(t, want.Equal(got), "want %s, got %s", want, got)
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	a := DecimalArithmetic{Division: &Division{Precision: 4, Rounding: RoundHalfUp}}
+
+	q, err := a.Quo(decimal.NewFromInt(2), decimal.NewFromInt(3))
+	assert.NoError(t, err)
+	assert.Equal(t, "0.6667", a.Format(q))
+
+	_, err = a.Quo(decimalOne, decimal.Zero)
+	assert.ErrorIs(t, err, numeric.ErrDivisionByZero)
+
+	c, err := a.Ceil(decimal.RequireFromString("1.231"), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.24", a.Format(c))
+
+	_, err = a.Parse("1,5")
+	assert.ErrorIs(t, err, numeric.ErrSyntax)
+}
+
+func TestNumericRates(t *testing.T) {
+	rates, err := NumericRates[int64](numeric.Fixed{Scale: 2}, testCurrencies())
+	require.NoError(t, err)
+	assert.Equal(t, numeric.Rate[int64]{Code: "EUR", Precision: 2, Buy: 90, Sell: 95}, rates[1])
+
+	rat, err := NumericRates[*big.Rat](numeric.Rat{}, testCurrencies())
+	require.NoError(t, err)
+	calc := numeric.NewCalculator(numeric.Arithmetic[*big.Rat](numeric.Rat{}), "USD", rat)
+	r, err := calc.Rate("EUR", "NGN")
+	assert.NoError(t, err)
+	want, err := CalculateRateExact(testCurrencies(), "USD", "EUR", "NGN")
+	assert.NoError(t, err)
+	assert.Equal(t, want.String(), r.String())
+
+	_, err = NumericRates[int64](numeric.Fixed{Scale: 0}, testCurrencies())
+	assert.ErrorIs(t, err, numeric.ErrPrecision)
+
+	own := append(testCurrencies(), Currency{ISOCode: "GBP", BuyRate: decimalOne, SellRate: decimalOne, BaseISOCode: "EUR"})
+	_, err = NumericRates[int64](numeric.Fixed{Scale: 2}, own)
+	assert.ErrorIs(t, err, ErrInvalidRate)
+}
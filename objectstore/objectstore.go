@@ -0,0 +1,241 @@
+// Package objectstore stores blobs in S3-compatible object storage — Amazon
+// S3, or GCS through its XML API with HMAC keys — for stateless deployments
+// that cannot rely on local disk. A Store is a converter.BlobStore, so it
+// holds snapshots through converter.BlobSnapshotStore and rate history
+// through RateHistory.SaveBlob, under a bucket and key prefix, optionally
+// encrypted server-side.
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/otyang/converter"
+)
+
+// Endpoints of the providers Store is known to work with.
+const (
+	// GCSEndpoint is the XML API of Google Cloud Storage; use it with HMAC
+	// keys and the "auto" region.
+	GCSEndpoint = "https://storage.googleapis.com"
+)
+
+// Server-side encryption modes
+const (
+	// SSES3 encrypts objects with keys managed by the storage service.
+	SSES3 = "AES256"
+	// SSEKMS encrypts objects with a KMS key, Config.KMSKeyID or the
+	// bucket's default.
+	SSEKMS = "aws:kms"
+)
+
+// maxObjectSize bounds the objects Get reads.
+const maxObjectSize = 64 << 20
+
+// Config errors
+var (
+	ErrNoBucket      = errors.New("objectstore: no bucket")
+	ErrNoCredentials = errors.New("objectstore: no credentials")
+	ErrInvalidSSE    = errors.New("objectstore: invalid server-side encryption")
+)
+
+// Credentials sign requests: an access key pair, with a session token for
+// temporary credentials.
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// Config configures a Store.
+type Config struct {
+	// Endpoint is the service's base URL. Empty means Amazon S3 in Region.
+	Endpoint string
+	// Region signs requests; empty means "us-east-1".
+	Region string
+	Bucket string
+	// Prefix is prepended to every key, e.g. "converter/prod/".
+	Prefix      string
+	Credentials Credentials
+	// PathStyle addresses the bucket in the path rather than the host name,
+	// as GCS, MinIO and other S3-compatible services may need.
+	PathStyle bool
+	// ServerSideEncryption is SSES3, SSEKMS or empty for the bucket's
+	// default.
+	ServerSideEncryption string
+	// KMSKeyID is the KMS key of SSEKMS; empty means the default key.
+	KMSKeyID string
+	// Client sends requests; nil means http.DefaultClient.
+	Client *http.Client
+	Clock  converter.Clock
+}
+
+// Store is a converter.BlobStore in an object storage bucket.
+type Store struct {
+	cfg  Config
+	base *url.URL
+}
+
+// New creates a store from cfg.
+func New(cfg Config) (*Store, error) {
+	if cfg.Bucket == "" {
+		return nil, ErrNoBucket
+	}
+	if cfg.Credentials.AccessKeyID == "" || cfg.Credentials.SecretAccessKey == "" {
+		return nil, ErrNoCredentials
+	}
+	switch cfg.ServerSideEncryption {
+	case "", SSES3, SSEKMS:
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidSSE, cfg.ServerSideEncryption)
+	}
+	if cfg.KMSKeyID != "" && cfg.ServerSideEncryption != SSEKMS {
+		return nil, fmt.Errorf("%w: a KMS key needs %s", ErrInvalidSSE, SSEKMS)
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://s3." + cfg.Region + ".amazonaws.com"
+	}
+	if cfg.Client == nil {
+		cfg.Client = http.DefaultClient
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = converter.SystemClock
+	}
+
+	base, err := url.Parse(strings.TrimSuffix(cfg.Endpoint, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("objectstore: endpoint: %w", err)
+	}
+	if base.Scheme != "http" && base.Scheme != "https" || base.Host == "" {
+		return nil, fmt.Errorf("objectstore: endpoint %q is not an http(s) URL", cfg.Endpoint)
+	}
+	return &Store{cfg: cfg, base: base}, nil
+}
+
+// Put implements converter.BlobStore.
+func (s *Store) Put(ctx context.Context, key string, data []byte) error {
+	req, err := s.request(ctx, http.MethodPut, key, data)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	if s.cfg.ServerSideEncryption != "" {
+		req.Header.Set("X-Amz-Server-Side-Encryption", s.cfg.ServerSideEncryption)
+	}
+	if s.cfg.KMSKeyID != "" {
+		req.Header.Set("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id", s.cfg.KMSKeyID)
+	}
+
+	resp, err := s.do(req, data)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Get implements converter.BlobStore.
+func (s *Store) Get(ctx context.Context, key string) ([]byte, error) {
+	req, err := s.request(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.do(req, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(io.LimitReader(resp.Body, maxObjectSize))
+}
+
+// Delete removes the object stored under key. Deleting a missing object
+// succeeds.
+func (s *Store) Delete(ctx context.Context, key string) error {
+	req, err := s.request(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(req, nil)
+	if err != nil && !errors.Is(err, converter.ErrBlobNotFound) {
+		return err
+	}
+	if resp != nil {
+		resp.Body.Close()
+	}
+	return nil
+}
+
+// request creates a request for the object under key, with the prefix.
+func (s *Store) request(ctx context.Context, method, key string, body []byte) (*http.Request, error) {
+	u := *s.base
+	object := s.cfg.Prefix + key
+	if s.cfg.PathStyle {
+		u.Path += "/" + s.cfg.Bucket + "/" + object
+	} else {
+		u.Host = s.cfg.Bucket + "." + u.Host
+		u.Path += "/" + object
+	}
+	u.RawPath = escapePath(u.Path)
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(body))
+	return req, nil
+}
+
+// do signs and sends req. Error responses are returned as *Error, with a
+// missing object as converter.ErrBlobNotFound.
+func (s *Store) do(req *http.Request, body []byte) (*http.Response, error) {
+	payloadHash := emptyPayloadHash
+	if len(body) > 0 {
+		payloadHash = hashHex(body)
+	}
+	sign(req, payloadHash, s.cfg.Credentials, s.cfg.Region, s.cfg.Clock.Now())
+
+	resp, err := s.cfg.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 == 2 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	e := &Error{StatusCode: resp.StatusCode}
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	_ = xml.Unmarshal(b, e)
+	if resp.StatusCode == http.StatusNotFound && (e.Code == "" || e.Code == "NoSuchKey") {
+		return nil, fmt.Errorf("%w: %s", converter.ErrBlobNotFound, req.URL.Path)
+	}
+	return nil, e
+}
+
+// Error is an error response of the storage service.
+type Error struct {
+	StatusCode int    `xml:"-"`
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("objectstore: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Code != "" {
+		msg += ": " + e.Code
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
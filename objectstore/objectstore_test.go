@@ -0,0 +1,163 @@
+package objectstore
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/otyang/converter"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+// fakeS3 serves objects from memory, recording the headers of each PUT.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	headers map[string]http.Header
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, *httptest.Server) {
+	f := &fakeS3{objects: map[string][]byte{}, headers: map[string]http.Header{}}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+		w.WriteHeader(http.StatusForbidden)
+		io.WriteString(w, "<Error><Code>AccessDenied</Code><Message>Access Denied</Message></Error>")
+		return
+	}
+	switch r.Method {
+	case http.MethodPut:
+		b, _ := io.ReadAll(r.Body)
+		f.objects[r.URL.Path] = b
+		f.headers[r.URL.Path] = r.Header.Clone()
+	case http.MethodGet:
+		b, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, "<Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>")
+			return
+		}
+		w.Write(b)
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func newTestStore(t *testing.T, endpoint string, configure func(*Config)) *Store {
+	cfg := Config{
+		Endpoint:    endpoint,
+		Bucket:      "rates",
+		Prefix:      "prod/",
+		Credentials: Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"},
+		PathStyle:   true,
+		Clock:       fixedClock(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)),
+	}
+	if configure != nil {
+		configure(&cfg)
+	}
+	s, err := New(cfg)
+	require.NoError(t, err)
+	return s
+}
+
+func TestNew(t *testing.T) {
+	creds := Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"}
+
+	_, err := New(Config{Credentials: creds})
+	assert.ErrorIs(t, err, ErrNoBucket)
+	_, err = New(Config{Bucket: "rates"})
+	assert.ErrorIs(t, err, ErrNoCredentials)
+	_, err = New(Config{Bucket: "rates", Credentials: creds, ServerSideEncryption: "rot13"})
+	assert.ErrorIs(t, err, ErrInvalidSSE)
+	_, err = New(Config{Bucket: "rates", Credentials: creds, KMSKeyID: "key"})
+	assert.ErrorIs(t, err, ErrInvalidSSE)
+	_, err = New(Config{Bucket: "rates", Credentials: creds, Endpoint: "storage.googleapis.com"})
+	assert.Error(t, err)
+
+	// Amazon S3 is addressed by region, the bucket in the host name
+	s, err := New(Config{Bucket: "rates", Credentials: creds, Region: "eu-west-1"})
+	require.NoError(t, err)
+	req, err := s.request(context.Background(), http.MethodGet, "snapshots/latest.json", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "https://rates.s3.eu-west-1.amazonaws.com/snapshots/latest.json", req.URL.String())
+}
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	fake, srv := newFakeS3(t)
+	s := newTestStore(t, srv.URL, func(cfg *Config) {
+		cfg.ServerSideEncryption = SSEKMS
+		cfg.KMSKeyID = "alias/rates"
+	})
+
+	_, err := s.Get(ctx, "snapshot.json")
+	assert.ErrorIs(t, err, converter.ErrBlobNotFound)
+
+	require.NoError(t, s.Put(ctx, "snapshot.json", []byte(`{"currencies":[]}`)))
+	b, err := s.Get(ctx, "snapshot.json")
+	require.NoError(t, err)
+	assert.Equal(t, `{"currencies":[]}`, string(b))
+
+	// Objects are stored under the bucket and prefix, encrypted
+	h := fake.headers["/rates/prod/snapshot.json"]
+	require.NotNil(t, h)
+	assert.Equal(t, SSEKMS, h.Get("X-Amz-Server-Side-Encryption"))
+	assert.Equal(t, "alias/rates", h.Get("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id"))
+	assert.Equal(t, hashHex(b), h.Get("X-Amz-Content-Sha256"))
+	assert.Contains(t, h.Get("Authorization"), "x-amz-server-side-encryption")
+
+	require.NoError(t, s.Delete(ctx, "snapshot.json"))
+	_, err = s.Get(ctx, "snapshot.json")
+	assert.ErrorIs(t, err, converter.ErrBlobNotFound)
+}
+
+func TestStore_Error(t *testing.T) {
+	_, srv := newFakeS3(t)
+	s := newTestStore(t, srv.URL, func(cfg *Config) {
+		cfg.Credentials.AccessKeyID = "wrong"
+	})
+
+	err := s.Put(context.Background(), "snapshot.json", []byte("{}"))
+	var e *Error
+	require.ErrorAs(t, err, &e)
+	assert.Equal(t, http.StatusForbidden, e.StatusCode)
+	assert.Equal(t, "AccessDenied", e.Code)
+	assert.EqualError(t, err, "objectstore: 403 Forbidden: AccessDenied: Access Denied")
+}
+
+func TestStore_Snapshots(t *testing.T) {
+	ctx := context.Background()
+	_, srv := newFakeS3(t)
+	store := converter.NewBlobSnapshotStore(newTestStore(t, srv.URL, nil), "snapshots/latest.json")
+
+	_, err := store.Load(ctx)
+	assert.ErrorIs(t, err, converter.ErrSnapshotNotFound)
+
+	table := converter.NewTable([]converter.Currency{
+		{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+	})
+	require.NoError(t, converter.NewLastKnownGood(table, store).Save(ctx))
+
+	restarted := converter.NewTable(nil)
+	require.NoError(t, converter.NewLastKnownGood(restarted, store).Restore(ctx))
+	assert.Equal(t, table.List(), restarted.List())
+}
@@ -0,0 +1,22 @@
+package converter
+
+import (
+	"slices"
+	"strings"
+)
+
+// SortCurrencies sorts currencies by ISO code, ignoring case. Tables and
+// snapshots keep their currencies in this order, so exports, diffs and
+// checksums over them are reproducible whatever order a feed used.
+func SortCurrencies(currencies []Currency) {
+	slices.SortStableFunc(currencies, func(a, b Currency) int {
+		return strings.Compare(strings.ToUpper(a.ISOCode), strings.ToUpper(b.ISOCode))
+	})
+}
+
+// sortedCurrencies returns a sorted copy of currencies.
+func sortedCurrencies(currencies []Currency) []Currency {
+	sorted := append([]Currency(nil), currencies...)
+	SortCurrencies(sorted)
+	return sorted
+}
@@ -0,0 +1,19 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortCurrencies(t *testing.T) {
+	currencies := []Currency{{ISOCode: "usd"}, {ISOCode: "BTC"}, {ISOCode: "eur"}, {ISOCode: "EUR", Precision: 1}}
+	SortCurrencies(currencies)
+	assert.Equal(t, []string{"BTC", "eur", "EUR", "usd"}, codes(currencies))
+	assert.Equal(t, 1, currencies[2].Precision)
+
+	// Tables sort whatever order the feed used.
+	table := NewTable([]Currency{testCurrencies()[2], testCurrencies()[0], testCurrencies()[1]})
+	assert.Equal(t, []string{"EUR", "NGN", "USD"}, codes(table.List()))
+	assert.Equal(t, table.List(), NewTable(testCurrencies()).Snapshot().Currencies)
+}
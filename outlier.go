@@ -0,0 +1,177 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidTickFilter is returned for a TickFilter with an unknown method,
+// a window below one or winsorizing limits outside [0, 0.5).
+var ErrInvalidTickFilter = errors.New("invalid tick filter")
+
+// TickFilterMethod is how a TickFilter smooths a rate.
+type TickFilterMethod string
+
+// Tick filter methods
+const (
+	// TickMedian replaces a rate with the median of the last K ticks.
+	TickMedian TickFilterMethod = "median"
+	// TickWinsorize clamps a rate between the Limit and 1-Limit quantiles
+	// of the last K ticks, so a one-off print is pulled back to the range
+	// of its neighbours while steady moves pass through.
+	TickWinsorize TickFilterMethod = "winsorize"
+)
+
+// TickFilter configures the filtering of one pair's ticks. The zero value
+// passes ticks through.
+type TickFilter struct {
+	Method TickFilterMethod
+	// K is the number of ticks considered, the incoming one included.
+	K int
+	// Limit is the winsorizing quantile, e.g. 0.1 for the 10th and 90th
+	// percentiles.
+	Limit decimal.Decimal
+}
+
+// Validate checks the method, window and limit.
+func (f TickFilter) Validate() error {
+	switch f.Method {
+	case "":
+		return nil
+	case TickMedian, TickWinsorize:
+	default:
+		return fmt.Errorf("%w: unknown method %q", ErrInvalidTickFilter, f.Method)
+	}
+	if f.K < 1 {
+		return fmt.Errorf("%w: window %d is below one", ErrInvalidTickFilter, f.K)
+	}
+	if f.Method == TickWinsorize && (f.Limit.IsNegative() || f.Limit.GreaterThanOrEqual(decimal.New(5, -1))) {
+		return fmt.Errorf("%w: limit %s is outside [0, 0.5)", ErrInvalidTickFilter, f.Limit)
+	}
+	return nil
+}
+
+// apply filters the latest of ticks, oldest first, which must not be
+// empty.
+func (f TickFilter) apply(ticks []decimal.Decimal) decimal.Decimal {
+	latest := ticks[len(ticks)-1]
+	if f.Method == "" || len(ticks) == 1 {
+		return latest
+	}
+	if f.Method == TickMedian {
+		return median(ticks)
+	}
+
+	sorted := sortedDecimals(ticks)
+	last := decimal.NewFromInt(int64(len(sorted) - 1))
+	low := sorted[f.Limit.Mul(last).Floor().IntPart()]
+	high := sorted[decimal.NewFromInt(1).Sub(f.Limit).Mul(last).Ceil().IntPart()]
+	return decimal.Min(decimal.Max(latest, low), high)
+}
+
+// median returns the median of values, which must not be empty.
+func median(values []decimal.Decimal) decimal.Decimal {
+	sorted := sortedDecimals(values)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return sorted[n/2-1].Add(sorted[n/2]).Div(decimal.NewFromInt(2))
+}
+
+// sortedDecimals returns a sorted copy of values.
+func sortedDecimals(values []decimal.Decimal) []decimal.Decimal {
+	sorted := append([]decimal.Decimal(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].LessThan(sorted[j]) })
+	return sorted
+}
+
+// OutlierFilter is a RateProvider that smooths the ticks of a noisy
+// Provider before they reach a live table, suppressing one-off bad prints.
+// It keeps the last ticks of every currency's buy and sell rates and
+// replaces each fetched rate with its pair's TickFilter over them.
+type OutlierFilter struct {
+	Provider RateProvider
+	// Base is the currency the rates are quoted against, naming the pairs.
+	Base string
+	// Default filters the pairs without a filter of their own.
+	Default TickFilter
+	// Pairs sets the filter of pairs, e.g. "USD/NGN".
+	Pairs map[string]TickFilter
+
+	mu    sync.Mutex
+	ticks map[string]*sideTicks
+}
+
+// sideTicks are the recent raw ticks of a currency, oldest first.
+type sideTicks struct {
+	buys, sells []decimal.Decimal
+}
+
+// FetchRates implements RateProvider. It returns ErrInvalidTickFilter if a
+// filter is invalid, before fetching.
+func (f *OutlierFilter) FetchRates(ctx context.Context) ([]Currency, error) {
+	if err := f.Default.Validate(); err != nil {
+		return nil, err
+	}
+	for pair, filter := range f.Pairs {
+		if err := filter.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", pair, err)
+		}
+	}
+
+	currencies, err := f.Provider.FetchRates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.ticks == nil {
+		f.ticks = map[string]*sideTicks{}
+	}
+
+	filtered := make([]Currency, len(currencies))
+	for i, c := range currencies {
+		code := strings.ToUpper(c.ISOCode)
+		filter := f.filter(code)
+		ticks, ok := f.ticks[code]
+		if !ok {
+			ticks = &sideTicks{}
+			f.ticks[code] = ticks
+		}
+		ticks.buys = recentTicks(ticks.buys, c.BuyRate, filter.K)
+		ticks.sells = recentTicks(ticks.sells, c.SellRate, filter.K)
+
+		c.BuyRate = filter.apply(ticks.buys)
+		c.SellRate = filter.apply(ticks.sells)
+		filtered[i] = c
+	}
+	return filtered, nil
+}
+
+// filter returns the filter of the pair of the base and code.
+func (f *OutlierFilter) filter(code string) TickFilter {
+	for pair, filter := range f.Pairs {
+		if from, to, err := parsePair(pair); err == nil && strings.EqualFold(from, f.Base) && to == code {
+			return filter
+		}
+	}
+	return f.Default
+}
+
+// recentTicks appends tick to ticks, keeping the last k, at least one.
+func recentTicks(ticks []decimal.Decimal, tick decimal.Decimal, k int) []decimal.Decimal {
+	k = max(k, 1)
+	ticks = append(ticks, tick)
+	if len(ticks) > k {
+		ticks = append(ticks[:0:0], ticks[len(ticks)-k:]...)
+	}
+	return ticks
+}
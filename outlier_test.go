@@ -0,0 +1,99 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decimals(values ...int64) []decimal.Decimal {
+	d := make([]decimal.Decimal, len(values))
+	for i, v := range values {
+		d[i] = decimal.NewFromInt(v)
+	}
+	return d
+}
+
+func TestTickFilter_Validate(t *testing.T) {
+	assert.NoError(t, TickFilter{}.Validate())
+	assert.NoError(t, TickFilter{Method: TickMedian, K: 3}.Validate())
+	assert.NoError(t, TickFilter{Method: TickWinsorize, K: 5, Limit: decimal.RequireFromString("0.25")}.Validate())
+
+	assert.ErrorIs(t, TickFilter{Method: "mean", K: 3}.Validate(), ErrInvalidTickFilter)
+	assert.ErrorIs(t, TickFilter{Method: TickMedian}.Validate(), ErrInvalidTickFilter)
+	assert.ErrorIs(t, TickFilter{Method: TickWinsorize, K: 5, Limit: decimal.RequireFromString("0.5")}.Validate(), ErrInvalidTickFilter)
+	assert.ErrorIs(t, TickFilter{Method: TickWinsorize, K: 5, Limit: decimal.NewFromInt(-1)}.Validate(), ErrInvalidTickFilter)
+}
+
+func TestTickFilter_Apply(t *testing.T) {
+	median := TickFilter{Method: TickMedian, K: 5}
+	assert.Equal(t, "7", median.apply(decimals(7)).String())
+	assert.Equal(t, "3", median.apply(decimals(1, 100, 3)).String())
+	assert.Equal(t, "2.5", median.apply(decimals(1, 2, 3, 10)).String())
+
+	winsorize := TickFilter{Method: TickWinsorize, K: 5, Limit: decimal.RequireFromString("0.25")}
+	assert.Equal(t, "12", winsorize.apply(decimals(10, 11, 12, 11, 50)).String())
+	assert.Equal(t, "10", winsorize.apply(decimals(10, 11, 12, 11, 1)).String())
+	assert.Equal(t, "11", winsorize.apply(decimals(10, 12, 12, 10, 11)).String())
+	// A zero limit clamps to the window's range, which holds the tick.
+	winsorize.Limit = decimal.Zero
+	assert.Equal(t, "50", winsorize.apply(decimals(10, 11, 50)).String())
+
+	assert.Equal(t, "50", TickFilter{}.apply(decimals(10, 11, 50)).String())
+}
+
+func TestOutlierFilter(t *testing.T) {
+	sells := []int64{1550, 1555, 9999, 1560}
+	euros := []string{"0.95", "0.96", "0.5", "0.97"}
+	fetch := 0
+	f := &OutlierFilter{
+		Provider: RateProviderFunc(func(context.Context) ([]Currency, error) {
+			currencies := ngnSnapshot(sells[fetch], time.Time{}).Currencies
+			currencies[0].SellRate = decimal.RequireFromString(euros[fetch])
+			fetch++
+			return currencies, nil
+		}),
+		Base:  "USD",
+		Pairs: map[string]TickFilter{"usd/ngn": {Method: TickMedian, K: 3}},
+	}
+
+	var ngn, eur []string
+	for range sells {
+		currencies, err := f.FetchRates(context.Background())
+		require.NoError(t, err)
+		require.Len(t, currencies, 3)
+		ngn = append(ngn, currencies[1].SellRate.String())
+		eur = append(eur, currencies[0].SellRate.String())
+		assert.Equal(t, "1500", currencies[1].BuyRate.String())
+	}
+	assert.Equal(t, []string{"1550", "1552.5", "1555", "1560"}, ngn)
+	// Pairs without a filter of their own get the default, here none.
+	assert.Equal(t, euros, eur)
+	assert.Len(t, f.ticks["EUR"].sells, 1)
+}
+
+func TestOutlierFilter_Errors(t *testing.T) {
+	fetched := false
+	provider := RateProviderFunc(func(context.Context) ([]Currency, error) {
+		fetched = true
+		return nil, errors.New("feed down")
+	})
+
+	f := &OutlierFilter{Provider: provider, Default: TickFilter{Method: TickMedian}}
+	_, err := f.FetchRates(context.Background())
+	assert.ErrorIs(t, err, ErrInvalidTickFilter)
+	f = &OutlierFilter{Provider: provider, Pairs: map[string]TickFilter{"USD/NGN": {Method: "mean", K: 3}}}
+	_, err = f.FetchRates(context.Background())
+	assert.ErrorIs(t, err, ErrInvalidTickFilter)
+	assert.ErrorContains(t, err, "USD/NGN: ")
+	assert.False(t, fetched)
+
+	f = &OutlierFilter{Provider: provider}
+	_, err = f.FetchRates(context.Background())
+	assert.EqualError(t, err, "feed down")
+}
@@ -0,0 +1,33 @@
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Pair errors
+var (
+	ErrInvalidPair = errors.New("invalid currency pair")
+)
+
+// parsePair splits a pair such as "USD/NGN" into its upper-cased ISO codes.
+func parsePair(pair string) (from, to string, err error) {
+	from, to, ok := strings.Cut(strings.TrimSpace(pair), "/")
+	from = strings.ToUpper(strings.TrimSpace(from))
+	to = strings.ToUpper(strings.TrimSpace(to))
+	if !ok || from == "" || to == "" || strings.Contains(to, "/") {
+		return "", "", fmt.Errorf("%w: %q", ErrInvalidPair, pair)
+	}
+	return from, to, nil
+}
+
+// pairKey names the pair of two currencies regardless of direction, so
+// "NGN/USD" and "USD/NGN" share settings.
+func pairKey(a, b string) string {
+	a, b = strings.ToUpper(a), strings.ToUpper(b)
+	if b < a {
+		a, b = b, a
+	}
+	return a + "/" + b
+}
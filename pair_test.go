@@ -0,0 +1,24 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePair(t *testing.T) {
+	from, to, err := parsePair(" usd / ngn ")
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", from)
+	assert.Equal(t, "NGN", to)
+
+	for _, pair := range []string{"", "USD", "USD/", "/NGN", "USD/NGN/EUR"} {
+		_, _, err := parsePair(pair)
+		assert.ErrorIs(t, err, ErrInvalidPair, pair)
+	}
+}
+
+func TestPairKey(t *testing.T) {
+	assert.Equal(t, "NGN/USD", pairKey("usd", "NGN"))
+	assert.Equal(t, "NGN/USD", pairKey("NGN", "USD"))
+}
@@ -0,0 +1,87 @@
+package converter
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// FlagPegBroken marks a quote involving a pegged currency whose market rate
+// is outside its peg's tolerance band.
+const FlagPegBroken QuoteFlag = "peg-broken"
+
+// Peg declares a currency pegged to an anchor, e.g. USDT to USD.
+type Peg struct {
+	Currency string
+	Anchor   string
+	// Rate is the peg, in units of Currency per unit of Anchor. Zero means
+	// 1:1.
+	Rate decimal.Decimal
+	// Tolerance is the largest relative deviation of the market mid rate
+	// from the peg, e.g. 0.005 for 0.5%, before the peg counts as broken.
+	Tolerance decimal.Decimal
+	// PriceAtPeg prices the currency at the peg from the anchor's rates
+	// while the peg holds, instead of at its market rates.
+	PriceAtPeg bool
+}
+
+// rate returns the peg rate, 1 if unset.
+func (p Peg) rate() decimal.Decimal {
+	if p.Rate.IsZero() {
+		return decimalOne
+	}
+	return p.Rate
+}
+
+// WithPegs declares pegged currencies. Quotes involving a pegged currency
+// are flagged FlagPegBroken while its market rate is outside the peg's
+// tolerance. Pegs whose currency or anchor is not in the table are ignored.
+func WithPegs(pegs ...Peg) TableOption {
+	return func(c *Currencies) {
+		c.pegs = slices.Clone(pegs)
+	}
+}
+
+// applyPegs applies the pegs of the currencies from and to to items priced
+// against base. It reports whether one of the pegs is broken. The caller
+// holds the read lock.
+func (c *Currencies) applyPegs(items []Currency, base, from, to string) ([]Currency, bool) {
+	broken := false
+	cloned := false
+	for _, p := range c.pegs {
+		if !strings.EqualFold(p.Currency, from) && !strings.EqualFold(p.Currency, to) {
+			continue
+		}
+		market, err := CalculateRateKind(items, base, p.Anchor, p.Currency, RateMid)
+		if err != nil {
+			continue
+		}
+		peg := p.rate()
+		deviation := market.Sub(peg).Abs().DivRound(peg, maxDigits)
+		if deviation.GreaterThan(p.Tolerance) {
+			broken = true
+			continue
+		}
+		if !p.PriceAtPeg {
+			continue
+		}
+
+		anchor, err := FindCurrency(items, p.Anchor)
+		if err != nil {
+			continue
+		}
+		anchorRates := *anchor
+		if !cloned {
+			items, cloned = slices.Clone(items), true
+		}
+		for i := range items {
+			if strings.EqualFold(items[i].ISOCode, p.Currency) {
+				items[i].BuyRate = anchorRates.BuyRate.Mul(peg)
+				items[i].SellRate = anchorRates.SellRate.Mul(peg)
+				items[i].BaseISOCode = anchorRates.BaseISOCode
+			}
+		}
+	}
+	return items, broken
+}
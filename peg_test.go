@@ -0,0 +1,73 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func pegCurrencies(usdt string) []Currency {
+	return []Currency{
+		{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+		{ISOCode: "NGN", Precision: 2, BuyRate: decimal.NewFromInt(1500), SellRate: decimal.NewFromInt(1550)},
+		{ISOCode: "USDT", Precision: 6, BuyRate: decimal.RequireFromString(usdt), SellRate: decimal.RequireFromString(usdt)},
+	}
+}
+
+func TestWithPegs(t *testing.T) {
+	peg := Peg{Currency: "USDT", Anchor: "USD", Tolerance: decimal.NewFromFloat(0.005)}
+
+	// Within the band, market rates are used and nothing is flagged.
+	table := NewTable(pegCurrencies("1.002"), WithPegs(peg))
+	quote, err := table.NewQuote("USD", "USD", "USDT", decimal.NewFromInt(100), decimal.Zero)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.002", quote.Rate.String())
+	assert.Empty(t, quote.Flags)
+
+	// Priced at the peg, USDT trades 1:1 with USD.
+	peg.PriceAtPeg = true
+	table = NewTable(pegCurrencies("1.002"), WithPegs(peg))
+	quote, err = table.NewQuote("USD", "USD", "USDT", decimal.NewFromInt(100), decimal.Zero)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", quote.Rate.String())
+	rate, err := table.CalculateRate("USD", "USDT", "NGN")
+	assert.NoError(t, err)
+	assert.Equal(t, "1550", rate.String())
+
+	// Outside the band the peg is broken: market rates, flagged quotes.
+	table = NewTable(pegCurrencies("1.01"), WithPegs(peg))
+	quote, err = table.NewQuote("USD", "USDT", "NGN", decimal.NewFromInt(100), decimal.Zero)
+	assert.NoError(t, err)
+	assert.True(t, quote.HasFlag(FlagPegBroken))
+	assert.Equal(t, "1534.653465346534655", quote.Rate.String())
+
+	// Quotes not involving the pegged currency are not flagged.
+	quote, err = table.NewQuote("USD", "USD", "NGN", decimal.NewFromInt(100), decimal.Zero)
+	assert.NoError(t, err)
+	assert.Empty(t, quote.Flags)
+}
+
+func TestWithPegs_Rate(t *testing.T) {
+	// A currency pegged at 3.75 per USD, within 0.1%.
+	currencies := []Currency{
+		{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+		{ISOCode: "SAR", Precision: 2, BuyRate: decimal.RequireFromString("3.752"), SellRate: decimal.RequireFromString("3.751")},
+	}
+	table := NewTable(currencies, WithPegs(Peg{
+		Currency:   "SAR",
+		Anchor:     "USD",
+		Rate:       decimal.RequireFromString("3.75"),
+		Tolerance:  decimal.NewFromFloat(0.001),
+		PriceAtPeg: true,
+	}))
+
+	rate, err := table.CalculateRateKind("USD", "USD", "SAR", RateSell)
+	assert.NoError(t, err)
+	assert.Equal(t, "3.75", rate.String())
+
+	quote, err := table.NewQuote("USD", "USD", "SAR", decimal.NewFromInt(100), decimal.Zero)
+	assert.NoError(t, err)
+	assert.Equal(t, "375", quote.FinalAmount.String())
+	assert.Empty(t, quote.Flags)
+}
@@ -0,0 +1,16 @@
+package converter
+
+import "context"
+
+// RateProvider fetches the current rates from an upstream source.
+type RateProvider interface {
+	FetchRates(ctx context.Context) ([]Currency, error)
+}
+
+// RateProviderFunc adapts a function to the RateProvider interface.
+type RateProviderFunc func(ctx context.Context) ([]Currency, error)
+
+// FetchRates implements RateProvider.
+func (f RateProviderFunc) FetchRates(ctx context.Context) ([]Currency, error) {
+	return f(ctx)
+}
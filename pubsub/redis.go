@@ -0,0 +1,188 @@
+// Package pubsub carries table announcements between instances over Redis
+// pub/sub: Redis is a converter.InvalidationBus, so when one instance
+// publishes a new table, the others reload it and drop what they derived
+// from the old one at once.
+package pubsub
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// DefaultRedisAddress is the address of a local Redis server.
+const DefaultRedisAddress = "localhost:6379"
+
+// DefaultChannel is the channel announcements are published on.
+const DefaultChannel = "converter:tables"
+
+// Redis publishes and subscribes to a Redis channel. Publishing reuses one
+// connection; each subscription holds its own, as Redis requires.
+type Redis struct {
+	// Address is host:port; DefaultRedisAddress if empty.
+	Address string
+	// Username and Password authenticate, if set. Username needs Redis 6
+	// ACLs.
+	Username string
+	Password string
+	// Channel is DefaultChannel if empty.
+	Channel string
+	// TLS, if set, secures connections.
+	TLS *tls.Config
+
+	mu   sync.Mutex
+	conn *redisConn
+}
+
+// NewRedis creates a bus on the Redis server at address.
+func NewRedis(address string) *Redis {
+	return &Redis{Address: address}
+}
+
+// Publish implements converter.InvalidationBus. A broken connection is
+// dropped, so the next publish dials again.
+func (r *Redis) Publish(ctx context.Context, msg []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.conn == nil {
+		conn, err := r.dial(ctx)
+		if err != nil {
+			return err
+		}
+		r.conn = conn
+	}
+	reply, err := r.conn.do(ctx, "PUBLISH", r.channel(), string(msg))
+	if err == nil {
+		err = replyErr(reply)
+	}
+	var redisErr redisError
+	if err != nil && !errors.As(err, &redisErr) {
+		r.conn.Close()
+		r.conn = nil
+	}
+	return err
+}
+
+// Subscribe implements converter.InvalidationBus.
+func (r *Redis) Subscribe(ctx context.Context, msgs chan<- []byte) error {
+	conn, err := r.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	if err := writeCommand(conn.w, "SUBSCRIBE", r.channel()); err != nil {
+		return ctxErr(ctx, err)
+	}
+	for {
+		reply, err := readReply(conn.r)
+		if err != nil {
+			return ctxErr(ctx, err)
+		}
+		if err := replyErr(reply); err != nil {
+			return err
+		}
+		// Pushes are ["subscribe", channel, count] and
+		// ["message", channel, payload].
+		push, ok := reply.([]any)
+		if !ok || len(push) != 3 || push[0] != "message" {
+			continue
+		}
+		payload, _ := push[2].(string)
+		select {
+		case msgs <- []byte(payload):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (r *Redis) channel() string {
+	if r.Channel == "" {
+		return DefaultChannel
+	}
+	return r.Channel
+}
+
+// redisConn is a connection with buffered reads and writes.
+type redisConn struct {
+	net.Conn
+	r *bufio.Reader
+	w *bufio.Writer
+}
+
+// dial connects and authenticates.
+func (r *Redis) dial(ctx context.Context) (*redisConn, error) {
+	address := r.Address
+	if address == "" {
+		address = DefaultRedisAddress
+	}
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	if r.TLS != nil {
+		conn = tls.Client(conn, r.TLS)
+	}
+	c := &redisConn{Conn: conn, r: bufio.NewReader(conn), w: bufio.NewWriter(conn)}
+
+	if r.Password != "" {
+		args := []string{"AUTH", r.Password}
+		if r.Username != "" {
+			args = []string{"AUTH", r.Username, r.Password}
+		}
+		reply, err := c.do(ctx, args...)
+		if err == nil {
+			err = replyErr(reply)
+		}
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// do sends a command and reads its reply, giving up once ctx is done.
+func (c *redisConn) do(ctx context.Context, args ...string) (any, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		c.SetDeadline(deadline)
+		defer c.SetDeadline(time.Time{})
+	}
+	stop := context.AfterFunc(ctx, func() { c.SetDeadline(aLongTimeAgo) })
+	defer stop()
+
+	if err := writeCommand(c.w, args...); err != nil {
+		return nil, ctxErr(ctx, err)
+	}
+	reply, err := readReply(c.r)
+	return reply, ctxErr(ctx, err)
+}
+
+// aLongTimeAgo is a deadline in the past, interrupting blocked I/O.
+var aLongTimeAgo = time.Unix(1, 0)
+
+// replyErr returns an error reply as an error.
+func replyErr(reply any) error {
+	if err, ok := reply.(redisError); ok {
+		return err
+	}
+	return nil
+}
+
+// ctxErr prefers ctx's error to an I/O error caused by its cancellation.
+func ctxErr(ctx context.Context, err error) error {
+	if err != nil && ctx.Err() != nil {
+		return fmt.Errorf("%w: %v", ctx.Err(), err)
+	}
+	return err
+}
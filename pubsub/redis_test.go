@@ -0,0 +1,161 @@
+package pubsub
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/otyang/converter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRedis serves AUTH, PUBLISH and SUBSCRIBE.
+type fakeRedis struct {
+	mu          sync.Mutex
+	subscribers map[string][]*bufio.Writer
+}
+
+func newFakeRedis(t *testing.T) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	f := &fakeRedis{subscribers: map[string][]*bufio.Writer{}}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { conn.Close() })
+			go f.serve(conn)
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	r, w := bufio.NewReader(conn), bufio.NewWriter(conn)
+	authed := false
+	for {
+		reply, err := readReply(r)
+		if err != nil {
+			return
+		}
+		args := make([]string, 0, 3)
+		for _, a := range reply.([]any) {
+			args = append(args, a.(string))
+		}
+
+		f.mu.Lock()
+		switch cmd := strings.ToUpper(args[0]); {
+		case cmd == "AUTH":
+			authed = args[len(args)-1] == "secret"
+			if authed {
+				w.WriteString("+OK\r\n")
+			} else {
+				w.WriteString("-WRONGPASS invalid username-password pair or user is disabled.\r\n")
+			}
+		case !authed:
+			w.WriteString("-NOAUTH Authentication required.\r\n")
+		case cmd == "SUBSCRIBE":
+			f.subscribers[args[1]] = append(f.subscribers[args[1]], w)
+			writeCommand(w, "subscribe", args[1])
+		case cmd == "PUBLISH":
+			subs := f.subscribers[args[1]]
+			for _, sub := range subs {
+				writeCommand(sub, "message", args[1], args[2])
+			}
+			w.WriteString(":" + strconv.Itoa(len(subs)) + "\r\n")
+		}
+		w.Flush()
+		f.mu.Unlock()
+	}
+}
+
+// eventually calls try until it succeeds, for a second at most. Unlike
+// require.Eventually, try runs on the test's goroutine and may use require.
+func eventually(t *testing.T, try func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); !try(); {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met within a second")
+		}
+	}
+}
+
+func TestRedis(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	addr := newFakeRedis(t)
+
+	bus := NewRedis(addr)
+	bus.Password = "secret"
+	msgs := make(chan []byte)
+	done := make(chan error)
+	go func() { done <- bus.Subscribe(ctx, msgs) }()
+
+	// Publish until the subscription is in place
+	eventually(t, func() bool {
+		require.NoError(t, bus.Publish(ctx, []byte("hello")))
+		select {
+		case msg := <-msgs:
+			return string(msg) == "hello"
+		case <-time.After(10 * time.Millisecond):
+			return false
+		}
+	})
+
+	require.NoError(t, bus.Publish(ctx, []byte(`{"instance":"a"}`)))
+	assert.Equal(t, `{"instance":"a"}`, string(<-msgs))
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestRedis_Auth(t *testing.T) {
+	addr := newFakeRedis(t)
+
+	bus := NewRedis(addr)
+	err := bus.Publish(context.Background(), []byte("hello"))
+	assert.EqualError(t, err, "redis: NOAUTH Authentication required.")
+
+	bus.Password = "wrong"
+	err = bus.Subscribe(context.Background(), make(chan []byte))
+	assert.ErrorContains(t, err, "WRONGPASS")
+}
+
+func TestRedis_Invalidation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	bus := NewRedis(newFakeRedis(t))
+	bus.Password = "secret"
+	shared := converter.NewBlobSnapshotStore(converter.NewMemoryBlobStore(), "rates")
+
+	invalidated := make(chan struct{}, 1)
+	follower := converter.NewInvalidation(converter.NewTable(nil), bus, shared, "b")
+	follower.OnInvalidate = func() { invalidated <- struct{}{} }
+	go follower.Run(ctx)
+
+	publisher := converter.NewInvalidation(converter.NewTable(nil), bus, shared, "a")
+	at := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, publisher.Publisher().Save(ctx, converter.NewSnapshot([]converter.Currency{{ISOCode: "USD", Precision: 2}}, at)))
+
+	// Announcements are lost until the follower has subscribed
+	eventually(t, func() bool {
+		require.NoError(t, publisher.Publisher().Save(ctx, converter.NewSnapshot([]converter.Currency{{ISOCode: "USD", Precision: 2}}, at)))
+		select {
+		case <-invalidated:
+			return true
+		case <-time.After(10 * time.Millisecond):
+			return false
+		}
+	})
+	assert.Equal(t, at, follower.Table.UpdatedAt())
+}
@@ -0,0 +1,157 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
+
+// Quorum errors
+var (
+	ErrInvalidQuorum = errors.New("quorum must be from 1 to the number of providers")
+	ErrNoQuorum      = errors.New("no provider quorum")
+)
+
+// QuorumProvider is a RateProvider that publishes a currency's rates only
+// when at least Quorum of its Providers agree on them within Tolerance, so
+// a single faulty feed cannot move a live table. A currency the providers
+// disagree on keeps its previously published rates and raises an
+// EventRateDisagreement.
+type QuorumProvider struct {
+	Providers []RateProvider
+	// Quorum is the number of providers that must agree, M of the N
+	// Providers.
+	Quorum int
+	// Tolerance is the largest relative difference between agreeing
+	// rates, e.g. 0.005 for 0.5%.
+	Tolerance decimal.Decimal
+	// Base is the currency the rates are quoted against, naming pairs in
+	// events.
+	Base string
+	// OnEvent, if set, receives an EventRateDisagreement per currency the
+	// providers disagree on.
+	OnEvent EventHandler
+	// Clock defaults to SystemClock.
+	Clock Clock
+
+	mu        sync.Mutex
+	published map[string]Currency
+}
+
+// FetchRates implements RateProvider. It fetches from every provider
+// concurrently and returns, per currency, the median rates of the largest
+// group of agreeing providers if it reaches the quorum, else the rates
+// published before. Failed providers count as disagreeing. It returns
+// ErrNoQuorum, joined with the providers' errors, if no currency can be
+// published.
+func (q *QuorumProvider) FetchRates(ctx context.Context) ([]Currency, error) {
+	if q.Quorum < 1 || q.Quorum > len(q.Providers) {
+		return nil, fmt.Errorf("%w: %d of %d", ErrInvalidQuorum, q.Quorum, len(q.Providers))
+	}
+
+	results := make([][]Currency, len(q.Providers))
+	errs := make([]error, len(q.Providers))
+	var wg sync.WaitGroup
+	for i, p := range q.Providers {
+		wg.Add(1)
+		go func(i int, p RateProvider) {
+			defer wg.Done()
+			results[i], errs[i] = p.FetchRates(ctx)
+		}(i, p)
+	}
+	wg.Wait()
+
+	candidates := map[string][]Currency{}
+	var codes []string
+	for _, currencies := range results {
+		for _, c := range currencies {
+			code := strings.ToUpper(c.ISOCode)
+			if _, seen := candidates[code]; !seen {
+				codes = append(codes, code)
+			}
+			candidates[code] = append(candidates[code], c)
+		}
+	}
+	sort.Strings(codes)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.published == nil {
+		q.published = map[string]Currency{}
+	}
+
+	var published []Currency
+	for _, code := range codes {
+		agreed := q.agreement(candidates[code])
+		if len(agreed) >= q.Quorum {
+			c := agreed[0]
+			c.BuyRate = medianRate(agreed, func(c Currency) decimal.Decimal { return c.BuyRate })
+			c.SellRate = medianRate(agreed, func(c Currency) decimal.Decimal { return c.SellRate })
+			q.published[code] = c
+			published = append(published, c)
+			continue
+		}
+
+		q.emit(code, fmt.Sprintf("%d of %d providers agree, %d required", len(agreed), len(q.Providers), q.Quorum))
+		if previous, ok := q.published[code]; ok {
+			published = append(published, previous)
+		}
+	}
+	if len(published) == 0 {
+		return nil, errors.Join(append([]error{ErrNoQuorum}, errs...)...)
+	}
+	return published, nil
+}
+
+// agreement returns the largest group of candidates whose buy and sell
+// rates are all within tolerance of one of them.
+func (q *QuorumProvider) agreement(candidates []Currency) []Currency {
+	var best []Currency
+	for _, anchor := range candidates {
+		var group []Currency
+		for _, c := range candidates {
+			if withinTolerance(c.BuyRate, anchor.BuyRate, q.Tolerance) && withinTolerance(c.SellRate, anchor.SellRate, q.Tolerance) {
+				group = append(group, c)
+			}
+		}
+		if len(group) > len(best) {
+			best = group
+		}
+	}
+	return best
+}
+
+func (q *QuorumProvider) emit(code, reason string) {
+	if q.OnEvent == nil {
+		return
+	}
+	clock := q.Clock
+	if clock == nil {
+		clock = SystemClock
+	}
+	q.OnEvent(Event{Type: EventRateDisagreement, Time: clock.Now(), Pair: strings.ToUpper(q.Base) + "/" + code, Reason: reason})
+}
+
+// withinTolerance reports whether rate is within tolerance of reference,
+// relative to reference.
+func withinTolerance(rate, reference, tolerance decimal.Decimal) bool {
+	if reference.IsZero() {
+		return rate.IsZero()
+	}
+	return rate.Sub(reference).Abs().DivRound(reference.Abs(), maxDigits).LessThanOrEqual(tolerance)
+}
+
+// medianRate returns the median of a rate of currencies, which must not be
+// empty.
+func medianRate(currencies []Currency, rate func(Currency) decimal.Decimal) decimal.Decimal {
+	rates := make([]decimal.Decimal, len(currencies))
+	for i, c := range currencies {
+		rates[i] = rate(c)
+	}
+	return median(rates)
+}
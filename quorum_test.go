@@ -0,0 +1,85 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// feed returns a provider of testCurrencies with NGN selling at each of
+// sells in turn, or failing on a zero.
+func feed(sells ...int64) RateProvider {
+	fetch := 0
+	return RateProviderFunc(func(context.Context) ([]Currency, error) {
+		sell := sells[min(fetch, len(sells)-1)]
+		fetch++
+		if sell == 0 {
+			return nil, errors.New("feed down")
+		}
+		return ngnSnapshot(sell, time.Time{}).Currencies, nil
+	})
+}
+
+func TestQuorumProvider(t *testing.T) {
+	now := time.Date(2024, 3, 28, 12, 0, 0, 0, time.UTC)
+	var events []Event
+	q := &QuorumProvider{
+		Providers: []RateProvider{feed(1550, 1550, 1550), feed(1552, 1600, 0), feed(1600, 1650, 1553)},
+		Quorum:    2,
+		Tolerance: decimal.RequireFromString("0.005"),
+		Base:      "usd",
+		OnEvent:   func(e Event) { events = append(events, e) },
+		Clock:     fixedClock(now),
+	}
+	ngnSell := func(currencies []Currency) string {
+		ngn, err := FindCurrency(currencies, "NGN")
+		require.NoError(t, err)
+		return ngn.SellRate.String()
+	}
+
+	// Two of three agree: the median of the agreeing rates is published.
+	currencies, err := q.FetchRates(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, currencies, 3)
+	assert.Equal(t, "1551", ngnSell(currencies))
+	assert.Empty(t, events)
+
+	// No two agree: NGN keeps its published rates; the others move on.
+	currencies, err = q.FetchRates(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, currencies, 3)
+	assert.Equal(t, "1551", ngnSell(currencies))
+	assert.Equal(t, []Event{{Type: EventRateDisagreement, Time: now, Pair: "USD/NGN", Reason: "1 of 3 providers agree, 2 required"}}, events)
+
+	// A failed provider counts as disagreeing.
+	currencies, err = q.FetchRates(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "1551.5", ngnSell(currencies))
+}
+
+func TestQuorumProvider_NoQuorum(t *testing.T) {
+	q := &QuorumProvider{Providers: []RateProvider{feed(1550), feed(0)}, Quorum: 2}
+	_, err := q.FetchRates(context.Background())
+	assert.ErrorIs(t, err, ErrNoQuorum)
+	assert.ErrorContains(t, err, "feed down")
+
+	q.Quorum = 3
+	_, err = q.FetchRates(context.Background())
+	assert.ErrorIs(t, err, ErrInvalidQuorum)
+	q.Quorum = 0
+	_, err = q.FetchRates(context.Background())
+	assert.ErrorIs(t, err, ErrInvalidQuorum)
+}
+
+func TestWithinTolerance(t *testing.T) {
+	d := decimal.RequireFromString
+	assert.True(t, withinTolerance(d("100.5"), d("100"), d("0.005")))
+	assert.False(t, withinTolerance(d("100.6"), d("100"), d("0.005")))
+	assert.True(t, withinTolerance(d("0"), d("0"), d("0")))
+	assert.False(t, withinTolerance(d("1"), d("0"), d("0.5")))
+}
@@ -0,0 +1,52 @@
+package converter
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"time"
+)
+
+// quoteCSVHeader is the stable column set of ExportQuotesCSV.
+var quoteCSVHeader = []string{
+	"id", "reference", "customerRef", "pair", "fromCurrency", "toCurrency",
+	"fromAmount", "fee", "feeMode", "feeCurrency", "amountToDeduct", "rate",
+	"spread", "finalAmount", "effectiveRate", "status", "cancelReason",
+	"date", "expiresAt", "valueDate",
+}
+
+// ExportQuotesCSV writes the quotes of store matching filter as CSV with a
+// header row, oldest first, for reconciliation in spreadsheets. Amounts
+// keep every digit and times are RFC 3339 in UTC, empty when unset.
+func ExportQuotesCSV(ctx context.Context, w io.Writer, store QuoteStore, filter QuoteFilter) error {
+	page, err := store.List(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(quoteCSVHeader); err != nil {
+		return err
+	}
+	for _, q := range page.Quotes {
+		record := []string{
+			q.ID, q.Reference, q.CustomerRef, q.FromCurrency + "/" + q.ToCurrency, q.FromCurrency, q.ToCurrency,
+			q.FromAmount.String(), q.Fee.String(), string(q.FeeMode), q.FeeCurrency, q.AmountToDeduct.String(), q.Rate.String(),
+			q.Spread.String(), q.FinalAmount.String(), q.EffectiveRate.String(), string(q.Status), string(q.CancelReason),
+			csvTime(q.Date), csvTime(q.ExpiresAt), csvTime(q.ValueDate),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvTime formats t for CSV exports, or "" for the zero time.
+func csvTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
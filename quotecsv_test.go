@@ -0,0 +1,49 @@
+package converter
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportQuotesCSV(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryQuoteStore()
+
+	first, err := NewQuote(testCurrencies(), "USD", "USD", "NGN", decimal.NewFromInt(100), decimal.NewFromInt(2),
+		WithClock(fixedClock(now)), WithTTL(time.Minute), WithCustomerRef("cust-1"))
+	assert.NoError(t, err)
+	second, err := NewQuote(testCurrencies(), "USD", "EUR", "USD", decimal.NewFromInt(10), decimal.Zero, WithClock(fixedClock(now.Add(time.Hour))))
+	assert.NoError(t, err)
+	assert.NoError(t, store.Save(ctx, second))
+	assert.NoError(t, store.Save(ctx, first))
+
+	var buf bytes.Buffer
+	assert.NoError(t, ExportQuotesCSV(ctx, &buf, store, QuoteFilter{}))
+	records, err := csv.NewReader(&buf).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, records, 3)
+	assert.Equal(t, quoteCSVHeader, records[0])
+	assert.Equal(t, []string{
+		first.ID, first.Reference, "cust-1", "USD/NGN", "USD", "NGN",
+		"100", "2", "additive", "USD", "102", "1550",
+		"0", "155000", first.EffectiveRate.String(), "pending", "",
+		"2024-01-02T12:00:00Z", "2024-01-02T12:01:00Z", "",
+	}, records[1])
+	assert.Equal(t, second.ID, records[2][0])
+
+	buf.Reset()
+	assert.NoError(t, ExportQuotesCSV(ctx, &buf, store, QuoteFilter{FromCurrency: "EUR"}))
+	records, err = csv.NewReader(&buf).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+
+	err = ExportQuotesCSV(ctx, &buf, store, QuoteFilter{Limit: -1})
+	assert.ErrorIs(t, err, ErrInvalidQuoteFilter)
+}
@@ -0,0 +1,283 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Quote store errors
+var (
+	ErrQuoteNotFound      = errors.New("quote not found")
+	ErrInvalidQuoteFilter = errors.New("invalid quote filter")
+	ErrQuoteStatusChanged = errors.New("quote status changed")
+)
+
+// QuoteStore persists quotes and answers reporting queries over them.
+type QuoteStore interface {
+	// Save inserts or replaces a quote.
+	Save(ctx context.Context, quote *Quote) error
+	// Get returns the quote with the given ID or ErrQuoteNotFound.
+	Get(ctx context.Context, id string) (*Quote, error)
+	// UpdateStatus moves a quote from one status to another atomically. It
+	// returns ErrQuoteStatusChanged if the quote is no longer in status from,
+	// so concurrent writers cannot overwrite each other's transitions.
+	UpdateStatus(ctx context.Context, id string, from, to QuoteStatus) error
+	// List returns one page of quotes matching filter, oldest first.
+	List(ctx context.Context, filter QuoteFilter) (*QuotePage, error)
+	// Aggregate totals the quotes matching filter per pair per day.
+	Aggregate(ctx context.Context, filter QuoteFilter) ([]QuoteAggregate, error)
+}
+
+// QuoteFilter selects quotes. Zero-valued fields match everything.
+type QuoteFilter struct {
+	FromCurrency string
+	ToCurrency   string
+	// Since and Until bound the quote date: Since <= Date < Until.
+	Since       time.Time
+	Until       time.Time
+	Statuses    []QuoteStatus
+	CustomerRef string
+	// ExpiredAt, if set, matches quotes that have expired by then (see
+	// Quote.IsExpired). Quotes without an expiry never match.
+	ExpiredAt time.Time
+	// Offset and Limit page through the results. A zero Limit returns all.
+	// A negative Offset is treated as zero; a negative Limit is invalid.
+	Offset int
+	Limit  int
+}
+
+// Validate checks the paging fields of the filter.
+func (f QuoteFilter) Validate() error {
+	if f.Limit < 0 {
+		return fmt.Errorf("%w: negative limit %d", ErrInvalidQuoteFilter, f.Limit)
+	}
+	return nil
+}
+
+// Match reports whether a quote satisfies the filter, ignoring paging.
+func (f QuoteFilter) Match(q *Quote) bool {
+	if f.FromCurrency != "" && !strings.EqualFold(f.FromCurrency, q.FromCurrency) {
+		return false
+	}
+	if f.ToCurrency != "" && !strings.EqualFold(f.ToCurrency, q.ToCurrency) {
+		return false
+	}
+	if !f.Since.IsZero() && q.Date.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !q.Date.Before(f.Until) {
+		return false
+	}
+	if f.CustomerRef != "" && f.CustomerRef != q.CustomerRef {
+		return false
+	}
+	if !f.ExpiredAt.IsZero() && !q.IsExpired(f.ExpiredAt) {
+		return false
+	}
+	if len(f.Statuses) > 0 {
+		found := false
+		for _, status := range f.Statuses {
+			if status == q.Status {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// QuotePage is one page of a quote listing.
+type QuotePage struct {
+	Quotes []*Quote `json:"quotes"`
+	// Total is the number of matching quotes across all pages.
+	Total int `json:"total"`
+	// NextOffset is the offset of the next page, or zero on the last page.
+	NextOffset int `json:"nextOffset"`
+}
+
+// QuoteAggregate holds the totals for one currency pair on one day (UTC).
+type QuoteAggregate struct {
+	Day          time.Time       `json:"day"`
+	FromCurrency string          `json:"fromCurrency"`
+	ToCurrency   string          `json:"toCurrency"`
+	Count        int             `json:"count"`
+	FromVolume   decimal.Decimal `json:"fromVolume"`
+	ToVolume     decimal.Decimal `json:"toVolume"`
+	// SourceFees and DestinationFees total the fees charged in the from
+	// and the to currency, which are never added together.
+	SourceFees      decimal.Decimal `json:"sourceFees"`
+	DestinationFees decimal.Decimal `json:"destinationFees"`
+}
+
+// MemoryQuoteStore is an in-memory QuoteStore.
+type MemoryQuoteStore struct {
+	mu     sync.RWMutex
+	quotes map[string]Quote
+}
+
+// NewMemoryQuoteStore creates an empty in-memory quote store.
+func NewMemoryQuoteStore() *MemoryQuoteStore {
+	return &MemoryQuoteStore{quotes: map[string]Quote{}}
+}
+
+// Save implements QuoteStore.
+func (s *MemoryQuoteStore) Save(_ context.Context, quote *Quote) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.quotes[quote.ID] = *copyQuote(*quote)
+	return nil
+}
+
+// Get implements QuoteStore.
+func (s *MemoryQuoteStore) Get(_ context.Context, id string) (*Quote, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	q, ok := s.quotes[id]
+	if !ok {
+		return nil, ErrQuoteNotFound
+	}
+	return copyQuote(q), nil
+}
+
+// UpdateStatus implements QuoteStore.
+func (s *MemoryQuoteStore) UpdateStatus(_ context.Context, id string, from, to QuoteStatus) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, ok := s.quotes[id]
+	if !ok {
+		return ErrQuoteNotFound
+	}
+	if q.Status != from {
+		return fmt.Errorf("%w: %s is %s, not %s", ErrQuoteStatusChanged, id, q.Status, from)
+	}
+
+	q.Status = to
+	s.quotes[id] = q
+	return nil
+}
+
+// List implements QuoteStore.
+func (s *MemoryQuoteStore) List(_ context.Context, filter QuoteFilter) (*QuotePage, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	matched := s.match(filter)
+
+	page := &QuotePage{Total: len(matched)}
+	start := min(max(filter.Offset, 0), len(matched))
+	end := len(matched)
+	if filter.Limit > 0 && start+filter.Limit < end {
+		end = start + filter.Limit
+		page.NextOffset = end
+	}
+	page.Quotes = matched[start:end]
+
+	return page, nil
+}
+
+// Aggregate implements QuoteStore.
+func (s *MemoryQuoteStore) Aggregate(_ context.Context, filter QuoteFilter) ([]QuoteAggregate, error) {
+	return aggregateQuotes(s.match(filter)), nil
+}
+
+// match returns copies of the quotes matching filter, oldest first.
+func (s *MemoryQuoteStore) match(filter QuoteFilter) []*Quote {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var matched []*Quote
+	for _, q := range s.quotes {
+		if filter.Match(&q) {
+			matched = append(matched, copyQuote(q))
+		}
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		if !matched[i].Date.Equal(matched[j].Date) {
+			return matched[i].Date.Before(matched[j].Date)
+		}
+		return matched[i].ID < matched[j].ID
+	})
+
+	return matched
+}
+
+// aggregateQuotes totals quotes per pair per day, ordered by day and pair.
+func aggregateQuotes(quotes []*Quote) []QuoteAggregate {
+	type key struct {
+		day      time.Time
+		from, to string
+	}
+
+	var (
+		index      = map[key]int{}
+		aggregates []QuoteAggregate
+	)
+	for _, q := range quotes {
+		k := key{
+			day:  q.Date.UTC().Truncate(24 * time.Hour),
+			from: strings.ToUpper(q.FromCurrency),
+			to:   strings.ToUpper(q.ToCurrency),
+		}
+		i, ok := index[k]
+		if !ok {
+			i = len(aggregates)
+			index[k] = i
+			aggregates = append(aggregates, QuoteAggregate{Day: k.day, FromCurrency: k.from, ToCurrency: k.to})
+		}
+
+		a := &aggregates[i]
+		a.Count++
+		a.FromVolume = a.FromVolume.Add(q.FromAmount)
+		a.ToVolume = a.ToVolume.Add(q.FinalAmount)
+		if feeInDestination(q) {
+			a.DestinationFees = a.DestinationFees.Add(q.Fee)
+		} else {
+			a.SourceFees = a.SourceFees.Add(q.Fee)
+		}
+	}
+
+	sort.SliceStable(aggregates, func(i, j int) bool {
+		a, b := aggregates[i], aggregates[j]
+		if !a.Day.Equal(b.Day) {
+			return a.Day.Before(b.Day)
+		}
+		if a.FromCurrency != b.FromCurrency {
+			return a.FromCurrency < b.FromCurrency
+		}
+		return a.ToCurrency < b.ToCurrency
+	})
+
+	return aggregates
+}
+
+// feeInDestination reports whether q's fee is in its to currency. Quotes
+// without a fee currency predate it and were charged in the from currency,
+// unless their fee mode says otherwise.
+func feeInDestination(q *Quote) bool {
+	if q.FeeCurrency == "" {
+		return q.FeeMode == FeeInDestination
+	}
+	return strings.EqualFold(q.FeeCurrency, q.ToCurrency) && !strings.EqualFold(q.FeeCurrency, q.FromCurrency)
+}
+
+// copyQuote returns a copy of q that shares no slices with it.
+func copyQuote(q Quote) *Quote {
+	q.Flags = slices.Clone(q.Flags)
+	return &q
+}
@@ -0,0 +1,188 @@
+package converter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func seedQuoteStore(t *testing.T) *MemoryQuoteStore {
+	t.Helper()
+
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	quotes := []*Quote{
+		{ID: "q1", FromCurrency: "USD", ToCurrency: "NGN", FromAmount: decimal.NewFromInt(100), Fee: decimal.NewFromInt(1), FinalAmount: decimal.NewFromInt(150000), Date: day.Add(1 * time.Hour), Status: QuoteStatusPending, CustomerRef: "c1"},
+		{ID: "q2", FromCurrency: "USD", ToCurrency: "NGN", FromAmount: decimal.NewFromInt(50), Fee: decimal.NewFromInt(1), FinalAmount: decimal.NewFromInt(75000), Date: day.Add(2 * time.Hour), Status: QuoteStatusExecuted, CustomerRef: "c2"},
+		{ID: "q3", FromCurrency: "USD", ToCurrency: "EUR", FromAmount: decimal.NewFromInt(10), Fee: decimal.Zero, FinalAmount: decimal.NewFromInt(9), Date: day.Add(3 * time.Hour), Status: QuoteStatusExecuted, CustomerRef: "c1"},
+		{ID: "q4", FromCurrency: "USD", ToCurrency: "NGN", FromAmount: decimal.NewFromInt(20), Fee: decimal.NewFromInt(2), FinalAmount: decimal.NewFromInt(30000), Date: day.Add(25 * time.Hour), ExpiresAt: day.Add(26 * time.Hour), Status: QuoteStatusExpired, CustomerRef: "c1"},
+	}
+
+	store := NewMemoryQuoteStore()
+	for _, q := range quotes {
+		assert.NoError(t, store.Save(context.Background(), q))
+	}
+	return store
+}
+
+func TestMemoryQuoteStore_SaveGet(t *testing.T) {
+	ctx := context.Background()
+	store := seedQuoteStore(t)
+
+	q, err := store.Get(ctx, "q1")
+	assert.NoError(t, err)
+	assert.Equal(t, "NGN", q.ToCurrency)
+
+	// Returned quotes are copies
+	q.Status = QuoteStatusCancelled
+	again, _ := store.Get(ctx, "q1")
+	assert.Equal(t, QuoteStatusPending, again.Status)
+
+	// Save replaces
+	assert.NoError(t, store.Save(ctx, q))
+	again, _ = store.Get(ctx, "q1")
+	assert.Equal(t, QuoteStatusCancelled, again.Status)
+
+	_, err = store.Get(ctx, "missing")
+	assert.ErrorIs(t, err, ErrQuoteNotFound)
+}
+
+func TestMemoryQuoteStore_UpdateStatus(t *testing.T) {
+	ctx := context.Background()
+	store := seedQuoteStore(t)
+
+	assert.NoError(t, store.UpdateStatus(ctx, "q1", QuoteStatusPending, QuoteStatusAccepted))
+	q, _ := store.Get(ctx, "q1")
+	assert.Equal(t, QuoteStatusAccepted, q.Status)
+
+	// The transition only applies from the expected status
+	assert.ErrorIs(t, store.UpdateStatus(ctx, "q1", QuoteStatusPending, QuoteStatusExpired), ErrQuoteStatusChanged)
+	q, _ = store.Get(ctx, "q1")
+	assert.Equal(t, QuoteStatusAccepted, q.Status)
+
+	assert.ErrorIs(t, store.UpdateStatus(ctx, "missing", QuoteStatusPending, QuoteStatusExpired), ErrQuoteNotFound)
+}
+
+func TestMemoryQuoteStore_List(t *testing.T) {
+	ctx := context.Background()
+	store := seedQuoteStore(t)
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name   string
+		filter QuoteFilter
+		ids    []string
+	}{
+		{name: "All", filter: QuoteFilter{}, ids: []string{"q1", "q2", "q3", "q4"}},
+		{name: "By pair", filter: QuoteFilter{FromCurrency: "usd", ToCurrency: "ngn"}, ids: []string{"q1", "q2", "q4"}},
+		{name: "By date range", filter: QuoteFilter{Since: day.Add(2 * time.Hour), Until: day.Add(24 * time.Hour)}, ids: []string{"q2", "q3"}},
+		{name: "By status", filter: QuoteFilter{Statuses: []QuoteStatus{QuoteStatusExecuted, QuoteStatusExpired}}, ids: []string{"q2", "q3", "q4"}},
+		{name: "By customer", filter: QuoteFilter{CustomerRef: "c1", ToCurrency: "NGN"}, ids: []string{"q1", "q4"}},
+		{name: "By expiry", filter: QuoteFilter{ExpiredAt: day.Add(26 * time.Hour)}, ids: []string{"q4"}},
+		{name: "Not yet expired", filter: QuoteFilter{ExpiredAt: day.Add(25 * time.Hour)}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			page, err := store.List(ctx, tc.filter)
+			assert.NoError(t, err)
+			assert.Equal(t, len(tc.ids), page.Total)
+
+			var ids []string
+			for _, q := range page.Quotes {
+				ids = append(ids, q.ID)
+			}
+			assert.Equal(t, tc.ids, ids)
+		})
+	}
+}
+
+func TestMemoryQuoteStore_ListPagination(t *testing.T) {
+	ctx := context.Background()
+	store := seedQuoteStore(t)
+
+	page, err := store.List(ctx, QuoteFilter{Limit: 3})
+	assert.NoError(t, err)
+	assert.Len(t, page.Quotes, 3)
+	assert.Equal(t, 4, page.Total)
+	assert.Equal(t, 3, page.NextOffset)
+
+	page, err = store.List(ctx, QuoteFilter{Limit: 3, Offset: page.NextOffset})
+	assert.NoError(t, err)
+	assert.Len(t, page.Quotes, 1)
+	assert.Equal(t, "q4", page.Quotes[0].ID)
+	assert.Zero(t, page.NextOffset)
+
+	page, err = store.List(ctx, QuoteFilter{Offset: 10})
+	assert.NoError(t, err)
+	assert.Empty(t, page.Quotes)
+	assert.Equal(t, 4, page.Total)
+
+	// A negative offset starts at the beginning
+	page, err = store.List(ctx, QuoteFilter{Limit: 1, Offset: -5})
+	assert.NoError(t, err)
+	assert.Equal(t, "q1", page.Quotes[0].ID)
+	assert.Equal(t, 1, page.NextOffset)
+
+	_, err = store.List(ctx, QuoteFilter{Limit: -1})
+	assert.ErrorIs(t, err, ErrInvalidQuoteFilter)
+}
+
+func TestMemoryQuoteStore_Aggregate(t *testing.T) {
+	ctx := context.Background()
+	store := seedQuoteStore(t)
+
+	aggregates, err := store.Aggregate(ctx, QuoteFilter{})
+	assert.NoError(t, err)
+	assert.Len(t, aggregates, 3)
+
+	first := aggregates[0]
+	assert.Equal(t, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), first.Day)
+	assert.Equal(t, "USD", first.FromCurrency)
+	assert.Equal(t, "EUR", first.ToCurrency)
+
+	second := aggregates[1]
+	assert.Equal(t, "NGN", second.ToCurrency)
+	assert.Equal(t, 2, second.Count)
+	assert.Equal(t, "150", second.FromVolume.String())
+	assert.Equal(t, "225000", second.ToVolume.String())
+	assert.Equal(t, "2", second.SourceFees.String())
+	assert.True(t, second.DestinationFees.IsZero())
+
+	// Filters apply before aggregation
+	aggregates, err = store.Aggregate(ctx, QuoteFilter{Statuses: []QuoteStatus{QuoteStatusExecuted}})
+	assert.NoError(t, err)
+	assert.Len(t, aggregates, 2)
+}
+
+func TestMemoryQuoteStore_AggregateFeeCurrencies(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryQuoteStore()
+	for _, q := range []*Quote{
+		{ID: "q1", FromCurrency: "USD", ToCurrency: "NGN", Fee: decimal.NewFromInt(2), FeeCurrency: "USD", Date: day},
+		{ID: "q2", FromCurrency: "USD", ToCurrency: "NGN", Fee: decimal.NewFromInt(2000), FeeMode: FeeInDestination, FeeCurrency: "NGN", Date: day},
+		{ID: "q3", FromCurrency: "USD", ToCurrency: "NGN", Fee: decimal.NewFromInt(500), FeeMode: FeeInDestination, Date: day},
+	} {
+		assert.NoError(t, store.Save(ctx, q))
+	}
+
+	aggregates, err := store.Aggregate(ctx, QuoteFilter{})
+	assert.NoError(t, err)
+	assert.Len(t, aggregates, 1)
+	assert.Equal(t, "2", aggregates[0].SourceFees.String())
+	assert.Equal(t, "2500", aggregates[0].DestinationFees.String())
+}
+
+func TestMemoryQuoteStore_Flags(t *testing.T) {
+	store := NewMemoryQuoteStore()
+	quote := &Quote{ID: "q1", Flags: []QuoteFlag{FlagReviewRequired}}
+	assert.NoError(t, store.Save(context.Background(), quote))
+	quote.Flags[0] = "changed"
+
+	stored, err := store.Get(context.Background(), "q1")
+	assert.NoError(t, err)
+	assert.Equal(t, []QuoteFlag{FlagReviewRequired}, stored.Flags)
+}
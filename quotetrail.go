@@ -0,0 +1,94 @@
+package converter
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"time"
+)
+
+// QuoteTrail records the state transitions of quotes, from creation to
+// execution, expiry or cancellation, and exports them per quote for dispute
+// resolution. Feed it events by setting its Handle method as the OnEvent of
+// Guarantees and Sweeper, and call Created for new quotes. Trails are held
+// in memory.
+type QuoteTrail struct {
+	mu     sync.Mutex
+	events map[string][]Event
+}
+
+// NewQuoteTrail creates an empty trail.
+func NewQuoteTrail() *QuoteTrail {
+	return &QuoteTrail{events: make(map[string][]Event)}
+}
+
+// Handle records an event about a quote. Events without a quote ID are
+// ignored. It is an EventHandler.
+func (t *QuoteTrail) Handle(e Event) {
+	if e.QuoteID == "" {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.events[e.QuoteID] = append(t.events[e.QuoteID], e)
+}
+
+// Created records the creation of a quote at its date, by the actor of ctx.
+func (t *QuoteTrail) Created(ctx context.Context, q *Quote) {
+	t.Handle(Event{
+		Type:    EventQuoteCreated,
+		Time:    q.Date,
+		QuoteID: q.ID,
+		Pair:    q.FromCurrency + "/" + q.ToCurrency,
+		Actor:   ActorFromContext(ctx),
+	})
+}
+
+// Events returns the transitions of a quote in the order they were
+// recorded.
+func (t *QuoteTrail) Events(id string) []Event {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return slices.Clone(t.events[id])
+}
+
+// ExportJSON writes the transitions of a quote as a JSON array. It returns
+// ErrQuoteNotFound if the trail has none.
+func (t *QuoteTrail) ExportJSON(w io.Writer, id string) error {
+	events := t.Events(id)
+	if len(events) == 0 {
+		return fmt.Errorf("%w: %s", ErrQuoteNotFound, id)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(events)
+}
+
+// ExportCSV writes the transitions of a quote as CSV with a header row. It
+// returns ErrQuoteNotFound if the trail has none.
+func (t *QuoteTrail) ExportCSV(w io.Writer, id string) error {
+	events := t.Events(id)
+	if len(events) == 0 {
+		return fmt.Errorf("%w: %s", ErrQuoteNotFound, id)
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"quoteId", "type", "time", "actor", "pair", "reason"}); err != nil {
+		return err
+	}
+	for _, e := range events {
+		record := []string{e.QuoteID, string(e.Type), e.Time.UTC().Format(time.RFC3339Nano), e.Actor, e.Pair, e.Reason}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,62 @@
+package converter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuoteTrail(t *testing.T) {
+	var (
+		ctx   = ContextWithActor(context.Background(), "ops:ada")
+		now   = time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+		trail = NewQuoteTrail()
+		store = NewMemoryQuoteStore()
+	)
+	quote, err := NewQuote(testCurrencies(), "USD", "USD", "NGN", decimal.NewFromInt(100), decimal.Zero, WithClock(fixedClock(now)))
+	assert.NoError(t, err)
+	assert.NoError(t, store.Save(ctx, quote))
+	trail.Created(ctx, quote)
+
+	g, err := NewGuarantees(store, time.Hour)
+	assert.NoError(t, err)
+	g.Clock = fixedClock(now.Add(time.Minute))
+	g.OnEvent = trail.Handle
+
+	_, err = g.Accept(ctx, quote.ID)
+	assert.NoError(t, err)
+	_, err = g.Amend(ctx, quote.ID, decimal.NewFromInt(50))
+	assert.NoError(t, err)
+	_, err = g.Execute(ContextWithActor(ctx, "desk:bob"), quote.ID, decimal.NewFromInt(50))
+	assert.NoError(t, err)
+	trail.Handle(Event{Type: EventQuoteBlocked})
+
+	var types []EventType
+	for _, e := range trail.Events(quote.ID) {
+		types = append(types, e.Type)
+	}
+	assert.Equal(t, []EventType{EventQuoteCreated, EventQuoteAccepted, EventQuoteLocked, EventQuoteAmended, EventQuoteExecuted}, types)
+
+	var buf bytes.Buffer
+	assert.NoError(t, trail.ExportJSON(&buf, quote.ID))
+	var exported []Event
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &exported))
+	assert.Equal(t, trail.Events(quote.ID), exported)
+
+	buf.Reset()
+	assert.NoError(t, trail.ExportCSV(&buf, quote.ID))
+	assert.Equal(t, "quoteId,type,time,actor,pair,reason\n"+
+		quote.ID+",quote.created,2024-01-02T12:00:00Z,ops:ada,USD/NGN,\n"+
+		quote.ID+",quote.accepted,2024-01-02T12:01:00Z,ops:ada,USD/NGN,\n"+
+		quote.ID+",quote.locked,2024-01-02T12:01:00Z,ops:ada,USD/NGN,\n"+
+		quote.ID+",quote.amended,2024-01-02T12:01:00Z,ops:ada,USD/NGN,\n"+
+		quote.ID+",quote.executed,2024-01-02T12:01:00Z,desk:bob,USD/NGN,\n", buf.String())
+
+	assert.ErrorIs(t, trail.ExportJSON(&buf, "missing"), ErrQuoteNotFound)
+	assert.ErrorIs(t, trail.ExportCSV(&buf, "missing"), ErrQuoteNotFound)
+}
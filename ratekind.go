@@ -0,0 +1,103 @@
+package converter
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// RateKind selects which side of the currencies' rates a rate is priced at.
+type RateKind int
+
+// Rate kinds
+const (
+	// RateMid prices at the midpoint of each currency's buy and sell
+	// rates, without spread.
+	RateMid RateKind = iota
+	// RateBuy prices at the currencies' buy rates.
+	RateBuy
+	// RateSell prices at the currencies' sell rates.
+	RateSell
+)
+
+// String returns the name of the rate kind.
+func (k RateKind) String() string {
+	switch k {
+	case RateMid:
+		return "mid"
+	case RateBuy:
+		return "buy"
+	case RateSell:
+		return "sell"
+	default:
+		return "unknown"
+	}
+}
+
+// CalculateRateKind calculates the rate from one currency to another at the
+// chosen side of the rates, instead of CalculateRate's convention of the
+// target's sell rate over the source's buy rate: RateSell divides the sell
+// rates, RateBuy the buy rates and RateMid the midpoints, following
+// CalculateRate's routing otherwise.
+func CalculateRateKind(currencies []Currency, baseCurrency, from, to string, kind RateKind) (decimal.Decimal, error) {
+	sided, err := ratesAt(currencies, kind)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return CalculateRate(sided, baseCurrency, from, to)
+}
+
+// ratesAt returns copies of currencies with both rates set to the chosen
+// side.
+func ratesAt(currencies []Currency, kind RateKind) ([]Currency, error) {
+	half := decimal.New(5, -1)
+
+	sided := make([]Currency, len(currencies))
+	for i, c := range currencies {
+		switch kind {
+		case RateMid:
+			c.BuyRate = c.BuyRate.Add(c.SellRate).Mul(half)
+			c.SellRate = c.BuyRate
+		case RateBuy:
+			c.SellRate = c.BuyRate
+		case RateSell:
+			c.BuyRate = c.SellRate
+		default:
+			return nil, fmt.Errorf("unknown rate kind %d", kind)
+		}
+		sided[i] = c
+	}
+	return sided, nil
+}
+
+// CalculateRateKind calculates a rate at the chosen side of the table's
+// rates (see the CalculateRateKind function), with the table's division and
+// arithmetic. The table's spread policy applies to buy and sell rates, not
+// to mid rates.
+func (c *Currencies) CalculateRateKind(baseCurrency, from, to string, kind RateKind) (decimal.Decimal, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	items, _ := c.applyPegs(c.pricingItems(baseCurrency, from, to), baseCurrency, from, to)
+	items, err := ratesAt(items, kind)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	spread := decimal.Zero
+	if kind != RateMid {
+		spread = c.spreadFor(from, to)
+	}
+
+	if c.exact {
+		r, err := CalculateRateExact(items, baseCurrency, from, to)
+		if err != nil {
+			return decimal.Zero, err
+		}
+		return RatToDecimal(applySpreadExact(r, spread), exactDivision(c.division)), nil
+	}
+	rate, err := calculateRate(items, baseCurrency, from, to, c.division)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return applySpread(rate, spread), nil
+}
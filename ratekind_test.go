@@ -0,0 +1,55 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateRateKind(t *testing.T) {
+	testCases := []struct {
+		from, to string
+		kind     RateKind
+		rate     string
+	}{
+		{from: "USD", to: "NGN", kind: RateSell, rate: "1550"},
+		{from: "USD", to: "NGN", kind: RateBuy, rate: "1500"},
+		{from: "USD", to: "NGN", kind: RateMid, rate: "1525"},
+		// The default convention would divide by the NGN buy rate.
+		{from: "NGN", to: "USD", kind: RateSell, rate: "0.0006451612903226"},
+		{from: "EUR", to: "NGN", kind: RateBuy, rate: "1666.66666666666665"},
+		{from: "EUR", to: "NGN", kind: RateMid, rate: "1648.6486486486486775"},
+	}
+	for _, tc := range testCases {
+		rate, err := CalculateRateKind(testCurrencies(), "USD", tc.from, tc.to, tc.kind)
+		assert.NoError(t, err, tc.kind.String())
+		assert.Equal(t, tc.rate, rate.String(), "%s %s/%s", tc.kind, tc.from, tc.to)
+	}
+
+	_, err := CalculateRateKind(testCurrencies(), "USD", "USD", "NGN", RateKind(9))
+	assert.Error(t, err)
+	assert.Equal(t, "unknown", RateKind(9).String())
+}
+
+func TestCurrencies_CalculateRateKind(t *testing.T) {
+	table := NewTable(testCurrencies(), WithSpreadPolicy(SpreadPolicy{
+		Pairs: map[string]decimal.Decimal{"USD/NGN": decimal.NewFromFloat(0.01)},
+	}))
+
+	rate, err := table.CalculateRateKind("USD", "USD", "NGN", RateMid)
+	assert.NoError(t, err)
+	assert.Equal(t, "1525", rate.String())
+
+	rate, err = table.CalculateRateKind("USD", "USD", "NGN", RateBuy)
+	assert.NoError(t, err)
+	assert.Equal(t, "1485", rate.String())
+
+	exact := NewTable(testCurrencies(), WithExactArithmetic())
+	rate, err = exact.CalculateRateKind("USD", "NGN", "USD", RateSell)
+	assert.NoError(t, err)
+	assert.Equal(t, "0.0006451612903226", rate.String())
+
+	_, err = table.CalculateRateKind("USD", "USD", "NGN", RateKind(-1))
+	assert.Error(t, err)
+}
@@ -0,0 +1,59 @@
+package converter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// NewCurrenciesFromJSON reads a JSON array of currencies, such as a rate file
+// or an HTTP response body, decoding rates as NewCurrencies does. Data after
+// the array is an error. Limit untrusted bodies with http.MaxBytesReader or
+// io.LimitReader.
+func NewCurrenciesFromJSON(r io.Reader) ([]Currency, error) {
+	dec := json.NewDecoder(r)
+
+	var currencies []Currency
+	if err := dec.Decode(&currencies); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, ErrEmptyCurrencySource
+		}
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("json: unexpected data after currencies")
+	}
+
+	if err := checkIngested(currencies); err != nil {
+		return nil, err
+	}
+	return currencies, nil
+}
+
+// NewCurrenciesFromFile reads currencies from a file by its extension: CSV
+// for .csv, the first sheet of a workbook for .xlsx, and JSON otherwise.
+func NewCurrenciesFromFile(path string) ([]Currency, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var currencies []Currency
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		currencies, err = NewCurrenciesFromCSV(f)
+	case ".xlsx":
+		currencies, err = newCurrenciesFromXLSXFile(f)
+	default:
+		currencies, err = NewCurrenciesFromJSON(f)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return currencies, nil
+}
@@ -0,0 +1,67 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCurrenciesFromJSON(t *testing.T) {
+	currencies, err := NewCurrenciesFromJSON(strings.NewReader(`[
+		{"isoCode":"USD","precision":2,"buyRate":1,"sellRate":1},
+		{"isoCode":"BTC","precision":8,"buyRate":0.0000152345678901234567,"sellRate":"1.5e-5"}
+	]`))
+	assert.NoError(t, err)
+	assert.Len(t, currencies, 2)
+	assert.Equal(t, "0.0000152345678901234567", currencies[1].BuyRate.String())
+
+	testCases := []struct {
+		name string
+		json string
+		err  error
+	}{
+		{name: "Empty input", json: "", err: ErrEmptyCurrencySource},
+		{name: "Empty array", json: "[]", err: ErrEmptyCurrencySource},
+		{name: "Not an array", json: `{"isoCode":"USD"}`},
+		{name: "Trailing data", json: `[{"isoCode":"USD"}] []`},
+		{name: "Missing code", json: `[{"ccy":"USD"}]`, err: ErrMissingISOCode},
+		{name: "Bad rate", json: `[{"isoCode":"USD","buyRate":"one"}]`, err: ErrInvalidRate},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewCurrenciesFromJSON(strings.NewReader(tc.json))
+			assert.Error(t, err)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestNewCurrenciesFromFile(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "rates.json")
+	csvPath := filepath.Join(dir, "rates.CSV")
+	assert.NoError(t, os.WriteFile(jsonPath, []byte(`[{"isoCode":"USD","precision":2,"buyRate":1,"sellRate":1}]`), 0o600))
+	assert.NoError(t, os.WriteFile(csvPath, []byte("isoCode,precision,buyRate,sellRate\nEUR,2,0.9,0.95\n"), 0o600))
+
+	currencies, err := NewCurrenciesFromFile(jsonPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", currencies[0].ISOCode)
+
+	currencies, err = NewCurrenciesFromFile(csvPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "EUR", currencies[0].ISOCode)
+
+	_, err = NewCurrenciesFromFile(filepath.Join(dir, "missing.json"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	assert.NoError(t, os.WriteFile(jsonPath, []byte(`[]`), 0o600))
+	_, err = NewCurrenciesFromFile(jsonPath)
+	assert.ErrorIs(t, err, ErrEmptyCurrencySource)
+	assert.ErrorContains(t, err, jsonPath)
+}
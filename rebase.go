@@ -0,0 +1,63 @@
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// RebaseTo returns a copy of the table with every rate expressed per unit
+// of another of its currencies, so one USD-based feed can serve EUR-based
+// books. Rates follow cross-rate math with both currencies' spreads: a
+// currency's buy rate becomes buy/base sell rate, rounded up, and its sell
+// rate sell/base buy rate, rounded down, to the table's division precision
+// (the global DivisionPrecision without one); the new base gets 1 for both.
+// Rates frozen by a closed market policy restart from the rebased rates.
+//
+// It returns ErrBaseCurrencyNotFound if base is not in the table and an
+// ErrInvalidRate error if its rates are not positive. Tables whose currencies
+// carry their own bases (see Currency.BaseISOCode) cannot be rebased.
+func (c *Currencies) RebaseTo(base string) (*Currencies, error) {
+	s := c.Snapshot()
+	if hasOwnBases(s.Currencies) {
+		return nil, errors.New("cannot rebase a table whose currencies carry their own bases")
+	}
+
+	c.mu.RLock()
+	precision := int32(decimal.DivisionPrecision)
+	if c.division != nil {
+		precision = c.division.Precision
+	}
+	c.mu.RUnlock()
+
+	b, err := FindCurrency(s.Currencies, base)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrBaseCurrencyNotFound, base)
+	}
+	if !b.BuyRate.IsPositive() || !b.SellRate.IsPositive() {
+		return nil, fmt.Errorf("%w: cannot rebase to %s buy %s sell %s", ErrInvalidRate, b.ISOCode, b.BuyRate, b.SellRate)
+	}
+
+	one := decimal.NewFromInt(1)
+	up := Division{Precision: precision, Rounding: RoundCeiling}
+	down := Division{Precision: precision, Rounding: RoundFloor}
+
+	items := make([]Currency, len(s.Currencies))
+	for i, item := range s.Currencies {
+		if strings.EqualFold(item.ISOCode, b.ISOCode) {
+			item.BuyRate, item.SellRate = one, one
+		} else {
+			item.BuyRate, item.SellRate = up.Div(item.BuyRate, b.SellRate), down.Div(item.SellRate, b.BuyRate)
+		}
+		items[i] = item
+	}
+
+	rebased := c.derive(items, s.TakenAt)
+	rebased.mu.Lock()
+	rebased.closes = nil
+	rebased.recordClose()
+	rebased.mu.Unlock()
+	return rebased, nil
+}
@@ -0,0 +1,50 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurrencies_RebaseTo(t *testing.T) {
+	table := NewTable(testCurrencies(), WithDivision(Division{Precision: 6, Rounding: RoundHalfEven}))
+
+	rebased, err := table.RebaseTo("eur")
+	assert.NoError(t, err)
+
+	rates := map[string][2]string{}
+	for _, c := range rebased.List() {
+		rates[c.ISOCode] = [2]string{c.BuyRate.String(), c.SellRate.String()}
+	}
+	assert.Equal(t, map[string][2]string{
+		"EUR": {"1", "1"},
+		"NGN": {"1578.947369", "1722.222222"},
+		"USD": {"1.052632", "1.111111"},
+	}, rates)
+
+	// EUR to NGN prices the same against either base, within the rounding.
+	direct, err := table.CalculateRate("USD", "EUR", "NGN")
+	assert.NoError(t, err)
+	rebasedRate, err := rebased.CalculateRate("EUR", "EUR", "NGN")
+	assert.NoError(t, err)
+	assert.Equal(t, direct.StringFixed(2), rebasedRate.StringFixed(2))
+
+	_, err = table.RebaseTo("GBP")
+	assert.ErrorIs(t, err, ErrBaseCurrencyNotFound)
+
+	zero := NewTable([]Currency{
+		{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+		{ISOCode: "XXX", Precision: 2, BuyRate: decimal.Zero, SellRate: decimal.NewFromInt(1)},
+	})
+	_, err = zero.RebaseTo("XXX")
+	assert.ErrorIs(t, err, ErrInvalidRate)
+
+	ownBases := NewTable([]Currency{
+		{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+		{ISOCode: "GBP", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1), BaseISOCode: "USD"},
+		{ISOCode: "EUR", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1), BaseISOCode: "GBP"},
+	})
+	_, err = ownBases.RebaseTo("USD")
+	assert.ErrorContains(t, err, "carry their own bases")
+}
@@ -0,0 +1,6 @@
+// Package receipt renders receipts for quotes and their executions, as many
+// businesses must issue for FX transactions. WritePDF produces a one-page PDF
+// without dependencies beyond the standard library, and Template renders
+// user-supplied text or HTML templates. Amounts, dates, currency names and
+// wording follow the receipt's Locale.
+package receipt
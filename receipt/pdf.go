@@ -0,0 +1,175 @@
+package receipt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"strings"
+)
+
+// PDF errors
+var (
+	ErrInvalidLogo = errors.New("receipt: logo is not a JPEG image")
+	ErrNoQuote     = errors.New("receipt: no quote")
+)
+
+// Page layout of PDF receipts, in points on an A4 page.
+const (
+	pageWidth   = 595
+	pageHeight  = 842
+	margin      = 50
+	logoHeight  = 48
+	valueColumn = 230
+	lineHeight  = 22
+)
+
+// WritePDF renders the receipt as a one-page PDF: the logo, title and
+// issuer, then the line items (see Receipt.Lines). Text is set in
+// Helvetica, so characters outside Windows-1252 print as "?"; amounts are
+// written with ISO codes rather than symbols for that reason. The output
+// is deterministic.
+func WritePDF(w io.Writer, r Receipt) error {
+	if r.Quote == nil {
+		return ErrNoQuote
+	}
+
+	var logo *pdfImage
+	if len(r.Logo) > 0 {
+		var err error
+		if logo, err = newPDFImage(r.Logo); err != nil {
+			return err
+		}
+	}
+
+	var content bytes.Buffer
+	y := pageHeight - margin
+	if logo != nil {
+		width := logo.width * logoHeight / logo.height
+		y -= logoHeight
+		fmt.Fprintf(&content, "q %d 0 0 %d %d %d cm /Im1 Do Q\n", width, logoHeight, margin, y)
+		y -= 16
+	}
+	y -= 18
+	writeText(&content, "F2", 18, margin, y, r.title())
+	if r.Issuer != "" {
+		y -= 16
+		writeText(&content, "F1", 10, margin, y, r.Issuer)
+	}
+	y -= 2 * lineHeight
+	for _, line := range r.Lines() {
+		writeText(&content, "F2", 11, margin, y, line.Label)
+		writeText(&content, "F1", 11, valueColumn, y, line.Value)
+		y -= lineHeight
+	}
+
+	resources := "/Font << /F1 5 0 R /F2 6 0 R >>"
+	if logo != nil {
+		resources += " /XObject << /Im1 7 0 R >>"
+	}
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << %s >> /Contents 4 0 R >>", pageWidth, pageHeight, resources),
+		pdfStream("", content.Bytes()),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+	}
+	if logo != nil {
+		objects = append(objects, logo.object())
+	}
+	return writePDFObjects(w, objects)
+}
+
+// writeText sets one line of text at x, y.
+func writeText(b *bytes.Buffer, font string, size, x, y int, text string) {
+	fmt.Fprintf(b, "BT /%s %d Tf %d %d Td (%s) Tj ET\n", font, size, x, y, pdfString(text))
+}
+
+// pdfString encodes text in Windows-1252 and escapes it for a PDF string.
+func pdfString(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteByte(byte(r))
+		case r == '€':
+			b.WriteString(`\200`)
+		case r >= ' ' && r < 0x7f:
+			b.WriteByte(byte(r))
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&b, `\%03o`, r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// pdfStream returns a stream object with extra dictionary entries.
+func pdfStream(dict string, data []byte) string {
+	if dict != "" {
+		dict += " "
+	}
+	return fmt.Sprintf("<< %s/Length %d >>\nstream\n%s\nendstream", dict, len(data), data)
+}
+
+// writePDFObjects writes a PDF file of objects numbered from 1, the first
+// being the catalog, with its cross-reference table.
+func writePDFObjects(w io.Writer, objects []string) error {
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// pdfImage is a JPEG embedded as is, PDF readers decoding it natively.
+type pdfImage struct {
+	data          []byte
+	width, height int
+	colorSpace    string
+	decode        string
+}
+
+func newPDFImage(data []byte) (*pdfImage, error) {
+	config, err := jpeg.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidLogo, err)
+	}
+	img := &pdfImage{data: data, width: config.Width, height: config.Height}
+	switch config.ColorModel {
+	case color.GrayModel:
+		img.colorSpace = "/DeviceGray"
+	case color.CMYKModel:
+		// Adobe CMYK JPEGs store inverted values.
+		img.colorSpace, img.decode = "/DeviceCMYK", " /Decode [1 0 1 0 1 0 1 0]"
+	default:
+		img.colorSpace = "/DeviceRGB"
+	}
+	if img.width <= 0 || img.height <= 0 {
+		return nil, fmt.Errorf("%w: empty image", ErrInvalidLogo)
+	}
+	return img, nil
+}
+
+func (img *pdfImage) object() string {
+	dict := fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace %s /BitsPerComponent 8 /Filter /DCTDecode%s",
+		img.width, img.height, img.colorSpace, img.decode)
+	return pdfStream(dict, img.data)
+}
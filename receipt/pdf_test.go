@@ -0,0 +1,63 @@
+package receipt
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWritePDF(t *testing.T) {
+	q := testQuote(t)
+	var buf bytes.Buffer
+	assert.NoError(t, WritePDF(&buf, Receipt{Quote: q, Issuer: "Acme (FX) Ltd", Title: "Reçu"}))
+
+	pdf := buf.String()
+	assert.Regexp(t, `^%PDF-1.4\n`, pdf)
+	assert.Regexp(t, `%%EOF\n$`, pdf)
+	assert.Contains(t, pdf, `(Acme \(FX\) Ltd) Tj`)
+	assert.Contains(t, pdf, `(Re\347u) Tj`)
+	assert.Contains(t, pdf, `(1,550,500.00 NGN) Tj`)
+	assert.NotContains(t, pdf, "/XObject")
+	checkXref(t, buf.Bytes())
+
+	var again bytes.Buffer
+	assert.NoError(t, WritePDF(&again, Receipt{Quote: q, Issuer: "Acme (FX) Ltd", Title: "Reçu"}))
+	assert.Equal(t, buf.Bytes(), again.Bytes(), "deterministic")
+}
+
+func TestWritePDF_Logo(t *testing.T) {
+	var logo bytes.Buffer
+	assert.NoError(t, jpeg.Encode(&logo, image.NewRGBA(image.Rect(0, 0, 96, 48)), nil))
+
+	var buf bytes.Buffer
+	assert.NoError(t, WritePDF(&buf, Receipt{Quote: testQuote(t), Logo: logo.Bytes()}))
+	pdf := buf.String()
+	assert.Contains(t, pdf, "/XObject << /Im1 7 0 R >>")
+	assert.Contains(t, pdf, "/Width 96 /Height 48 /ColorSpace /DeviceRGB")
+	assert.Contains(t, pdf, "q 96 0 0 48 50 744 cm /Im1 Do Q")
+	checkXref(t, buf.Bytes())
+
+	assert.ErrorIs(t, WritePDF(&buf, Receipt{Quote: testQuote(t), Logo: []byte("GIF89a")}), ErrInvalidLogo)
+	assert.ErrorIs(t, WritePDF(&buf, Receipt{}), ErrNoQuote)
+}
+
+// checkXref checks that every cross-reference entry points at its object.
+func checkXref(t *testing.T, pdf []byte) {
+	t.Helper()
+	start := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(pdf)
+	assert.NotNil(t, start)
+	offset, _ := strconv.Atoi(string(start[1]))
+	assert.True(t, bytes.HasPrefix(pdf[offset:], []byte("xref\n")))
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(pdf[offset:], -1)
+	assert.NotEmpty(t, entries)
+	for i, entry := range entries {
+		at, _ := strconv.Atoi(string(entry[1]))
+		assert.True(t, bytes.HasPrefix(pdf[at:], []byte(strconv.Itoa(i+1)+" 0 obj\n")), "object %d", i+1)
+	}
+}
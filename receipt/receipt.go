@@ -0,0 +1,236 @@
+package receipt
+
+import (
+	"strings"
+	"time"
+
+	"github.com/otyang/converter"
+	"github.com/shopspring/decimal"
+)
+
+// Receipt is the content of a receipt for a quote, or for one execution of
+// it.
+type Receipt struct {
+	// Title defaults to "Currency exchange receipt".
+	Title string
+	// Issuer names the business issuing the receipt, e.g. with its
+	// registration number.
+	Issuer string
+	// Logo, if set, is a JPEG image printed at the top of PDF receipts.
+	Logo  []byte
+	Quote *converter.Quote
+	// Execution, if set, is the part of the quote the receipt is for.
+	Execution *converter.Execution
+	// Locale formats amounts and dates. It defaults to English.
+	Locale Locale
+}
+
+// Line is one labelled value of a receipt.
+type Line struct {
+	Label string
+	Value string
+}
+
+// defaultTitle is the title of receipts without one.
+const defaultTitle = "Currency exchange receipt"
+
+// title returns the receipt's title.
+func (r Receipt) title() string {
+	if r.Title == "" {
+		return r.locale().Label(defaultTitle)
+	}
+	return r.Title
+}
+
+// locale returns the receipt's locale.
+func (r Receipt) locale() Locale {
+	if r.Locale.Tag == "" {
+		return English
+	}
+	return r.Locale
+}
+
+// Lines returns the line items of the receipt: the quote reference and
+// date, what was sent and received, the fee and rate, and the value date
+// and status where known.
+func (r Receipt) Lines() []Line {
+	q, loc := r.Quote, r.locale()
+	sent, received := q.AmountToDeduct, q.FinalAmount
+	date := q.Date
+	if r.Execution != nil {
+		sent, received, date = r.Execution.Amount, r.Execution.FinalAmount, r.Execution.Time
+	}
+
+	lines := []Line{
+		{loc.Label("Reference"), q.Reference},
+		{loc.Label("Date"), loc.FormatTime(date)},
+		{loc.Label("You send"), loc.FormatAmount(sent, q.FromCurrency)},
+	}
+	if r.Execution == nil {
+		feeCurrency := q.FeeCurrency
+		if feeCurrency == "" {
+			feeCurrency = q.FromCurrency
+		}
+		lines = append(lines, Line{loc.Label("Fee"), loc.FormatAmount(q.Fee, feeCurrency)})
+	}
+	lines = append(lines,
+		Line{loc.Label("Rate"), "1 " + q.FromCurrency + " = " + loc.FormatNumber(q.Rate) + " " + q.ToCurrency},
+		Line{loc.Label("They receive"), loc.FormatAmount(received, q.ToCurrency)},
+	)
+	if r.Execution != nil && r.Execution.Remaining.IsPositive() {
+		lines = append(lines, Line{loc.Label("Remaining"), loc.FormatAmount(r.Execution.Remaining, q.FromCurrency)})
+	}
+	if !q.ValueDate.IsZero() {
+		lines = append(lines, Line{loc.Label("Value date"), loc.FormatDate(q.ValueDate)})
+	}
+	if q.Status != "" {
+		lines = append(lines, Line{loc.Label("Status"), loc.Label(string(q.Status))})
+	}
+	return lines
+}
+
+// Locale formats amounts and dates for a market.
+type Locale struct {
+	// Tag is the BCP 47 tag of the locale, e.g. "de".
+	Tag string
+	// Decimal and Group separate the fraction and the thousands.
+	Decimal string
+	Group   string
+	// CodeFirst writes the currency code before amounts, e.g. "USD 1.00".
+	CodeFirst bool
+	// DateLayout and TimeLayout are time.Format layouts.
+	DateLayout string
+	TimeLayout string
+	// Labels translates the English wording of receipts, e.g. "Fee" or
+	// "pending". Wording without a translation is kept.
+	Labels map[string]string
+	// CurrencyNames names currencies by ISO code. Currencies without a
+	// name use their English name (see converter.LookupCurrencyInfo).
+	CurrencyNames map[string]string
+}
+
+// Built-in locales
+var (
+	English = Locale{Tag: "en", Decimal: ".", Group: ",", DateLayout: "2 Jan 2006", TimeLayout: "2 Jan 2006 15:04 MST"}
+	German  = Locale{
+		Tag: "de", Decimal: ",", Group: ".", DateLayout: "02.01.2006", TimeLayout: "02.01.2006 15:04 MST",
+		Labels: map[string]string{
+			defaultTitle: "Devisenumtauschbeleg", "Reference": "Referenz", "Date": "Datum",
+			"You send": "Sie senden", "Fee": "Gebühr", "Rate": "Kurs", "They receive": "Empfänger erhält",
+			"Remaining": "Verbleibend", "Value date": "Valuta", "Status": "Status",
+			"pending": "offen", "accepted": "angenommen", "executed": "ausgeführt",
+			"expired": "abgelaufen", "cancelled": "storniert",
+		},
+		CurrencyNames: map[string]string{
+			"USD": "US-Dollar", "EUR": "Euro", "GBP": "Britisches Pfund", "CHF": "Schweizer Franken",
+			"JPY": "Japanischer Yen", "NGN": "Nigerianischer Naira",
+		},
+	}
+	French = Locale{
+		Tag: "fr", Decimal: ",", Group: "\u00a0", DateLayout: "02/01/2006", TimeLayout: "02/01/2006 15:04 MST",
+		Labels: map[string]string{
+			defaultTitle: "Reçu de change", "Reference": "Référence", "Date": "Date",
+			"You send": "Vous envoyez", "Fee": "Frais", "Rate": "Taux", "They receive": "Le bénéficiaire reçoit",
+			"Remaining": "Restant", "Value date": "Date de valeur", "Status": "Statut",
+			"pending": "en attente", "accepted": "accepté", "executed": "exécuté",
+			"expired": "expiré", "cancelled": "annulé",
+		},
+		CurrencyNames: map[string]string{
+			"USD": "dollar américain", "EUR": "euro", "GBP": "livre sterling", "CHF": "franc suisse",
+			"JPY": "yen japonais", "NGN": "naira nigérian", "XOF": "franc CFA (BCEAO)",
+		},
+	}
+)
+
+// locales are the built-in locales by tag.
+var locales = map[string]Locale{"en": English, "de": German, "fr": French}
+
+// LookupLocale returns the built-in locale of a BCP 47 tag, matching its
+// language if there is no exact match, e.g. "de-AT" to German.
+func LookupLocale(tag string) (Locale, bool) {
+	tag = strings.ToLower(strings.ReplaceAll(tag, "_", "-"))
+	if loc, ok := locales[tag]; ok {
+		return loc, true
+	}
+	lang, _, _ := strings.Cut(tag, "-")
+	loc, ok := locales[lang]
+	return loc, ok
+}
+
+// Label translates English receipt wording, or returns it as is.
+func (l Locale) Label(english string) string {
+	if label, ok := l.Labels[english]; ok {
+		return label
+	}
+	return english
+}
+
+// CurrencyName names a currency in the locale, falling back to its English
+// name and then to its code.
+func (l Locale) CurrencyName(code string) string {
+	code = strings.ToUpper(code)
+	if name, ok := l.CurrencyNames[code]; ok {
+		return name
+	}
+	if info, ok := converter.LookupCurrencyInfo(code); ok {
+		return info.Name
+	}
+	return code
+}
+
+// FormatNumber formats a number with the locale's separators, keeping every
+// digit.
+func (l Locale) FormatNumber(d decimal.Decimal) string {
+	return l.separate(d.String())
+}
+
+// FormatAmount formats an amount of a currency, padded to the currency's
+// minor units (see converter.MinorUnits), e.g. "1,234.50 USD".
+func (l Locale) FormatAmount(amount decimal.Decimal, code string) string {
+	s := amount.String()
+	_, frac, _ := strings.Cut(s, ".")
+	if units, ok := converter.MinorUnits(code); ok && len(frac) < units {
+		s = amount.StringFixed(int32(units))
+	}
+	return l.withCode(l.separate(s), code)
+}
+
+// separate rewrites a decimal string with the locale's separators.
+func (l Locale) separate(s string) string {
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	whole, frac, hasFrac := strings.Cut(s, ".")
+
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteString(l.Group)
+		}
+		b.WriteRune(digit)
+	}
+	if hasFrac {
+		b.WriteString(l.Decimal)
+		b.WriteString(frac)
+	}
+	return b.String()
+}
+
+func (l Locale) withCode(number, code string) string {
+	if l.CodeFirst {
+		return code + " " + number
+	}
+	return number + " " + code
+}
+
+// FormatDate formats the day of t.
+func (l Locale) FormatDate(t time.Time) string {
+	return t.Format(l.DateLayout)
+}
+
+// FormatTime formats t to the minute.
+func (l Locale) FormatTime(t time.Time) string {
+	return t.Format(l.TimeLayout)
+}
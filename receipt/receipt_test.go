@@ -0,0 +1,79 @@
+package receipt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/otyang/converter"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func testQuote(t *testing.T) *converter.Quote {
+	t.Helper()
+	currencies := []converter.Currency{
+		{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+		{ISOCode: "NGN", Precision: 2, BuyRate: decimal.NewFromInt(1500), SellRate: decimal.RequireFromString("1550.5")},
+	}
+	now := time.Date(2024, 3, 28, 14, 5, 0, 0, time.UTC)
+	q, err := converter.NewQuote(currencies, "USD", "USD", "NGN", decimal.NewFromInt(1000), decimal.NewFromInt(5), converter.WithClock(fixedClock(now)))
+	assert.NoError(t, err)
+	return q
+}
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+func TestReceipt_Lines(t *testing.T) {
+	q := testQuote(t)
+	assert.Equal(t, []Line{
+		{"Reference", q.Reference},
+		{"Date", "28 Mar 2024 14:05 UTC"},
+		{"You send", "1,005.00 USD"},
+		{"Fee", "5.00 USD"},
+		{"Rate", "1 USD = 1,550.5 NGN"},
+		{"They receive", "1,550,500.00 NGN"},
+		{"Status", "pending"},
+	}, Receipt{Quote: q}.Lines())
+
+	execution := &converter.Execution{
+		QuoteID:     q.ID,
+		Amount:      decimal.NewFromInt(400),
+		FinalAmount: decimal.NewFromInt(620200),
+		Remaining:   decimal.NewFromInt(600),
+		Time:        time.Date(2024, 3, 29, 9, 0, 0, 0, time.UTC),
+	}
+	assert.Equal(t, []Line{
+		{"Referenz", q.Reference},
+		{"Datum", "29.03.2024 09:00 UTC"},
+		{"Sie senden", "400,00 USD"},
+		{"Kurs", "1 USD = 1.550,5 NGN"},
+		{"Empfänger erhält", "620.200,00 NGN"},
+		{"Verbleibend", "600,00 USD"},
+		{"Status", "offen"},
+	}, Receipt{Quote: q, Execution: execution, Locale: German}.Lines())
+}
+
+func TestLocale_FormatAmount(t *testing.T) {
+	assert.Equal(t, "1,234,567.5 XYZ", English.FormatAmount(decimal.RequireFromString("1234567.5"), "XYZ"))
+	assert.Equal(t, "-1,234 JPY", English.FormatAmount(decimal.NewFromInt(-1234), "JPY"))
+	assert.Equal(t, "12.500 KWD", English.FormatAmount(decimal.RequireFromString("12.5"), "KWD"))
+	assert.Equal(t, "0.123456 USD", English.FormatAmount(decimal.RequireFromString("0.123456"), "USD"))
+	assert.Equal(t, "1\u00a0000,00 EUR", French.FormatAmount(decimal.NewFromInt(1000), "EUR"))
+
+	codeFirst := English
+	codeFirst.CodeFirst = true
+	assert.Equal(t, "USD 100.00", codeFirst.FormatAmount(decimal.NewFromInt(100), "USD"))
+}
+
+func TestLookupLocale(t *testing.T) {
+	loc, ok := LookupLocale("de_AT")
+	assert.True(t, ok)
+	assert.Equal(t, German, loc)
+	loc, ok = LookupLocale("FR")
+	assert.True(t, ok)
+	assert.Equal(t, French, loc)
+	_, ok = LookupLocale("xx")
+	assert.False(t, ok)
+}
@@ -0,0 +1,110 @@
+package receipt
+
+import (
+	htmltemplate "html/template"
+	"io"
+	texttemplate "text/template"
+)
+
+// Default receipt templates. Their wording comes from the receipt's line
+// items, so it follows the receipt's locale.
+const (
+	DefaultText = `{{.Title}}
+{{with .Issuer}}{{.}}
+{{end}}
+{{range .Lines}}{{.Label}}: {{.Value}}
+{{end}}`
+
+	DefaultHTML = `<!DOCTYPE html>
+<html lang="{{.Locale.Tag}}">
+<head><meta charset="utf-8"><title>{{.Title}}</title></head>
+<body>
+<h1>{{.Title}}</h1>
+{{with .Issuer}}<p>{{.}}</p>
+{{end}}<table>
+{{range .Lines}}<tr><th>{{.Label}}</th><td>{{.Value}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`
+)
+
+// TemplateData is what receipt templates execute on: the receipt with its
+// title and locale defaulted, and its line items.
+type TemplateData struct {
+	Receipt
+	Title string
+	Lines []Line
+}
+
+// Template renders receipts from a user-supplied text/template or
+// html/template source, so receipt wording can vary per market without
+// code changes. Templates can call these functions, bound to the receipt's
+// locale:
+//
+//	amount DECIMAL CODE  the amount formatted with its currency, e.g. "1,234.50 USD"
+//	number DECIMAL       the number with the locale's separators
+//	currencyName CODE    the currency's name in the locale, e.g. "US-Dollar"
+//	date TIME            the day of the time
+//	time TIME            the time to the minute
+//	label TEXT           the locale's translation of English wording
+type Template struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// ParseText parses a text template.
+func ParseText(name, text string) (*Template, error) {
+	t, err := texttemplate.New(name).Funcs(texttemplate.FuncMap(FuncMap(English))).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	return &Template{text: t}, nil
+}
+
+// ParseHTML parses an HTML template, escaping the receipt's values in
+// context.
+func ParseHTML(name, text string) (*Template, error) {
+	t, err := htmltemplate.New(name).Funcs(htmltemplate.FuncMap(FuncMap(English))).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	return &Template{html: t}, nil
+}
+
+// Execute renders the receipt. It returns ErrNoQuote if the receipt has no
+// quote.
+func (t *Template) Execute(w io.Writer, r Receipt) error {
+	if r.Quote == nil {
+		return ErrNoQuote
+	}
+	r.Locale = r.locale()
+	data := TemplateData{Receipt: r, Title: r.title(), Lines: r.Lines()}
+	funcs := FuncMap(r.Locale)
+
+	if t.html != nil {
+		html, err := t.html.Clone()
+		if err != nil {
+			return err
+		}
+		return html.Funcs(htmltemplate.FuncMap(funcs)).Execute(w, data)
+	}
+	text, err := t.text.Clone()
+	if err != nil {
+		return err
+	}
+	return text.Funcs(texttemplate.FuncMap(funcs)).Execute(w, data)
+}
+
+// FuncMap returns the functions available to receipt templates, bound to a
+// locale. See Template.
+func FuncMap(loc Locale) map[string]any {
+	return map[string]any{
+		"amount":       loc.FormatAmount,
+		"number":       loc.FormatNumber,
+		"currencyName": loc.CurrencyName,
+		"date":         loc.FormatDate,
+		"time":         loc.FormatTime,
+		"label":        loc.Label,
+	}
+}
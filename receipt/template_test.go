@@ -0,0 +1,57 @@
+package receipt
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplate_Text(t *testing.T) {
+	q := testQuote(t)
+	tmpl, err := ParseText("receipt", DefaultText)
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, tmpl.Execute(&buf, Receipt{Quote: q, Issuer: "Acme FX", Locale: French}))
+	assert.Equal(t, "Reçu de change\nAcme FX\n\n"+
+		"Référence: "+q.Reference+"\n"+
+		"Date: 28/03/2024 14:05 UTC\n"+
+		"Vous envoyez: 1\u00a0005,00 USD\n"+
+		"Frais: 5,00 USD\n"+
+		"Taux: 1 USD = 1\u00a0550,5 NGN\n"+
+		"Le bénéficiaire reçoit: 1\u00a0550\u00a0500,00 NGN\n"+
+		"Statut: en attente\n", buf.String())
+}
+
+func TestTemplate_Funcs(t *testing.T) {
+	q := testQuote(t)
+	tmpl, err := ParseText("custom", `{{label "Fee"}} {{amount .Quote.Fee .Quote.FromCurrency}} ({{currencyName .Quote.FromCurrency}}, {{currencyName "XYZ"}}) {{date .Quote.Date}} {{number .Quote.Rate}}`)
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, tmpl.Execute(&buf, Receipt{Quote: q, Locale: German}))
+	assert.Equal(t, "Gebühr 5,00 USD (US-Dollar, XYZ) 28.03.2024 1.550,5", buf.String())
+
+	buf.Reset()
+	assert.NoError(t, tmpl.Execute(&buf, Receipt{Quote: q}))
+	assert.Equal(t, "Fee 5.00 USD (US Dollar, XYZ) 28 Mar 2024 1,550.5", buf.String())
+}
+
+func TestTemplate_HTML(t *testing.T) {
+	q := testQuote(t)
+	tmpl, err := ParseHTML("receipt", DefaultHTML)
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, tmpl.Execute(&buf, Receipt{Quote: q, Issuer: "<Acme & Co>", Locale: German}))
+	html := buf.String()
+	assert.Contains(t, html, `<html lang="de">`)
+	assert.Contains(t, html, "<h1>Devisenumtauschbeleg</h1>")
+	assert.Contains(t, html, "<p>&lt;Acme &amp; Co&gt;</p>")
+	assert.Contains(t, html, "<tr><th>Empfänger erhält</th><td>1.550.500,00 NGN</td></tr>")
+
+	assert.ErrorIs(t, tmpl.Execute(&buf, Receipt{}), ErrNoQuote)
+	_, err = ParseHTML("bad", "{{.Title")
+	assert.Error(t, err)
+}
@@ -0,0 +1,182 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Refresher keeps a table up to date by fetching rates from a provider every
+// interval. With a SnapshotStore it persists each refreshed table and, on
+// start-up, loads the last persisted table before the first fetch, so quotes
+// can be served immediately after a restart.
+type Refresher struct {
+	Table    *Currencies
+	Provider RateProvider
+	Interval time.Duration
+	// Snapshots, if set, is used for warm starts and saved after each refresh.
+	Snapshots SnapshotStore
+	// MaxSnapshotAge, if set, stops WarmStart from serving a snapshot taken
+	// longer ago than this.
+	MaxSnapshotAge time.Duration
+	// Verify, if set, gates each refresh: fetched rates it rejects are not
+	// applied and the previous table keeps being served. Invariants.Check
+	// fits here.
+	Verify func(currencies []Currency) error
+	// Audit, if set, records the changes made by each refresh.
+	Audit *AuditLog
+	// Fallback, if set, holds hard-coded rates loaded into the table, as
+	// degraded, when a refresh fails while the table is still empty: no
+	// provider and no last-known-good snapshot could supply rates. See
+	// Currencies.UpdateFallback.
+	Fallback []Currency
+	// FallbackAsOf is when the Fallback rates were set, which the table
+	// reports as their age. Zero, if unknown, makes them as stale as can
+	// be rather than as fresh as a fetch.
+	FallbackAsOf time.Time
+	// Clock defaults to SystemClock.
+	Clock Clock
+
+	mu    sync.Mutex
+	stats RefresherStats
+}
+
+// RefresherStats are the counters exposed by a Refresher.
+type RefresherStats struct {
+	Runs        int64     `json:"runs"`
+	Failures    int64     `json:"failures"`
+	LastSuccess time.Time `json:"lastSuccess"`
+	LastError   string    `json:"lastError,omitempty"`
+}
+
+// NewRefresher creates a refresher updating table from provider every
+// interval. It returns ErrInvalidInterval if interval is not positive.
+func NewRefresher(table *Currencies, provider RateProvider, interval time.Duration) (*Refresher, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidInterval, interval)
+	}
+	return &Refresher{
+		Table:    table,
+		Provider: provider,
+		Interval: interval,
+		Clock:    SystemClock,
+	}, nil
+}
+
+// Run warm-starts the table and then refreshes it every interval until ctx is
+// cancelled. It returns ErrInvalidInterval straight away if Interval is not
+// positive.
+func (r *Refresher) Run(ctx context.Context) error {
+	if r.Interval <= 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidInterval, r.Interval)
+	}
+
+	// A missing or unreadable snapshot only means a cold start.
+	_ = r.WarmStart(ctx)
+
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+
+	for {
+		// Errors are counted in Stats; the next tick retries.
+		_ = r.Refresh(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// WarmStart loads the persisted snapshot into the table if the table is still
+// empty. It returns ErrSnapshotNotFound if nothing has been persisted yet,
+// ErrSnapshotTooOld if the snapshot is older than MaxSnapshotAge, and the
+// validation error if a persisted currency is invalid.
+func (r *Refresher) WarmStart(ctx context.Context) error {
+	if r.Snapshots == nil || r.Table.Len() > 0 {
+		return nil
+	}
+
+	snapshot, err := loadValidSnapshot(ctx, r.Snapshots)
+	if err != nil {
+		return err
+	}
+	if age := r.clock().Now().Sub(snapshot.TakenAt); r.MaxSnapshotAge > 0 && age > r.MaxSnapshotAge {
+		return fmt.Errorf("%w: taken %s ago", ErrSnapshotTooOld, age)
+	}
+
+	// A fetch may have completed while the snapshot was loading.
+	if r.Table.Len() == 0 {
+		r.Table.Restore(snapshot)
+	}
+	return nil
+}
+
+// Refresh fetches rates once, updates the table and persists a snapshot.
+func (r *Refresher) Refresh(ctx context.Context) error {
+	currencies, err := r.Provider.FetchRates(ctx)
+	if err == nil && len(currencies) == 0 {
+		err = ErrEmptyCurrencySource
+	}
+	if err == nil && r.Verify != nil {
+		err = r.Verify(currencies)
+	}
+	if err != nil {
+		r.record(err)
+		if len(r.Fallback) > 0 && r.Table.Len() == 0 {
+			r.Table.UpdateFallback(r.Fallback, r.FallbackAsOf)
+		}
+		return err
+	}
+
+	previous := r.Table.List()
+	now := r.clock().Now()
+	r.Table.Update(currencies, now)
+	if r.Audit != nil {
+		r.Audit.Record(previous, currencies, now)
+	}
+
+	if r.Snapshots != nil {
+		if err := r.Snapshots.Save(ctx, r.Table.Snapshot()); err != nil {
+			r.record(err)
+			return err
+		}
+	}
+
+	r.record(nil)
+	return nil
+}
+
+// Stats returns the refresher's counters.
+func (r *Refresher) Stats() RefresherStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.stats
+}
+
+func (r *Refresher) record(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.stats.Runs++
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			r.stats.Failures++
+			r.stats.LastError = err.Error()
+		}
+		return
+	}
+	r.stats.LastSuccess = r.clock().Now()
+	r.stats.LastError = ""
+}
+
+func (r *Refresher) clock() Clock {
+	if r.Clock == nil {
+		return SystemClock
+	}
+	return r.Clock
+}
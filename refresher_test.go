@@ -0,0 +1,215 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefresher_Refresh(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	table := NewTable(nil)
+	store := NewFileSnapshotStore(filepath.Join(t.TempDir(), "rates.json"))
+
+	fail := false
+	provider := RateProviderFunc(func(context.Context) ([]Currency, error) {
+		if fail {
+			return nil, errors.New("provider down")
+		}
+		return testCurrencies(), nil
+	})
+
+	r, err := NewRefresher(table, provider, time.Minute)
+	assert.NoError(t, err)
+	r.Snapshots = store
+	r.Audit = NewAuditLog()
+	r.Clock = fixedClock(now)
+
+	assert.NoError(t, r.Refresh(ctx))
+	assert.Equal(t, 3, table.Len())
+	assert.Equal(t, now, table.UpdatedAt())
+
+	// Every added currency was audited
+	assert.Len(t, r.Audit.Entries(), 3)
+
+	// The refreshed table was persisted
+	snapshot, err := store.Load(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, snapshot.Currencies, 3)
+
+	// Non-positive intervals would make the ticker panic
+	_, err = NewRefresher(table, provider, 0)
+	assert.ErrorIs(t, err, ErrInvalidInterval)
+
+	// A failed fetch keeps the previous table
+	fail = true
+	assert.Error(t, r.Refresh(ctx))
+	assert.Equal(t, 3, table.Len())
+	assert.Len(t, r.Audit.Entries(), 3)
+
+	stats := r.Stats()
+	assert.Equal(t, int64(2), stats.Runs)
+	assert.Equal(t, int64(1), stats.Failures)
+	assert.Equal(t, "provider down", stats.LastError)
+	assert.Equal(t, now, stats.LastSuccess)
+}
+
+func TestRefresher_EmptyFetch(t *testing.T) {
+	table := NewTable(testCurrencies())
+	r, err := NewRefresher(table, RateProviderFunc(func(context.Context) ([]Currency, error) {
+		return nil, nil
+	}), time.Minute)
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, r.Refresh(context.Background()), ErrEmptyCurrencySource)
+	assert.Equal(t, 3, table.Len())
+}
+
+func TestRefresher_Fallback(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	fail := true
+	provider := RateProviderFunc(func(context.Context) ([]Currency, error) {
+		if fail {
+			return nil, errors.New("provider down")
+		}
+		return testCurrencies(), nil
+	})
+	store := NewFileSnapshotStore(filepath.Join(t.TempDir(), "rates.json"))
+
+	r, err := NewRefresher(NewTable(nil), provider, time.Minute)
+	assert.NoError(t, err)
+	r.Snapshots = store
+	r.Clock = fixedClock(now)
+	r.Fallback = testCurrencies()[:2]
+
+	// Neither the provider nor a snapshot has rates: the fallback is used,
+	// degraded, and not persisted as last-known-good.
+	assert.ErrorIs(t, r.WarmStart(ctx), ErrSnapshotNotFound)
+	assert.Error(t, r.Refresh(ctx))
+	assert.Equal(t, 2, r.Table.Len())
+	assert.True(t, r.Table.Degraded())
+	assert.True(t, r.Table.UpdatedAt().IsZero(), "fallback rates are not timestamped as fetched")
+	assert.True(t, r.Table.Snapshot().Degraded)
+	_, err = store.Load(ctx)
+	assert.ErrorIs(t, err, ErrSnapshotNotFound)
+
+	q, err := r.Table.NewQuote("USD", "USD", "EUR", decimal.NewFromInt(100), decimal.Zero)
+	assert.NoError(t, err)
+	assert.True(t, q.HasFlag(FlagDegraded))
+
+	// Live rates replace it.
+	fail = false
+	assert.NoError(t, r.Refresh(ctx))
+	assert.Equal(t, 3, r.Table.Len())
+	assert.False(t, r.Table.Degraded())
+	assert.False(t, r.Table.Snapshot().Degraded)
+	assert.Equal(t, now, r.Table.UpdatedAt())
+	q, err = r.Table.NewQuote("USD", "USD", "EUR", decimal.NewFromInt(100), decimal.Zero)
+	assert.NoError(t, err)
+	assert.False(t, q.HasFlag(FlagDegraded))
+
+	// Once the table has rates, failures keep them rather than fall back.
+	fail = true
+	assert.Error(t, r.Refresh(ctx))
+	assert.Equal(t, 3, r.Table.Len())
+	assert.False(t, r.Table.Degraded())
+
+	// A last-known-good snapshot is preferred to the fallback.
+	r.Table = NewTable(nil)
+	assert.NoError(t, r.WarmStart(ctx))
+	assert.Error(t, r.Refresh(ctx))
+	assert.Equal(t, 3, r.Table.Len())
+	assert.False(t, r.Table.Degraded())
+
+	// Fallback rates carry the time they were set, when known.
+	asOf := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	r.Table, r.Snapshots, r.FallbackAsOf = NewTable(nil), nil, asOf
+	assert.Error(t, r.Refresh(ctx))
+	assert.True(t, r.Table.Degraded())
+	assert.Equal(t, asOf, r.Table.UpdatedAt())
+}
+
+func TestRefresher_WarmStart(t *testing.T) {
+	ctx := context.Background()
+	takenAt := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	store := NewFileSnapshotStore(filepath.Join(t.TempDir(), "rates.json"))
+
+	// Nothing persisted yet
+	r, err := NewRefresher(NewTable(nil), nil, time.Minute)
+	assert.NoError(t, err)
+	r.Snapshots = store
+	r.MaxSnapshotAge = time.Hour
+	r.Clock = fixedClock(takenAt.Add(2 * time.Hour))
+	assert.ErrorIs(t, r.WarmStart(ctx), ErrSnapshotNotFound)
+
+	// Snapshots older than MaxSnapshotAge are not served
+	assert.NoError(t, store.Save(ctx, NewSnapshot(testCurrencies(), takenAt)))
+	assert.ErrorIs(t, r.WarmStart(ctx), ErrSnapshotTooOld)
+	assert.Zero(t, r.Table.Len())
+
+	r.Clock = fixedClock(takenAt.Add(time.Minute))
+	assert.NoError(t, r.WarmStart(ctx))
+	assert.Equal(t, 3, r.Table.Len())
+	assert.Equal(t, takenAt, r.Table.UpdatedAt())
+
+	// Invalid persisted currencies are not served
+	invalid := testCurrencies()
+	invalid[1].SellRate = decimal.NewFromInt(-1)
+	assert.NoError(t, store.Save(ctx, NewSnapshot(invalid, takenAt)))
+	r.Table = NewTable(nil)
+	assert.ErrorIs(t, r.WarmStart(ctx), ErrInvalidRate)
+	assert.Zero(t, r.Table.Len())
+
+	// A table that already has rates is left alone
+	table := NewTable(testCurrencies()[:1])
+	r, err = NewRefresher(table, nil, time.Minute)
+	assert.NoError(t, err)
+	r.Snapshots = store
+	assert.NoError(t, r.WarmStart(ctx))
+	assert.Equal(t, 1, table.Len())
+}
+
+func TestRefresher_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	store := NewFileSnapshotStore(filepath.Join(t.TempDir(), "rates.json"))
+	assert.NoError(t, store.Save(ctx, NewSnapshot(testCurrencies(), time.Now())))
+
+	// The provider blocks until released, so the table is served from the
+	// snapshot in the meantime.
+	release := make(chan struct{})
+	var fetches atomic.Int32
+	provider := RateProviderFunc(func(ctx context.Context) ([]Currency, error) {
+		<-release
+		fetches.Add(1)
+		return []Currency{
+			{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+		}, nil
+	})
+
+	table := NewTable(nil)
+	r, err := NewRefresher(table, provider, time.Millisecond)
+	assert.NoError(t, err)
+	r.Snapshots = store
+
+	done := make(chan error)
+	go func() { done <- r.Run(ctx) }()
+
+	assert.Eventually(t, func() bool { return table.Len() == 3 }, time.Second, time.Millisecond)
+	_, err = table.CalculateRate("USD", "USD", "NGN")
+	assert.NoError(t, err)
+
+	close(release)
+	assert.Eventually(t, func() bool { return table.Len() == 1 }, time.Second, time.Millisecond)
+
+	cancel()
+	assert.NoError(t, <-done)
+	assert.Positive(t, fetches.Load())
+}
@@ -0,0 +1,45 @@
+package converter
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// QuoteDrift is how a repriced quote moved from the original, new minus
+// old.
+type QuoteDrift struct {
+	Rate        decimal.Decimal `json:"rate"`
+	FinalAmount decimal.Decimal `json:"finalAmount"`
+	// RateChange is the percent movement of the rate, or nil if the
+	// original rate is zero.
+	RateChange *decimal.Decimal `json:"rateChange,omitempty"`
+}
+
+// Reprice quotes q's parameters again against c's current rates, for "your
+// quote expired, here's the new price" flows. The new quote keeps q's base
+// and currencies, amount, fee and fee mode, customer reference and, if q
+// expires, a TTL as long as q's. opts are applied after those, e.g. to
+// apply a tier again; options q was created with are not recorded on it.
+func (q *Quote) Reprice(c *Currencies, opts ...QuoteOption) (*Quote, *QuoteDrift, error) {
+	options := []QuoteOption{WithCustomerRef(q.CustomerRef)}
+	if q.FeeMode != "" {
+		options = append(options, WithFeeMode(q.FeeMode))
+	}
+	if !q.ExpiresAt.IsZero() {
+		options = append(options, WithTTL(q.ExpiresAt.Sub(q.Date)))
+	}
+
+	repriced, err := c.NewQuote(q.BaseCurrency, q.FromCurrency, q.ToCurrency, q.FromAmount, q.Fee, append(options, opts...)...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return repriced, q.Drift(repriced), nil
+}
+
+// Drift returns how other moved from q.
+func (q *Quote) Drift(other *Quote) *QuoteDrift {
+	return &QuoteDrift{
+		Rate:        other.Rate.Sub(q.Rate),
+		FinalAmount: other.FinalAmount.Sub(q.FinalAmount),
+		RateChange:  percentChange(q.Rate, other.Rate),
+	}
+}
@@ -0,0 +1,45 @@
+package converter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuote_Reprice(t *testing.T) {
+	now := time.Date(2024, 3, 28, 12, 0, 0, 0, time.UTC)
+	table := NewTable(testCurrencies())
+
+	quote, err := table.NewQuote("USD", "USD", "NGN", decimal.NewFromInt(100), decimal.NewFromInt(2),
+		WithCustomerRef("cust-1"), WithTTL(time.Minute), WithFeeMode(FeeDeducted), WithClock(fixedClock(now)))
+	assert.NoError(t, err)
+
+	rates := testCurrencies()
+	rates[2].SellRate = decimal.NewFromInt(1600)
+	table.Update(rates, now)
+
+	later := now.Add(time.Hour)
+	repriced, drift, err := quote.Reprice(table, WithClock(fixedClock(later)))
+	assert.NoError(t, err)
+	assert.NotEqual(t, quote.ID, repriced.ID)
+	assert.Equal(t, "cust-1", repriced.CustomerRef)
+	assert.Equal(t, FeeDeducted, repriced.FeeMode)
+	assert.Equal(t, later.Add(time.Minute), repriced.ExpiresAt)
+	assert.Equal(t, "156800", repriced.FinalAmount.String())
+
+	assert.Equal(t, "50", drift.Rate.String())
+	assert.Equal(t, "4900", drift.FinalAmount.String())
+	assert.Equal(t, "3.2258", drift.RateChange.String())
+}
+
+func TestQuote_RepriceError(t *testing.T) {
+	table := NewTable(testCurrencies())
+	quote, err := table.NewQuote("USD", "USD", "NGN", decimal.NewFromInt(100), decimal.Zero)
+	assert.NoError(t, err)
+
+	table.Update(testCurrencies()[:2], time.Now())
+	_, _, err = quote.Reprice(table)
+	assert.Error(t, err)
+}
@@ -0,0 +1,71 @@
+package converter
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// QuoteFlag marks a quote for downstream workflows.
+type QuoteFlag string
+
+// Quote flags
+const (
+	// FlagReviewRequired marks a quote whose amount reached a review
+	// threshold, e.g. for AML checks.
+	FlagReviewRequired QuoteFlag = "review-required"
+	// FlagDegraded marks a quote priced from fallback rates, see
+	// Currencies.UpdateFallback.
+	FlagDegraded QuoteFlag = "degraded"
+)
+
+// HasFlag reports whether the quote carries a flag.
+func (q *Quote) HasFlag(flag QuoteFlag) bool {
+	return slices.Contains(q.Flags, flag)
+}
+
+// ReviewThresholds are amounts, in units of the base currency, from which
+// quotes are flagged FlagReviewRequired. A quote's amount is normalized to
+// the base currency with the source currency's buy rate and checked against
+// the lower threshold of its two currencies. Zero thresholds are not
+// checked.
+type ReviewThresholds struct {
+	// Default applies to currencies without their own threshold.
+	Default decimal.Decimal
+	// Currencies sets the threshold of a currency, e.g. "NGN".
+	Currencies map[string]decimal.Decimal
+}
+
+// WithReviewThresholds flags quotes reaching a threshold FlagReviewRequired.
+func WithReviewThresholds(t ReviewThresholds) QuoteOption {
+	return func(o *quoteOptions) {
+		o.review = &t
+	}
+}
+
+// threshold returns the review threshold of a currency.
+func (t ReviewThresholds) threshold(code string) decimal.Decimal {
+	for c, threshold := range t.Currencies {
+		if strings.EqualFold(c, code) {
+			return threshold
+		}
+	}
+	return t.Default
+}
+
+// exceeded reports whether converting amount from one currency to another
+// reaches a review threshold.
+func (t ReviewThresholds) exceeded(amount decimal.Decimal, from, to *Currency) bool {
+	for _, code := range []string{from.ISOCode, to.ISOCode} {
+		threshold := t.threshold(code)
+		if !threshold.IsPositive() {
+			continue
+		}
+		// amount/rate >= threshold, without dividing.
+		if amount.GreaterThanOrEqual(threshold.Mul(from.BuyRate)) {
+			return true
+		}
+	}
+	return false
+}
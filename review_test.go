@@ -0,0 +1,41 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithReviewThresholds(t *testing.T) {
+	table := NewTable(testCurrencies())
+	review := WithReviewThresholds(ReviewThresholds{
+		Default:    decimal.NewFromInt(10000),
+		Currencies: map[string]decimal.Decimal{"ngn": decimal.NewFromInt(1000)},
+	})
+
+	testCases := []struct {
+		from, to string
+		amount   int64
+		flagged  bool
+	}{
+		{from: "USD", to: "EUR", amount: 9999},
+		{from: "USD", to: "EUR", amount: 10000, flagged: true},
+		// 9,000 EUR at 0.9 is 10,000 USD.
+		{from: "EUR", to: "USD", amount: 8999},
+		{from: "EUR", to: "USD", amount: 9000, flagged: true},
+		// NGN's lower threshold applies in either direction.
+		{from: "NGN", to: "USD", amount: 1499999},
+		{from: "NGN", to: "USD", amount: 1500000, flagged: true},
+		{from: "USD", to: "NGN", amount: 1000, flagged: true},
+	}
+	for _, tc := range testCases {
+		quote, err := table.NewQuote("USD", tc.from, tc.to, decimal.NewFromInt(tc.amount), decimal.Zero, review)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.flagged, quote.HasFlag(FlagReviewRequired), "%s %d %s", tc.from, tc.amount, tc.to)
+	}
+
+	quote, err := table.NewQuote("USD", "USD", "EUR", decimal.NewFromInt(1000000), decimal.Zero)
+	assert.NoError(t, err)
+	assert.Empty(t, quote.Flags)
+}
@@ -0,0 +1,62 @@
+package converter
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// NewCurrenciesFromRows builds currencies from a query result, with the
+// result columns named by mapping as CSV columns are; other columns are
+// ignored. Values are read as text, so DECIMAL and NUMERIC columns keep
+// every digit. NULL or empty values are invalid, as in CSV.
+//
+// Rows are read to the end; the caller still closes them.
+func NewCurrenciesFromRows(rows *sql.Rows, mapping FieldMapping) ([]Currency, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	index, err := columnIndex(columns, mapping.withDefaults())
+	if err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+
+	values := make([]sql.NullString, len(columns))
+	dest := make([]any, len(columns))
+	for _, i := range index {
+		dest[i] = &values[i]
+	}
+	for i := range dest {
+		if dest[i] == nil {
+			dest[i] = new(any)
+		}
+	}
+
+	var currencies []Currency
+	record := make([]string, len(columns))
+	for n := 1; rows.Next(); n++ {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("rows: row %d: %w", n, err)
+		}
+		for _, i := range index {
+			record[i] = values[i].String
+		}
+
+		c, err := currencyFromRecord(record, index)
+		if err != nil {
+			return nil, fmt.Errorf("rows: row %d: %w", n, err)
+		}
+		if err := c.Validate(); err != nil {
+			return nil, fmt.Errorf("rows: row %d: %w", n, err)
+		}
+		currencies = append(currencies, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(currencies) == 0 {
+		return nil, ErrEmptyCurrencySource
+	}
+	return currencies, nil
+}
@@ -0,0 +1,47 @@
+package converter
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	_ "modernc.org/sqlite"
+)
+
+func TestNewCurrenciesFromRows(t *testing.T) {
+	db, err := sql.Open("sqlite", "file:"+filepath.Join(t.TempDir(), "rates.db"))
+	assert.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec(`CREATE TABLE rates (ccy TEXT, dp INTEGER, bid TEXT, ask REAL, source TEXT);
+		INSERT INTO rates VALUES ('usd', 2, '1', 1, 'manual');
+		INSERT INTO rates VALUES ('BTC', 8, '0.0000152345678901234567', 0.000015, 'feed');`)
+	assert.NoError(t, err)
+
+	mapping := FieldMapping{ISO: "ccy", Precision: "dp", Buy: "bid", Sell: "ask"}
+	query := func(q string) *sql.Rows {
+		rows, err := db.Query(q)
+		assert.NoError(t, err)
+		t.Cleanup(func() { rows.Close() })
+		return rows
+	}
+
+	currencies, err := NewCurrenciesFromRows(query("SELECT * FROM rates ORDER BY dp"), mapping)
+	assert.NoError(t, err)
+	assert.Len(t, currencies, 2)
+	assert.Equal(t, "USD", currencies[0].ISOCode)
+	assert.Equal(t, 8, currencies[1].Precision)
+	assert.Equal(t, "0.0000152345678901234567", currencies[1].BuyRate.String())
+	assert.Equal(t, "0.000015", currencies[1].SellRate.String())
+
+	_, err = NewCurrenciesFromRows(query("SELECT ccy, bid, ask FROM rates"), mapping)
+	assert.EqualError(t, err, "rows: missing column dp")
+
+	_, err = NewCurrenciesFromRows(query("SELECT * FROM rates WHERE dp > 100"), mapping)
+	assert.Equal(t, ErrEmptyCurrencySource, err)
+
+	_, err = NewCurrenciesFromRows(query("SELECT ccy, dp, NULL AS bid, ask FROM rates"), mapping)
+	assert.ErrorIs(t, err, ErrInvalidRate)
+	assert.ErrorContains(t, err, "row 1")
+}
@@ -0,0 +1,110 @@
+package converter
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Sandbox quote flags
+const (
+	// FlagSandbox marks a quote priced by a sandbox table; it must never be
+	// settled.
+	FlagSandbox QuoteFlag = "sandbox"
+	// FlagPinned marks a sandbox quote priced at a pinned rate rather than
+	// from the providers' rates.
+	FlagPinned QuoteFlag = "pinned"
+)
+
+// Pin fixes the rate of a pair in a sandbox table, e.g. USD to NGN at 1500,
+// so integration environments price deterministically. The reverse pair is
+// priced at the inverse of the pin.
+type Pin struct {
+	From string
+	To   string
+	// Rate is the units of To per unit of From.
+	Rate decimal.Decimal
+}
+
+// validate checks the pin names two currencies and has a positive rate.
+func (p Pin) validate() error {
+	if p.From == "" || p.To == "" || strings.EqualFold(p.From, p.To) {
+		return fmt.Errorf("%w: pin %s/%s", ErrInvalidPair, p.From, p.To)
+	}
+	if !p.Rate.IsPositive() {
+		return fmt.Errorf("%w: pin %s/%s has rate %s", ErrInvalidRate, p.From, p.To, p.Rate)
+	}
+	return nil
+}
+
+// WithSandbox makes the table a sandbox: every quote it prices is flagged
+// FlagSandbox, and pairs pinned with pins are priced at exactly their pin,
+// without spread and whatever the providers say, and flagged FlagPinned.
+// Invalid pins are ignored; use SetPins to check them.
+func WithSandbox(pins ...Pin) TableOption {
+	return func(c *Currencies) {
+		c.sandbox = true
+		for _, p := range pins {
+			if p.validate() == nil {
+				c.pins = append(c.pins, p)
+			}
+		}
+	}
+}
+
+// SetPins makes the table a sandbox, as WithSandbox does, replacing its
+// pins. It returns an error, changing nothing, if a pin is invalid.
+func (c *Currencies) SetPins(pins ...Pin) error {
+	for _, p := range pins {
+		if err := p.validate(); err != nil {
+			return err
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sandbox = true
+	c.pins = slices.Clone(pins)
+	return nil
+}
+
+// Sandbox reports whether the table is a sandbox.
+func (c *Currencies) Sandbox() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.sandbox
+}
+
+// Pins returns the table's pins.
+func (c *Currencies) Pins() []Pin {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return slices.Clone(c.pins)
+}
+
+// pinnedRate returns the pinned rate from from to to, the inverse of a
+// reverse pin, and reports whether the pair is pinned. A pair pinned both
+// ways uses its direct pin. The caller holds the read lock.
+func (c *Currencies) pinnedRate(from, to string) (decimal.Decimal, bool) {
+	var (
+		reverse decimal.Decimal
+		found   bool
+	)
+	for _, p := range c.pins {
+		switch {
+		case strings.EqualFold(p.From, from) && strings.EqualFold(p.To, to):
+			return p.Rate, true
+		case !found && strings.EqualFold(p.From, to) && strings.EqualFold(p.To, from):
+			reverse, found = p.Rate, true
+		}
+	}
+	if !found {
+		return decimal.Zero, false
+	}
+	if c.exact {
+		return RatToDecimal(new(big.Rat).Inv(reverse.Rat()), exactDivision(c.division)), true
+	}
+	return divide(c.division, decimalOne, reverse), true
+}
@@ -0,0 +1,86 @@
+package converter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithSandbox(t *testing.T) {
+	table := NewTable(testCurrencies(), WithSandbox(Pin{From: "USD", To: "NGN", Rate: decimal.NewFromInt(1600)}))
+	assert.True(t, table.Sandbox())
+
+	// A pinned pair ignores the providers' rates.
+	quote, err := table.NewQuote("USD", "USD", "NGN", decimal.NewFromInt(100), decimal.Zero)
+	assert.NoError(t, err)
+	assert.Equal(t, "1600", quote.Rate.String())
+	assert.Equal(t, "160000", quote.Receive.Amount.String())
+	assert.True(t, quote.HasFlag(FlagSandbox))
+	assert.True(t, quote.HasFlag(FlagPinned))
+
+	rate, err := table.CalculateRate("USD", "ngn", "usd")
+	assert.NoError(t, err)
+	assert.Equal(t, "0.000625", rate.String())
+
+	// The pin survives refreshes.
+	table.Update([]Currency{
+		{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+		{ISOCode: "NGN", Precision: 2, BuyRate: decimal.NewFromInt(1700), SellRate: decimal.NewFromInt(1750)},
+	}, time.Now())
+	rate, err = table.CalculateRate("USD", "USD", "NGN")
+	assert.NoError(t, err)
+	assert.Equal(t, "1600", rate.String())
+
+	// Other pairs are priced from the table but still flagged.
+	table = NewTable(testCurrencies(), WithSandbox(Pin{From: "USD", To: "NGN", Rate: decimal.NewFromInt(1600)}))
+	quote, err = table.NewQuote("USD", "USD", "EUR", decimal.NewFromInt(100), decimal.Zero)
+	assert.NoError(t, err)
+	assert.Equal(t, "0.95", quote.Rate.String())
+	assert.True(t, quote.HasFlag(FlagSandbox))
+	assert.False(t, quote.HasFlag(FlagPinned))
+
+	// Tables that are not sandboxes flag nothing.
+	quote, err = NewTable(testCurrencies()).NewQuote("USD", "USD", "NGN", decimal.NewFromInt(100), decimal.Zero)
+	assert.NoError(t, err)
+	assert.Empty(t, quote.Flags)
+}
+
+func TestWithSandbox_Spread(t *testing.T) {
+	table := NewTable(testCurrencies(),
+		WithSpreadPolicy(SpreadPolicy{Currencies: map[string]decimal.Decimal{"NGN": decimal.NewFromFloat(0.01)}}),
+		WithSandbox(Pin{From: "USD", To: "NGN", Rate: decimal.NewFromInt(1600)}))
+
+	quote, err := table.NewQuote("USD", "USD", "NGN", decimal.NewFromInt(1), decimal.Zero)
+	assert.NoError(t, err)
+	assert.Equal(t, "1600", quote.Rate.String())
+	assert.True(t, quote.Spread.IsZero())
+}
+
+func TestCurrencies_SetPins(t *testing.T) {
+	table := NewTable(testCurrencies())
+
+	err := table.SetPins(Pin{From: "USD", To: "NGN", Rate: decimal.Zero})
+	assert.ErrorIs(t, err, ErrInvalidRate)
+	err = table.SetPins(Pin{From: "USD", To: "usd", Rate: decimal.NewFromInt(1)})
+	assert.ErrorIs(t, err, ErrInvalidPair)
+	assert.False(t, table.Sandbox())
+
+	pin := Pin{From: "EUR", To: "NGN", Rate: decimal.NewFromInt(1700)}
+	assert.NoError(t, table.SetPins(pin))
+	assert.True(t, table.Sandbox())
+	assert.Equal(t, []Pin{pin}, table.Pins())
+
+	quote, err := table.NewQuote("USD", "EUR", "NGN", decimal.NewFromInt(10), decimal.Zero)
+	assert.NoError(t, err)
+	assert.Equal(t, "1700", quote.Rate.String())
+	assert.True(t, quote.HasFlag(FlagPinned))
+
+	// Clearing the pins keeps the table a sandbox.
+	assert.NoError(t, table.SetPins())
+	quote, err = table.NewQuote("USD", "EUR", "NGN", decimal.NewFromInt(10), decimal.Zero)
+	assert.NoError(t, err)
+	assert.False(t, quote.HasFlag(FlagPinned))
+	assert.True(t, quote.HasFlag(FlagSandbox))
+}
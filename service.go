@@ -0,0 +1,159 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Service errors
+var (
+	ErrServiceStarted    = errors.New("service already started")
+	ErrServiceNotStarted = errors.New("service not started")
+)
+
+// Runner is a long-running component. Run blocks until ctx is cancelled and
+// must return once it has released its resources. Refresher and Sweeper are
+// Runners.
+type Runner interface {
+	Run(ctx context.Context) error
+}
+
+// RunnerFunc adapts a function to the Runner interface.
+type RunnerFunc func(ctx context.Context) error
+
+// Run implements Runner.
+func (f RunnerFunc) Run(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Service runs a set of components together, so embedding the converter in a
+// larger application is a matter of calling Start and Stop.
+type Service struct {
+	runners []Runner
+
+	mu  sync.Mutex
+	run *serviceRun
+}
+
+// serviceRun is one Start/Stop cycle of a Service.
+type serviceRun struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu   sync.Mutex
+	errs []error
+}
+
+// NewService creates a service running the given components.
+func NewService(runners ...Runner) *Service {
+	return &Service{runners: runners}
+}
+
+// Start launches every component in its own goroutine and returns
+// immediately. Components stop when ctx is cancelled or Stop is called.
+func (s *Service) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.run != nil {
+		return ErrServiceStarted
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	run := &serviceRun{cancel: cancel, done: make(chan struct{})}
+	s.run = run
+
+	var wg sync.WaitGroup
+	for _, r := range s.runners {
+		wg.Add(1)
+		go func(r Runner) {
+			defer wg.Done()
+			if err := r.Run(ctx); err != nil {
+				run.mu.Lock()
+				run.errs = append(run.errs, err)
+				run.mu.Unlock()
+			}
+		}(r)
+	}
+
+	go func() {
+		wg.Wait()
+		close(run.done)
+	}()
+
+	return nil
+}
+
+// Stop cancels every component and waits for them to return, or for ctx to
+// expire. It returns the errors reported by the components, or ctx's error
+// if they did not return in time. Either way the service is stopped and can
+// be started again; components that missed the deadline keep shutting down
+// in the background.
+func (s *Service) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	run := s.run
+	s.run = nil
+	s.mu.Unlock()
+
+	if run == nil {
+		return ErrServiceNotStarted
+	}
+	run.cancel()
+
+	select {
+	case <-run.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	run.mu.Lock()
+	defer run.mu.Unlock()
+
+	return errors.Join(run.errs...)
+}
+
+// defaultShutdownTimeout is used when ServerRunner.ShutdownTimeout is zero.
+const defaultShutdownTimeout = 15 * time.Second
+
+// ServerRunner runs an HTTP server as a service component.
+type ServerRunner struct {
+	Server *http.Server
+	// ShutdownTimeout bounds how long in-flight requests may take to finish
+	// before their connections are closed. It defaults to 15 seconds.
+	ShutdownTimeout time.Duration
+}
+
+// Run implements Runner.
+func (r ServerRunner) Run(ctx context.Context) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- r.Server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	timeout := r.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = defaultShutdownTimeout
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := r.Server.Shutdown(shutdownCtx); err != nil {
+		// Requests still running past the deadline are cut off.
+		r.Server.Close()
+		<-errCh
+		return err
+	}
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_StartStop(t *testing.T) {
+	var running atomic.Int32
+	runner := RunnerFunc(func(ctx context.Context) error {
+		running.Add(1)
+		defer running.Add(-1)
+		<-ctx.Done()
+		return nil
+	})
+
+	table := NewTable(nil)
+	refresher, err := NewRefresher(table, RateProviderFunc(func(context.Context) ([]Currency, error) {
+		return testCurrencies(), nil
+	}), time.Millisecond)
+	assert.NoError(t, err)
+	sweeper, err := NewSweeper(NewMemoryQuoteStore(), time.Millisecond)
+	assert.NoError(t, err)
+
+	svc := NewService(runner, runner, refresher, sweeper)
+	assert.ErrorIs(t, svc.Stop(context.Background()), ErrServiceNotStarted)
+
+	assert.NoError(t, svc.Start(context.Background()))
+	assert.ErrorIs(t, svc.Start(context.Background()), ErrServiceStarted)
+
+	assert.Eventually(t, func() bool { return running.Load() == 2 && table.Len() == 3 }, time.Second, time.Millisecond)
+
+	assert.NoError(t, svc.Stop(context.Background()))
+	assert.Zero(t, running.Load())
+
+	// A stopped service can be started again
+	assert.NoError(t, svc.Start(context.Background()))
+	assert.NoError(t, svc.Stop(context.Background()))
+}
+
+func TestService_Errors(t *testing.T) {
+	boom := errors.New("boom")
+	svc := NewService(
+		RunnerFunc(func(context.Context) error { return boom }),
+		RunnerFunc(func(ctx context.Context) error { <-ctx.Done(); return nil }),
+	)
+
+	assert.NoError(t, svc.Start(context.Background()))
+	assert.ErrorIs(t, svc.Stop(context.Background()), boom)
+}
+
+func TestService_StopTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	svc := NewService(RunnerFunc(func(context.Context) error {
+		<-release
+		return nil
+	}))
+	assert.NoError(t, svc.Start(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, svc.Stop(ctx), context.DeadlineExceeded)
+
+	// The timed-out service can be started again
+	assert.ErrorIs(t, svc.Stop(context.Background()), ErrServiceNotStarted)
+	assert.NoError(t, svc.Start(context.Background()))
+}
+
+func TestServerRunner_ShutdownTimeout(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	addr := ln.Addr().String()
+	ln.Close()
+
+	started := make(chan struct{})
+	srv := &http.Server{Addr: addr, Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done()
+	})}
+	svc := NewService(ServerRunner{Server: srv, ShutdownTimeout: 10 * time.Millisecond})
+	assert.NoError(t, svc.Start(context.Background()))
+
+	go func() {
+		for {
+			if resp, err := http.Get("http://" + addr); err == nil {
+				resp.Body.Close()
+				return
+			}
+			select {
+			case <-started:
+				return
+			case <-time.After(time.Millisecond):
+			}
+		}
+	}()
+	<-started
+
+	// A request outliving the timeout is cut off instead of blocking Stop
+	assert.ErrorIs(t, svc.Stop(context.Background()), context.DeadlineExceeded)
+}
+
+func TestServerRunner(t *testing.T) {
+	srv := &http.Server{Addr: "127.0.0.1:0", Handler: http.NotFoundHandler()}
+	svc := NewService(ServerRunner{Server: srv, ShutdownTimeout: time.Second})
+
+	assert.NoError(t, svc.Start(context.Background()))
+	time.Sleep(10 * time.Millisecond)
+	assert.NoError(t, svc.Stop(context.Background()))
+
+	// Listen errors are reported
+	bad := &http.Server{Addr: "256.0.0.1:0"}
+	svc = NewService(ServerRunner{Server: bad})
+	assert.NoError(t, svc.Start(context.Background()))
+	time.Sleep(10 * time.Millisecond)
+	assert.Error(t, svc.Stop(context.Background()))
+}
@@ -0,0 +1,60 @@
+package converter
+
+import (
+	"fmt"
+	"time"
+)
+
+// SetSettlement sets the settlement convention of a pair such as "USD/CAD":
+// trades settle days trading days after the trade date, e.g. 1 for T+1 or 2
+// for the usual spot T+2. The convention applies in both directions.
+func (c *Calendar) SetSettlement(pair string, days int) error {
+	from, to, err := parsePair(pair)
+	if err != nil {
+		return err
+	}
+	if days < 0 {
+		return fmt.Errorf("negative settlement days %d for %s", days, pair)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.settlements == nil {
+		c.settlements = map[string]int{}
+	}
+	c.settlements[pairKey(from, to)] = days
+	return nil
+}
+
+// settlementDays returns the settlement convention of a pair, zero if none
+// is set.
+func (c *Calendar) settlementDays(from, to string) int {
+	if c == nil {
+		return 0
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.settlements[pairKey(from, to)]
+}
+
+// SettlementDate returns the value date of a trade of pair booked on
+// tradeDate: tradeDate moved to the next day both currencies trade, then on
+// by the pair's settlement convention counting only such days. Pairs without
+// a convention settle on the trade date.
+func (c *Calendar) SettlementDate(pair string, tradeDate time.Time) (time.Time, error) {
+	from, to, err := parsePair(pair)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return c.settlementDate(from, to, tradeDate)
+}
+
+func (c *Calendar) settlementDate(from, to string, tradeDate time.Time) (time.Time, error) {
+	date, err := c.Adjust(tradeDate, AdjustFollowing, from, to)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return c.AddTradingDays(date, c.settlementDays(from, to), from, to)
+}
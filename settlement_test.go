@@ -0,0 +1,58 @@
+package converter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalendar_SettlementDate(t *testing.T) {
+	cal := NewCalendar()
+	assert.NoError(t, cal.SetSettlement("USD/NGN", 2))
+	assert.NoError(t, cal.SetSettlement("cad/usd", 1))
+	cal.AddHoliday("NGN", day(2024, 4, 1), "Easter Monday")
+
+	// Thursday T+2 skips the weekend and the NGN holiday, in either direction.
+	for _, pair := range []string{"USD/NGN", "NGN/USD"} {
+		date, err := cal.SettlementDate(pair, day(2024, 3, 28))
+		assert.NoError(t, err)
+		assert.Equal(t, day(2024, 4, 2), date, pair)
+	}
+
+	date, err := cal.SettlementDate("USD/CAD", day(2024, 3, 28))
+	assert.NoError(t, err)
+	assert.Equal(t, day(2024, 3, 29), date)
+
+	// A weekend trade date counts from Monday.
+	date, err = cal.SettlementDate("USD/CAD", day(2024, 3, 30))
+	assert.NoError(t, err)
+	assert.Equal(t, day(2024, 4, 2), date)
+
+	// Pairs without a convention settle on the trade date.
+	date, err = cal.SettlementDate("USD/EUR", day(2024, 3, 28))
+	assert.NoError(t, err)
+	assert.Equal(t, day(2024, 3, 28), date)
+
+	_, err = cal.SettlementDate("USDEUR", day(2024, 3, 28))
+	assert.ErrorIs(t, err, ErrInvalidPair)
+	assert.ErrorIs(t, cal.SetSettlement("USD", 1), ErrInvalidPair)
+	assert.Error(t, cal.SetSettlement("USD/EUR", -1))
+}
+
+func TestNewQuote_SettlementDate(t *testing.T) {
+	rateSource := []Currency{
+		{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+		{ISOCode: "NGN", Precision: 2, BuyRate: decimal.NewFromInt(1500), SellRate: decimal.NewFromInt(1550)},
+	}
+	cal := NewCalendar()
+	cal.SetCutOff("NGN", CutOff{Hour: 15})
+	assert.NoError(t, cal.SetSettlement("USD/NGN", 2))
+
+	// Past Thursday's cut-off the trade books on Friday and settles Tuesday.
+	now := time.Date(2024, 3, 28, 16, 0, 0, 0, time.UTC)
+	quote, err := NewQuote(rateSource, "USD", "USD", "NGN", decimal.NewFromInt(100), decimal.Zero, WithClock(fixedClock(now)), WithCalendar(cal))
+	assert.NoError(t, err)
+	assert.Equal(t, day(2024, 4, 2), quote.ValueDate)
+}
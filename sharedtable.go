@@ -0,0 +1,130 @@
+package converter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// DefaultWatchRetry is how long a SharedTable waits before watching again
+// after a watch fails.
+const DefaultWatchRetry = 5 * time.Second
+
+// WatchableBlobStore is a BlobStore whose blobs can be watched, such as an
+// etcd or Consul key space shared by a fleet of instances.
+type WatchableBlobStore interface {
+	BlobStore
+	// Watch sends the blob stored under key, if any, then the blob after
+	// every change, until ctx is done or the watch fails. It returns the
+	// error ending the watch.
+	Watch(ctx context.Context, key string, blobs chan<- []byte) error
+}
+
+// SharedTable keeps one authoritative table in a distributed store, so a
+// fleet of instances serves the same rates instead of each refreshing
+// independently and drifting. The instance refreshing rates publishes
+// them; every instance runs the SharedTable to follow the published table.
+type SharedTable struct {
+	Table *Currencies
+	Store WatchableBlobStore
+	Key   string
+	// Verify, if set, is checked on top of each currency's Validate before
+	// a published table is served. Invariants.Check fits here.
+	Verify func(currencies []Currency) error
+	// Retry is the wait before watching again after a failed watch;
+	// DefaultWatchRetry if zero.
+	Retry time.Duration
+	// OnError, if set, receives watch failures and refused tables.
+	OnError func(err error)
+}
+
+// NewSharedTable creates a shared table following key in store into table.
+func NewSharedTable(table *Currencies, store WatchableBlobStore, key string) *SharedTable {
+	return &SharedTable{Table: table, Store: store, Key: key}
+}
+
+// Snapshots returns a SnapshotStore over the shared key, for a Refresher
+// to publish each refreshed table through.
+func (t *SharedTable) Snapshots() SnapshotStore {
+	return NewBlobSnapshotStore(t.Store, t.Key)
+}
+
+// Publish stores the table's current contents as the shared table.
+func (t *SharedTable) Publish(ctx context.Context) error {
+	return t.Snapshots().Save(ctx, t.Table.Snapshot())
+}
+
+// Run follows the shared table into Table until ctx is cancelled, watching
+// again after Retry whenever the watch fails. Published tables no newer
+// than the table held are ignored, so a slow watch never rolls rates back
+// and the publishing instance does not reload its own table.
+func (t *SharedTable) Run(ctx context.Context) error {
+	retry := t.Retry
+	if retry <= 0 {
+		retry = DefaultWatchRetry
+	}
+
+	for {
+		blobs := make(chan []byte)
+		done := make(chan error, 1)
+		go func() {
+			done <- t.Store.Watch(ctx, t.Key, blobs)
+		}()
+
+		var err error
+	watch:
+		for {
+			select {
+			case b := <-blobs:
+				if applyErr := t.apply(b); applyErr != nil {
+					t.report(fmt.Errorf("shared table %s: %w", t.Key, applyErr))
+				}
+			case err = <-done:
+				break watch
+			}
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			t.report(fmt.Errorf("watch shared table %s: %w", t.Key, err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(retry):
+		}
+	}
+}
+
+// apply validates a published table and restores it if it is newer than
+// the table held.
+func (t *SharedTable) apply(b []byte) error {
+	var snapshot Snapshot
+	if err := json.Unmarshal(b, &snapshot); err != nil {
+		return err
+	}
+	for _, c := range snapshot.Currencies {
+		if err := c.Validate(); err != nil {
+			return err
+		}
+	}
+	if t.Verify != nil {
+		if err := t.Verify(snapshot.Currencies); err != nil {
+			return err
+		}
+	}
+	if len(snapshot.Currencies) == 0 || !snapshot.TakenAt.After(t.Table.UpdatedAt()) {
+		return nil
+	}
+	t.Table.Restore(&snapshot)
+	return nil
+}
+
+func (t *SharedTable) report(err error) {
+	if t.OnError != nil {
+		t.OnError(err)
+	}
+}
@@ -0,0 +1,140 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// watchableBlobs is a MemoryBlobStore whose watches are fed by Put, and
+// fail with failWatch until it is cleared.
+type watchableBlobs struct {
+	*MemoryBlobStore
+
+	mu        sync.Mutex
+	watchers  []chan []byte
+	failWatch error
+}
+
+func newWatchableBlobs() *watchableBlobs {
+	return &watchableBlobs{MemoryBlobStore: NewMemoryBlobStore()}
+}
+
+func (w *watchableBlobs) Put(ctx context.Context, key string, data []byte) error {
+	if err := w.MemoryBlobStore.Put(ctx, key, data); err != nil {
+		return err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, ch := range w.watchers {
+		ch <- data
+	}
+	return nil
+}
+
+func (w *watchableBlobs) Watch(ctx context.Context, key string, blobs chan<- []byte) error {
+	w.mu.Lock()
+	if err := w.failWatch; err != nil {
+		w.mu.Unlock()
+		return err
+	}
+	changes := make(chan []byte, 16)
+	w.watchers = append(w.watchers, changes)
+	w.mu.Unlock()
+
+	if b, err := w.Get(ctx, key); err == nil {
+		blobs <- b
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case b := <-changes:
+			blobs <- b
+		}
+	}
+}
+
+func TestSharedTable(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	takenAt := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	store := newWatchableBlobs()
+
+	// The instance refreshing rates publishes them
+	leader := NewSharedTable(NewTable(nil), store, "rates/table")
+	leader.Table.Update(testCurrencies(), takenAt)
+	require.NoError(t, leader.Publish(ctx))
+
+	// Followers load the published table, then follow its updates
+	follower := NewSharedTable(NewTable(nil), store, "rates/table")
+	done := make(chan error)
+	go func() { done <- follower.Run(ctx) }()
+	require.Eventually(t, func() bool { return follower.Table.Len() == 3 }, time.Second, time.Millisecond)
+	assert.Equal(t, takenAt, follower.Table.UpdatedAt())
+
+	updated := testCurrencies()
+	updated[1].SellRate = decimal.NewFromInt(1600)
+	leader.Table.Update(updated, takenAt.Add(time.Minute))
+	require.NoError(t, leader.Snapshots().Save(ctx, leader.Table.Snapshot()))
+	require.Eventually(t, func() bool { return follower.Table.UpdatedAt().Equal(takenAt.Add(time.Minute)) }, time.Second, time.Millisecond)
+	assert.Equal(t, leader.Table.List(), follower.Table.List())
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestSharedTable_Refused(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	takenAt := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	store := newWatchableBlobs()
+	publisher := NewBlobSnapshotStore(store, "rates")
+
+	errs := make(chan error, 10)
+	follower := NewSharedTable(NewTable(nil), store, "rates")
+	follower.OnError = func(err error) { errs <- err }
+	go follower.Run(ctx)
+
+	// Invalid tables are refused
+	invalid := testCurrencies()
+	invalid[1].SellRate = decimal.NewFromInt(-1)
+	require.NoError(t, publisher.Save(ctx, NewSnapshot(invalid, takenAt)))
+	assert.ErrorIs(t, <-errs, ErrInvalidRate)
+	assert.Zero(t, follower.Table.Len())
+
+	// Tables older than the one held are ignored
+	require.NoError(t, publisher.Save(ctx, NewSnapshot(testCurrencies(), takenAt)))
+	require.Eventually(t, func() bool { return follower.Table.Len() == 3 }, time.Second, time.Millisecond)
+	require.NoError(t, publisher.Save(ctx, NewSnapshot(testCurrencies()[:1], takenAt.Add(-time.Minute))))
+	require.NoError(t, publisher.Save(ctx, NewSnapshot(testCurrencies()[:2], takenAt.Add(time.Minute))))
+	require.Eventually(t, func() bool { return follower.Table.Len() == 2 }, time.Second, time.Millisecond)
+	assert.Equal(t, takenAt.Add(time.Minute), follower.Table.UpdatedAt())
+}
+
+func TestSharedTable_Retry(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errDown := errors.New("store down")
+	store := newWatchableBlobs()
+	store.failWatch = errDown
+	require.NoError(t, NewBlobSnapshotStore(store, "rates").Save(ctx, NewSnapshot(testCurrencies(), time.Now())))
+
+	errs := make(chan error, 10)
+	follower := NewSharedTable(NewTable(nil), store, "rates")
+	follower.Retry = time.Millisecond
+	follower.OnError = func(err error) { errs <- err }
+	go follower.Run(ctx)
+
+	assert.ErrorIs(t, <-errs, errDown)
+	store.mu.Lock()
+	store.failWatch = nil
+	store.mu.Unlock()
+	require.Eventually(t, func() bool { return follower.Table.Len() == 3 }, time.Second, time.Millisecond)
+}
@@ -0,0 +1,38 @@
+package converter
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// QuoteSide is one side of a quote, so UIs can show "You send" and "They
+// receive" without recomputing.
+type QuoteSide struct {
+	Currency string `json:"currency"`
+	// Amount is the total leaving or arriving, fee included: the quote's
+	// AmountToDeduct on the send side and its FinalAmount on the receive
+	// side.
+	Amount decimal.Decimal `json:"amount"`
+	// Fee is the quote's fee in this side's currency, converted at the
+	// quote's rate and rounded up to the currency's precision when the fee
+	// is charged on the other side.
+	Fee decimal.Decimal `json:"fee"`
+}
+
+// quoteSides returns the send and receive sides of a quote and its
+// effective all-in rate: what arrives per unit leaving, with div.
+func quoteSides(from, to *Currency, amountToDeduct, finalAmount, fee, rate decimal.Decimal, mode FeeMode, div *Division) (send, receive QuoteSide, effective decimal.Decimal) {
+	send = QuoteSide{Currency: from.ISOCode, Amount: amountToDeduct, Fee: fee}
+	receive = QuoteSide{Currency: to.ISOCode, Amount: finalAmount, Fee: fee}
+	if mode == FeeInDestination {
+		if rate.IsPositive() {
+			send.Fee = divide(div, fee, rate).RoundCeil(int32(from.Precision))
+		}
+	} else {
+		receive.Fee = fee.Mul(rate).RoundCeil(int32(to.Precision))
+	}
+
+	if amountToDeduct.IsPositive() {
+		effective = divide(div, finalAmount, amountToDeduct)
+	}
+	return send, receive, effective
+}
@@ -0,0 +1,30 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewQuote_Sides(t *testing.T) {
+	quote, err := NewQuote(testCurrencies(), "USD", "USD", "NGN", decimal.NewFromInt(100), decimal.NewFromInt(2))
+	assert.NoError(t, err)
+	assert.Equal(t, QuoteSide{Currency: "USD", Amount: quote.AmountToDeduct, Fee: quote.Fee}, quote.Send)
+	assert.Equal(t, "NGN", quote.Receive.Currency)
+	assert.Equal(t, "155000", quote.Receive.Amount.String())
+	assert.Equal(t, "3100", quote.Receive.Fee.String())
+	assert.Equal(t, "1519.607843137254902", quote.EffectiveRate.String())
+
+	quote, err = NewQuote(testCurrencies(), "USD", "USD", "NGN", decimal.NewFromInt(100), decimal.NewFromInt(3100), WithFeeMode(FeeInDestination))
+	assert.NoError(t, err)
+	assert.Equal(t, "100", quote.Send.Amount.String())
+	assert.Equal(t, "2", quote.Send.Fee.String())
+	assert.Equal(t, "151900", quote.Receive.Amount.String())
+	assert.Equal(t, "3100", quote.Receive.Fee.String())
+	assert.Equal(t, "1519", quote.EffectiveRate.String())
+
+	quote, err = NewQuote(testCurrencies(), "USD", "USD", "NGN", decimal.Zero, decimal.Zero)
+	assert.NoError(t, err)
+	assert.True(t, quote.EffectiveRate.IsZero())
+}
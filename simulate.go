@@ -0,0 +1,178 @@
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Simulation errors
+var (
+	ErrInvalidShock = errors.New("invalid shock")
+)
+
+// Shock is a hypothetical move of a currency's rates, for what-if and
+// stress-testing simulations.
+type Shock struct {
+	// Currency is the ISO code of the shocked currency; empty shocks every
+	// currency but the base.
+	Currency string `json:"currency,omitempty"`
+	// RateChange moves the buy and sell rates by a fraction of themselves,
+	// e.g. -0.1 for -10%. Rates are units per base unit, so a positive change
+	// weakens the currency against the base.
+	RateChange decimal.Decimal `json:"rateChange"`
+	// SpreadChange widens the spread by a fraction of the mid on each side,
+	// as SpreadPolicy.TwoWay does, e.g. 0.005 for +50bps; negative narrows
+	// it.
+	SpreadChange decimal.Decimal `json:"spreadChange"`
+}
+
+// Shock returns a copy of the table with shocks applied in order, so shocks
+// of the same currency compound. The table itself is not modified. The base
+// currency cannot be shocked: its rates stay 1. A shock must name a currency
+// in the table and leave its rates valid.
+func (c *Currencies) Shock(base string, shocks ...Shock) (*Currencies, error) {
+	s := c.Snapshot()
+	items := s.Currencies
+
+	if _, err := FindCurrency(items, base); err != nil {
+		return nil, ErrBaseCurrencyNotFound
+	}
+
+	one := decimal.NewFromInt(1)
+	half := decimal.New(5, -1)
+	for _, shock := range shocks {
+		if strings.EqualFold(shock.Currency, base) {
+			return nil, fmt.Errorf("%w: cannot shock base currency %s", ErrInvalidShock, base)
+		}
+		if shock.RateChange.LessThanOrEqual(one.Neg()) {
+			return nil, fmt.Errorf("%w: rate change %s leaves no rate", ErrInvalidShock, shock.RateChange)
+		}
+
+		matched := false
+		for i, item := range items {
+			if strings.EqualFold(item.ISOCode, base) ||
+				(shock.Currency != "" && !strings.EqualFold(item.ISOCode, shock.Currency)) {
+				continue
+			}
+			matched = true
+
+			factor := one.Add(shock.RateChange)
+			item.BuyRate = item.BuyRate.Mul(factor)
+			item.SellRate = item.SellRate.Mul(factor)
+			if !shock.SpreadChange.IsZero() {
+				crossed := item.BuyRate.LessThan(item.SellRate)
+				widen := item.BuyRate.Add(item.SellRate).Mul(half).Mul(shock.SpreadChange)
+				item.BuyRate = item.BuyRate.Add(widen)
+				item.SellRate = item.SellRate.Sub(widen)
+				if !crossed && item.BuyRate.LessThan(item.SellRate) {
+					return nil, fmt.Errorf("%w: spread change %s crosses %s buy and sell rates", ErrInvalidShock, shock.SpreadChange, item.ISOCode)
+				}
+			}
+			if err := item.Validate(); err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidShock, err)
+			}
+			items[i] = item
+		}
+		if !matched && shock.Currency != "" {
+			return nil, &CurrencyNotFoundError{Code: shock.Currency}
+		}
+	}
+
+	return c.derive(items, s.TakenAt), nil
+}
+
+// Position is an amount held in a currency, valued by simulations.
+type Position struct {
+	Currency string          `json:"currency"`
+	Amount   decimal.Decimal `json:"amount"`
+}
+
+// PositionValue is a position valued in the base currency before and after
+// a shock.
+type PositionValue struct {
+	Position
+	Value        decimal.Decimal `json:"value"`
+	ShockedValue decimal.Decimal `json:"shockedValue"`
+	// Change is ShockedValue minus Value.
+	Change decimal.Decimal `json:"change"`
+}
+
+// QuoteSimulation is a quote repriced against a shocked table.
+type QuoteSimulation struct {
+	Original *Quote `json:"original"`
+	// Shocked and Drift are nil if the quote could not be repriced, e.g.
+	// because a fee now exceeds the amount; Err says why.
+	Shocked *Quote      `json:"shocked,omitempty"`
+	Drift   *QuoteDrift `json:"drift,omitempty"`
+	Err     error       `json:"-"`
+}
+
+// Simulation is the outcome of a what-if scenario.
+type Simulation struct {
+	// Table is the shocked table, for further pricing.
+	Table     *Currencies       `json:"-"`
+	Quotes    []QuoteSimulation `json:"quotes"`
+	Positions []PositionValue   `json:"positions"`
+	// Value and ShockedValue total the positions in the base currency;
+	// Change is ShockedValue minus Value.
+	Value        decimal.Decimal `json:"value"`
+	ShockedValue decimal.Decimal `json:"shockedValue"`
+	Change       decimal.Decimal `json:"change"`
+}
+
+// Failed returns the quotes that could not be repriced.
+func (s *Simulation) Failed() []QuoteSimulation {
+	return slices.DeleteFunc(slices.Clone(s.Quotes), func(q QuoteSimulation) bool {
+		return q.Err == nil
+	})
+}
+
+// Simulate applies shocks to a copy of the table, as Shock does, then
+// reprices quotes against it with Quote.Reprice and values positions in the
+// base currency at the rate the table buys them at, before and after. A
+// quote that cannot be repriced is recorded with its error rather than
+// failing the simulation; a position that cannot be valued fails it. opts
+// are passed to Reprice.
+func (c *Currencies) Simulate(base string, shocks []Shock, quotes []*Quote, positions []Position, opts ...QuoteOption) (*Simulation, error) {
+	shocked, err := c.Shock(base, shocks...)
+	if err != nil {
+		return nil, err
+	}
+
+	sim := &Simulation{
+		Table:     shocked,
+		Quotes:    make([]QuoteSimulation, len(quotes)),
+		Positions: make([]PositionValue, len(positions)),
+	}
+	for i, q := range quotes {
+		repriced, drift, err := q.Reprice(shocked, opts...)
+		sim.Quotes[i] = QuoteSimulation{Original: q, Shocked: repriced, Drift: drift, Err: err}
+	}
+
+	for i, p := range positions {
+		before, err := c.CalculateRate(base, p.Currency, base)
+		if err != nil {
+			return nil, fmt.Errorf("value position in %s: %w", p.Currency, err)
+		}
+		after, err := shocked.CalculateRate(base, p.Currency, base)
+		if err != nil {
+			return nil, fmt.Errorf("value position in %s: %w", p.Currency, err)
+		}
+
+		v := PositionValue{
+			Position:     p,
+			Value:        p.Amount.Mul(before),
+			ShockedValue: p.Amount.Mul(after),
+		}
+		v.Change = v.ShockedValue.Sub(v.Value)
+		sim.Positions[i] = v
+		sim.Value = sim.Value.Add(v.Value)
+		sim.ShockedValue = sim.ShockedValue.Add(v.ShockedValue)
+	}
+	sim.Change = sim.ShockedValue.Sub(sim.Value)
+	return sim, nil
+}
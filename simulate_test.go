@@ -0,0 +1,96 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurrencies_Shock(t *testing.T) {
+	table := NewTable(testCurrencies())
+
+	shocked, err := table.Shock("USD",
+		Shock{Currency: "ngn", RateChange: decimal.RequireFromString("0.1")},
+		Shock{SpreadChange: decimal.RequireFromString("0.01")},
+	)
+	assert.NoError(t, err)
+
+	usd, _ := shocked.FindCurrency("USD")
+	assert.Equal(t, "1", usd.BuyRate.String())
+	assert.Equal(t, "1", usd.SellRate.String())
+	eur, _ := shocked.FindCurrency("EUR")
+	assert.Equal(t, "0.90925", eur.BuyRate.String())
+	assert.Equal(t, "0.94075", eur.SellRate.String())
+	ngn, _ := shocked.FindCurrency("NGN")
+	assert.Equal(t, "1666.775", ngn.BuyRate.String())
+	assert.Equal(t, "1688.225", ngn.SellRate.String())
+
+	original, _ := table.FindCurrency("NGN")
+	assert.Equal(t, "1500", original.BuyRate.String())
+}
+
+func TestCurrencies_ShockErrors(t *testing.T) {
+	table := NewTable(testCurrencies())
+
+	_, err := table.Shock("GBP")
+	assert.ErrorIs(t, err, ErrBaseCurrencyNotFound)
+
+	_, err = table.Shock("USD", Shock{Currency: "usd", RateChange: decimal.RequireFromString("0.1")})
+	assert.ErrorIs(t, err, ErrInvalidShock)
+
+	_, err = table.Shock("USD", Shock{Currency: "NGN", RateChange: decimal.NewFromInt(-1)})
+	assert.ErrorIs(t, err, ErrInvalidShock)
+
+	_, err = table.Shock("USD", Shock{Currency: "GBP", RateChange: decimal.RequireFromString("0.1")})
+	assert.ErrorIs(t, err, ErrUnknownCurrency)
+
+	rates := testCurrencies()
+	rates[2].BuyRate, rates[2].SellRate = decimal.NewFromInt(1550), decimal.NewFromInt(1500)
+	_, err = NewTable(rates).Shock("USD", Shock{Currency: "NGN", SpreadChange: decimal.RequireFromString("-0.1")})
+	assert.ErrorIs(t, err, ErrInvalidShock)
+}
+
+func TestCurrencies_Simulate(t *testing.T) {
+	table := NewTable(testCurrencies())
+
+	quote, err := table.NewQuote("USD", "USD", "NGN", decimal.NewFromInt(100), decimal.Zero)
+	assert.NoError(t, err)
+	orphan := &Quote{BaseCurrency: "USD", FromCurrency: "USD", ToCurrency: "GBP", FromAmount: decimal.NewFromInt(1)}
+
+	sim, err := table.Simulate("USD",
+		[]Shock{{Currency: "NGN", RateChange: decimal.RequireFromString("0.1")}},
+		[]*Quote{quote, orphan},
+		[]Position{
+			{Currency: "NGN", Amount: decimal.NewFromInt(3300)},
+			{Currency: "USD", Amount: decimal.NewFromInt(10)},
+		},
+	)
+	assert.NoError(t, err)
+
+	assert.Len(t, sim.Quotes, 2)
+	assert.Same(t, quote, sim.Quotes[0].Original)
+	assert.NoError(t, sim.Quotes[0].Err)
+	assert.Equal(t, "170500", sim.Quotes[0].Shocked.FinalAmount.String())
+	assert.Equal(t, "15500", sim.Quotes[0].Drift.FinalAmount.String())
+	assert.Equal(t, "10", sim.Quotes[0].Drift.RateChange.String())
+
+	failed := sim.Failed()
+	assert.Len(t, failed, 1)
+	assert.Same(t, orphan, failed[0].Original)
+	assert.ErrorIs(t, failed[0].Err, ErrUnknownCurrency)
+	assert.Nil(t, failed[0].Shocked)
+
+	assert.Equal(t, "2.2", sim.Positions[0].Value.Round(2).String())
+	assert.Equal(t, "2", sim.Positions[0].ShockedValue.Round(2).String())
+	assert.Equal(t, "-0.2", sim.Positions[0].Change.Round(2).String())
+	assert.Equal(t, "10", sim.Positions[1].Change.Add(sim.Positions[1].Value).String())
+	assert.Equal(t, "12.2", sim.Value.Round(2).String())
+	assert.Equal(t, "-0.2", sim.Change.Round(2).String())
+
+	ngn, _ := sim.Table.FindCurrency("NGN")
+	assert.Equal(t, "1650", ngn.BuyRate.String())
+
+	_, err = table.Simulate("USD", nil, nil, []Position{{Currency: "GBP", Amount: decimal.NewFromInt(1)}})
+	assert.ErrorIs(t, err, ErrUnknownCurrency)
+}
@@ -0,0 +1,102 @@
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidSmoothing is returned for a Smoothing with an unknown average
+// or a window below one.
+var ErrInvalidSmoothing = errors.New("invalid smoothing")
+
+// MovingAverage is a kind of moving average.
+type MovingAverage string
+
+// Moving averages
+const (
+	// SMA is the simple moving average: the mean of the last Window rates.
+	SMA MovingAverage = "sma"
+	// EMA is the exponential moving average with smoothing factor
+	// 2/(Window+1), seeded with the oldest rate in the history, so recent
+	// rates weigh more and older ones fade out gradually.
+	EMA MovingAverage = "ema"
+)
+
+// Smoothing configures smoothed rates: display rates that do not jitter
+// tick by tick, averaged over a RateHistory.
+type Smoothing struct {
+	Average MovingAverage `json:"average"`
+	// Window is the number of most recent rates averaged, or the EMA's
+	// span.
+	Window int `json:"window"`
+}
+
+// Validate checks the average and window.
+func (s Smoothing) Validate() error {
+	if s.Average != SMA && s.Average != EMA {
+		return fmt.Errorf("%w: unknown average %q", ErrInvalidSmoothing, s.Average)
+	}
+	if s.Window < 1 {
+		return fmt.Errorf("%w: window %d is below one", ErrInvalidSmoothing, s.Window)
+	}
+	return nil
+}
+
+// apply averages rates, oldest first, which must not be empty.
+func (s Smoothing) apply(rates []decimal.Decimal) decimal.Decimal {
+	if s.Average == SMA {
+		recent := rates[max(len(rates)-s.Window, 0):]
+		return decimal.Sum(recent[0], recent[1:]...).Div(decimal.NewFromInt(int64(len(recent))))
+	}
+
+	alpha := decimal.NewFromInt(2).Div(decimal.NewFromInt(int64(s.Window) + 1))
+	keep := decimal.NewFromInt(1).Sub(alpha)
+	ema := rates[0]
+	for _, r := range rates[1:] {
+		// Rounding keeps the digits from growing with every rate.
+		ema = r.Mul(alpha).Add(ema.Mul(keep)).Round(int32(decimal.DivisionPrecision))
+	}
+	return ema
+}
+
+// SmoothedRate returns the rate from one currency to another averaged over
+// the history as s configures, each rate priced against base as the
+// CalculateRate function does. It returns ErrNoRateSamples if no table in
+// the history can price the pair.
+func (h *RateHistory) SmoothedRate(base, from, to string, s Smoothing) (decimal.Decimal, error) {
+	if err := s.Validate(); err != nil {
+		return decimal.Zero, err
+	}
+	_, rates := h.pairSamples(base, from, to)
+	if len(rates) == 0 {
+		return decimal.Zero, fmt.Errorf("%w: %s/%s", ErrNoRateSamples, strings.ToUpper(from), strings.ToUpper(to))
+	}
+	return s.apply(rates), nil
+}
+
+// Smoothed returns the currencies of the latest table with their buy and
+// sell rates each averaged over the tables holding the currency, as s
+// configures. A table of them is an alternative rate basis: every pair
+// priced from it is priced from smoothed rates. It returns ErrNoRateSamples
+// for an empty history.
+func (h *RateHistory) Smoothed(s Smoothing) ([]Currency, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	tables := h.tables()
+	if len(tables) == 0 {
+		return nil, ErrNoRateSamples
+	}
+
+	latest := tables[len(tables)-1].Currencies
+	smoothed := make([]Currency, len(latest))
+	for i, c := range latest {
+		buys, sells := currencySamples(tables, c.ISOCode)
+		c.BuyRate, c.SellRate = s.apply(buys), s.apply(sells)
+		smoothed[i] = c
+	}
+	return smoothed, nil
+}
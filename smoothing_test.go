@@ -0,0 +1,76 @@
+package converter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func smoothingHistory(t *testing.T) *RateHistory {
+	start := time.Date(2024, 3, 28, 12, 0, 0, 0, time.UTC)
+	h, err := NewRateHistory(time.Hour)
+	require.NoError(t, err)
+	h.Clock = fixedClock(start.Add(5 * time.Minute))
+	for i, sell := range []int64{1550, 1560, 1540, 1580} {
+		h.Record(ngnSnapshot(sell, start.Add(time.Duration(i)*time.Minute)))
+	}
+	return h
+}
+
+func TestSmoothing_Validate(t *testing.T) {
+	assert.NoError(t, Smoothing{Average: SMA, Window: 1}.Validate())
+	assert.NoError(t, Smoothing{Average: EMA, Window: 20}.Validate())
+	assert.ErrorIs(t, Smoothing{Average: "wma", Window: 3}.Validate(), ErrInvalidSmoothing)
+	assert.ErrorIs(t, Smoothing{Average: SMA}.Validate(), ErrInvalidSmoothing)
+}
+
+func TestRateHistory_SmoothedRate(t *testing.T) {
+	h := smoothingHistory(t)
+
+	tests := []struct {
+		smoothing Smoothing
+		want      string
+	}{
+		{Smoothing{Average: SMA, Window: 1}, "1580"},
+		{Smoothing{Average: SMA, Window: 2}, "1560"},
+		{Smoothing{Average: SMA, Window: 10}, "1557.5"},
+		{Smoothing{Average: EMA, Window: 3}, "1563.75"},
+		{Smoothing{Average: EMA, Window: 1}, "1580"},
+	}
+	for _, tt := range tests {
+		rate, err := h.SmoothedRate("USD", "USD", "NGN", tt.smoothing)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, rate.String(), "%+v", tt.smoothing)
+	}
+
+	_, err := h.SmoothedRate("USD", "USD", "GBP", Smoothing{Average: SMA, Window: 2})
+	assert.ErrorIs(t, err, ErrNoRateSamples)
+	_, err = h.SmoothedRate("USD", "USD", "NGN", Smoothing{Average: SMA})
+	assert.ErrorIs(t, err, ErrInvalidSmoothing)
+}
+
+func TestRateHistory_Smoothed(t *testing.T) {
+	h := smoothingHistory(t)
+
+	smoothed, err := h.Smoothed(Smoothing{Average: SMA, Window: 2})
+	require.NoError(t, err)
+	require.Len(t, smoothed, 3)
+	ngn, err := FindCurrency(smoothed, "NGN")
+	require.NoError(t, err)
+	assert.Equal(t, "1500", ngn.BuyRate.String())
+	assert.Equal(t, "1560", ngn.SellRate.String())
+
+	// A table of smoothed rates prices every pair from them.
+	rate, err := NewTable(smoothed).CalculateRate("USD", "USD", "NGN")
+	require.NoError(t, err)
+	assert.Equal(t, "1560", rate.String())
+
+	empty, err := NewRateHistory(time.Hour)
+	require.NoError(t, err)
+	_, err = empty.Smoothed(Smoothing{Average: EMA, Window: 5})
+	assert.ErrorIs(t, err, ErrNoRateSamples)
+	_, err = h.Smoothed(Smoothing{Average: "wma", Window: 5})
+	assert.ErrorIs(t, err, ErrInvalidSmoothing)
+}
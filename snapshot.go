@@ -0,0 +1,60 @@
+package converter
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Snapshot is a point-in-time copy of a rate table.
+type Snapshot struct {
+	Currencies []Currency `json:"currencies"`
+	TakenAt    time.Time  `json:"takenAt"`
+	// Degraded marks a snapshot of fallback rates (see
+	// Currencies.UpdateFallback), which must not be persisted as last
+	// known good.
+	Degraded bool `json:"degraded,omitempty"`
+}
+
+// NewSnapshot creates a snapshot of currencies taken at the given time. The
+// snapshot holds a copy of currencies sorted by ISO code, so snapshots of the
+// same rates encode identically.
+func NewSnapshot(currencies []Currency, takenAt time.Time) *Snapshot {
+	return &Snapshot{
+		Currencies: sortedCurrencies(currencies),
+		TakenAt:    takenAt,
+	}
+}
+
+// snapshotJSON has the fields of Snapshot without its methods, so the JSON
+// encoding stays an object even though Snapshot is a TextMarshaler.
+type snapshotJSON Snapshot
+
+// MarshalJSON implements json.Marshaler.
+func (s Snapshot) MarshalJSON() ([]byte, error) {
+	return json.Marshal(snapshotJSON(s))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *Snapshot) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, (*snapshotJSON)(s))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s Snapshot) MarshalText() ([]byte, error) {
+	return s.MarshalJSON()
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *Snapshot) UnmarshalText(text []byte) error {
+	return s.UnmarshalJSON(text)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (s Snapshot) MarshalBinary() ([]byte, error) {
+	return s.MarshalJSON()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (s *Snapshot) UnmarshalBinary(data []byte) error {
+	return s.UnmarshalJSON(data)
+}
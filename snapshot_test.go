@@ -0,0 +1,45 @@
+package converter
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshot_Marshalers(t *testing.T) {
+	currencies := []Currency{
+		{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+		{ISOCode: "EUR", Precision: 2, BuyRate: decimal.NewFromFloat(0.9), SellRate: decimal.NewFromFloat(0.95)},
+	}
+	s := NewSnapshot(currencies, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	// The snapshot owns its copy of the currencies
+	currencies[0].ISOCode = "XXX"
+	assert.Equal(t, "USD", s.Currencies[1].ISOCode)
+
+	// Currencies are sorted by ISO code
+	assert.Equal(t, "EUR", s.Currencies[0].ISOCode)
+
+	// JSON stays an object
+	b, err := json.Marshal(s)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `"takenAt":"2024-01-02T03:04:05Z"`)
+
+	text, err := s.MarshalText()
+	assert.NoError(t, err)
+	var fromText Snapshot
+	assert.NoError(t, fromText.UnmarshalText(text))
+	assert.Len(t, fromText.Currencies, 2)
+	assert.True(t, s.TakenAt.Equal(fromText.TakenAt))
+
+	var buf bytes.Buffer
+	assert.NoError(t, gob.NewEncoder(&buf).Encode(s))
+	var fromGob Snapshot
+	assert.NoError(t, gob.NewDecoder(&buf).Decode(&fromGob))
+	assert.Equal(t, "0.95", fromGob.Currencies[0].SellRate.String())
+}
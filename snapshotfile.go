@@ -0,0 +1,132 @@
+package converter
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// ErrSnapshotChecksum is returned when a snapshot file does not match its
+// checksum, e.g. after a partial copy or a hand edit.
+var ErrSnapshotChecksum = errors.New("snapshot checksum mismatch")
+
+// SnapshotFormat is the encoding of a snapshot file.
+type SnapshotFormat string
+
+// Snapshot formats
+const (
+	SnapshotJSON SnapshotFormat = "json"
+	SnapshotGob  SnapshotFormat = "gob"
+)
+
+// SnapshotFormatOf returns the format of a snapshot file by its extension:
+// gob for ".gob", else JSON.
+func SnapshotFormatOf(path string) SnapshotFormat {
+	if strings.EqualFold(filepath.Ext(path), ".gob") {
+		return SnapshotGob
+	}
+	return SnapshotJSON
+}
+
+// SaveSnapshot writes the table to a snapshot file at path, in the format
+// of its extension and with a checksum. The file is replaced atomically.
+func (c *Currencies) SaveSnapshot(path string) error {
+	return NewFileSnapshotStore(path).Save(context.Background(), c.Snapshot())
+}
+
+// LoadSnapshot replaces the table contents with the snapshot file at path,
+// keeping the snapshot's original timestamp. The file's checksum and
+// currencies are verified first; on any error the table is unchanged.
+func (c *Currencies) LoadSnapshot(path string) error {
+	snapshot, err := loadValidSnapshot(context.Background(), NewFileSnapshotStore(path))
+	if err != nil {
+		return err
+	}
+	c.Restore(snapshot)
+	return nil
+}
+
+// snapshotFile is the envelope of a snapshot file: the encoded snapshot
+// and the hex SHA-256 checksum of its bytes.
+type snapshotFile struct {
+	Checksum string          `json:"checksum"`
+	Snapshot json.RawMessage `json:"snapshot"`
+}
+
+// snapshotGobFile is snapshotFile in gob.
+type snapshotGobFile struct {
+	Checksum string
+	Snapshot []byte
+}
+
+// encodeSnapshotFile encodes s in format, in a checksummed envelope.
+func encodeSnapshotFile(s *Snapshot, format SnapshotFormat) ([]byte, error) {
+	if format == SnapshotGob {
+		var payload bytes.Buffer
+		if err := gob.NewEncoder(&payload).Encode((*snapshotJSON)(s)); err != nil {
+			return nil, err
+		}
+		var file bytes.Buffer
+		err := gob.NewEncoder(&file).Encode(snapshotGobFile{
+			Checksum: checksum(payload.Bytes()),
+			Snapshot: payload.Bytes(),
+		})
+		return file.Bytes(), err
+	}
+
+	payload, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(snapshotFile{Checksum: checksum(payload), Snapshot: payload})
+}
+
+// decodeSnapshotFile decodes a snapshot file in format, verifying its
+// checksum. JSON files written before checksums, holding a bare snapshot,
+// are read as they are.
+func decodeSnapshotFile(b []byte, format SnapshotFormat) (*Snapshot, error) {
+	var snapshot Snapshot
+	if format == SnapshotGob {
+		var file snapshotGobFile
+		if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&file); err != nil {
+			return nil, err
+		}
+		if checksum(file.Snapshot) != file.Checksum {
+			return nil, fmt.Errorf("%w: want %s", ErrSnapshotChecksum, file.Checksum)
+		}
+		if err := gob.NewDecoder(bytes.NewReader(file.Snapshot)).Decode((*snapshotJSON)(&snapshot)); err != nil {
+			return nil, err
+		}
+		return &snapshot, nil
+	}
+
+	var file snapshotFile
+	if err := json.Unmarshal(b, &file); err != nil {
+		return nil, err
+	}
+	if file.Checksum == "" && file.Snapshot == nil {
+		if err := json.Unmarshal(b, &snapshot); err != nil {
+			return nil, err
+		}
+		return &snapshot, nil
+	}
+	if checksum(file.Snapshot) != file.Checksum {
+		return nil, fmt.Errorf("%w: want %s", ErrSnapshotChecksum, file.Checksum)
+	}
+	if err := json.Unmarshal(file.Snapshot, &snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+func checksum(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,68 @@
+package converter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCurrencies_SaveLoadSnapshot(t *testing.T) {
+	takenAt := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	table := NewTable(nil)
+	table.Update(testCurrencies(), takenAt)
+
+	for _, name := range []string{"rates.json", "rates.gob"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			require.NoError(t, table.SaveSnapshot(path))
+
+			loaded := NewTable(testCurrencies()[:1])
+			require.NoError(t, loaded.LoadSnapshot(path))
+			assert.Equal(t, table.List(), loaded.List())
+			assert.Equal(t, takenAt, loaded.UpdatedAt())
+
+			// A corrupted file is refused and the table left unchanged
+			b, err := os.ReadFile(path)
+			require.NoError(t, err)
+			if SnapshotFormatOf(path) == SnapshotJSON {
+				b = bytes.Replace(b, []byte(`"1550"`), []byte(`"1590"`), 1)
+			} else {
+				// The snapshot bytes end the gob envelope
+				b[len(b)-2] ^= 1
+			}
+			require.NoError(t, os.WriteFile(path, b, 0o600))
+
+			unchanged := NewTable(testCurrencies()[:1])
+			assert.ErrorIs(t, unchanged.LoadSnapshot(path), ErrSnapshotChecksum)
+			assert.Equal(t, 1, unchanged.Len())
+		})
+	}
+
+	// Missing file
+	assert.ErrorIs(t, NewTable(nil).LoadSnapshot(filepath.Join(t.TempDir(), "none.json")), ErrSnapshotNotFound)
+}
+
+func TestSnapshotFormatOf(t *testing.T) {
+	assert.Equal(t, SnapshotGob, SnapshotFormatOf("/var/lib/rates.GOB"))
+	assert.Equal(t, SnapshotJSON, SnapshotFormatOf("rates.json"))
+	assert.Equal(t, SnapshotJSON, SnapshotFormatOf("rates"))
+}
+
+func TestFileSnapshotStore_Legacy(t *testing.T) {
+	// Files written before checksums hold a bare snapshot
+	path := filepath.Join(t.TempDir(), "rates.json")
+	b, err := json.Marshal(NewSnapshot(testCurrencies(), time.Now()))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, b, 0o600))
+
+	snapshot, err := NewFileSnapshotStore(path).Load(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, snapshot.Currencies, 3)
+}
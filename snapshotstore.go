@@ -0,0 +1,78 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// Snapshot store errors
+var (
+	ErrSnapshotNotFound = errors.New("snapshot not found")
+	ErrSnapshotTooOld   = errors.New("snapshot too old")
+)
+
+// SnapshotStore persists the latest rate table snapshot. Implementations can
+// be backed by a file, Redis, SQL or any other storage.
+type SnapshotStore interface {
+	// Save replaces the stored snapshot.
+	Save(ctx context.Context, s *Snapshot) error
+	// Load returns the stored snapshot or ErrSnapshotNotFound.
+	Load(ctx context.Context) (*Snapshot, error)
+}
+
+// FileSnapshotStore stores a snapshot as a file, in JSON or, for a ".gob"
+// path, gob, with a checksum verified on load.
+type FileSnapshotStore struct {
+	Path string
+}
+
+// NewFileSnapshotStore creates a snapshot store writing to path.
+func NewFileSnapshotStore(path string) *FileSnapshotStore {
+	return &FileSnapshotStore{Path: path}
+}
+
+// Save implements SnapshotStore. The file is replaced atomically so a crash
+// mid-write never leaves a truncated snapshot behind.
+func (s *FileSnapshotStore) Save(_ context.Context, snapshot *Snapshot) error {
+	b, err := encodeSnapshotFile(snapshot, SnapshotFormatOf(s.Path))
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.Path), filepath.Base(s.Path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	// Flush to disk before the rename, or a crash can leave an empty file
+	// under the final name.
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.Path)
+}
+
+// Load implements SnapshotStore.
+func (s *FileSnapshotStore) Load(_ context.Context) (*Snapshot, error) {
+	b, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrSnapshotNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeSnapshotFile(b, SnapshotFormatOf(s.Path))
+}
@@ -0,0 +1,38 @@
+package converter
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileSnapshotStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewFileSnapshotStore(filepath.Join(t.TempDir(), "rates.json"))
+
+	_, err := store.Load(ctx)
+	assert.ErrorIs(t, err, ErrSnapshotNotFound)
+
+	at := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	assert.NoError(t, store.Save(ctx, NewSnapshot(testCurrencies(), at)))
+
+	snapshot, err := store.Load(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, snapshot.Currencies, 3)
+	assert.True(t, at.Equal(snapshot.TakenAt))
+	assert.Equal(t, "1550", snapshot.Currencies[1].SellRate.String())
+
+	// No temporary files left behind
+	entries, err := os.ReadDir(filepath.Dir(store.Path))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	// Corrupt file
+	assert.NoError(t, os.WriteFile(store.Path, []byte("{"), 0o600))
+	_, err = store.Load(ctx)
+	assert.Error(t, err)
+}
@@ -0,0 +1,181 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Spike errors
+var (
+	ErrRateSpike = errors.New("rate spike")
+)
+
+// SpikeError reports a fetched rate that moved further from the recent
+// history of its currency than a SpikeGuard allows. It matches ErrRateSpike.
+type SpikeError struct {
+	ISOCode string
+	// Side is "buy" or "sell".
+	Side string
+	Rate decimal.Decimal
+	// Previous is the latest rate in the history and Change the relative
+	// move from it, e.g. 0.1 for 10%.
+	Previous decimal.Decimal
+	Change   decimal.Decimal
+	// Mean is the mean of the history's rates and StdDevs how many
+	// standard deviations Rate is from it, zero if not checked.
+	Mean    decimal.Decimal
+	StdDevs decimal.Decimal
+}
+
+// Error implements error.
+func (e *SpikeError) Error() string {
+	msg := fmt.Sprintf("%v: %s %s rate %s moved %s from %s",
+		ErrRateSpike, e.ISOCode, e.Side, e.Rate, e.Change.StringFixed(4), e.Previous)
+	if !e.StdDevs.IsZero() {
+		msg += fmt.Sprintf(", %s standard deviations from the mean %s", e.StdDevs.StringFixed(2), e.Mean.StringFixed(4))
+	}
+	return msg
+}
+
+// Is makes every SpikeError match ErrRateSpike.
+func (e *SpikeError) Is(target error) bool {
+	return target == ErrRateSpike
+}
+
+// SpikeGuard is a RateProvider that checks the rates of Provider against
+// their recent History, so a bad tick from a feed is caught at refresh
+// time before it reaches a live table. History should follow the guarded
+// table, see RateHistory.Follow. Currencies without history are not
+// checked.
+type SpikeGuard struct {
+	Provider RateProvider
+	History  *RateHistory
+	// Base is the currency the rates are quoted against, naming pairs in
+	// events.
+	Base string
+	// MaxChange, if positive, is the largest accepted relative move from
+	// the latest rate in the history, e.g. 0.1 for 10%.
+	MaxChange decimal.Decimal
+	// MaxStdDevs, if positive, is the largest accepted distance from the
+	// mean of the history's rates, in standard deviations. Histories with
+	// fewer than MinSamples rates, or without variation, are only checked
+	// against MaxChange.
+	MaxStdDevs decimal.Decimal
+	MinSamples int
+	// Quarantine keeps serving a spiking currency's latest rates from the
+	// history and passes the others through, instead of failing the fetch.
+	Quarantine bool
+	// OnSpike, if set, is called with the spikes of every fetch that has
+	// any, for human review.
+	OnSpike func(spikes []*SpikeError)
+	// OnEvent, if set, receives an EventRateRejected or
+	// EventRateQuarantined per spike.
+	OnEvent EventHandler
+	// Clock defaults to SystemClock.
+	Clock Clock
+}
+
+// FetchRates implements RateProvider. Unless Quarantine is set, it fails
+// with the joined spikes when any rate spikes.
+func (g *SpikeGuard) FetchRates(ctx context.Context) ([]Currency, error) {
+	currencies, err := g.Provider.FetchRates(ctx)
+	if err != nil || g.History == nil {
+		return currencies, err
+	}
+
+	tables := g.History.tables()
+	spikes := g.Spikes(currencies, tables)
+	if len(spikes) == 0 {
+		return currencies, nil
+	}
+	if g.OnSpike != nil {
+		g.OnSpike(spikes)
+	}
+	if g.OnEvent != nil {
+		clock := g.Clock
+		if clock == nil {
+			clock = SystemClock
+		}
+		event := EventRateRejected
+		if g.Quarantine {
+			event = EventRateQuarantined
+		}
+		for _, s := range spikes {
+			g.OnEvent(Event{Type: event, Time: clock.Now(), Pair: strings.ToUpper(g.Base) + "/" + s.ISOCode, Reason: s.Error()})
+		}
+	}
+
+	if !g.Quarantine {
+		errs := make([]error, len(spikes))
+		for i, s := range spikes {
+			errs[i] = s
+		}
+		return nil, errors.Join(errs...)
+	}
+
+	quarantined := map[string]bool{}
+	for _, s := range spikes {
+		quarantined[s.ISOCode] = true
+	}
+	kept := make([]Currency, len(currencies))
+	for i, c := range currencies {
+		kept[i] = c
+		if !quarantined[strings.ToUpper(c.ISOCode)] {
+			continue
+		}
+		for j := len(tables) - 1; j >= 0; j-- {
+			if previous, err := FindCurrency(tables[j].Currencies, c.ISOCode); err == nil {
+				kept[i] = *previous
+				break
+			}
+		}
+	}
+	return kept, nil
+}
+
+// Spikes checks the buy and sell rates of currencies against tables, oldest
+// first, and returns those that spike, in the order of currencies.
+func (g *SpikeGuard) Spikes(currencies []Currency, tables []*Snapshot) []*SpikeError {
+	var spikes []*SpikeError
+	for _, c := range currencies {
+		code := strings.ToUpper(c.ISOCode)
+		buys, sells := currencySamples(tables, code)
+		if s := g.spike(code, "buy", c.BuyRate, buys); s != nil {
+			spikes = append(spikes, s)
+		}
+		if s := g.spike(code, "sell", c.SellRate, sells); s != nil {
+			spikes = append(spikes, s)
+		}
+	}
+	return spikes
+}
+
+// spike returns the spike of rate from history if it is beyond the guard's
+// limits.
+func (g *SpikeGuard) spike(code, side string, rate decimal.Decimal, history []decimal.Decimal) *SpikeError {
+	if len(history) == 0 {
+		return nil
+	}
+	s := &SpikeError{ISOCode: code, Side: side, Rate: rate, Previous: history[len(history)-1]}
+	spiked := false
+	if s.Previous.IsPositive() {
+		s.Change = rate.Sub(s.Previous).Abs().DivRound(s.Previous, maxDigits)
+		spiked = g.MaxChange.IsPositive() && s.Change.GreaterThan(g.MaxChange)
+	}
+	if g.MaxStdDevs.IsPositive() && len(history) >= max(g.MinSamples, 2) {
+		mean, stdDev := meanStdDev(history)
+		if stdDev.IsPositive() {
+			s.Mean = mean
+			s.StdDevs = rate.Sub(mean).Abs().DivRound(stdDev, maxDigits)
+			spiked = spiked || s.StdDevs.GreaterThan(g.MaxStdDevs)
+		}
+	}
+	if !spiked {
+		return nil
+	}
+	return s
+}
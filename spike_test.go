@@ -0,0 +1,122 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpikeGuard_Spikes(t *testing.T) {
+	tables := smoothingHistory(t).tables()
+	incoming := func(sell int64) []Currency { return ngnSnapshot(sell, time.Time{}).Currencies }
+
+	g := &SpikeGuard{MaxChange: decimal.RequireFromString("0.1")}
+	assert.Empty(t, g.Spikes(incoming(1600), tables))
+	spikes := g.Spikes(incoming(3000), tables)
+	require.Len(t, spikes, 1)
+	assert.Equal(t, "NGN", spikes[0].ISOCode)
+	assert.Equal(t, "sell", spikes[0].Side)
+	assert.Equal(t, "1580", spikes[0].Previous.String())
+	assert.Equal(t, "0.8987", spikes[0].Change.StringFixed(4))
+	assert.EqualError(t, spikes[0], "rate spike: NGN sell rate 3000 moved 0.8987 from 1580")
+
+	g = &SpikeGuard{MaxStdDevs: decimal.NewFromInt(2)}
+	spikes = g.Spikes(incoming(1600), tables)
+	require.Len(t, spikes, 1)
+	assert.Equal(t, "2.87", spikes[0].StdDevs.StringFixed(2))
+	assert.EqualError(t, spikes[0], "rate spike: NGN sell rate 1600 moved 0.0127 from 1580, 2.87 standard deviations from the mean 1557.5000")
+	assert.ErrorIs(t, spikes[0], ErrRateSpike)
+
+	g.MaxStdDevs = decimal.NewFromInt(3)
+	assert.Empty(t, g.Spikes(incoming(1600), tables))
+	// Too short a history is not checked in standard deviations.
+	g.MaxStdDevs, g.MinSamples = decimal.NewFromInt(2), 5
+	assert.Empty(t, g.Spikes(incoming(1600), tables))
+
+	// Rates without variation are only checked against MaxChange, and
+	// currencies without history not at all.
+	g = &SpikeGuard{MaxStdDevs: decimal.NewFromInt(2)}
+	moved := incoming(1580)
+	moved[1].BuyRate = decimal.NewFromInt(1501)
+	moved = append(moved, Currency{ISOCode: "GBP", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)})
+	assert.Empty(t, g.Spikes(moved, tables))
+	assert.Empty(t, g.Spikes(moved, nil))
+}
+
+func TestSpikeGuard_FetchRates(t *testing.T) {
+	now := time.Date(2024, 3, 28, 12, 5, 0, 0, time.UTC)
+	incoming := ngnSnapshot(3000, now).Currencies
+	incoming[0].SellRate = decimal.RequireFromString("0.96")
+
+	var events []Event
+	var reviewed []*SpikeError
+	g := &SpikeGuard{
+		Provider:  staticRates(incoming),
+		History:   smoothingHistory(t),
+		Base:      "usd",
+		MaxChange: decimal.RequireFromString("0.1"),
+		OnSpike:   func(spikes []*SpikeError) { reviewed = append(reviewed, spikes...) },
+		OnEvent:   func(e Event) { events = append(events, e) },
+		Clock:     fixedClock(now),
+	}
+
+	_, err := g.FetchRates(context.Background())
+	assert.ErrorIs(t, err, ErrRateSpike)
+	var spike *SpikeError
+	require.True(t, errors.As(err, &spike))
+	assert.Equal(t, "NGN", spike.ISOCode)
+	require.Len(t, reviewed, 1)
+	assert.Equal(t, []Event{{Type: EventRateRejected, Time: now, Pair: "USD/NGN", Reason: spike.Error()}}, events)
+
+	// Quarantined rates keep their latest history; the others pass.
+	events = nil
+	g.Quarantine = true
+	currencies, err := g.FetchRates(context.Background())
+	require.NoError(t, err)
+	ngn, err := FindCurrency(currencies, "NGN")
+	require.NoError(t, err)
+	assert.Equal(t, "1580", ngn.SellRate.String())
+	eur, err := FindCurrency(currencies, "EUR")
+	require.NoError(t, err)
+	assert.Equal(t, "0.96", eur.SellRate.String())
+	assert.Equal(t, "3000", incoming[1].SellRate.String())
+	require.Len(t, events, 1)
+	assert.Equal(t, EventRateQuarantined, events[0].Type)
+
+	// Without history, rates pass unchecked.
+	g.History = nil
+	currencies, err = g.FetchRates(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, incoming, currencies)
+}
+
+func TestSpikeGuard_Refresher(t *testing.T) {
+	table := NewTable(testCurrencies())
+	history, err := NewRateHistory(time.Hour)
+	require.NoError(t, err)
+	history.Record(table.Snapshot())
+
+	g := &SpikeGuard{
+		Provider:  staticRates(ngnSnapshot(3000, time.Time{}).Currencies),
+		History:   history,
+		MaxChange: decimal.RequireFromString("0.1"),
+	}
+	refresher, err := NewRefresher(table, g, time.Minute)
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, refresher.Refresh(context.Background()), ErrRateSpike)
+	rate, err := table.CalculateRate("USD", "USD", "NGN")
+	require.NoError(t, err)
+	assert.Equal(t, "1550", rate.String())
+}
+
+func staticRates(currencies []Currency) RateProvider {
+	return RateProviderFunc(func(context.Context) ([]Currency, error) {
+		return currencies, nil
+	})
+}
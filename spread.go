@@ -0,0 +1,85 @@
+package converter
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// SpreadPolicy sets the margins a table takes on top of its rates, resolved
+// each time a rate is calculated. A spread is a fraction of the rate, e.g.
+// 0.02 for 2%, by which the rate is lowered in the table's favour.
+type SpreadPolicy struct {
+	// Currencies sets the default spread of every pair involving a
+	// currency, e.g. "NGN". A pair involving two such currencies gets the
+	// wider spread.
+	Currencies map[string]decimal.Decimal
+	// Pairs sets the spread of converting from one currency to another,
+	// e.g. "USD/NGN" for USD to NGN, overriding the currencies' defaults.
+	// Invalid pairs are ignored.
+	Pairs map[string]decimal.Decimal
+	// Widen, if set, returns a spread added to every pair's, e.g. a
+	// VolatilityWidening's Widen to widen volatile pairs dynamically.
+	Widen func(from, to string) decimal.Decimal
+}
+
+// Spread returns the spread of converting from one currency to another: the
+// pair's spread if set, else the wider default of its currencies, else zero,
+// plus Widen's.
+func (p SpreadPolicy) Spread(from, to string) decimal.Decimal {
+	spread := p.spread(from, to)
+	if p.Widen != nil {
+		spread = spread.Add(p.Widen(from, to))
+	}
+	return spread
+}
+
+func (p SpreadPolicy) spread(from, to string) decimal.Decimal {
+	for pair, spread := range p.Pairs {
+		if a, b, err := parsePair(pair); err == nil && strings.EqualFold(a, from) && strings.EqualFold(b, to) {
+			return spread
+		}
+	}
+
+	spread := decimal.Zero
+	for code, s := range p.Currencies {
+		if (strings.EqualFold(code, from) || strings.EqualFold(code, to)) && s.GreaterThan(spread) {
+			spread = s
+		}
+	}
+	return spread
+}
+
+// WithSpreadPolicy makes the table apply the policy's spreads to the rates
+// it calculates and quotes. Quotes record the applied spread.
+func WithSpreadPolicy(p SpreadPolicy) TableOption {
+	return func(c *Currencies) {
+		c.spreadPolicy = &p
+	}
+}
+
+// applySpread lowers rate by spread.
+func applySpread(rate, spread decimal.Decimal) decimal.Decimal {
+	if spread.IsZero() {
+		return rate
+	}
+	return rate.Mul(decimalOne.Sub(spread))
+}
+
+// applySpreadExact lowers an exact rate by spread.
+func applySpreadExact(rate *big.Rat, spread decimal.Decimal) *big.Rat {
+	if spread.IsZero() {
+		return rate
+	}
+	return new(big.Rat).Mul(rate, decimalOne.Sub(spread).Rat())
+}
+
+// spreadFor returns the spread the table applies from one currency to
+// another.
+func (c *Currencies) spreadFor(from, to string) decimal.Decimal {
+	if c.spreadPolicy == nil {
+		return decimal.Zero
+	}
+	return c.spreadPolicy.Spread(from, to)
+}
@@ -0,0 +1,49 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpreadPolicy_Spread(t *testing.T) {
+	p := SpreadPolicy{
+		Currencies: map[string]decimal.Decimal{"ngn": decimal.NewFromFloat(0.01), "EUR": decimal.NewFromFloat(0.005)},
+		Pairs:      map[string]decimal.Decimal{"usd/ngn": decimal.NewFromFloat(0.03), "bad": decimal.NewFromInt(1)},
+	}
+
+	assert.Equal(t, "0.03", p.Spread("USD", "NGN").String())
+	// Pairs are directional; the reverse falls back to the defaults.
+	assert.Equal(t, "0.01", p.Spread("NGN", "USD").String())
+	assert.Equal(t, "0.01", p.Spread("EUR", "NGN").String())
+	assert.Equal(t, "0.005", p.Spread("USD", "EUR").String())
+	assert.True(t, p.Spread("USD", "GBP").IsZero())
+}
+
+func TestWithSpreadPolicy(t *testing.T) {
+	policy := WithSpreadPolicy(SpreadPolicy{
+		Currencies: map[string]decimal.Decimal{"NGN": decimal.NewFromFloat(0.01)},
+		Pairs:      map[string]decimal.Decimal{"USD/NGN": decimal.NewFromFloat(0.02)},
+	})
+
+	for _, table := range []*Currencies{
+		NewTable(testCurrencies(), policy),
+		NewTable(testCurrencies(), policy, WithExactArithmetic()),
+	} {
+		rate, err := table.CalculateRate("USD", "USD", "NGN")
+		assert.NoError(t, err)
+		assert.Equal(t, "1519", rate.String())
+
+		quote, err := table.NewQuote("USD", "USD", "NGN", decimal.NewFromInt(100), decimal.Zero)
+		assert.NoError(t, err)
+		assert.Equal(t, "1519", quote.Rate.String())
+		assert.Equal(t, "0.02", quote.Spread.String())
+		assert.Equal(t, "151900", quote.FinalAmount.String())
+
+		quote, err = table.NewQuote("USD", "USD", "EUR", decimal.NewFromInt(100), decimal.Zero)
+		assert.NoError(t, err)
+		assert.Equal(t, "0.95", quote.Rate.String())
+		assert.True(t, quote.Spread.IsZero())
+	}
+}
@@ -0,0 +1,212 @@
+// Package sqlstore holds the SQL schema used to persist rates, quotes and
+// rate history, and the migrations that create it.
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// lockRetryInterval is how often MigrateContext retries a held lock.
+var lockRetryInterval = 100 * time.Millisecond
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migration is a single schema change.
+type Migration struct {
+	Version int
+	Name    string
+	SQL     string
+}
+
+// Migrations returns the embedded migrations ordered by version.
+func Migrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []Migration
+	for _, entry := range entries {
+		name := entry.Name()
+		prefix, _, ok := strings.Cut(name, "_")
+		if !ok {
+			return nil, fmt.Errorf("sqlstore: migration %s has no version prefix", name)
+		}
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("sqlstore: migration %s has no version prefix", name)
+		}
+
+		b, err := migrationFiles.ReadFile("migrations/" + name)
+		if err != nil {
+			return nil, err
+		}
+
+		migrations = append(migrations, Migration{Version: version, Name: name, SQL: string(b)})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Migrate applies every pending migration to db.
+func Migrate(db *sql.DB) error {
+	return MigrateContext(context.Background(), db)
+}
+
+// MigrateContext applies every pending migration to db. Applied versions are
+// tracked in the schema_migrations table, so it is safe to call on every start.
+//
+// Instances starting together are serialized through a single-row lock in
+// the schema_migrations_lock table: the others wait until ctx is done for the
+// lock to be released. A migrator that crashes while holding the lock leaves
+// the row behind; remove it with Unlock once no migration is running.
+func MigrateContext(ctx context.Context, db *sql.DB) error {
+	migrations, err := Migrations()
+	if err != nil {
+		return err
+	}
+
+	if _, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version    INTEGER   NOT NULL PRIMARY KEY,
+    applied_at TIMESTAMP NOT NULL
+)`); err != nil {
+		return fmt.Errorf("sqlstore: creating schema_migrations: %w", err)
+	}
+
+	if err := lock(ctx, db); err != nil {
+		return err
+	}
+	defer Unlock(context.WithoutCancel(ctx), db)
+
+	// Read after locking, so versions applied by the previous holder count.
+	applied, err := appliedVersions(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if applied[m.Version] {
+			continue
+		}
+		if err := apply(ctx, db, m); err != nil {
+			return fmt.Errorf("sqlstore: applying %s: %w", m.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// Unlock releases the migration lock. MigrateContext releases it itself, so
+// this is only needed after a migrator crashed while holding it.
+func Unlock(ctx context.Context, db *sql.DB) error {
+	if _, err := db.ExecContext(ctx, `DELETE FROM schema_migrations_lock WHERE id = 1`); err != nil {
+		return fmt.Errorf("sqlstore: releasing migration lock: %w", err)
+	}
+	return nil
+}
+
+// lock takes the migration lock, retrying until ctx is done. Inserting the
+// single lock row fails while another instance holds it.
+func lock(ctx context.Context, db *sql.DB) error {
+	if _, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations_lock (
+    id        INTEGER   NOT NULL PRIMARY KEY,
+    locked_at TIMESTAMP NOT NULL
+)`); err != nil {
+		return fmt.Errorf("sqlstore: creating schema_migrations_lock: %w", err)
+	}
+
+	for {
+		_, err := db.ExecContext(ctx, `INSERT INTO schema_migrations_lock (id, locked_at) VALUES (1, CURRENT_TIMESTAMP)`)
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("sqlstore: waiting for migration lock: %w (last error: %v)", ctx.Err(), err)
+		case <-time.After(lockRetryInterval):
+		}
+	}
+}
+
+func appliedVersions(ctx context.Context, db *sql.DB) (map[int]bool, error) {
+	rows, err := db.QueryContext(ctx, `SELECT version FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("sqlstore: reading schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := map[int]bool{}
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		applied[version] = true
+	}
+
+	return applied, rows.Err()
+}
+
+func apply(ctx context.Context, db *sql.DB, m Migration) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range splitStatements(m.SQL) {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+
+	// The version is an integer we control, so it is formatted into the
+	// statement rather than bound, which keeps it placeholder-style agnostic.
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(
+		`INSERT INTO schema_migrations (version, applied_at) VALUES (%d, CURRENT_TIMESTAMP)`, m.Version,
+	)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// splitStatements splits a migration into single statements, since not every
+// driver accepts several statements in one Exec. Comment lines are dropped.
+func splitStatements(script string) []string {
+	var (
+		statements []string
+		current    strings.Builder
+	)
+
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+
+		current.WriteString(line)
+		current.WriteString("\n")
+
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSuffix(strings.TrimSpace(current.String()), ";"))
+			current.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+
+	return statements
+}
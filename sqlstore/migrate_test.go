@@ -0,0 +1,135 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/otyang/converter/internal/sqlfake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrations(t *testing.T) {
+	migrations, err := Migrations()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, migrations)
+
+	for i, m := range migrations {
+		assert.Equal(t, i+1, m.Version)
+		assert.NotEmpty(t, splitStatements(m.SQL))
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	var (
+		executed []string
+		applied  []int
+		all      []int
+	)
+
+	migrations, err := Migrations()
+	assert.NoError(t, err)
+	for _, m := range migrations {
+		all = append(all, m.Version)
+	}
+
+	db := sqlfake.Open(&sqlfake.Handler{
+		Exec: func(query string, _ []driver.Value) error {
+			executed = append(executed, query)
+
+			var version int
+			if _, err := fmt.Sscanf(query, "INSERT INTO schema_migrations (version, applied_at) VALUES (%d,", &version); err == nil {
+				applied = append(applied, version)
+			}
+			return nil
+		},
+		Query: func(string, []driver.Value) ([]string, [][]driver.Value, error) {
+			var rows [][]driver.Value
+			for _, v := range applied {
+				rows = append(rows, []driver.Value{int64(v)})
+			}
+			return []string{"version"}, rows, nil
+		},
+	})
+
+	// First run applies everything under the lock
+	assert.NoError(t, Migrate(db))
+	assert.Equal(t, all, applied)
+	assert.True(t, strings.HasPrefix(executed[2], "INSERT INTO schema_migrations_lock"))
+	assert.True(t, strings.HasPrefix(executed[3], "CREATE TABLE rates"))
+	assert.True(t, strings.HasPrefix(executed[len(executed)-1], "DELETE FROM schema_migrations_lock"))
+
+	// Second run is a no-op apart from the tracking tables and the lock
+	executed = nil
+	assert.NoError(t, Migrate(db))
+	assert.Len(t, executed, 4)
+	assert.Equal(t, all, applied)
+}
+
+func TestMigrate_Locked(t *testing.T) {
+	defer func(d time.Duration) { lockRetryInterval = d }(lockRetryInterval)
+	lockRetryInterval = time.Millisecond
+
+	attempts := 0
+	db := sqlfake.Open(&sqlfake.Handler{
+		Exec: func(query string, _ []driver.Value) error {
+			if strings.HasPrefix(query, "INSERT INTO schema_migrations_lock") {
+				attempts++
+				if attempts < 3 {
+					return errors.New("UNIQUE constraint failed")
+				}
+			}
+			return nil
+		},
+		Query: func(string, []driver.Value) ([]string, [][]driver.Value, error) {
+			return []string{"version"}, nil, nil
+		},
+	})
+
+	// Waits for the lock to be released
+	assert.NoError(t, Migrate(db))
+	assert.Equal(t, 3, attempts)
+
+	// Gives up when the context is done
+	attempts = -1000
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := MigrateContext(ctx, db)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestMigrate_Error(t *testing.T) {
+	db := sqlfake.Open(&sqlfake.Handler{
+		Exec: func(query string, _ []driver.Value) error {
+			if strings.HasPrefix(query, "CREATE TABLE quotes") {
+				return errors.New("boom")
+			}
+			return nil
+		},
+		Query: func(string, []driver.Value) ([]string, [][]driver.Value, error) {
+			return []string{"version"}, nil, nil
+		},
+	})
+
+	err := Migrate(db)
+	assert.ErrorContains(t, err, "0002_create_quotes.sql")
+}
+
+func TestSplitStatements(t *testing.T) {
+	statements := splitStatements(`
+-- comment
+CREATE TABLE a (id INTEGER);
+
+CREATE INDEX a_idx ON a (id);
+SELECT 1`)
+
+	assert.Equal(t, []string{
+		"CREATE TABLE a (id INTEGER)",
+		"CREATE INDEX a_idx ON a (id)",
+		"SELECT 1",
+	}, statements)
+}
@@ -0,0 +1,8 @@
+-- Current rate table, one row per currency.
+CREATE TABLE rates (
+    iso_code       VARCHAR(16) NOT NULL PRIMARY KEY,
+    rate_precision INTEGER     NOT NULL,
+    buy_rate       TEXT        NOT NULL,
+    sell_rate      TEXT        NOT NULL,
+    updated_at     TIMESTAMP   NOT NULL
+);
@@ -0,0 +1,17 @@
+-- Issued quotes. Amounts and rates are stored as decimal strings.
+CREATE TABLE quotes (
+    id               VARCHAR(64) NOT NULL PRIMARY KEY,
+    reference        VARCHAR(16) NOT NULL,
+    base_currency    VARCHAR(16) NOT NULL,
+    from_currency    VARCHAR(16) NOT NULL,
+    to_currency      VARCHAR(16) NOT NULL,
+    from_amount      TEXT        NOT NULL,
+    fee              TEXT        NOT NULL,
+    amount_to_deduct TEXT        NOT NULL,
+    rate             TEXT        NOT NULL,
+    final_amount     TEXT        NOT NULL,
+    created_at       TIMESTAMP   NOT NULL
+);
+
+CREATE INDEX quotes_pair_idx ON quotes (from_currency, to_currency);
+CREATE INDEX quotes_created_at_idx ON quotes (created_at);
@@ -0,0 +1,9 @@
+-- Every published rate, kept for history and audit queries.
+CREATE TABLE rate_history (
+    iso_code       VARCHAR(16) NOT NULL,
+    recorded_at    TIMESTAMP   NOT NULL,
+    rate_precision INTEGER     NOT NULL,
+    buy_rate       TEXT        NOT NULL,
+    sell_rate      TEXT        NOT NULL,
+    PRIMARY KEY (iso_code, recorded_at)
+);
@@ -0,0 +1,6 @@
+-- Quote lifecycle status and the caller's customer reference.
+ALTER TABLE quotes ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'pending';
+ALTER TABLE quotes ADD COLUMN customer_ref VARCHAR(64) NOT NULL DEFAULT '';
+
+CREATE INDEX quotes_status_idx ON quotes (status);
+CREATE INDEX quotes_customer_ref_idx ON quotes (customer_ref);
@@ -0,0 +1,4 @@
+-- Quote expiry, scanned by the expiry sweeper.
+ALTER TABLE quotes ADD COLUMN expires_at TIMESTAMP NULL;
+
+CREATE INDEX quotes_expires_at_idx ON quotes (status, expires_at);
@@ -0,0 +1,56 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	_ "modernc.org/sqlite"
+)
+
+// TestMigrate_SQLite runs the migrations against a real SQLite engine, so the
+// DDL itself is exercised and not only the migration bookkeeping.
+func TestMigrate_SQLite(t *testing.T) {
+	dsn := "file:" + filepath.Join(t.TempDir(), "converter.db") + "?_pragma=busy_timeout(5000)"
+	db, err := sql.Open("sqlite", dsn)
+	assert.NoError(t, err)
+	defer db.Close()
+
+	// Concurrent instances serialize on the lock
+	var wg sync.WaitGroup
+	errs := make([]error, 4)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = Migrate(db)
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+
+	migrations, err := Migrations()
+	assert.NoError(t, err)
+	var applied int
+	assert.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM schema_migrations`).Scan(&applied))
+	assert.Equal(t, len(migrations), applied)
+
+	// The schema accepts a quote with the columns added by later migrations
+	_, err = db.Exec(`INSERT INTO quotes (id, reference, base_currency, from_currency, to_currency,
+    from_amount, fee, amount_to_deduct, rate, final_amount, created_at, status, customer_ref, expires_at)
+VALUES ('q1', 'ABCD-EFGH', 'USD', 'USD', 'NGN', '100', '1', '101', '1500', '150000', CURRENT_TIMESTAMP, 'pending', 'c1', NULL)`)
+	assert.NoError(t, err)
+
+	var status string
+	assert.NoError(t, db.QueryRow(`SELECT status FROM quotes WHERE id = 'q1'`).Scan(&status))
+	assert.Equal(t, "pending", status)
+
+	// The lock was released
+	var locks int
+	assert.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM schema_migrations_lock`).Scan(&locks))
+	assert.Zero(t, locks)
+}
@@ -0,0 +1,240 @@
+package converter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Rate history errors
+var (
+	ErrInvalidHistoryWindow = errors.New("history window must be positive")
+	ErrNoRateSamples        = errors.New("no rate samples")
+)
+
+// RateHistory keeps the tables seen over a rolling window, so statistics of
+// pair rates can be computed for product UIs: how far a rate moved, how
+// much it jitters, and sparkline data to draw it.
+type RateHistory struct {
+	Window time.Duration
+	// Clock defaults to SystemClock.
+	Clock Clock
+
+	mu        sync.Mutex
+	snapshots []*Snapshot
+}
+
+// NewRateHistory creates a history keeping the tables of the last window.
+// It returns ErrInvalidHistoryWindow if window is not positive.
+func NewRateHistory(window time.Duration) (*RateHistory, error) {
+	if window <= 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidHistoryWindow, window)
+	}
+	return &RateHistory{Window: window, Clock: SystemClock}, nil
+}
+
+// Record adds a table to the history, in order of the time it was taken,
+// and drops the tables taken before the window. The latest table is always
+// kept, as its rates still hold.
+func (h *RateHistory) Record(s *Snapshot) {
+	if s == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	i := sort.Search(len(h.snapshots), func(i int) bool { return h.snapshots[i].TakenAt.After(s.TakenAt) })
+	h.snapshots = append(h.snapshots, nil)
+	copy(h.snapshots[i+1:], h.snapshots[i:])
+	h.snapshots[i] = s
+	h.prune()
+}
+
+// prune drops the tables taken before the window but the latest one.
+func (h *RateHistory) prune() {
+	clock := h.Clock
+	if clock == nil {
+		clock = SystemClock
+	}
+	cutoff := clock.Now().Add(-h.Window)
+	n := sort.Search(len(h.snapshots), func(i int) bool { return !h.snapshots[i].TakenAt.Before(cutoff) })
+	n = min(n, len(h.snapshots)-1)
+	if n > 0 {
+		h.snapshots = append(h.snapshots[:0:0], h.snapshots[n:]...)
+	}
+}
+
+// Follow records the table's current contents and every update of it until
+// ctx is cancelled.
+func (h *RateHistory) Follow(ctx context.Context, table *Currencies) {
+	updates := table.Subscribe(ctx)
+	if table.Len() > 0 {
+		h.Record(table.Snapshot())
+	}
+	for s := range updates {
+		h.Record(s)
+	}
+}
+
+// Len returns the number of tables held.
+func (h *RateHistory) Len() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.prune()
+	return len(h.snapshots)
+}
+
+// SaveBlob stores the tables in the window as JSON under key in blobs, so
+// an instance without local disk can restore its history with LoadBlob.
+func (h *RateHistory) SaveBlob(ctx context.Context, blobs BlobStore, key string) error {
+	b, err := json.Marshal(h.tables())
+	if err != nil {
+		return err
+	}
+	return blobs.Put(ctx, key, b)
+}
+
+// LoadBlob records the tables stored under key in blobs by SaveBlob,
+// dropping those now outside the window. It returns ErrBlobNotFound if
+// nothing was stored.
+func (h *RateHistory) LoadBlob(ctx context.Context, blobs BlobStore, key string) error {
+	b, err := blobs.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	var snapshots []*Snapshot
+	if err := json.Unmarshal(b, &snapshots); err != nil {
+		return err
+	}
+	for _, s := range snapshots {
+		h.Record(s)
+	}
+	return nil
+}
+
+// RateStats are statistics of a pair's rate over a RateHistory's window.
+type RateStats struct {
+	Base string `json:"base"`
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Since and Until are the times of the first and last samples.
+	Since   time.Time       `json:"since"`
+	Until   time.Time       `json:"until"`
+	Samples int             `json:"samples"`
+	First   decimal.Decimal `json:"first"`
+	Last    decimal.Decimal `json:"last"`
+	Min     decimal.Decimal `json:"min"`
+	Max     decimal.Decimal `json:"max"`
+	// Mean and StdDev are the mean and population standard deviation of
+	// the samples, each sample weighing the same however long it held.
+	Mean   decimal.Decimal `json:"mean"`
+	StdDev decimal.Decimal `json:"stdDev"`
+	// Change is the percent movement from First to Last, or nil if First
+	// is zero.
+	Change *decimal.Decimal `json:"change"`
+	// Sparkline splits Since to Until into equal buckets and holds the rate
+	// in effect at the end of each, oldest first.
+	Sparkline []decimal.Decimal `json:"sparkline"`
+}
+
+// Stats computes statistics of the rate from one currency to another over
+// the window, priced against base as the CalculateRate function does, with
+// a sparkline of buckets points. Tables the pair cannot be priced in are
+// skipped; ErrNoRateSamples is returned if no table can price it.
+func (h *RateHistory) Stats(base, from, to string, buckets int) (*RateStats, error) {
+	s := &RateStats{Base: strings.ToUpper(base), From: strings.ToUpper(from), To: strings.ToUpper(to)}
+	times, rates := h.pairSamples(base, from, to)
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("%w: %s/%s", ErrNoRateSamples, s.From, s.To)
+	}
+
+	s.Since, s.Until = times[0], times[len(times)-1]
+	s.Samples = len(rates)
+	s.First, s.Last = rates[0], rates[len(rates)-1]
+	s.Min, s.Max = decimal.Min(rates[0], rates[1:]...), decimal.Max(rates[0], rates[1:]...)
+	s.Mean, s.StdDev = meanStdDev(rates)
+	s.Change = percentChange(s.First, s.Last)
+	s.Sparkline = sparkline(times, rates, buckets)
+	return s, nil
+}
+
+// tables returns the tables in the window, oldest first.
+func (h *RateHistory) tables() []*Snapshot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.prune()
+	return append([]*Snapshot(nil), h.snapshots...)
+}
+
+// pairSamples returns the times and rates of the tables in the window that
+// can price the pair.
+func (h *RateHistory) pairSamples(base, from, to string) ([]time.Time, []decimal.Decimal) {
+	var times []time.Time
+	var rates []decimal.Decimal
+	for _, snapshot := range h.tables() {
+		rate, err := CalculateRate(snapshot.Currencies, base, from, to)
+		if err != nil {
+			continue
+		}
+		times = append(times, snapshot.TakenAt)
+		rates = append(rates, rate)
+	}
+	return times, rates
+}
+
+// currencySamples returns the buy and sell rates of a currency in tables,
+// oldest first, skipping the tables without it.
+func currencySamples(tables []*Snapshot, code string) (buys, sells []decimal.Decimal) {
+	for _, table := range tables {
+		if found, err := FindCurrency(table.Currencies, code); err == nil {
+			buys = append(buys, found.BuyRate)
+			sells = append(sells, found.SellRate)
+		}
+	}
+	return buys, sells
+}
+
+// meanStdDev returns the mean and population standard deviation of rates,
+// which must not be empty.
+func meanStdDev(rates []decimal.Decimal) (mean, stdDev decimal.Decimal) {
+	n := decimal.NewFromInt(int64(len(rates)))
+	mean = decimal.Sum(rates[0], rates[1:]...).Div(n)
+	variance := decimal.Zero
+	for _, r := range rates {
+		d := r.Sub(mean)
+		variance = variance.Add(d.Mul(d))
+	}
+	return mean, decimal.NewFromFloat(math.Sqrt(variance.Div(n).InexactFloat64()))
+}
+
+// sparkline samples the step function of rates over time at the end of
+// each of buckets equal spans from the first time to the last.
+func sparkline(times []time.Time, rates []decimal.Decimal, buckets int) []decimal.Decimal {
+	if buckets <= 0 {
+		return nil
+	}
+	span := times[len(times)-1].Sub(times[0])
+	points := make([]decimal.Decimal, buckets)
+	j := 0
+	for i := range points {
+		end := times[len(times)-1]
+		if i < buckets-1 {
+			end = times[0].Add(time.Duration(float64(span) * float64(i+1) / float64(buckets)))
+		}
+		for j+1 < len(times) && !times[j+1].After(end) {
+			j++
+		}
+		points[i] = rates[j]
+	}
+	return points
+}
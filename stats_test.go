@@ -0,0 +1,145 @@
+package converter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ngnSnapshot(sell int64, at time.Time) *Snapshot {
+	currencies := testCurrencies()
+	currencies[2].SellRate = decimal.NewFromInt(sell)
+	return NewSnapshot(currencies, at)
+}
+
+func TestNewRateHistory(t *testing.T) {
+	_, err := NewRateHistory(0)
+	assert.ErrorIs(t, err, ErrInvalidHistoryWindow)
+
+	h, err := NewRateHistory(time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, h.Window)
+	assert.Equal(t, SystemClock, h.Clock)
+}
+
+func TestRateHistory_Stats(t *testing.T) {
+	start := time.Date(2024, 3, 28, 12, 0, 0, 0, time.UTC)
+	h, err := NewRateHistory(time.Hour)
+	require.NoError(t, err)
+	h.Clock = fixedClock(start.Add(5 * time.Minute))
+
+	// Recorded out of order; the history keeps them by time.
+	h.Record(ngnSnapshot(1580, start.Add(4*time.Minute)))
+	h.Record(ngnSnapshot(1550, start))
+	h.Record(ngnSnapshot(1540, start.Add(2*time.Minute)))
+	h.Record(ngnSnapshot(1560, start.Add(time.Minute)))
+	h.Record(nil)
+	assert.Equal(t, 4, h.Len())
+
+	s, err := h.Stats("usd", "usd", "ngn", 4)
+	require.NoError(t, err)
+	assert.Equal(t, "USD", s.Base)
+	assert.Equal(t, "USD", s.From)
+	assert.Equal(t, "NGN", s.To)
+	assert.Equal(t, start, s.Since)
+	assert.Equal(t, start.Add(4*time.Minute), s.Until)
+	assert.Equal(t, 4, s.Samples)
+	assert.Equal(t, "1550", s.First.String())
+	assert.Equal(t, "1580", s.Last.String())
+	assert.Equal(t, "1540", s.Min.String())
+	assert.Equal(t, "1580", s.Max.String())
+	assert.Equal(t, "1557.5", s.Mean.String())
+	assert.Equal(t, "14.7902", s.StdDev.StringFixed(4))
+	require.NotNil(t, s.Change)
+	assert.Equal(t, "1.9355", s.Change.String())
+	assert.Equal(t, []string{"1560", "1540", "1540", "1580"}, decimalStrings(s.Sparkline))
+
+	s, err = h.Stats("USD", "USD", "NGN", 0)
+	require.NoError(t, err)
+	assert.Nil(t, s.Sparkline)
+
+	_, err = h.Stats("USD", "USD", "GBP", 4)
+	assert.ErrorIs(t, err, ErrNoRateSamples)
+}
+
+func TestRateHistory_Window(t *testing.T) {
+	start := time.Date(2024, 3, 28, 12, 0, 0, 0, time.UTC)
+	h, err := NewRateHistory(time.Minute)
+	require.NoError(t, err)
+	h.Clock = fixedClock(start)
+
+	h.Record(ngnSnapshot(1550, start.Add(-3*time.Minute)))
+	h.Record(ngnSnapshot(1560, start.Add(-2*time.Minute)))
+	// The latest table is kept, however old.
+	assert.Equal(t, 1, h.Len())
+
+	h.Record(ngnSnapshot(1570, start.Add(-30*time.Second)))
+	assert.Equal(t, 1, h.Len())
+
+	h.Clock = fixedClock(start.Add(time.Hour))
+	s, err := h.Stats("USD", "USD", "NGN", 3)
+	require.NoError(t, err)
+	assert.Equal(t, 1, s.Samples)
+	assert.Equal(t, "0", s.StdDev.String())
+	assert.Equal(t, []string{"1570", "1570", "1570"}, decimalStrings(s.Sparkline))
+}
+
+func TestRateHistory_SaveLoadBlob(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 3, 28, 12, 0, 0, 0, time.UTC)
+	blobs := NewMemoryBlobStore()
+
+	h, err := NewRateHistory(time.Hour)
+	require.NoError(t, err)
+	h.Clock = fixedClock(start)
+	assert.ErrorIs(t, h.LoadBlob(ctx, blobs, "history.json"), ErrBlobNotFound)
+
+	h.Record(ngnSnapshot(1550, start.Add(-30*time.Minute)))
+	h.Record(ngnSnapshot(1560, start.Add(-10*time.Minute)))
+	require.NoError(t, h.SaveBlob(ctx, blobs, "history.json"))
+
+	// Tables that left the window since are dropped
+	restored, err := NewRateHistory(time.Hour)
+	require.NoError(t, err)
+	restored.Clock = fixedClock(start.Add(40 * time.Minute))
+	require.NoError(t, restored.LoadBlob(ctx, blobs, "history.json"))
+	assert.Equal(t, 1, restored.Len())
+	s, err := restored.Stats("USD", "USD", "NGN", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "1560", s.Last.String())
+}
+
+func TestRateHistory_Follow(t *testing.T) {
+	table := NewTable(testCurrencies())
+	h, err := NewRateHistory(time.Hour)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		h.Follow(ctx, table)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return h.Len() == 1 }, time.Second, time.Millisecond)
+	table.Update(ngnSnapshot(1600, time.Now()).Currencies, time.Now())
+	assert.Eventually(t, func() bool { return h.Len() == 2 }, time.Second, time.Millisecond)
+
+	cancel()
+	<-done
+	s, err := h.Stats("USD", "USD", "NGN", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "1600", s.Last.String())
+}
+
+func decimalStrings(values []decimal.Decimal) []string {
+	s := make([]string, len(values))
+	for i, v := range values {
+		s[i] = v.String()
+	}
+	return s
+}
@@ -0,0 +1,135 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Background job errors
+var (
+	ErrInvalidInterval = errors.New("interval must be positive")
+)
+
+// Sweeper periodically marks expired pending quotes in a QuoteStore as
+// expired, so they can no longer be executed.
+type Sweeper struct {
+	Store    QuoteStore
+	Interval time.Duration
+	// Clock defaults to SystemClock.
+	Clock Clock
+	// OnEvent, if set, receives an EventQuoteExpired per expired quote.
+	OnEvent EventHandler
+
+	mu    sync.Mutex
+	stats SweeperStats
+}
+
+// SweeperStats are the counters exposed by a Sweeper.
+type SweeperStats struct {
+	Runs    int64     `json:"runs"`
+	Scanned int64     `json:"scanned"`
+	Expired int64     `json:"expired"`
+	Errors  int64     `json:"errors"`
+	LastRun time.Time `json:"lastRun"`
+}
+
+// NewSweeper creates a sweeper that scans store every interval. It returns
+// ErrInvalidInterval if interval is not positive.
+func NewSweeper(store QuoteStore, interval time.Duration) (*Sweeper, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidInterval, interval)
+	}
+	return &Sweeper{
+		Store:    store,
+		Interval: interval,
+		Clock:    SystemClock,
+	}, nil
+}
+
+// Run sweeps every interval until ctx is cancelled. It returns
+// ErrInvalidInterval straight away if Interval is not positive.
+func (s *Sweeper) Run(ctx context.Context) error {
+	if s.Interval <= 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidInterval, s.Interval)
+	}
+
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		// Errors are counted in Stats; the next tick retries.
+		_, _ = s.Sweep(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sweep runs a single pass and returns the number of quotes it expired.
+func (s *Sweeper) Sweep(ctx context.Context) (int, error) {
+	now := s.clock().Now()
+
+	page, err := s.Store.List(ctx, QuoteFilter{
+		Statuses:  []QuoteStatus{QuoteStatusPending},
+		ExpiredAt: now,
+	})
+	if err != nil {
+		s.record(now, 0, 0, err)
+		return 0, err
+	}
+
+	expired := 0
+	for _, q := range page.Quotes {
+		err := s.Store.UpdateStatus(ctx, q.ID, QuoteStatusPending, QuoteStatusExpired)
+		if errors.Is(err, ErrQuoteStatusChanged) {
+			// Accepted or cancelled since it was listed; leave it alone.
+			continue
+		}
+		if err != nil {
+			s.record(now, len(page.Quotes), expired, err)
+			return expired, err
+		}
+		expired++
+
+		if s.OnEvent != nil {
+			s.OnEvent(Event{Type: EventQuoteExpired, Time: now, QuoteID: q.ID, Actor: ActorFromContext(ctx)})
+		}
+	}
+
+	s.record(now, len(page.Quotes), expired, nil)
+	return expired, nil
+}
+
+// Stats returns the sweeper's counters.
+func (s *Sweeper) Stats() SweeperStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.stats
+}
+
+func (s *Sweeper) record(at time.Time, scanned, expired int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stats.Runs++
+	s.stats.Scanned += int64(scanned)
+	s.stats.Expired += int64(expired)
+	if err != nil {
+		s.stats.Errors++
+	}
+	s.stats.LastRun = at
+}
+
+func (s *Sweeper) clock() Clock {
+	if s.Clock == nil {
+		return SystemClock
+	}
+	return s.Clock
+}
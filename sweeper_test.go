@@ -0,0 +1,136 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+func TestSweeper_Sweep(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		now   = time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+		store = NewMemoryQuoteStore()
+	)
+
+	for _, q := range []*Quote{
+		{ID: "expired", Date: now.Add(-time.Hour), ExpiresAt: now.Add(-time.Minute), Status: QuoteStatusPending},
+		{ID: "valid", Date: now.Add(-time.Hour), ExpiresAt: now.Add(time.Minute), Status: QuoteStatusPending},
+		{ID: "no-ttl", Date: now.Add(-time.Hour), Status: QuoteStatusPending},
+		{ID: "executed", Date: now.Add(-time.Hour), ExpiresAt: now.Add(-time.Minute), Status: QuoteStatusExecuted},
+	} {
+		assert.NoError(t, store.Save(ctx, q))
+	}
+
+	var events []Event
+	sweeper, err := NewSweeper(store, time.Minute)
+	assert.NoError(t, err)
+	sweeper.Clock = fixedClock(now)
+	sweeper.OnEvent = func(e Event) { events = append(events, e) }
+
+	n, err := sweeper.Sweep(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	q, _ := store.Get(ctx, "expired")
+	assert.Equal(t, QuoteStatusExpired, q.Status)
+	for _, id := range []string{"valid", "no-ttl"} {
+		q, _ := store.Get(ctx, id)
+		assert.Equal(t, QuoteStatusPending, q.Status)
+	}
+	q, _ = store.Get(ctx, "executed")
+	assert.Equal(t, QuoteStatusExecuted, q.Status)
+
+	assert.Equal(t, []Event{{Type: EventQuoteExpired, Time: now, QuoteID: "expired"}}, events)
+
+	// Second pass finds nothing new
+	n, err = sweeper.Sweep(ctx)
+	assert.NoError(t, err)
+	assert.Zero(t, n)
+
+	stats := sweeper.Stats()
+	assert.Equal(t, int64(2), stats.Runs)
+	assert.Equal(t, int64(1), stats.Expired)
+	assert.Equal(t, int64(1), stats.Scanned)
+	assert.Equal(t, now, stats.LastRun)
+}
+
+type failingQuoteStore struct{ QuoteStore }
+
+func (failingQuoteStore) List(context.Context, QuoteFilter) (*QuotePage, error) {
+	return nil, errors.New("store down")
+}
+
+// racingQuoteStore accepts every quote just before the sweeper updates it.
+type racingQuoteStore struct{ *MemoryQuoteStore }
+
+func (s racingQuoteStore) UpdateStatus(ctx context.Context, id string, from, to QuoteStatus) error {
+	if err := s.MemoryQuoteStore.UpdateStatus(ctx, id, QuoteStatusPending, QuoteStatusAccepted); err != nil {
+		return err
+	}
+	return s.MemoryQuoteStore.UpdateStatus(ctx, id, from, to)
+}
+
+func TestSweeper_Errors(t *testing.T) {
+	sweeper, err := NewSweeper(failingQuoteStore{}, time.Minute)
+	assert.NoError(t, err)
+
+	_, err = sweeper.Sweep(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, int64(1), sweeper.Stats().Errors)
+
+	// Non-positive intervals would make the ticker panic
+	_, err = NewSweeper(NewMemoryQuoteStore(), 0)
+	assert.ErrorIs(t, err, ErrInvalidInterval)
+	sweeper.Interval = -time.Second
+	assert.ErrorIs(t, sweeper.Run(context.Background()), ErrInvalidInterval)
+}
+
+func TestSweeper_ConcurrentTransition(t *testing.T) {
+	ctx := context.Background()
+	store := racingQuoteStore{NewMemoryQuoteStore()}
+	assert.NoError(t, store.Save(ctx, &Quote{ID: "q1", ExpiresAt: time.Now().Add(-time.Second), Status: QuoteStatusPending}))
+
+	sweeper, err := NewSweeper(store, time.Minute)
+	assert.NoError(t, err)
+
+	// The accepted quote is not overwritten with expired
+	n, err := sweeper.Sweep(ctx)
+	assert.NoError(t, err)
+	assert.Zero(t, n)
+	q, _ := store.Get(ctx, "q1")
+	assert.Equal(t, QuoteStatusAccepted, q.Status)
+}
+
+func TestSweeper_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	store := NewMemoryQuoteStore()
+	assert.NoError(t, store.Save(ctx, &Quote{ID: "q1", ExpiresAt: time.Now().Add(-time.Second), Status: QuoteStatusPending}))
+
+	sweeper, err := NewSweeper(store, time.Millisecond)
+	assert.NoError(t, err)
+	done := make(chan error)
+	go func() { done <- sweeper.Run(ctx) }()
+
+	assert.Eventually(t, func() bool {
+		q, _ := store.Get(context.Background(), "q1")
+		return q.Status == QuoteStatusExpired
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestQuote_IsExpired(t *testing.T) {
+	now := time.Now()
+	assert.False(t, (&Quote{}).IsExpired(now))
+	assert.False(t, (&Quote{ExpiresAt: now.Add(time.Second)}).IsExpired(now))
+	assert.True(t, (&Quote{ExpiresAt: now}).IsExpired(now))
+}
@@ -0,0 +1,166 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidSynthetic is returned for a synthetic currency that cannot be
+// generated, e.g. one without a starting rate.
+var ErrInvalidSynthetic = errors.New("invalid synthetic currency")
+
+// syntheticDigits is the number of significant digits generated rates are
+// rounded to.
+const syntheticDigits = 8
+
+// SyntheticCurrency configures the random walk of a currency's rates.
+type SyntheticCurrency struct {
+	ISOCode   string
+	Precision int
+	// Start is the mid rate the walk starts from, in units per base unit.
+	Start decimal.Decimal
+	// Drift is the expected relative move of the mid rate per step, e.g.
+	// 0.0001 for a currency weakening 0.01% a step.
+	Drift float64
+	// Volatility is the standard deviation of the relative move per step,
+	// e.g. 0.002 for 0.2%.
+	Volatility float64
+	// Spread is the relative difference between the sell and buy rates,
+	// centred on the mid, e.g. 0.01 for 1%.
+	Spread float64
+}
+
+// validate checks the currency can be generated against base.
+func (s SyntheticCurrency) validate(base string) error {
+	switch {
+	case s.ISOCode == "":
+		return fmt.Errorf("%w: no ISO code", ErrInvalidSynthetic)
+	case strings.EqualFold(s.ISOCode, base):
+		return fmt.Errorf("%w: %s is the base currency", ErrInvalidSynthetic, s.ISOCode)
+	case s.Precision < 0 || s.Precision > maxDigits:
+		return fmt.Errorf("%w: %s has precision %d", ErrInvalidSynthetic, s.ISOCode, s.Precision)
+	case !s.Start.IsPositive():
+		return fmt.Errorf("%w: %s has no starting rate", ErrInvalidSynthetic, s.ISOCode)
+	case s.Volatility < 0 || math.IsNaN(s.Volatility) || math.IsInf(s.Volatility, 0) || math.IsNaN(s.Drift) || math.IsInf(s.Drift, 0):
+		return fmt.Errorf("%w: %s has drift %v and volatility %v", ErrInvalidSynthetic, s.ISOCode, s.Drift, s.Volatility)
+	case s.Spread < 0 || s.Spread >= 2 || math.IsNaN(s.Spread):
+		return fmt.Errorf("%w: %s has spread %v", ErrInvalidSynthetic, s.ISOCode, s.Spread)
+	}
+	return nil
+}
+
+// SyntheticRates generates realistic rates without a provider: each
+// currency's mid rate follows a geometric random walk with its drift and
+// volatility, and is quoted with its spread around the mid. It is a
+// RateProvider advancing one step per fetch, for demos, load tests and
+// developer environments. It is safe for concurrent use.
+type SyntheticRates struct {
+	base       string
+	currencies []SyntheticCurrency
+
+	mu   sync.Mutex
+	rand *rand.Rand
+	mids []float64
+}
+
+var _ RateProvider = (*SyntheticRates)(nil)
+
+// NewSyntheticRates creates a generator of currencies' rates against base,
+// which is quoted at 1. Generators with the same seed and currencies
+// produce the same series.
+func NewSyntheticRates(base string, seed int64, currencies ...SyntheticCurrency) (*SyntheticRates, error) {
+	if base == "" {
+		return nil, ErrBaseCurrencyNotFound
+	}
+	seen := map[string]bool{}
+	mids := make([]float64, len(currencies))
+	for i, c := range currencies {
+		if err := c.validate(base); err != nil {
+			return nil, err
+		}
+		code := strings.ToUpper(c.ISOCode)
+		if seen[code] {
+			return nil, fmt.Errorf("%w: %s is repeated", ErrInvalidSynthetic, code)
+		}
+		seen[code] = true
+		mids[i] = c.Start.InexactFloat64()
+	}
+	return &SyntheticRates{
+		base:       strings.ToUpper(base),
+		currencies: append([]SyntheticCurrency(nil), currencies...),
+		rand:       rand.New(rand.NewSource(seed)),
+		mids:       mids,
+	}, nil
+}
+
+// Current returns the rates of the current step, without advancing.
+func (g *SyntheticRates) Current() []Currency {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.rates()
+}
+
+// Next advances the walk one step and returns its rates.
+func (g *SyntheticRates) Next() []Currency {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for i, c := range g.currencies {
+		// The mean of a lognormal step is exp(drift), so the walk drifts
+		// by Drift a step whatever its volatility.
+		step := c.Drift - c.Volatility*c.Volatility/2 + c.Volatility*g.rand.NormFloat64()
+		// A walk that would leave the representable range stays put.
+		if mid := g.mids[i] * math.Exp(step); mid > 0 && !math.IsInf(mid, 0) {
+			g.mids[i] = mid
+		}
+	}
+	return g.rates()
+}
+
+// Series advances the walk n steps and returns the rates of each.
+func (g *SyntheticRates) Series(n int) [][]Currency {
+	series := make([][]Currency, 0, max(n, 0))
+	for i := 0; i < n; i++ {
+		series = append(series, g.Next())
+	}
+	return series
+}
+
+// FetchRates implements RateProvider, advancing the walk one step.
+func (g *SyntheticRates) FetchRates(ctx context.Context) ([]Currency, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return g.Next(), nil
+}
+
+// rates returns the current step's rates, base first. The caller holds
+// g.mu.
+func (g *SyntheticRates) rates() []Currency {
+	one := decimal.NewFromInt(1)
+	currencies := []Currency{{ISOCode: g.base, Precision: 2, BuyRate: one, SellRate: one}}
+	for i, c := range g.currencies {
+		mid := g.mids[i]
+		currencies = append(currencies, Currency{
+			ISOCode:   strings.ToUpper(c.ISOCode),
+			Precision: c.Precision,
+			BuyRate:   syntheticRate(mid * (1 - c.Spread/2)),
+			SellRate:  syntheticRate(mid * (1 + c.Spread/2)),
+		})
+	}
+	return currencies
+}
+
+// syntheticRate rounds a generated rate to syntheticDigits significant
+// digits.
+func syntheticRate(x float64) decimal.Decimal {
+	return decimal.RequireFromString(strconv.FormatFloat(x, 'g', syntheticDigits, 64))
+}
@@ -0,0 +1,90 @@
+package converter
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func syntheticNGN() SyntheticCurrency {
+	return SyntheticCurrency{
+		ISOCode:    "NGN",
+		Precision:  2,
+		Start:      decimal.NewFromInt(1500),
+		Volatility: 0.002,
+		Spread:     0.02,
+	}
+}
+
+func TestSyntheticRates(t *testing.T) {
+	eur := SyntheticCurrency{ISOCode: "eur", Precision: 2, Start: decimal.NewFromFloat(0.92), Volatility: 0.001, Spread: 0.004}
+	g, err := NewSyntheticRates("usd", 42, syntheticNGN(), eur)
+	require.NoError(t, err)
+
+	start := g.Current()
+	require.Len(t, start, 3)
+	assert.Equal(t, "USD", start[0].ISOCode)
+	assert.Equal(t, "1485", start[1].BuyRate.String())
+	assert.Equal(t, "1515", start[1].SellRate.String())
+	assert.Equal(t, "EUR", start[2].ISOCode)
+
+	series := g.Series(100)
+	require.Len(t, series, 100)
+	for _, step := range series {
+		for _, c := range step {
+			assert.NoError(t, c.Validate())
+			assert.True(t, c.SellRate.GreaterThanOrEqual(c.BuyRate), c.ISOCode)
+		}
+		_, err := CalculateRate(step, "USD", "EUR", "NGN")
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, series[99], g.Current())
+	assert.NotEqual(t, series[0][1].BuyRate.String(), series[1][1].BuyRate.String())
+
+	// The same seed replays the same series.
+	replay, err := NewSyntheticRates("USD", 42, syntheticNGN(), eur)
+	require.NoError(t, err)
+	assert.Equal(t, series, replay.Series(100))
+
+	rates, err := replay.FetchRates(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, g.Next(), rates)
+}
+
+func TestSyntheticRates_Drift(t *testing.T) {
+	// Without volatility the walk moves by exactly its drift.
+	ngn := syntheticNGN()
+	ngn.Volatility, ngn.Spread, ngn.Drift = 0, 0, 0.01
+	g, err := NewSyntheticRates("USD", 1, ngn)
+	require.NoError(t, err)
+
+	series := g.Series(10)
+	assert.InDelta(t, 1500*math.Exp(0.1), series[9][1].BuyRate.InexactFloat64(), 0.001)
+	assert.True(t, series[9][1].BuyRate.Equal(series[9][1].SellRate))
+}
+
+func TestNewSyntheticRates_Invalid(t *testing.T) {
+	for name, mutate := range map[string]func(*SyntheticCurrency){
+		"no code":             func(c *SyntheticCurrency) { c.ISOCode = "" },
+		"base":                func(c *SyntheticCurrency) { c.ISOCode = "usd" },
+		"no start":            func(c *SyntheticCurrency) { c.Start = decimal.Zero },
+		"negative volatility": func(c *SyntheticCurrency) { c.Volatility = -0.1 },
+		"nan drift":           func(c *SyntheticCurrency) { c.Drift = math.NaN() },
+		"spread":              func(c *SyntheticCurrency) { c.Spread = 2 },
+		"precision":           func(c *SyntheticCurrency) { c.Precision = -1 },
+	} {
+		c := syntheticNGN()
+		mutate(&c)
+		_, err := NewSyntheticRates("USD", 1, c)
+		assert.ErrorIs(t, err, ErrInvalidSynthetic, name)
+	}
+
+	_, err := NewSyntheticRates("USD", 1, syntheticNGN(), syntheticNGN())
+	assert.ErrorIs(t, err, ErrInvalidSynthetic)
+	_, err = NewSyntheticRates("", 1, syntheticNGN())
+	assert.ErrorIs(t, err, ErrBaseCurrencyNotFound)
+}
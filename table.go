@@ -0,0 +1,416 @@
+package converter
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Currencies is a live rate table. It is safe for concurrent use, so quotes
+// can be priced while a Refresher replaces its contents.
+type Currencies struct {
+	mu        sync.RWMutex
+	items     []Currency
+	updatedAt time.Time
+	version   uint64
+	division  *Division
+	exact     bool
+	// changedAt records when each currency, by upper-case ISO code, was
+	// last added or requoted.
+	changedAt map[string]time.Time
+	// closedMarket prices currencies whose market is closed; closes holds
+	// their rates as of their last trading day, by upper-case ISO code.
+	closedMarket *ClosedMarketPolicy
+	closes       map[string]Currency
+	ttlPolicy    *TTLPolicy
+	compliance   *Compliance
+	spreadPolicy *SpreadPolicy
+	pegs         []Peg
+	// sandbox flags every quote FlagSandbox, and pins fixes pair rates;
+	// see WithSandbox.
+	sandbox bool
+	pins    []Pin
+	// nextDay prices quotes booking after today; see SetNextDayBook.
+	nextDay []Currency
+	// degraded marks contents loaded from fallback rates; see
+	// UpdateFallback.
+	degraded bool
+
+	subscribers map[chan *Snapshot]struct{}
+}
+
+// TableOption configures a Currencies table.
+type TableOption func(*Currencies)
+
+// WithDivision makes the table divide with a fixed precision and rounding
+// mode instead of shopspring/decimal's global DivisionPrecision, so its rates
+// do not change when other code touches the global.
+func WithDivision(d Division) TableOption {
+	return func(c *Currencies) {
+		c.division = &d
+	}
+}
+
+// NewTable creates a rate table holding currencies.
+func NewTable(currencies []Currency, opts ...TableOption) *Currencies {
+	c := &Currencies{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if len(currencies) > 0 {
+		c.Update(currencies, time.Now())
+	}
+	return c
+}
+
+// Update replaces the table contents with currencies as of at. The table
+// keeps its own copy, sorted by ISO code.
+func (c *Currencies) Update(currencies []Currency, at time.Time) {
+	c.update(currencies, at, false)
+}
+
+// UpdateFallback replaces the table contents with hard-coded fallback rates
+// as of at, for when no live or last-known-good rates are available. at is
+// when the fallback rates were set, not when they were loaded, or zero if
+// that is unknown, so they never look fresh. Until the next Update the
+// table is degraded: quotes priced from it are flagged FlagDegraded, and
+// its snapshots are marked Degraded.
+func (c *Currencies) UpdateFallback(currencies []Currency, at time.Time) {
+	c.update(currencies, at, true)
+}
+
+// Degraded reports whether the table holds fallback rates.
+func (c *Currencies) Degraded() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.degraded
+}
+
+func (c *Currencies) update(currencies []Currency, at time.Time, degraded bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	previous := indexByCode(c.items)
+	c.items = sortedCurrencies(currencies)
+	changedAt := make(map[string]time.Time, len(c.items))
+	for _, item := range c.items {
+		code := strings.ToUpper(item.ISOCode)
+		if prev, ok := previous[code]; ok && sameCurrency(*prev, item) {
+			changedAt[code] = c.changedAt[code]
+		} else {
+			changedAt[code] = at
+		}
+	}
+	c.changedAt = changedAt
+	c.updatedAt = at
+	c.degraded = degraded
+	c.recordClose()
+	c.version++
+
+	if len(c.subscribers) > 0 {
+		snapshot := NewSnapshot(c.items, c.updatedAt)
+		snapshot.Degraded = c.degraded
+		for ch := range c.subscribers {
+			// Slow subscribers miss intermediate updates rather than
+			// blocking the writer: a stale buffered snapshot is replaced,
+			// so the latest one always arrives.
+			select {
+			case <-ch:
+			default:
+			}
+			select {
+			case ch <- snapshot:
+			default:
+			}
+		}
+	}
+}
+
+// Subscribe returns a channel receiving a snapshot after every update. The
+// channel is buffered by one and always holds the latest snapshot if the
+// subscriber falls behind. It is closed once ctx is cancelled.
+func (c *Currencies) Subscribe(ctx context.Context) <-chan *Snapshot {
+	ch := make(chan *Snapshot, 1)
+
+	c.mu.Lock()
+	if c.subscribers == nil {
+		c.subscribers = map[chan *Snapshot]struct{}{}
+	}
+	c.subscribers[ch] = struct{}{}
+	c.mu.Unlock()
+
+	context.AfterFunc(ctx, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		delete(c.subscribers, ch)
+		close(ch)
+	})
+
+	return ch
+}
+
+// Restore replaces the table contents with a snapshot, keeping the
+// snapshot's original timestamp. A Degraded snapshot leaves the table
+// degraded.
+func (c *Currencies) Restore(s *Snapshot) {
+	c.update(s.Currencies, s.TakenAt, s.Degraded)
+}
+
+// Snapshot returns a copy of the current table contents.
+func (c *Currencies) Snapshot() *Snapshot {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	s := NewSnapshot(c.items, c.updatedAt)
+	s.Degraded = c.degraded
+	return s
+}
+
+// List returns a copy of the currencies in the table, sorted by ISO code.
+func (c *Currencies) List() []Currency {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return append([]Currency(nil), c.items...)
+}
+
+// Len returns the number of currencies in the table.
+func (c *Currencies) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.items)
+}
+
+// UpdatedAt returns when the table contents were last replaced.
+func (c *Currencies) UpdatedAt() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.updatedAt
+}
+
+// Version returns a counter incremented on every update.
+func (c *Currencies) Version() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.version
+}
+
+// FindCurrency finds a currency in the table by its ISO code.
+func (c *Currencies) FindCurrency(code string) (*Currency, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	found, err := FindCurrency(c.items, code)
+	if err != nil {
+		return nil, err
+	}
+	currency := *found
+	return &currency, nil
+}
+
+// CalculateRate calculates the exchange rate between two currencies in the
+// table. See the CalculateRate function for the conversion rules. A
+// currency converts to itself at 1, without spread and without taking the
+// table's lock.
+func (c *Currencies) CalculateRate(baseCurrency, from, to string) (decimal.Decimal, error) {
+	if strings.EqualFold(from, to) {
+		return decimalOne, nil
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.calculateRate(baseCurrency, from, to)
+}
+
+// calculateRate implements CalculateRate; the caller holds c.mu.
+func (c *Currencies) calculateRate(baseCurrency, from, to string) (decimal.Decimal, error) {
+	if strings.EqualFold(from, to) {
+		return decimalOne, nil
+	}
+	if rate, ok := c.pinnedRate(from, to); ok {
+		return rate, nil
+	}
+	items, _ := c.applyPegs(c.pricingItems(baseCurrency, from, to), baseCurrency, from, to)
+	spread := c.spreadFor(from, to)
+	if c.exact {
+		r, err := CalculateRateExact(items, baseCurrency, from, to)
+		if err != nil {
+			return decimal.Zero, err
+		}
+		return RatToDecimal(applySpreadExact(r, spread), exactDivision(c.division)), nil
+	}
+	rate, err := calculateRate(items, baseCurrency, from, to, c.division)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return applySpread(rate, spread), nil
+}
+
+// NewQuote creates a quote priced against the current table contents.
+func (c *Currencies) NewQuote(baseCurrency, fromCurrency, toCurrency string, fromAmount, fee decimal.Decimal, opts ...QuoteOption) (*Quote, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if len(c.items) == 0 {
+		return nil, ErrEmptyCurrencySource
+	}
+
+	// The table's settings are applied directly rather than as options,
+	// sparing the hot path their closures; the caller's options still
+	// override them.
+	options := getQuoteOptions()
+	defer putQuoteOptions(options)
+	options.division = c.division
+	options.spread = c.spreadFor(fromCurrency, toCurrency)
+	if c.exact {
+		div := exactDivision(c.division)
+		options.exact = &div
+	}
+	if c.ttlPolicy != nil {
+		options.ttl = c.ttlPolicy.TTL(fromCurrency, toCurrency)
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	// Price and date the quote at one instant, so the book chosen and the
+	// quote's value date agree.
+	now := options.clock.Now()
+	options.clock = instantClock(now)
+
+	if c.compliance != nil {
+		if err := c.compliance.check(fromCurrency, toCurrency, now); err != nil {
+			return nil, err
+		}
+	}
+
+	items := c.pricingItems(baseCurrency, fromCurrency, toCurrency)
+	if c.nextDay != nil && options.calendar != nil {
+		tradeDate, err := options.calendar.TradeDate(now, fromCurrency, toCurrency)
+		if err != nil {
+			return nil, err
+		}
+		y, m, d := now.Date()
+		if tradeDate.After(time.Date(y, m, d, 0, 0, 0, 0, now.Location())) {
+			items = c.nextDay
+		}
+	}
+
+	items, broken := c.applyPegs(items, baseCurrency, fromCurrency, toCurrency)
+
+	// The table's flags come before any added by the caller's options.
+	var flags []QuoteFlag
+	if broken {
+		flags = append(flags, FlagPegBroken)
+	}
+	if c.degraded {
+		flags = append(flags, FlagDegraded)
+	}
+	if c.sandbox {
+		flags = append(flags, FlagSandbox)
+	}
+	if rate, ok := c.pinnedRate(fromCurrency, toCurrency); ok {
+		options.pinned = &rate
+		options.spread = decimal.Zero
+		flags = append(flags, FlagPinned)
+	}
+	if len(flags) > 0 {
+		options.flags = append(flags, options.flags...)
+	}
+	return newQuote(items, baseCurrency, fromCurrency, toCurrency, fromAmount, fee, options)
+}
+
+// SetNextDayBook sets the rates quoted for trades booking after today, such
+// as those made after a cut-off (see CutOff). Only quotes created
+// WithCalendar use it, and the closed market policy does not apply to it.
+// A nil book removes it.
+func (c *Currencies) SetNextDayBook(currencies []Currency) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if currencies == nil {
+		c.nextDay = nil
+		return
+	}
+	c.nextDay = sortedCurrencies(currencies)
+}
+
+// Clone returns an independent copy of the table with the same contents and
+// settings. Subscribers are not copied.
+func (c *Currencies) Clone() *Currencies {
+	s := c.Snapshot()
+	return c.derive(s.Currencies, s.TakenAt)
+}
+
+// WithOverrides returns a copy of the table with the currencies in overrides,
+// keyed by ISO code, replacing the table's entries, e.g. a promo table with
+// discounted NGN rates. The table itself is not modified. An override must
+// name a currency in the table and be valid; an empty ISO code is the
+// table's.
+func (c *Currencies) WithOverrides(overrides map[string]Currency) (*Currencies, error) {
+	s := c.Snapshot()
+	items := s.Currencies
+
+	for code, override := range overrides {
+		if override.ISOCode != "" && !strings.EqualFold(override.ISOCode, code) {
+			return nil, fmt.Errorf("override for %s has ISO code %s", code, override.ISOCode)
+		}
+
+		i := slices.IndexFunc(items, func(item Currency) bool {
+			return strings.EqualFold(item.ISOCode, code)
+		})
+		if i < 0 {
+			return nil, &CurrencyNotFoundError{Code: code}
+		}
+		if override.ISOCode == "" {
+			override.ISOCode = items[i].ISOCode
+		}
+		if err := override.Validate(); err != nil {
+			return nil, err
+		}
+		items[i] = override
+	}
+
+	return c.derive(items, s.TakenAt), nil
+}
+
+// Filter returns a copy of the table holding only the currencies keep
+// returns true for. The table itself is not modified.
+func (c *Currencies) Filter(keep func(Currency) bool) *Currencies {
+	s := c.Snapshot()
+	items := slices.DeleteFunc(s.Currencies, func(item Currency) bool {
+		return !keep(item)
+	})
+	return c.derive(items, s.TakenAt)
+}
+
+// Only returns a copy of the table restricted to codes, e.g. the currencies
+// one product may quote, so other currencies cannot be quoted by
+// construction. Include the base currency to price cross rates. Every code
+// must be in the table.
+func (c *Currencies) Only(codes ...string) (*Currencies, error) {
+	s := c.Snapshot()
+
+	wanted := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		if _, err := FindCurrency(s.Currencies, code); err != nil {
+			return nil, &CurrencyNotFoundError{Code: code}
+		}
+		wanted[strings.ToUpper(code)] = true
+	}
+
+	items := slices.DeleteFunc(s.Currencies, func(item Currency) bool {
+		return !wanted[strings.ToUpper(item.ISOCode)]
+	})
+	return c.derive(items, s.TakenAt), nil
+}
@@ -0,0 +1,325 @@
+package converter
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func testCurrencies() []Currency {
+	return []Currency{
+		{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+		{ISOCode: "EUR", Precision: 2, BuyRate: decimal.NewFromFloat(0.9), SellRate: decimal.NewFromFloat(0.95)},
+		{ISOCode: "NGN", Precision: 2, BuyRate: decimal.NewFromInt(1500), SellRate: decimal.NewFromInt(1550)},
+	}
+}
+
+func TestCurrencies_Table(t *testing.T) {
+	table := NewTable(testCurrencies())
+	assert.Equal(t, 3, table.Len())
+	assert.Equal(t, uint64(1), table.Version())
+
+	rate, err := table.CalculateRate("USD", "USD", "NGN")
+	assert.NoError(t, err)
+	assert.Equal(t, "1550", rate.String())
+
+	currency, err := table.FindCurrency("eur")
+	assert.NoError(t, err)
+	assert.Equal(t, "EUR", currency.ISOCode)
+
+	// Returned values do not alias the table
+	currency.ISOCode = "XXX"
+	table.List()[0].ISOCode = "XXX"
+	_, err = table.FindCurrency("eur")
+	assert.NoError(t, err)
+	assert.Equal(t, "EUR", table.List()[0].ISOCode)
+
+	quote, err := table.NewQuote("USD", "USD", "EUR", decimal.NewFromInt(100), decimal.NewFromInt(1))
+	assert.NoError(t, err)
+	assert.Equal(t, "95", quote.FinalAmount.String())
+
+	// Update replaces contents and bumps the version
+	at := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	table.Update(testCurrencies()[:2], at)
+	assert.Equal(t, 2, table.Len())
+	assert.Equal(t, uint64(2), table.Version())
+	assert.Equal(t, at, table.UpdatedAt())
+	_, err = table.CalculateRate("USD", "USD", "NGN")
+	assert.Error(t, err)
+}
+
+func TestCurrencies_Empty(t *testing.T) {
+	table := NewTable(nil)
+	assert.Zero(t, table.Len())
+	assert.Zero(t, table.Version())
+
+	_, err := table.CalculateRate("USD", "USD", "EUR")
+	assert.Error(t, err)
+
+	_, err = table.NewQuote("USD", "USD", "EUR", decimal.NewFromInt(100), decimal.Zero)
+	assert.ErrorIs(t, err, ErrEmptyCurrencySource)
+}
+
+func TestCurrencies_SnapshotRestore(t *testing.T) {
+	at := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	source := NewTable(nil)
+	source.Update(testCurrencies(), at)
+
+	snapshot := source.Snapshot()
+	assert.Equal(t, at, snapshot.TakenAt)
+
+	restored := NewTable(nil)
+	restored.Restore(snapshot)
+	assert.Equal(t, 3, restored.Len())
+	assert.Equal(t, at, restored.UpdatedAt())
+}
+
+func TestCurrencies_Concurrent(t *testing.T) {
+	table := NewTable(testCurrencies())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_, _ = table.CalculateRate("USD", "EUR", "NGN")
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				table.Update(testCurrencies(), time.Now())
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, uint64(801), table.Version())
+}
+
+func TestCurrencies_Subscribe(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	table := NewTable(nil)
+
+	updates := table.Subscribe(ctx)
+
+	at := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	table.Update(testCurrencies(), at)
+
+	snapshot := <-updates
+	assert.Len(t, snapshot.Currencies, 3)
+	assert.Equal(t, at, snapshot.TakenAt)
+
+	// Intermediate updates are dropped rather than blocking when the
+	// subscriber lags, but the latest one is delivered
+	table.Update(testCurrencies()[:1], at)
+	table.Update(testCurrencies()[:2], at)
+	assert.Len(t, (<-updates).Currencies, 2)
+
+	// Cancelling closes the channel
+	cancel()
+	assert.Eventually(t, func() bool {
+		_, ok := <-updates
+		return !ok
+	}, time.Second, time.Millisecond)
+}
+
+func TestCurrencies_Clone(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	table := NewTable(nil, WithExactArithmetic())
+	table.Update(testCurrencies(), now)
+
+	clone := table.Clone()
+	assert.Equal(t, table.List(), clone.List())
+	assert.Equal(t, now, clone.UpdatedAt())
+	assert.True(t, clone.exact)
+
+	clone.Update(testCurrencies()[:1], now)
+	assert.Equal(t, 3, table.Len())
+}
+
+func TestCurrencies_UpdateFallback(t *testing.T) {
+	table := NewTable(nil)
+	assert.False(t, table.Degraded())
+
+	table.UpdateFallback(testCurrencies(), time.Now())
+	assert.True(t, table.Degraded())
+	// Tables derived from fallback rates are degraded too.
+	assert.True(t, table.Clone().Degraded())
+
+	table.Update(testCurrencies(), time.Now())
+	assert.False(t, table.Degraded())
+}
+
+func TestCurrencies_WithOverrides(t *testing.T) {
+	table := NewTable(testCurrencies())
+
+	promo, err := table.WithOverrides(map[string]Currency{
+		"ngn": {Precision: 2, BuyRate: decimal.NewFromInt(1500), SellRate: decimal.NewFromInt(1600)},
+	})
+	assert.NoError(t, err)
+	rate, err := promo.CalculateRate("USD", "USD", "NGN")
+	assert.NoError(t, err)
+	assert.Equal(t, "1600", rate.String())
+	assert.Equal(t, "NGN", promo.List()[1].ISOCode)
+
+	// The shared table is unchanged.
+	rate, err = table.CalculateRate("USD", "USD", "NGN")
+	assert.NoError(t, err)
+	assert.Equal(t, "1550", rate.String())
+
+	_, err = table.WithOverrides(map[string]Currency{"GBP": {ISOCode: "GBP", Precision: 2}})
+	assert.EqualError(t, err, "currency GBP not found")
+
+	_, err = table.WithOverrides(map[string]Currency{"NGN": {ISOCode: "EUR", Precision: 2}})
+	assert.Error(t, err)
+
+	_, err = table.WithOverrides(map[string]Currency{"NGN": {Precision: 2, BuyRate: decimal.NewFromInt(-1)}})
+	assert.ErrorIs(t, err, ErrInvalidRate)
+}
+
+func TestCurrencies_Filter(t *testing.T) {
+	table := NewTable(testCurrencies())
+
+	cheap := table.Filter(func(c Currency) bool {
+		return c.SellRate.LessThanOrEqual(decimal.NewFromInt(1))
+	})
+	assert.Equal(t, []string{"EUR", "USD"}, codes(cheap.List()))
+	assert.Equal(t, 3, table.Len())
+
+	_, err := cheap.CalculateRate("USD", "USD", "NGN")
+	assert.EqualError(t, err, "currency NGN not found")
+}
+
+func TestCurrencies_Only(t *testing.T) {
+	table := NewTable(testCurrencies())
+
+	menu, err := table.Only("ngn", "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"NGN", "USD"}, codes(menu.List()))
+
+	_, err = menu.NewQuote("USD", "USD", "EUR", decimal.NewFromInt(10), decimal.Zero)
+	assert.EqualError(t, err, "currency EUR not found")
+
+	_, err = table.Only("USD", "GBP")
+	assert.EqualError(t, err, "currency GBP not found")
+	assert.Equal(t, 3, table.Len())
+}
+
+func TestCurrencies_SetNextDayBook(t *testing.T) {
+	table := NewTable(testCurrencies())
+	nextDay := testCurrencies()
+	nextDay[2].SellRate = decimal.NewFromInt(1600)
+	table.SetNextDayBook(nextDay)
+
+	cal := NewCalendar()
+	cal.SetCutOff("NGN", CutOff{Hour: 15})
+	before := time.Date(2024, 3, 28, 14, 0, 0, 0, time.UTC)
+
+	// Without a calendar the next-day book is never used.
+	quote, err := table.NewQuote("USD", "USD", "NGN", decimal.NewFromInt(100), decimal.Zero, WithClock(fixedClock(before.Add(time.Hour))))
+	assert.NoError(t, err)
+	assert.Equal(t, "1550", quote.Rate.String())
+
+	quote, err = table.NewQuote("USD", "USD", "NGN", decimal.NewFromInt(100), decimal.Zero, WithClock(fixedClock(before)), WithCalendar(cal))
+	assert.NoError(t, err)
+	assert.Equal(t, "1550", quote.Rate.String())
+	assert.Equal(t, day(2024, 3, 28), quote.ValueDate)
+
+	// Past the cut-off the quote books tomorrow at tomorrow's rates.
+	quote, err = table.NewQuote("USD", "USD", "NGN", decimal.NewFromInt(100), decimal.Zero, WithClock(fixedClock(before.Add(time.Hour))), WithCalendar(cal))
+	assert.NoError(t, err)
+	assert.Equal(t, "1600", quote.Rate.String())
+	assert.Equal(t, day(2024, 3, 29), quote.ValueDate)
+
+	table.SetNextDayBook(nil)
+	quote, err = table.NewQuote("USD", "USD", "NGN", decimal.NewFromInt(100), decimal.Zero, WithClock(fixedClock(before.Add(time.Hour))), WithCalendar(cal))
+	assert.NoError(t, err)
+	assert.Equal(t, "1550", quote.Rate.String())
+}
+
+func BenchmarkCurrencies_CalculateRate(b *testing.B) {
+	table := NewTable(testCurrencies())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := table.CalculateRate("USD", "EUR", "NGN"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCurrencies_NewQuote(b *testing.B) {
+	table := NewTable(testCurrencies())
+	amount, fee := decimal.NewFromInt(100), decimal.NewFromInt(2)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := table.NewQuote("USD", "EUR", "NGN", amount, fee); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestCurrencies_NewQuoteAllocs(t *testing.T) {
+	table := NewTable(testCurrencies())
+	amount, fee := decimal.NewFromInt(100), decimal.NewFromInt(2)
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = table.NewQuote("USD", "EUR", "NGN", amount, fee)
+	})
+	assert.LessOrEqual(t, allocs, float64(66))
+}
+
+func TestCurrencies_CalculateRateSameCurrency(t *testing.T) {
+	// Same-currency conversions are 1, without spread or the table's lock.
+	table := NewTable(testCurrencies(), WithSpreadPolicy(SpreadPolicy{
+		Currencies: map[string]decimal.Decimal{"NGN": decimal.RequireFromString("0.01")},
+	}))
+	table.mu.Lock()
+	rate, err := table.CalculateRate("USD", "NGN", "ngn")
+	table.mu.Unlock()
+	assert.NoError(t, err)
+	assert.Equal(t, "1", rate.String())
+
+	rate, err = NewTable(nil).CalculateRate("USD", "NGN", "NGN")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", rate.String())
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = table.CalculateRate("USD", "NGN", "NGN")
+	})
+	assert.Zero(t, allocs)
+}
+
+func TestCurrencies_DerivedNextDayBook(t *testing.T) {
+	table := NewTable(testCurrencies())
+	nextDay := testCurrencies()
+	nextDay[2].SellRate = decimal.NewFromInt(1600)
+	table.SetNextDayBook(nextDay)
+
+	cal := NewCalendar()
+	cal.SetCutOff("NGN", CutOff{Hour: 15})
+	cal.SetCutOff("EUR", CutOff{Hour: 15})
+	after := WithClock(fixedClock(time.Date(2024, 3, 28, 16, 0, 0, 0, time.UTC)))
+
+	only, err := table.Only("USD", "NGN")
+	assert.NoError(t, err)
+	overridden, err := table.WithOverrides(map[string]Currency{"EUR": {Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)}})
+	assert.NoError(t, err)
+	for name, derived := range map[string]*Currencies{"Clone": table.Clone(), "Only": only, "WithOverrides": overridden} {
+		quote, err := derived.NewQuote("USD", "USD", "NGN", decimal.NewFromInt(100), decimal.Zero, after, WithCalendar(cal))
+		assert.NoError(t, err, name)
+		assert.Equal(t, "1600", quote.Rate.String(), name)
+	}
+
+	// The derived book is a copy, restricted to the derived currencies.
+	table.SetNextDayBook(nil)
+	quote, err := only.NewQuote("USD", "USD", "NGN", decimal.NewFromInt(100), decimal.Zero, after, WithCalendar(cal))
+	assert.NoError(t, err)
+	assert.Equal(t, "1600", quote.Rate.String())
+	_, err = only.NewQuote("USD", "USD", "EUR", decimal.NewFromInt(100), decimal.Zero, after, WithCalendar(cal))
+	assert.Error(t, err)
+}
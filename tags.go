@@ -0,0 +1,233 @@
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// TagKey is the struct tag NewCurrencies reads to map source fields onto a
+// Currency, e.g.
+//
+//	type bankRate struct {
+//		Code string  `converter:"iso"`
+//		Bid  float64 `converter:"buy"`
+//		Ask  string  `converter:"sell"`
+//	}
+//
+// The tag values are iso, precision, buy, sell and base (the optional
+// BaseISOCode); "-" ignores a field.
+const TagKey = "converter"
+
+// Tag values
+const (
+	tagISO       = "iso"
+	tagPrecision = "precision"
+	tagBuy       = "buy"
+	tagSell      = "sell"
+	tagBase      = "base"
+)
+
+var decimalType = reflect.TypeOf(decimal.Decimal{})
+
+// taggedFields returns the index of each field of t carrying a converter tag,
+// by tag value. It returns nil if t is not a struct or has no tagged fields.
+func taggedFields(t reflect.Type) (map[string][]int, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, nil
+	}
+
+	var fields map[string][]int
+	for _, f := range reflect.VisibleFields(t) {
+		tag, ok := f.Tag.Lookup(TagKey)
+		if !ok || tag == "-" {
+			continue
+		}
+		switch tag {
+		case tagISO, tagPrecision, tagBuy, tagSell, tagBase:
+		default:
+			return nil, fmt.Errorf("converter: field %s has unknown tag %q", f.Name, tag)
+		}
+		if !f.IsExported() {
+			return nil, fmt.Errorf("converter: tagged field %s is unexported", f.Name)
+		}
+		if fields == nil {
+			fields = map[string][]int{}
+		}
+		if _, dup := fields[tag]; dup {
+			return nil, fmt.Errorf("converter: more than one field tagged %q", tag)
+		}
+		fields[tag] = f.Index
+	}
+
+	if fields != nil {
+		if _, ok := fields[tagISO]; !ok {
+			return nil, fmt.Errorf("converter: no field tagged %q", tagISO)
+		}
+	}
+	return fields, nil
+}
+
+// currencyFromTagged reads a Currency from a struct value using the fields
+// found by taggedFields.
+func currencyFromTagged(v reflect.Value, fields map[string][]int) (Currency, error) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return Currency{}, fmt.Errorf("%w: nil entry", ErrMissingISOCode)
+		}
+		v = v.Elem()
+	}
+
+	field := func(tag string) (reflect.Value, bool) {
+		index, ok := fields[tag]
+		if !ok {
+			return reflect.Value{}, false
+		}
+		// A nil embedded pointer leaves the field unset.
+		f, err := v.FieldByIndexErr(index)
+		return f, err == nil
+	}
+
+	var c Currency
+	if f, ok := field(tagISO); ok {
+		f = indirect(f)
+		if f.Kind() != reflect.String {
+			return Currency{}, fmt.Errorf("converter: iso field has type %s, want string", f.Type())
+		}
+		c.ISOCode = f.String()
+	}
+	if f, ok := field(tagBase); ok {
+		f = indirect(f)
+		if f.IsValid() && f.Kind() != reflect.String {
+			return Currency{}, fmt.Errorf("converter: base field has type %s, want string", f.Type())
+		}
+		if f.IsValid() {
+			c.BaseISOCode = f.String()
+		}
+	}
+	if f, ok := field(tagPrecision); ok {
+		precision, err := precisionFromValue(indirect(f))
+		if err != nil {
+			return Currency{}, fmt.Errorf("%w: %s: %v", ErrInvalidPrecision, c.ISOCode, err)
+		}
+		c.Precision = precision
+	}
+	if f, ok := field(tagBuy); ok {
+		buy, err := rateFromValue(indirect(f))
+		if err != nil {
+			return Currency{}, fmt.Errorf("%w: %s buy rate: %v", ErrInvalidRate, c.ISOCode, err)
+		}
+		c.BuyRate = buy
+	}
+	if f, ok := field(tagSell); ok {
+		sell, err := rateFromValue(indirect(f))
+		if err != nil {
+			return Currency{}, fmt.Errorf("%w: %s sell rate: %v", ErrInvalidRate, c.ISOCode, err)
+		}
+		c.SellRate = sell
+	}
+	return c, nil
+}
+
+// indirect follows pointers, returning the zero Value for a nil pointer.
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// rateFromValue converts a decimal, json.Number, string, float or integer
+// field into a rate. Unset values and empty strings are zero, as in JSON.
+func rateFromValue(v reflect.Value) (decimal.Decimal, error) {
+	if !v.IsValid() {
+		return decimal.Zero, nil
+	}
+	if v.Type() == decimalType {
+		return v.Interface().(decimal.Decimal), nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		// Covers json.Number.
+		if strings.TrimSpace(v.String()) == "" {
+			return decimal.Zero, nil
+		}
+		return parseRate(v.String())
+	case reflect.Float32, reflect.Float64:
+		return decimal.NewFromFloat(v.Float()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return decimal.NewFromInt(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return decimal.NewFromBigInt(new(big.Int).SetUint64(v.Uint()), 0), nil
+	default:
+		return decimal.Zero, fmt.Errorf("unsupported type %s", v.Type())
+	}
+}
+
+// precisionFromValue converts an integer or string field into a precision.
+func precisionFromValue(v reflect.Value) (int, error) {
+	if !v.IsValid() {
+		return 0, nil
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Int() < 0 || v.Int() > maxDigits {
+			return 0, fmt.Errorf("precision %d out of range", v.Int())
+		}
+		return int(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.Uint() > maxDigits {
+			return 0, fmt.Errorf("precision %d out of range", v.Uint())
+		}
+		return int(v.Uint()), nil
+	case reflect.String:
+		p, err := strconv.Atoi(strings.TrimSpace(v.String()))
+		if err != nil {
+			return 0, fmt.Errorf("not an integer")
+		}
+		return p, nil
+	default:
+		return 0, fmt.Errorf("unsupported type %s", v.Type())
+	}
+}
+
+// currenciesFromTagged maps each element of source through its converter tags.
+func currenciesFromTagged[T any](source []T, fields map[string][]int) ([]Currency, error) {
+	currencies := make([]Currency, 0, len(source))
+	for i := range source {
+		c, err := currencyFromTagged(reflect.ValueOf(&source[i]).Elem(), fields)
+		if err != nil {
+			return nil, err
+		}
+		currencies = append(currencies, c)
+	}
+	return currencies, nil
+}
+
+// currenciesFromJSON converts source through its JSON encoding, matching the
+// field names of Currency.
+func currenciesFromJSON[T any](source []T) ([]Currency, error) {
+	b, err := json.Marshal(source)
+	if err != nil {
+		return nil, err
+	}
+
+	var currencies []Currency
+	if err := json.Unmarshal(b, &currencies); err != nil {
+		return nil, err
+	}
+	return currencies, nil
+}
@@ -0,0 +1,105 @@
+package converter
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCurrencies_Tags(t *testing.T) {
+	type bankRate struct {
+		Code      string          `json:"ccy" converter:"iso"`
+		Decimals  uint8           `converter:"precision"`
+		Bid       float64         `json:"bid" converter:"buy"`
+		Ask       json.Number     `json:"ask" converter:"sell"`
+		UpdatedAt string          `json:"isoCode"`
+		Internal  decimal.Decimal `converter:"-"`
+	}
+
+	currencies, err := NewCurrencies([]bankRate{
+		{Code: "USD", Decimals: 2, Bid: 1, Ask: "1"},
+		{Code: "EUR", Decimals: 2, Bid: 0.9, Ask: "0.95"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []Currency{
+		{ISOCode: "USD", Precision: 2, BuyRate: decimal.NewFromInt(1), SellRate: decimal.NewFromInt(1)},
+		{ISOCode: "EUR", Precision: 2, BuyRate: decimal.RequireFromString("0.9"), SellRate: decimal.RequireFromString("0.95")},
+	}, currencies)
+
+	// Embedded structs, pointers and decimals.
+	type rates struct {
+		Buy  *decimal.Decimal `converter:"buy"`
+		Sell string           `converter:"sell"`
+	}
+	type feed struct {
+		ISO string `converter:"iso"`
+		*rates
+	}
+	buy := decimal.RequireFromString("1500.123456789012345678")
+	currencies, err = NewCurrencies([]*feed{
+		{ISO: "NGN", rates: &rates{Buy: &buy, Sell: "1.55e3"}},
+		{ISO: "GHS"},
+	})
+	assert.NoError(t, err)
+	assert.True(t, buy.Equal(currencies[0].BuyRate))
+	assert.Equal(t, "1550", currencies[0].SellRate.String())
+	assert.True(t, currencies[1].BuyRate.IsZero())
+
+	_, err = NewCurrencies([]rates{{Sell: "1"}})
+	assert.ErrorContains(t, err, `no field tagged "iso"`)
+
+	type badTag struct {
+		ISO string `converter:"code"`
+	}
+	_, err = NewCurrencies([]badTag{{ISO: "USD"}})
+	assert.ErrorContains(t, err, `unknown tag "code"`)
+
+	type badRate struct {
+		ISO string `converter:"iso"`
+		Buy string `converter:"buy"`
+	}
+	_, err = NewCurrencies([]badRate{{ISO: "BTC", Buy: "1.5e"}})
+	assert.ErrorIs(t, err, ErrInvalidRate)
+	assert.ErrorContains(t, err, "BTC buy rate: not a decimal number")
+
+	type badPrecision struct {
+		ISO       string `converter:"iso"`
+		Precision int    `converter:"precision"`
+	}
+	_, err = NewCurrencies([]badPrecision{{ISO: "USD", Precision: 99}})
+	assert.ErrorIs(t, err, ErrInvalidPrecision)
+}
+
+func TestNewCurrencies_BaseTag(t *testing.T) {
+	type feedRate struct {
+		Code string  `converter:"iso"`
+		Per  *string `converter:"base"`
+		Rate string  `converter:"buy"`
+	}
+	eur := "EUR"
+
+	currencies, err := NewCurrencies([]feedRate{{Code: "GBP", Per: &eur, Rate: "0.8"}, {Code: "EUR", Rate: "1"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "EUR", currencies[0].BaseISOCode)
+	assert.Empty(t, currencies[1].BaseISOCode)
+
+	type badBase struct {
+		Code string `converter:"iso"`
+		Per  int    `converter:"base"`
+	}
+	_, err = NewCurrencies([]badBase{{Code: "GBP", Per: 1}})
+	assert.ErrorContains(t, err, "base field has type int")
+}
+
+func TestNewCurrencies_MissingISOCode(t *testing.T) {
+	// JSON names that do not match Currency leave every field unset.
+	type bankRate struct {
+		Code string  `json:"ccy"`
+		Bid  float64 `json:"bid"`
+	}
+
+	_, err := NewCurrencies([]bankRate{{Code: "USD", Bid: 1}})
+	assert.ErrorIs(t, err, ErrMissingISOCode)
+}
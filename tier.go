@@ -0,0 +1,70 @@
+package converter
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// Tier errors
+var (
+	ErrTierLimitExceeded = errors.New("limit exceeded for tier")
+)
+
+// Tier is a customer tier, e.g. a KYC level, selecting the limits and fees
+// of its customers' quotes.
+type Tier struct {
+	Name string
+	// MaxAmount is the largest amount, in units of the base currency, a
+	// quote may convert. The quote's amount is normalized with the source
+	// currency's buy rate. Zero means no limit.
+	MaxAmount decimal.Decimal
+	// Fees, if set, prices the quote's fee instead of the fee passed to
+	// NewQuote or set with WithFeeSchedule.
+	Fees *FeeSchedule
+}
+
+// TierLimitError reports a quote above its tier's limit. It matches
+// ErrTierLimitExceeded.
+type TierLimitError struct {
+	Tier string
+	// Amount is the quote's amount and Max the tier's maximum, both in
+	// units of the base currency.
+	Amount decimal.Decimal
+	Max    decimal.Decimal
+	Base   string
+}
+
+// Error implements error.
+func (e *TierLimitError) Error() string {
+	return fmt.Sprintf("%v %q: %s %s is above the maximum of %s %s",
+		ErrTierLimitExceeded, e.Tier, e.Amount, e.Base, e.Max, e.Base)
+}
+
+// Is makes every TierLimitError match ErrTierLimitExceeded.
+func (e *TierLimitError) Is(target error) bool {
+	return target == ErrTierLimitExceeded
+}
+
+// WithTier applies a customer tier's limit and fee schedule to the quote.
+func WithTier(t Tier) QuoteOption {
+	return func(o *quoteOptions) {
+		o.tier = &t
+	}
+}
+
+// check returns a *TierLimitError if converting amount from a currency is
+// above the tier's limit. The error's amount is rounded up to the base
+// currency's precision, so it always shows above the maximum.
+func (t Tier) check(amount decimal.Decimal, from, base *Currency) error {
+	if !t.MaxAmount.IsPositive() || amount.LessThanOrEqual(t.MaxAmount.Mul(from.BuyRate)) {
+		return nil
+	}
+	return &TierLimitError{
+		Tier:   t.Name,
+		Amount: amount.DivRound(from.BuyRate, maxDigits).RoundCeil(int32(base.Precision)),
+		Max:    t.MaxAmount,
+		Base:   base.ISOCode,
+	}
+}
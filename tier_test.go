@@ -0,0 +1,42 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithTier(t *testing.T) {
+	table := NewTable(testCurrencies())
+	basic := Tier{
+		Name:      "basic",
+		MaxAmount: decimal.NewFromInt(1000),
+		Fees:      &FeeSchedule{Fixed: decimal.NewFromInt(1), Rate: decimal.NewFromFloat(0.01)},
+	}
+
+	// 1,000 USD is the limit; the fee is 1 USD plus 1%.
+	quote, err := table.NewQuote("USD", "USD", "EUR", decimal.NewFromInt(1000), decimal.NewFromInt(99), WithTier(basic))
+	assert.NoError(t, err)
+	assert.Equal(t, "11", quote.Fee.String())
+	assert.Equal(t, "1011", quote.AmountToDeduct.String())
+
+	// In NGN the fixed part is 1 USD at the buy rate.
+	quote, err = table.NewQuote("USD", "NGN", "USD", decimal.NewFromInt(150000), decimal.Zero, WithTier(basic))
+	assert.NoError(t, err)
+	assert.Equal(t, "3000", quote.Fee.String())
+
+	_, err = table.NewQuote("USD", "NGN", "USD", decimal.NewFromInt(1500001), decimal.Zero, WithTier(basic))
+	assert.ErrorIs(t, err, ErrTierLimitExceeded)
+	var limit *TierLimitError
+	assert.ErrorAs(t, err, &limit)
+	assert.Equal(t, "basic", limit.Tier)
+	assert.Equal(t, "1000", limit.Max.String())
+	assert.Equal(t, "1000.01", limit.Amount.String())
+	assert.EqualError(t, err, `limit exceeded for tier "basic": 1000.01 USD is above the maximum of 1000 USD`)
+
+	// A tier without a fee schedule or limit keeps the given fee.
+	quote, err = table.NewQuote("USD", "USD", "EUR", decimal.NewFromInt(1000000), decimal.NewFromInt(5), WithTier(Tier{Name: "premium"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "5", quote.Fee.String())
+}
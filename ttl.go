@@ -0,0 +1,54 @@
+package converter
+
+import (
+	"strings"
+	"time"
+)
+
+// TTLPolicy sets how long a table's quotes stay valid per corridor, e.g. 30
+// seconds for crypto pairs and 15 minutes for fiat. A zero duration means
+// quotes never expire.
+type TTLPolicy struct {
+	// Default applies to corridors matching nothing else.
+	Default time.Duration
+	// Pairs sets the TTL of pairs such as "USD/BTC", in either direction.
+	// Invalid pairs are ignored.
+	Pairs map[string]time.Duration
+	// Currencies sets the TTL of every pair involving a currency, e.g. "BTC".
+	// A pair involving two such currencies gets the shorter TTL.
+	Currencies map[string]time.Duration
+}
+
+// TTL returns how long a quote from one currency to another stays valid:
+// the TTL of the pair if set, else the shortest TTL of its currencies, else
+// Default.
+func (p TTLPolicy) TTL(from, to string) time.Duration {
+	key := pairKey(from, to)
+	for pair, ttl := range p.Pairs {
+		if a, b, err := parsePair(pair); err == nil && pairKey(a, b) == key {
+			return ttl
+		}
+	}
+
+	ttl, found := time.Duration(0), false
+	for code, d := range p.Currencies {
+		if !strings.EqualFold(code, from) && !strings.EqualFold(code, to) {
+			continue
+		}
+		if !found || d < ttl {
+			ttl, found = d, true
+		}
+	}
+	if found {
+		return ttl
+	}
+	return p.Default
+}
+
+// WithTTLPolicy makes the table's quotes expire by the policy. A WithTTL
+// option given to NewQuote overrides it.
+func WithTTLPolicy(p TTLPolicy) TableOption {
+	return func(c *Currencies) {
+		c.ttlPolicy = &p
+	}
+}
@@ -0,0 +1,48 @@
+package converter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTTLPolicy_TTL(t *testing.T) {
+	p := TTLPolicy{
+		Default:    15 * time.Minute,
+		Pairs:      map[string]time.Duration{"usd/ngn": time.Hour, "bad": time.Second},
+		Currencies: map[string]time.Duration{"BTC": 30 * time.Second, "ETH": 10 * time.Second},
+	}
+
+	assert.Equal(t, time.Hour, p.TTL("NGN", "USD"))
+	assert.Equal(t, 30*time.Second, p.TTL("usd", "btc"))
+	assert.Equal(t, 10*time.Second, p.TTL("BTC", "ETH"))
+	assert.Equal(t, 15*time.Minute, p.TTL("USD", "EUR"))
+	assert.Zero(t, TTLPolicy{}.TTL("USD", "EUR"))
+}
+
+func TestWithTTLPolicy(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	table := NewTable(testCurrencies(), WithTTLPolicy(TTLPolicy{
+		Default:    15 * time.Minute,
+		Currencies: map[string]time.Duration{"NGN": 30 * time.Second},
+	}))
+
+	quote, err := table.NewQuote("USD", "USD", "EUR", decimal.NewFromInt(100), decimal.Zero, WithClock(fixedClock(now)))
+	assert.NoError(t, err)
+	assert.Equal(t, now.Add(15*time.Minute), quote.ExpiresAt)
+
+	quote, err = table.NewQuote("USD", "USD", "NGN", decimal.NewFromInt(100), decimal.Zero, WithClock(fixedClock(now)))
+	assert.NoError(t, err)
+	assert.Equal(t, now.Add(30*time.Second), quote.ExpiresAt)
+
+	// An explicit TTL wins, and derived tables keep the policy.
+	quote, err = table.NewQuote("USD", "USD", "NGN", decimal.NewFromInt(100), decimal.Zero, WithClock(fixedClock(now)), WithTTL(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, now.Add(time.Minute), quote.ExpiresAt)
+
+	quote, err = table.Clone().NewQuote("USD", "USD", "NGN", decimal.NewFromInt(100), decimal.Zero, WithClock(fixedClock(now)))
+	assert.NoError(t, err)
+	assert.Equal(t, now.Add(30*time.Second), quote.ExpiresAt)
+}
@@ -0,0 +1,92 @@
+package converter
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Volatility returns the realized volatility of a pair's rate, e.g.
+// "USD/NGN", over the window before now: the root mean square of the log
+// returns between consecutive tables taken in the window, each rate priced
+// against base as the CalculateRate function does. 0.01 is 1% per update.
+// A pair priced by a single table has zero volatility; one priced by none
+// returns ErrNoRateSamples.
+func (h *RateHistory) Volatility(base, pair string, window time.Duration) (decimal.Decimal, error) {
+	from, to, err := parsePair(pair)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if window <= 0 {
+		return decimal.Zero, fmt.Errorf("%w: %s", ErrInvalidHistoryWindow, window)
+	}
+
+	clock := h.Clock
+	if clock == nil {
+		clock = SystemClock
+	}
+	cutoff := clock.Now().Add(-window)
+	times, rates := h.pairSamples(base, from, to)
+	var returns []float64
+	var previous float64
+	samples := 0
+	for i, rate := range rates {
+		if times[i].Before(cutoff) {
+			continue
+		}
+		r := rate.InexactFloat64()
+		if samples > 0 && previous > 0 && r > 0 {
+			returns = append(returns, math.Log(r/previous))
+		}
+		previous = r
+		samples++
+	}
+	if samples == 0 {
+		return decimal.Zero, fmt.Errorf("%w: %s/%s", ErrNoRateSamples, from, to)
+	}
+	if len(returns) == 0 {
+		return decimal.Zero, nil
+	}
+
+	sum := 0.0
+	for _, r := range returns {
+		sum += r * r
+	}
+	return decimal.NewFromFloat(math.Sqrt(sum / float64(len(returns)))), nil
+}
+
+// VolatilityWidening widens spreads by pairs' realized volatility, so risk
+// engines charge more on volatile corridors. Set its Widen method as a
+// SpreadPolicy's Widen.
+type VolatilityWidening struct {
+	History *RateHistory
+	// Base is the currency the history's rates are quoted against.
+	Base string
+	// Window is the period volatility is measured over.
+	Window time.Duration
+	// Multiplier scales volatility into spread: with 2, a pair moving 1%
+	// per update gets 2% more spread.
+	Multiplier decimal.Decimal
+	// Max, if positive, caps the widening.
+	Max decimal.Decimal
+}
+
+// Widen returns the spread to add to a pair: its volatility times the
+// multiplier, capped at Max. Pairs without volatility are not widened.
+func (w VolatilityWidening) Widen(from, to string) decimal.Decimal {
+	if w.History == nil {
+		return decimal.Zero
+	}
+	vol, err := w.History.Volatility(w.Base, strings.ToUpper(from)+"/"+strings.ToUpper(to), w.Window)
+	if err != nil {
+		return decimal.Zero
+	}
+	widen := vol.Mul(w.Multiplier)
+	if w.Max.IsPositive() && widen.GreaterThan(w.Max) {
+		widen = w.Max
+	}
+	return widen
+}
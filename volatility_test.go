@@ -0,0 +1,63 @@
+package converter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateHistory_Volatility(t *testing.T) {
+	h := smoothingHistory(t)
+
+	vol, err := h.Volatility("USD", "usd/ngn", time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, "0.016984", vol.StringFixed(6))
+
+	vol, err = h.Volatility("USD", "USD/NGN", 4*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "0.020298", vol.StringFixed(6))
+
+	// A single table in the window has no returns.
+	vol, err = h.Volatility("USD", "USD/NGN", 150*time.Second)
+	require.NoError(t, err)
+	assert.True(t, vol.IsZero())
+
+	_, err = h.Volatility("USD", "USD/NGN", time.Minute)
+	assert.ErrorIs(t, err, ErrNoRateSamples)
+	_, err = h.Volatility("USD", "USD/GBP", time.Hour)
+	assert.ErrorIs(t, err, ErrNoRateSamples)
+	_, err = h.Volatility("USD", "USDNGN", time.Hour)
+	assert.ErrorIs(t, err, ErrInvalidPair)
+	_, err = h.Volatility("USD", "USD/NGN", 0)
+	assert.ErrorIs(t, err, ErrInvalidHistoryWindow)
+}
+
+func TestVolatilityWidening(t *testing.T) {
+	w := VolatilityWidening{
+		History:    smoothingHistory(t),
+		Base:       "USD",
+		Window:     time.Hour,
+		Multiplier: decimal.NewFromInt(2),
+	}
+	assert.Equal(t, "0.033968", w.Widen("usd", "ngn").StringFixed(6))
+	assert.True(t, w.Widen("USD", "GBP").IsZero())
+
+	w.Max = decimal.RequireFromString("0.02")
+	assert.Equal(t, "0.02", w.Widen("USD", "NGN").String())
+	assert.True(t, VolatilityWidening{}.Widen("USD", "NGN").IsZero())
+
+	// Volatile pairs get wider spreads on top of the policy's own.
+	table := NewTable(testCurrencies(), WithSpreadPolicy(SpreadPolicy{
+		Currencies: map[string]decimal.Decimal{"NGN": decimal.RequireFromString("0.01")},
+		Widen:      w.Widen,
+	}))
+	rate, err := table.CalculateRate("USD", "USD", "NGN")
+	require.NoError(t, err)
+	assert.Equal(t, "1503.5", rate.String())
+	rate, err = table.CalculateRate("USD", "USD", "EUR")
+	require.NoError(t, err)
+	assert.Equal(t, "0.95", rate.String())
+}
@@ -0,0 +1,313 @@
+package converter
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// XLSX errors
+var (
+	ErrSheetNotFound = errors.New("xlsx: sheet not found")
+)
+
+// maxXLSXPart bounds the decompressed size of each part read from a workbook,
+// so a small, highly compressed file cannot exhaust memory.
+const maxXLSXPart = 64 << 20
+
+// XLSXOptions configures NewCurrenciesFromXLSX.
+type XLSXOptions struct {
+	// Sheet is the name of the sheet holding the rates. It defaults to the
+	// first sheet.
+	Sheet string
+	// HeaderRow is the 1-based row naming the columns. It defaults to 1;
+	// rows above it, such as a title, are skipped.
+	HeaderRow int
+	// Mapping names the header columns, as for CSV.
+	Mapping FieldMapping
+}
+
+// NewCurrenciesFromXLSX reads currencies from a sheet of an Excel (XLSX)
+// workbook, as treasury teams often publish official rates. The header row
+// names the columns as in NewCurrenciesFromCSV; other columns and blank rows
+// are ignored. Numeric cells are read from the text Excel stores for them.
+func NewCurrenciesFromXLSX(r io.ReaderAt, size int64, opts XLSXOptions) ([]Currency, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("xlsx: %w", err)
+	}
+	book := xlsxBook{files: map[string]*zip.File{}}
+	for _, f := range zr.File {
+		book.files[f.Name] = f
+	}
+
+	sheetPath, err := book.sheetPath(opts.Sheet)
+	if err != nil {
+		return nil, err
+	}
+	shared, err := book.sharedStrings()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := book.rows(sheetPath, shared)
+	if err != nil {
+		return nil, err
+	}
+
+	headerRow := opts.HeaderRow
+	if headerRow <= 0 {
+		headerRow = 1
+	}
+	if len(rows) < headerRow {
+		return nil, ErrEmptyCurrencySource
+	}
+
+	index, err := columnIndex(rows[headerRow-1], opts.Mapping.withDefaults())
+	if err != nil {
+		return nil, fmt.Errorf("xlsx: %w", err)
+	}
+	width := 0
+	for _, i := range index {
+		width = max(width, i+1)
+	}
+
+	var currencies []Currency
+	for i, record := range rows[headerRow:] {
+		if blankRecord(record) {
+			continue
+		}
+		for len(record) < width {
+			record = append(record, "")
+		}
+
+		row := headerRow + i + 1
+		c, err := currencyFromRecord(record, index)
+		if err != nil {
+			return nil, fmt.Errorf("xlsx: row %d: %w", row, err)
+		}
+		if err := c.Validate(); err != nil {
+			return nil, fmt.Errorf("xlsx: row %d: %w", row, err)
+		}
+		currencies = append(currencies, c)
+	}
+
+	if len(currencies) == 0 {
+		return nil, ErrEmptyCurrencySource
+	}
+	return currencies, nil
+}
+
+// newCurrenciesFromXLSXFile reads the first sheet of an XLSX file.
+func newCurrenciesFromXLSXFile(f *os.File) ([]Currency, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return NewCurrenciesFromXLSX(f, info.Size(), XLSXOptions{})
+}
+
+func blankRecord(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// xlsxBook reads the parts of a workbook needed to extract cell text.
+type xlsxBook struct {
+	files map[string]*zip.File
+}
+
+func (b xlsxBook) decode(name string, v any) error {
+	f, ok := b.files[name]
+	if !ok {
+		return fmt.Errorf("xlsx: missing %s", name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("xlsx: %s: %w", name, err)
+	}
+	defer rc.Close()
+
+	if err := xml.NewDecoder(io.LimitReader(rc, maxXLSXPart)).Decode(v); err != nil {
+		return fmt.Errorf("xlsx: %s: %w", name, err)
+	}
+	return nil
+}
+
+// sheetPath resolves a sheet name, or the first sheet if name is empty, to
+// the path of its part.
+func (b xlsxBook) sheetPath(name string) (string, error) {
+	var workbook struct {
+		Sheets []struct {
+			Name string `xml:"name,attr"`
+			ID   string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := b.decode("xl/workbook.xml", &workbook); err != nil {
+		return "", err
+	}
+
+	id := ""
+	for _, s := range workbook.Sheets {
+		if name == "" || strings.EqualFold(s.Name, name) {
+			id = s.ID
+			break
+		}
+	}
+	if id == "" {
+		return "", fmt.Errorf("%w: %q", ErrSheetNotFound, name)
+	}
+
+	var rels struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := b.decode("xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return "", err
+	}
+	for _, rel := range rels.Relationships {
+		if rel.ID == id {
+			if strings.HasPrefix(rel.Target, "/") {
+				return strings.TrimPrefix(rel.Target, "/"), nil
+			}
+			return path.Join("xl", rel.Target), nil
+		}
+	}
+	return "", fmt.Errorf("%w: %q", ErrSheetNotFound, name)
+}
+
+// xlsxText is a string item: plain text or runs of rich text.
+type xlsxText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.T
+	}
+	var sb strings.Builder
+	for _, r := range t.Runs {
+		sb.WriteString(r.T)
+	}
+	return sb.String()
+}
+
+// sharedStrings returns the workbook's shared string table, which may be
+// absent.
+func (b xlsxBook) sharedStrings() ([]string, error) {
+	if _, ok := b.files["xl/sharedStrings.xml"]; !ok {
+		return nil, nil
+	}
+	var sst struct {
+		Items []xlsxText `xml:"si"`
+	}
+	if err := b.decode("xl/sharedStrings.xml", &sst); err != nil {
+		return nil, err
+	}
+
+	strs := make([]string, len(sst.Items))
+	for i, item := range sst.Items {
+		strs[i] = item.String()
+	}
+	return strs, nil
+}
+
+// rows returns the text of every cell of a sheet, indexed by row and column
+// from zero. Missing rows and cells are empty.
+func (b xlsxBook) rows(name string, shared []string) ([][]string, error) {
+	var sheet struct {
+		Rows []struct {
+			R     int `xml:"r,attr"`
+			Cells []struct {
+				Ref    string   `xml:"r,attr"`
+				Type   string   `xml:"t,attr"`
+				Value  string   `xml:"v"`
+				Inline xlsxText `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := b.decode(name, &sheet); err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	for _, row := range sheet.Rows {
+		r := row.R - 1
+		if r < 0 {
+			r = len(rows)
+		}
+		if r >= maxXLSXRow {
+			return nil, fmt.Errorf("xlsx: row %d out of range", row.R)
+		}
+		for len(rows) <= r {
+			rows = append(rows, nil)
+		}
+
+		var record []string
+		for _, c := range row.Cells {
+			col := len(record)
+			if c.Ref != "" {
+				var err error
+				if col, err = xlsxColumn(c.Ref); err != nil {
+					return nil, err
+				}
+			}
+			for len(record) <= col {
+				record = append(record, "")
+			}
+
+			switch c.Type {
+			case "s":
+				i, err := strconv.Atoi(c.Value)
+				if err != nil || i < 0 || i >= len(shared) {
+					return nil, fmt.Errorf("xlsx: cell %s: bad shared string", c.Ref)
+				}
+				record[col] = shared[i]
+			case "inlineStr":
+				record[col] = c.Inline.String()
+			default:
+				record[col] = c.Value
+			}
+		}
+		rows[r] = record
+	}
+	return rows, nil
+}
+
+// Worksheet limits: 1048576 rows and columns up to XFD.
+const (
+	maxXLSXRow    = 1 << 20
+	maxXLSXColumn = 16384
+)
+
+// xlsxColumn returns the zero-based column of a cell reference such as "B7".
+func xlsxColumn(ref string) (int, error) {
+	col := 0
+	for _, ch := range ref {
+		if ch < 'A' || ch > 'Z' {
+			break
+		}
+		col = col*26 + int(ch-'A'+1)
+		if col > maxXLSXColumn {
+			break
+		}
+	}
+	if col == 0 || col > maxXLSXColumn {
+		return 0, fmt.Errorf("xlsx: bad cell reference %q", ref)
+	}
+	return col - 1, nil
+}
@@ -0,0 +1,115 @@
+package converter
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testNotesSheet = `<?xml version="1.0" encoding="UTF-8"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<sheetData><row r="1"><c r="A1" t="s"><v>0</v></c></row></sheetData>
+</worksheet>`
+
+// testWorkbook builds an XLSX file with a Notes sheet and a Rates sheet.
+func testWorkbook(t *testing.T, notes, rates string) []byte {
+	t.Helper()
+	parts := map[string]string{
+		"xl/workbook.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Notes" sheetId="1" r:id="rId1"/><sheet name="Rates" sheetId="2" r:id="rId2"/></sheets>
+</workbook>`,
+		"xl/_rels/workbook.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="/xl/worksheets/sheet2.xml"/>
+</Relationships>`,
+		"xl/sharedStrings.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<si><t>Official rates</t></si><si><t>Currency</t></si><si><r><t>B</t></r><r><t>id</t></r></si><si><t>Offer</t></si><si><t>EUR</t></si>
+</sst>`,
+		"xl/worksheets/sheet1.xml": notes,
+		"xl/worksheets/sheet2.xml": rates,
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range parts {
+		w, err := zw.Create(name)
+		assert.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+const testRatesSheet = `<?xml version="1.0" encoding="UTF-8"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<sheetData>
+<row r="1"><c r="A1" t="s"><v>0</v></c></row>
+<row r="3"><c r="A3" t="s"><v>1</v></c><c r="B3" t="inlineStr"><is><t>Decimals</t></is></c><c r="C3" t="s"><v>2</v></c><c r="D3" t="s"><v>3</v></c></row>
+<row r="4"><c r="A4" t="s"><v>4</v></c><c r="B4"><v>2</v></c><c r="C4"><v>0.9</v></c><c r="D4"><v>9.5E-1</v></c></row>
+<row r="5"><c r="B5"><v></v></c></row>
+<row r="6"><c r="A6" t="inlineStr"><is><t>btc</t></is></c><c r="B6"><v>8</v></c><c r="C6"><v>1.4999999999999999E-5</v></c><c r="D6"><v>1.5E-5</v></c></row>
+</sheetData>
+</worksheet>`
+
+func TestNewCurrenciesFromXLSX(t *testing.T) {
+	data := testWorkbook(t, testNotesSheet, testRatesSheet)
+	opts := XLSXOptions{
+		Sheet:     "rates",
+		HeaderRow: 3,
+		Mapping:   FieldMapping{ISO: "Currency", Precision: "decimals", Buy: "Bid", Sell: "Offer"},
+	}
+
+	currencies, err := NewCurrenciesFromXLSX(bytes.NewReader(data), int64(len(data)), opts)
+	assert.NoError(t, err)
+	assert.Len(t, currencies, 2)
+	assert.Equal(t, "EUR", currencies[0].ISOCode)
+	assert.Equal(t, 2, currencies[0].Precision)
+	assert.Equal(t, "0.9", currencies[0].BuyRate.String())
+	assert.Equal(t, "0.95", currencies[0].SellRate.String())
+	assert.Equal(t, "BTC", currencies[1].ISOCode)
+	assert.Equal(t, "0.000015", currencies[1].SellRate.String())
+	assert.Equal(t, "0.000014999999999999999", currencies[1].BuyRate.String())
+
+	_, err = NewCurrenciesFromXLSX(bytes.NewReader(data), int64(len(data)), XLSXOptions{Sheet: "Missing"})
+	assert.ErrorIs(t, err, ErrSheetNotFound)
+
+	// The first sheet has no rate columns.
+	_, err = NewCurrenciesFromXLSX(bytes.NewReader(data), int64(len(data)), XLSXOptions{})
+	assert.EqualError(t, err, "xlsx: missing column isoCode")
+
+	_, err = NewCurrenciesFromXLSX(bytes.NewReader(data), int64(len(data)), XLSXOptions{Sheet: "Rates", HeaderRow: 10})
+	assert.ErrorIs(t, err, ErrEmptyCurrencySource)
+
+	bad := testWorkbook(t, testNotesSheet, `<worksheet><sheetData>
+<row r="1"><c t="inlineStr"><is><t>isoCode</t></is></c><c t="inlineStr"><is><t>precision</t></is></c><c t="inlineStr"><is><t>buyRate</t></is></c><c t="inlineStr"><is><t>sellRate</t></is></c></row>
+<row r="2"><c t="inlineStr"><is><t>USD</t></is></c><c><v>2</v></c><c><v>-1</v></c><c><v>1</v></c></row>
+</sheetData></worksheet>`)
+	_, err = NewCurrenciesFromXLSX(bytes.NewReader(bad), int64(len(bad)), XLSXOptions{Sheet: "Rates"})
+	assert.ErrorIs(t, err, ErrInvalidRate)
+	assert.ErrorContains(t, err, "row 2")
+
+	_, err = NewCurrenciesFromXLSX(bytes.NewReader([]byte("not a zip")), 9, XLSXOptions{})
+	assert.Error(t, err)
+}
+
+func TestNewCurrenciesFromFile_XLSX(t *testing.T) {
+	sheet := `<worksheet><sheetData>
+<row><c t="inlineStr"><is><t>isoCode</t></is></c><c t="inlineStr"><is><t>precision</t></is></c><c t="inlineStr"><is><t>buyRate</t></is></c><c t="inlineStr"><is><t>sellRate</t></is></c></row>
+<row><c t="inlineStr"><is><t>USD</t></is></c><c><v>2</v></c><c><v>1</v></c><c><v>1</v></c></row>
+</sheetData></worksheet>`
+	data := testWorkbook(t, sheet, testNotesSheet)
+
+	path := filepath.Join(t.TempDir(), "rates.XLSX")
+	assert.NoError(t, os.WriteFile(path, data, 0o600))
+	currencies, err := NewCurrenciesFromFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", currencies[0].ISOCode)
+}